@@ -4,15 +4,30 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/buildinfo"
 	"github.com/benx421/payment-gateway/bank/internal/config"
 	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/db/migrations"
+	"github.com/benx421/payment-gateway/bank/internal/events"
+	"github.com/benx421/payment-gateway/bank/internal/grpcserver"
 	"github.com/benx421/payment-gateway/bank/internal/handlers"
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/benx421/payment-gateway/bank/internal/seed"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/benx421/payment-gateway/bank/internal/webhook"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
@@ -26,27 +41,90 @@ func main() {
 	slog.SetDefault(logger)
 
 	logger.Info("starting bank api",
+		"version", buildinfo.Version,
+		"commit", buildinfo.Revision(),
 		"port", cfg.Server.Port,
 		"log_level", cfg.Logger.Level,
+		"auth_enabled", cfg.Auth.Enabled(),
+		"webhooks_enabled", cfg.Webhook.Enabled(),
+		"tracing_enabled", cfg.Tracing.Enabled(),
 	)
 
 	ctx := context.Background()
+	shutdownTracing, err := tracing.Setup(ctx, &cfg.Tracing, buildinfo.Version)
+	if err != nil {
+		logger.Error("failed to configure tracing", "error", err)
+		os.Exit(1)
+	}
+
 	database, err := db.Connect(ctx, &cfg.Database, logger)
 	if err != nil {
 		logger.Error("failed to connect to database", "error", err)
 		os.Exit(1)
 	}
-	defer func() {
-		if err = database.Close(); err != nil {
-			logger.Error("failed to close database connection", "error", err)
+
+	repository.SetQueryTimeout(cfg.Database.QueryTimeout)
+	metrics.SetAmountBuckets(cfg.Metrics.AmountBuckets)
+
+	if cfg.Database.RunMigrations {
+		if _, err := database.Migrate(ctx, migrations.FS); err != nil {
+			logger.Error("failed to run database migrations", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.Seed.Enabled() {
+		if err := loadSeed(ctx, database, cfg, logger); err != nil {
+			logger.Error("failed to load seed data", "error", err)
+			os.Exit(1)
 		}
-	}()
+	}
 
-	// Start periodic cleanup goroutine
-	stopCleanup := make(chan struct{})
-	go runPeriodicCleanup(database, logger, stopCleanup)
+	// Background workers share a context that is cancelled before the pool is closed
+	bgCtx, stopBackground := context.WithCancel(ctx)
+	var background sync.WaitGroup
 
-	router := handlers.NewRouter(database, cfg, logger)
+	background.Go(func() {
+		runPeriodicCleanup(bgCtx, database, cfg, logger)
+	})
+	background.Go(func() {
+		database.MonitorReplica(bgCtx)
+	})
+
+	if cfg.Reconcile.Enabled() {
+		background.Go(func() {
+			runPeriodicReconciliation(bgCtx, service.NewAccountService(database), cfg.Reconcile.Interval, logger)
+		})
+	}
+
+	// Services publish committed transactions to the bus; webhooks and other consumers subscribe to it
+	bus := events.NewBus(cfg.Events.BufferSize, logger)
+	background.Go(func() {
+		bus.Run(bgCtx)
+	})
+
+	if cfg.Webhook.Enabled() {
+		dispatcher := webhook.NewDispatcher(webhook.Config{
+			URL:            cfg.Webhook.URL,
+			Secret:         cfg.Webhook.Secret,
+			MaxAttempts:    cfg.Webhook.MaxAttempts,
+			InitialBackoff: cfg.Webhook.InitialBackoff,
+			QueueSize:      cfg.Webhook.QueueSize,
+		}, nil, logger)
+		bus.Subscribe(func(ctx context.Context, event events.Event) {
+			dispatcher.Notify(ctx, event.Transaction)
+		})
+		background.Go(func() {
+			dispatcher.Run(bgCtx)
+		})
+	}
+
+	// Readiness flips true once dependencies are up and false again when draining
+	ready := &atomic.Bool{}
+	// Toggled through the admin API; pauses writes over HTTP and gRPC alike
+	maintenance := &atomic.Bool{}
+
+	router := handlers.NewRouter(database, cfg, ready, maintenance, bus, logger)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -56,64 +134,297 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	if cfg.Server.TLSEnabled() {
+		server.TLSConfig, err = cfg.Server.NewTLSConfig()
+		if err != nil {
+			logger.Error("failed to configure TLS", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	ready.Store(true)
+
 	go func() {
-		logger.Info("server listening", "address", server.Addr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("server listening",
+			"address", server.Addr,
+			"tls", cfg.Server.TLSEnabled(),
+			"mtls", cfg.Server.TLSClientCAFile != "",
+		)
+		if err := listenAndServe(server, &cfg.Server); err != nil && err != http.ErrServerClosed {
 			logger.Error("server failed", "error", err)
 			os.Exit(1)
 		}
 	}()
 
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled() {
+		grpcServer, err = newGRPCServer(database, cfg, maintenance, bus, logger)
+		if err != nil {
+			logger.Error("failed to configure gRPC server", "error", err)
+			os.Exit(1)
+		}
+
+		listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			logger.Error("failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+
+		go func() {
+			logger.Info("grpc server listening", "address", listener.Addr().String(), "tls", cfg.Server.TLSEnabled())
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("grpc server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("shutting down server...")
-	close(stopCleanup)
+	ready.Store(false)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	// Stops accepting connections and waits for in-flight requests to finish
+	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("server forced to shutdown", "error", err)
 	}
 
+	if grpcServer != nil {
+		stopGRPC(shutdownCtx, grpcServer)
+	}
+
+	stopBackground()
+	if !waitWithContext(shutdownCtx, &background) {
+		logger.Error("background jobs did not stop before the shutdown timeout")
+	}
+
+	// Flushes spans still buffered for export
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
+
+	if err := database.Close(); err != nil {
+		logger.Error("failed to close database connection", "error", err)
+	}
+
 	logger.Info("server stopped")
 }
 
+// newGRPCServer builds the gRPC server, sharing the HTTP server's API keys and TLS settings
+func newGRPCServer(
+	database *db.DB,
+	cfg *config.Config,
+	maintenance *atomic.Bool,
+	notifier service.Notifier,
+	logger *slog.Logger,
+) (*grpc.Server, error) {
+	svc := handlers.NewServices(database, cfg, notifier)
+	srv := grpcserver.NewServer(svc.Auth, svc.Capture, svc.Void, svc.Refund, svc.Transaction, logger)
+
+	tables := repository.NewTables(database.TablePrefix())
+	opts := grpcserver.Options{
+		APIKeys:             cfg.Auth.APIKeys,
+		Idempotency:         repository.NewIdempotencyRepository(database, repository.WithIdempotencyTables(tables)),
+		Tables:              tables,
+		IdempotencyFailOpen: cfg.Idempotency.FailOpen,
+		Maintenance:         maintenance,
+	}
+	if cfg.Server.TLSEnabled() {
+		tlsConfig, err := cfg.Server.NewGRPCTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts.Credentials = credentials.NewTLS(tlsConfig)
+	}
+	return grpcserver.NewGRPCServer(srv, opts), nil
+}
+
+// waitWithContext waits for wg, giving up when ctx expires. It reports whether wg finished.
+func waitWithContext(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// stopGRPC waits for in-flight calls to finish, cutting them off when ctx expires
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// listenAndServe serves over TLS when certificates are configured, plain HTTP otherwise
+func listenAndServe(server *http.Server, cfg *config.ServerConfig) error {
+	if cfg.TLSEnabled() {
+		return server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+	return server.ListenAndServe()
+}
+
+// loadSeed inserts the configured development fixture, skipping rows that already exist
+func loadSeed(ctx context.Context, database *db.DB, cfg *config.Config, logger *slog.Logger) error {
+	fixture, err := seed.ReadFile(cfg.Seed.File)
+	if err != nil {
+		return err
+	}
+
+	tables := repository.NewTables(database.TablePrefix())
+	accountOpts := []repository.AccountOption{repository.WithAccountTables(tables)}
+	cvvCipher, err := cfg.CVV.NewCipher()
+	if err != nil {
+		return err
+	}
+	if cvvCipher != nil {
+		accountOpts = append(accountOpts, repository.WithCVVCipher(cvvCipher))
+	}
+	txnOpts := []repository.TransactionOption{
+		repository.WithTransactionTables(tables),
+		repository.WithMaxMetadataBytes(cfg.Metadata.MaxBytes),
+	}
+
+	result, err := seed.Load(ctx, database, fixture, accountOpts, txnOpts)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("loaded seed data",
+		"file", cfg.Seed.File,
+		"accounts_created", result.AccountsCreated,
+		"accounts_skipped", result.AccountsSkipped,
+		"transactions_created", result.TransactionsCreated,
+		"transactions_skipped", result.TransactionsSkipped,
+	)
+	return nil
+}
+
 // cleanupIdempotencyKeys removes idempotency keys older than 24 hours
-func cleanupIdempotencyKeys(ctx context.Context, database *db.DB, logger *slog.Logger) {
+func cleanupIdempotencyKeys(ctx context.Context, database *db.DB, tables *repository.Tables, logger *slog.Logger) {
 	cutoffTime := time.Now().Add(-24 * time.Hour)
-	result, err := database.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at < $1", cutoffTime)
+	rowsDeleted, err := repository.NewIdempotencyRepository(database, repository.WithIdempotencyTables(tables)).DeleteOlderThan(ctx, cutoffTime)
 	if err != nil {
 		logger.Warn("failed to cleanup old idempotency keys", "error", err)
 		return
 	}
+	if rowsDeleted > 0 {
+		logger.Info("cleaned up old idempotency keys", "rows_deleted", rowsDeleted)
+	}
+}
 
-	rowsDeleted, err := result.RowsAffected()
+// archiveTransactions moves transaction chains settled for longer than archiveAfter into the archive
+func archiveTransactions(ctx context.Context, database *db.DB, tables *repository.Tables, archiveAfter time.Duration, logger *slog.Logger) {
+	var archived int64
+	err := database.WithTransaction(ctx, db.SerializableTx(), func(tx *db.Tx) error {
+		var err error
+		archived, err = repository.NewTransactionRepository(tx, repository.WithTransactionTables(tables)).Archive(ctx, time.Now().Add(-archiveAfter))
+		return err
+	})
 	if err != nil {
-		logger.Warn("failed to get rows affected", "error", err)
+		logger.Warn("failed to archive transactions", "error", err)
+		return
+	}
+	if archived > 0 {
+		logger.Info("archived settled transactions", "rows_archived", archived)
+	}
+}
+
+// cleanupRequestNonces removes request nonces too old to pass the signature window check again
+func cleanupRequestNonces(ctx context.Context, database *db.DB, tables *repository.Tables, ttl time.Duration, logger *slog.Logger) {
+	rowsDeleted, err := repository.NewNonceRepository(database, repository.WithNonceTables(tables)).DeleteOlderThan(ctx, time.Now().Add(-ttl))
+	if err != nil {
+		logger.Warn("failed to cleanup old request nonces", "error", err)
 		return
 	}
 	if rowsDeleted > 0 {
-		logger.Info("cleaned up old idempotency keys", "rows_deleted", rowsDeleted)
+		logger.Info("cleaned up old request nonces", "rows_deleted", rowsDeleted)
 	}
 }
 
-// runPeriodicCleanup runs idempotency key cleanup every hour
-func runPeriodicCleanup(database *db.DB, logger *slog.Logger, stop <-chan struct{}) {
+// runPeriodicCleanup runs idempotency key and request nonce cleanup, and archiving when enabled,
+// every hour until ctx is cancelled
+func runPeriodicCleanup(ctx context.Context, database *db.DB, cfg *config.Config, logger *slog.Logger) {
+	archive := &cfg.Archive
+	tables := repository.NewTables(database.TablePrefix())
+
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			cleanupIdempotencyKeys(ctx, database, logger)
+			cleanupCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			cleanupIdempotencyKeys(cleanupCtx, database, tables, logger)
+			if cfg.Auth.RequireNonce {
+				cleanupRequestNonces(cleanupCtx, database, tables, cfg.Auth.NonceTTL(), logger)
+			}
 			cancel()
-		case <-stop:
+
+			if archive.Enabled() {
+				archiveCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+				archiveTransactions(archiveCtx, database, tables, archive.After, logger)
+				cancel()
+			}
+		case <-ctx.Done():
 			logger.Info("stopping periodic cleanup")
 			return
 		}
 	}
 }
+
+// reconcileBalances checks every balance against its ledger, logging each one that differs
+func reconcileBalances(ctx context.Context, accounts *service.AccountService, logger *slog.Logger) {
+	drifts, err := accounts.ReconcileBalances(ctx)
+	if err != nil {
+		logger.Warn("failed to reconcile balances", "error", err)
+		return
+	}
+	for _, d := range drifts {
+		logger.Error("balance differs from its ledger",
+			"account_id", d.AccountID,
+			"currency", d.Currency,
+			"balance_cents", d.BalanceCents,
+			"ledger_cents", d.LedgerCents,
+			"available_balance_cents", d.AvailableBalanceCents,
+			"ledger_available_cents", d.LedgerAvailableCents,
+		)
+	}
+}
+
+// runPeriodicReconciliation reconciles balances every interval until ctx is cancelled
+func runPeriodicReconciliation(ctx context.Context, accounts *service.AccountService, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reconcileCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			reconcileBalances(reconcileCtx, accounts, logger)
+			cancel()
+		case <-ctx.Done():
+			logger.Info("stopping periodic reconciliation")
+			return
+		}
+	}
+}
@@ -5,6 +5,8 @@ package api
 
 import (
 	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 // Defines values for AuthorizationResponseStatus.
@@ -19,29 +21,51 @@ const (
 
 // Defines values for ErrorCode.
 const (
-	ErrorCodeAlreadyCaptured          ErrorCode = "already_captured"
-	ErrorCodeAlreadyRefunded          ErrorCode = "already_refunded"
-	ErrorCodeAlreadyVoided            ErrorCode = "already_voided"
-	ErrorCodeAmountMismatch           ErrorCode = "amount_mismatch"
-	ErrorCodeAuthorizationAlreadyUsed ErrorCode = "authorization_already_used"
-	ErrorCodeAuthorizationExpired     ErrorCode = "authorization_expired"
-	ErrorCodeAuthorizationNotFound    ErrorCode = "authorization_not_found"
-	ErrorCodeCaptureNotFound          ErrorCode = "capture_not_found"
-	ErrorCodeCardExpired              ErrorCode = "card_expired"
-	ErrorCodeInsufficientFunds        ErrorCode = "insufficient_funds"
-	ErrorCodeInternalError            ErrorCode = "internal_error"
-	ErrorCodeInvalidAmount            ErrorCode = "invalid_amount"
-	ErrorCodeInvalidCard              ErrorCode = "invalid_card"
-	ErrorCodeInvalidCvv               ErrorCode = "invalid_cvv"
-	ErrorCodeMissingIdempotencyKey    ErrorCode = "missing_idempotency_key"
-	ErrorCodeNotFound                 ErrorCode = "not_found"
-	ErrorCodeRefundNotFound           ErrorCode = "refund_not_found"
+	ErrorCodeAccountNotFound             ErrorCode = "account_not_found"
+	ErrorCodeAlreadyCaptured             ErrorCode = "already_captured"
+	ErrorCodeAlreadyRefunded             ErrorCode = "already_refunded"
+	ErrorCodeAlreadyVoided               ErrorCode = "already_voided"
+	ErrorCodeAmountExceedsLimit          ErrorCode = "amount_exceeds_limit"
+	ErrorCodeAmountMismatch              ErrorCode = "amount_mismatch"
+	ErrorCodeAuthorizationAlreadyUsed    ErrorCode = "authorization_already_used"
+	ErrorCodeAuthorizationExpired        ErrorCode = "authorization_expired"
+	ErrorCodeAuthorizationNotFound       ErrorCode = "authorization_not_found"
+	ErrorCodeCaptureExceedsAuthorization ErrorCode = "capture_exceeds_authorization"
+	ErrorCodeCaptureNotFound             ErrorCode = "capture_not_found"
+	ErrorCodeCardExpired                 ErrorCode = "card_expired"
+	ErrorCodeDailyLimitExceeded          ErrorCode = "daily_limit_exceeded"
+	ErrorCodeFxRateUnavailable           ErrorCode = "fx_rate_unavailable"
+	ErrorCodeIdempotencyUnavailable      ErrorCode = "idempotency_unavailable"
+	ErrorCodeInsufficientFunds           ErrorCode = "insufficient_funds"
+	ErrorCodeInternalError               ErrorCode = "internal_error"
+	ErrorCodeInvalidAmount               ErrorCode = "invalid_amount"
+	ErrorCodeInvalidCard                 ErrorCode = "invalid_card"
+	ErrorCodeInvalidCurrency             ErrorCode = "invalid_currency"
+	ErrorCodeInvalidCvv                  ErrorCode = "invalid_cvv"
+	ErrorCodeInvalidExpiry               ErrorCode = "invalid_expiry"
+	ErrorCodeInvalidIdempotencyKey       ErrorCode = "invalid_idempotency_key"
+	ErrorCodeInvalidRequest              ErrorCode = "invalid_request"
+	ErrorCodeMaintenance                 ErrorCode = "maintenance"
+	ErrorCodeMissingIdempotencyKey       ErrorCode = "missing_idempotency_key"
+	ErrorCodeNotFound                    ErrorCode = "not_found"
+	ErrorCodeOverloaded                  ErrorCode = "overloaded"
+	ErrorCodeRateLimited                 ErrorCode = "rate_limited"
+	ErrorCodeRefundExceedsCapture        ErrorCode = "refund_exceeds_capture"
+	ErrorCodeRefundNotFound              ErrorCode = "refund_not_found"
+	ErrorCodeRequestTooLarge             ErrorCode = "request_too_large"
+	ErrorCodeTimeout                     ErrorCode = "timeout"
+	ErrorCodeTransactionConflict         ErrorCode = "transaction_conflict"
+	ErrorCodeTransactionNotFound         ErrorCode = "transaction_not_found"
+	ErrorCodeUnauthorized                ErrorCode = "unauthorized"
+	ErrorCodeValidationFailed            ErrorCode = "validation_failed"
+	ErrorCodeVelocityExceeded            ErrorCode = "velocity_exceeded"
+	ErrorCodeVoidExceedsAuthorization    ErrorCode = "void_exceeds_authorization"
 )
 
-// Defines values for HealthResponseStatus.
+// Defines values for HealthStatus.
 const (
-	Healthy   HealthResponseStatus = "healthy"
-	Unhealthy HealthResponseStatus = "unhealthy"
+	Healthy   HealthStatus = "healthy"
+	Unhealthy HealthStatus = "unhealthy"
 )
 
 // Defines values for RefundResponseStatus.
@@ -49,24 +73,168 @@ const (
 	Refunded RefundResponseStatus = "refunded"
 )
 
+// Defines values for TransactionResponseStatus.
+const (
+	TransactionResponseStatusACTIVE    TransactionResponseStatus = "ACTIVE"
+	TransactionResponseStatusCOMPLETED TransactionResponseStatus = "COMPLETED"
+	TransactionResponseStatusDECLINED  TransactionResponseStatus = "DECLINED"
+	TransactionResponseStatusEXPIRED   TransactionResponseStatus = "EXPIRED"
+	TransactionResponseStatusVOIDED    TransactionResponseStatus = "VOIDED"
+)
+
+// Defines values for TransactionResponseType.
+const (
+	TransactionResponseTypeAUTHHOLD    TransactionResponseType = "AUTH_HOLD"
+	TransactionResponseTypeCAPTURE     TransactionResponseType = "CAPTURE"
+	TransactionResponseTypeCHARGEBACK  TransactionResponseType = "CHARGEBACK"
+	TransactionResponseTypeFUNDING     TransactionResponseType = "FUNDING"
+	TransactionResponseTypePARTIALVOID TransactionResponseType = "PARTIAL_VOID"
+	TransactionResponseTypeREFUND      TransactionResponseType = "REFUND"
+	TransactionResponseTypeTRANSFERIN  TransactionResponseType = "TRANSFER_IN"
+	TransactionResponseTypeTRANSFEROUT TransactionResponseType = "TRANSFER_OUT"
+	TransactionResponseTypeVOID        TransactionResponseType = "VOID"
+)
+
+// Defines values for TransactionStatusResultStatus.
+const (
+	TransactionStatusResultStatusACTIVE    TransactionStatusResultStatus = "ACTIVE"
+	TransactionStatusResultStatusCOMPLETED TransactionStatusResultStatus = "COMPLETED"
+	TransactionStatusResultStatusDECLINED  TransactionStatusResultStatus = "DECLINED"
+	TransactionStatusResultStatusEXPIRED   TransactionStatusResultStatus = "EXPIRED"
+	TransactionStatusResultStatusVOIDED    TransactionStatusResultStatus = "VOIDED"
+)
+
+// Defines values for TransactionStatusResultType.
+const (
+	TransactionStatusResultTypeAUTHHOLD    TransactionStatusResultType = "AUTH_HOLD"
+	TransactionStatusResultTypeCAPTURE     TransactionStatusResultType = "CAPTURE"
+	TransactionStatusResultTypeCHARGEBACK  TransactionStatusResultType = "CHARGEBACK"
+	TransactionStatusResultTypeFUNDING     TransactionStatusResultType = "FUNDING"
+	TransactionStatusResultTypePARTIALVOID TransactionStatusResultType = "PARTIAL_VOID"
+	TransactionStatusResultTypeREFUND      TransactionStatusResultType = "REFUND"
+	TransactionStatusResultTypeTRANSFERIN  TransactionStatusResultType = "TRANSFER_IN"
+	TransactionStatusResultTypeTRANSFEROUT TransactionStatusResultType = "TRANSFER_OUT"
+	TransactionStatusResultTypeVOID        TransactionStatusResultType = "VOID"
+)
+
 // Defines values for VoidResponseStatus.
 const (
-	Voided VoidResponseStatus = "voided"
+	PartiallyVoided VoidResponseStatus = "partially_voided"
+	Voided          VoidResponseStatus = "voided"
 )
 
+// AccountListResponse defines model for AccountListResponse.
+type AccountListResponse struct {
+	Accounts []AccountResponse `json:"accounts"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+// AccountReconciliation defines model for AccountReconciliation.
+type AccountReconciliation struct {
+	AccountId openapi_types.UUID `json:"account_id"`
+
+	// DriftCents Stored balance less ledger balance in the primary currency; zero when they agree
+	DriftCents int64 `json:"drift_cents"`
+}
+
+// AccountResponse defines model for AccountResponse.
+type AccountResponse struct {
+	// AccountNumber Card number with all but the last four digits masked
+	AccountNumber string `json:"account_number"`
+
+	// AvailableBalanceCents Balance less active authorization holds, in cents
+	AvailableBalanceCents int64 `json:"available_balance_cents"`
+
+	// BalanceCents Ledger balance in cents
+	BalanceCents int64              `json:"balance_cents"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Currency     string             `json:"currency"`
+	Id           openapi_types.UUID `json:"id"`
+
+	// PendingCents Funds reserved by active authorization holds, in cents
+	PendingCents int64     `json:"pending_cents"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// AdminStatsResponse defines model for AdminStatsResponse.
+type AdminStatsResponse struct {
+	// Accounts Number of accounts
+	Accounts int `json:"accounts"`
+
+	// ComputedAt When the counts were taken
+	ComputedAt time.Time `json:"computed_at"`
+
+	// Transactions Live transaction counts, one entry per type and status present
+	Transactions []TransactionCount `json:"transactions"`
+}
+
 // AuthorizationResponse defines model for AuthorizationResponse.
 type AuthorizationResponse struct {
-	Amount          int64                       `json:"amount"`
-	AuthorizationId string                      `json:"authorization_id"`
-	CreatedAt       time.Time                   `json:"created_at"`
-	Currency        string                      `json:"currency"`
-	ExpiresAt       time.Time                   `json:"expires_at"`
-	Status          AuthorizationResponseStatus `json:"status"`
+	Amount          int64     `json:"amount"`
+	AuthorizationId string    `json:"authorization_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	Currency        string    `json:"currency"`
+	ExpiresAt       time.Time `json:"expires_at"`
+
+	// Simulated True when the authorization was only simulated and no funds are held
+	Simulated bool                        `json:"simulated"`
+	Status    AuthorizationResponseStatus `json:"status"`
 }
 
 // AuthorizationResponseStatus defines model for AuthorizationResponse.Status.
 type AuthorizationResponseStatus string
 
+// BalanceDrift defines model for BalanceDrift.
+type BalanceDrift struct {
+	AccountId openapi_types.UUID `json:"account_id"`
+
+	// AvailableBalanceCents Stored available balance in cents
+	AvailableBalanceCents int64 `json:"available_balance_cents"`
+
+	// AvailableDriftCents Stored available balance less ledger available balance
+	AvailableDriftCents int64 `json:"available_drift_cents"`
+
+	// BalanceCents Stored balance in cents
+	BalanceCents int64  `json:"balance_cents"`
+	Currency     string `json:"currency"`
+
+	// DriftCents Stored balance less ledger balance
+	DriftCents int64 `json:"drift_cents"`
+
+	// LedgerAvailableCents Ledger balance less the outstanding authorization holds, in cents
+	LedgerAvailableCents int64 `json:"ledger_available_cents"`
+
+	// LedgerCents Opening balance plus the settled transactions, in cents
+	LedgerCents int64 `json:"ledger_cents"`
+}
+
+// BalanceResponse defines model for BalanceResponse.
+type BalanceResponse struct {
+	// AccountNumber Card number with all but the last four digits masked
+	AccountNumber string `json:"account_number"`
+
+	// AvailableBalanceCents Balance less active authorization holds, in cents
+	AvailableBalanceCents int64 `json:"available_balance_cents"`
+
+	// BalanceCents Ledger balance in cents
+	BalanceCents int64  `json:"balance_cents"`
+	Currency     string `json:"currency"`
+
+	// PendingCents Funds reserved by active authorization holds, in cents (balance less available balance, never negative)
+	PendingCents int64 `json:"pending_cents"`
+}
+
+// BatchStatusRequest defines model for BatchStatusRequest.
+type BatchStatusRequest struct {
+	Ids []openapi_types.UUID `json:"ids"`
+}
+
+// BatchStatusResponse defines model for BatchStatusResponse.
+type BatchStatusResponse struct {
+	Results []TransactionStatusResult `json:"results"`
+}
+
 // CaptureResponse defines model for CaptureResponse.
 type CaptureResponse struct {
 	Amount          int64                 `json:"amount"`
@@ -82,16 +250,28 @@ type CaptureResponseStatus string
 
 // CreateAuthorizationRequest defines model for CreateAuthorizationRequest.
 type CreateAuthorizationRequest struct {
-	// Amount Amount in cents
-	Amount int64 `json:"amount"`
+	// Amount Amount in minor units of `currency`. Required unless `amount_decimal` is set.
+	Amount int64 `json:"amount,omitempty,omitzero"`
+
+	// AmountDecimal Amount in major units of `currency`, e.g. "99.99", as an alternative to `amount`.
+	// At most as many decimal places as the currency has minor units; it is never rounded.
+	AmountDecimal string `json:"amount_decimal,omitempty,omitzero"`
 
 	// CardNumber Card number (Luhn validated)
 	CardNumber string `json:"card_number"`
 
+	// Currency ISO 4217 currency of the amount. Defaults to USD. When it differs from the
+	// account currency the hold is converted at the current exchange rate.
+	Currency string `json:"currency,omitempty,omitzero"`
+
 	// Cvv Card verification value
-	Cvv         string `json:"cvv"`
-	ExpiryMonth int    `json:"expiry_month"`
-	ExpiryYear  int    `json:"expiry_year"`
+	Cvv string `json:"cvv"`
+
+	// ExpiresAt When the hold lapses. Must be in the future and within the configured maximum
+	// (30 days by default). Defaults to the configured hold duration (7 days).
+	ExpiresAt   time.Time `json:"expires_at,omitempty,omitzero"`
+	ExpiryMonth int       `json:"expiry_month"`
+	ExpiryYear  int       `json:"expiry_year"`
 }
 
 // CreateCaptureRequest defines model for CreateCaptureRequest.
@@ -105,7 +285,7 @@ type CreateCaptureRequest struct {
 
 // CreateRefundRequest defines model for CreateRefundRequest.
 type CreateRefundRequest struct {
-	// Amount Amount in cents (must match capture)
+	// Amount Amount in cents (at most the capture's remaining refundable amount)
 	Amount int64 `json:"amount"`
 
 	// CaptureId Capture ID to refund
@@ -114,26 +294,87 @@ type CreateRefundRequest struct {
 
 // CreateVoidRequest defines model for CreateVoidRequest.
 type CreateVoidRequest struct {
+	// Amount Amount in cents to release from the authorization, leaving it active
+	Amount int64 `json:"amount,omitempty,omitzero"`
+
 	// AuthorizationId Authorization ID to void
 	AuthorizationId string `json:"authorization_id"`
 }
 
+// DatabaseStatus defines model for DatabaseStatus.
+type DatabaseStatus struct {
+	// Error Why the connectivity check failed
+	Error string `json:"error,omitempty,omitzero"`
+
+	// LatencyMs Round trip of the connectivity check
+	LatencyMs int64     `json:"latency_ms"`
+	Pool      PoolStats `json:"pool"`
+
+	// Replica Present when a read replica is configured. A lagging replica only moves reads to the primary, so it does not fail the status check.
+	Replica ReplicaStatus `json:"replica,omitempty,omitzero"`
+	Status  HealthStatus  `json:"status"`
+}
+
 // ErrorCode defines model for ErrorCode.
 type ErrorCode string
 
-// ErrorResponse defines model for ErrorResponse.
+// ErrorDetail defines model for ErrorDetail.
+type ErrorDetail struct {
+	Code ErrorCode `json:"code"`
+
+	// Fields Every invalid request field, set with `validation_failed`
+	Fields  []FieldError `json:"fields,omitempty,omitzero"`
+	Message string       `json:"message"`
+}
+
+// ErrorResponse Envelope returned by every endpoint and middleware on failure
 type ErrorResponse struct {
-	Error   ErrorCode `json:"error"`
-	Message string    `json:"message"`
+	Error ErrorDetail `json:"error"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Field JSON path of the field in the request body
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // HealthResponse defines model for HealthResponse.
 type HealthResponse struct {
-	Status HealthResponseStatus `json:"status"`
+	Status HealthStatus `json:"status"`
+}
+
+// HealthStatus defines model for HealthStatus.
+type HealthStatus string
+
+// MaintenanceStatus defines model for MaintenanceStatus.
+type MaintenanceStatus struct {
+	// Enabled Whether writes are rejected
+	Enabled bool `json:"enabled"`
+}
+
+// PoolStats defines model for PoolStats.
+type PoolStats struct {
+	Idle               int `json:"idle"`
+	InUse              int `json:"in_use"`
+	MaxOpenConnections int `json:"max_open_connections"`
+	OpenConnections    int `json:"open_connections"`
+
+	// WaitCount Total connections waited for since startup
+	WaitCount int64 `json:"wait_count"`
+
+	// WaitDurationMs Total time spent waiting for a connection since startup
+	WaitDurationMs int64 `json:"wait_duration_ms"`
 }
 
-// HealthResponseStatus defines model for HealthResponse.Status.
-type HealthResponseStatus string
+// ReconciliationReport defines model for ReconciliationReport.
+type ReconciliationReport struct {
+	// CheckedAt When the balances were compared
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Drifts Balances that differ from their ledger
+	Drifts []BalanceDrift `json:"drifts"`
+}
 
 // RefundResponse defines model for RefundResponse.
 type RefundResponse struct {
@@ -148,9 +389,100 @@ type RefundResponse struct {
 // RefundResponseStatus defines model for RefundResponse.Status.
 type RefundResponseStatus string
 
+// ReplicaStatus Present when a read replica is configured. A lagging replica only moves reads to the primary, so it does not fail the status check.
+type ReplicaStatus struct {
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Error Why the last lag check failed
+	Error string `json:"error,omitempty,omitzero"`
+
+	// LagMs Replay lag at the last check
+	LagMs int64 `json:"lag_ms"`
+
+	// MaxLagMs Lag beyond which reads go to the primary
+	MaxLagMs int64 `json:"max_lag_ms"`
+
+	// ServingReads Whether reads currently go to the replica
+	ServingReads bool `json:"serving_reads"`
+}
+
+// StatusResponse defines model for StatusResponse.
+type StatusResponse struct {
+	// Commit VCS revision the binary was built from, or "unknown"
+	Commit        string         `json:"commit"`
+	Database      DatabaseStatus `json:"database"`
+	StartedAt     time.Time      `json:"started_at"`
+	Status        HealthStatus   `json:"status"`
+	UptimeSeconds int64          `json:"uptime_seconds"`
+
+	// Version Release version injected at build time, or "dev"
+	Version string `json:"version"`
+}
+
+// TransactionCount defines model for TransactionCount.
+type TransactionCount struct {
+	Count  int    `json:"count"`
+	Status string `json:"status"`
+	Type   string `json:"type"`
+}
+
+// TransactionListResponse defines model for TransactionListResponse.
+type TransactionListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+}
+
+// TransactionResponse defines model for TransactionResponse.
+type TransactionResponse struct {
+	AccountId openapi_types.UUID `json:"account_id"`
+	Amount    int64              `json:"amount"`
+	CreatedAt time.Time          `json:"created_at"`
+	Currency  string             `json:"currency"`
+
+	// DeclineReason Why a DECLINED authorization was refused: `insufficient_funds`, `cvv_mismatch`, `expired_card`
+	// or `velocity_exceeded`. Absent for every other status.
+	DeclineReason string                 `json:"decline_reason,omitempty,omitzero"`
+	ExpiresAt     time.Time              `json:"expires_at,omitempty,omitzero"`
+	Id            openapi_types.UUID     `json:"id"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty,omitzero"`
+
+	// ReferenceId Transaction this one follows up on, e.g. the authorization of a capture, or the
+	// TRANSFER_OUT of a TRANSFER_IN.
+	ReferenceId openapi_types.UUID        `json:"reference_id,omitempty,omitzero"`
+	Status      TransactionResponseStatus `json:"status"`
+	Type        TransactionResponseType   `json:"type"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// TransactionResponseStatus defines model for TransactionResponse.Status.
+type TransactionResponseStatus string
+
+// TransactionResponseType defines model for TransactionResponse.Type.
+type TransactionResponseType string
+
+// TransactionStatusResult defines model for TransactionStatusResult.
+type TransactionStatusResult struct {
+	// Found False when no live transaction has this ID; type and status are then omitted
+	Found     bool                          `json:"found"`
+	Id        openapi_types.UUID            `json:"id"`
+	Status    TransactionStatusResultStatus `json:"status,omitempty,omitzero"`
+	Type      TransactionStatusResultType   `json:"type,omitempty,omitzero"`
+	UpdatedAt time.Time                     `json:"updated_at,omitempty,omitzero"`
+}
+
+// TransactionStatusResultStatus defines model for TransactionStatusResult.Status.
+type TransactionStatusResultStatus string
+
+// TransactionStatusResultType defines model for TransactionStatusResult.Type.
+type TransactionStatusResultType string
+
 // VoidResponse defines model for VoidResponse.
 type VoidResponse struct {
-	AuthorizationId string             `json:"authorization_id"`
+	// Amount Amount in cents released by a partial void
+	Amount          int64  `json:"amount,omitempty,omitzero"`
+	AuthorizationId string `json:"authorization_id"`
+
+	// RemainingAmount Authorized amount in cents after a partial void
+	RemainingAmount int64              `json:"remaining_amount,omitempty,omitzero"`
 	Status          VoidResponseStatus `json:"status"`
 	VoidId          string             `json:"void_id"`
 	VoidedAt        time.Time          `json:"voided_at"`
@@ -159,6 +491,12 @@ type VoidResponse struct {
 // VoidResponseStatus defines model for VoidResponse.Status.
 type VoidResponseStatus string
 
+// AccountId defines model for AccountId.
+type AccountId = string
+
+// AccountNumber defines model for AccountNumber.
+type AccountNumber = string
+
 // AuthorizationId defines model for AuthorizationId.
 type AuthorizationId = string
 
@@ -171,42 +509,106 @@ type IdempotencyKeyRequired = string
 // RefundId defines model for RefundId.
 type RefundId = string
 
-// BadRequest defines model for BadRequest.
+// TransactionId defines model for TransactionId.
+type TransactionId = string
+
+// BadRequest Envelope returned by every endpoint and middleware on failure
 type BadRequest = ErrorResponse
 
-// InternalError defines model for InternalError.
+// InternalError Envelope returned by every endpoint and middleware on failure
 type InternalError = ErrorResponse
 
-// NotFound defines model for NotFound.
+// NotFound Envelope returned by every endpoint and middleware on failure
 type NotFound = ErrorResponse
 
-// PaymentRequired defines model for PaymentRequired.
+// PaymentRequired Envelope returned by every endpoint and middleware on failure
 type PaymentRequired = ErrorResponse
 
+// ServiceUnavailable Envelope returned by every endpoint and middleware on failure
+type ServiceUnavailable = ErrorResponse
+
+// UnprocessableEntity Envelope returned by every endpoint and middleware on failure
+type UnprocessableEntity = ErrorResponse
+
+// ListLowBalanceAccountsParams defines parameters for ListLowBalanceAccounts.
+type ListLowBalanceAccountsParams struct {
+	// ThresholdCents Accounts with an available balance below this many minor units are listed
+	ThresholdCents int64 `form:"threshold_cents" json:"threshold_cents"`
+
+	// Limit Most accounts returned
+	Limit int `form:"limit,omitempty" json:"limit,omitempty,omitzero"`
+
+	// Offset Accounts skipped before the first one returned
+	Offset int `form:"offset,omitempty" json:"offset,omitempty,omitzero"`
+}
+
+// GetAccountParams defines parameters for GetAccount.
+type GetAccountParams struct {
+	// IfNoneMatch ETags from earlier responses; `*` matches any
+	IfNoneMatch string `json:"If-None-Match,omitempty,omitzero"`
+}
+
 // CreateAuthorizationParams defines parameters for CreateAuthorization.
 type CreateAuthorizationParams struct {
-	// IdempotencyKey Unique key for idempotent requests (max 255 chars)
+	// Simulate Validate the authorization without holding funds
+	Simulate bool `form:"simulate,omitempty" json:"simulate,omitempty,omitzero"`
+
+	// IdempotencyKey Unique key for idempotent requests: 1 to 255 characters from letters, digits and `-_.:~+/=`,
+	// which covers UUIDs, ULIDs and base64 tokens. Other keys are rejected with `invalid_idempotency_key`.
 	IdempotencyKey IdempotencyKeyRequired `json:"Idempotency-Key"`
 }
 
 // CreateCaptureParams defines parameters for CreateCapture.
 type CreateCaptureParams struct {
-	// IdempotencyKey Unique key for idempotent requests (max 255 chars)
+	// IdempotencyKey Unique key for idempotent requests: 1 to 255 characters from letters, digits and `-_.:~+/=`,
+	// which covers UUIDs, ULIDs and base64 tokens. Other keys are rejected with `invalid_idempotency_key`.
 	IdempotencyKey IdempotencyKeyRequired `json:"Idempotency-Key"`
 }
 
 // CreateRefundParams defines parameters for CreateRefund.
 type CreateRefundParams struct {
-	// IdempotencyKey Unique key for idempotent requests (max 255 chars)
+	// IdempotencyKey Unique key for idempotent requests: 1 to 255 characters from letters, digits and `-_.:~+/=`,
+	// which covers UUIDs, ULIDs and base64 tokens. Other keys are rejected with `invalid_idempotency_key`.
 	IdempotencyKey IdempotencyKeyRequired `json:"Idempotency-Key"`
 }
 
+// SearchTransactionsParams defines parameters for SearchTransactions.
+type SearchTransactionsParams struct {
+	MetadataKey   string `form:"metadata_key" json:"metadata_key"`
+	MetadataValue string `form:"metadata_value" json:"metadata_value"`
+
+	// Limit Most transactions returned
+	Limit int `form:"limit,omitempty" json:"limit,omitempty,omitzero"`
+}
+
+// ExportTransactionsParams defines parameters for ExportTransactions.
+type ExportTransactionsParams struct {
+	// From Start of the export window (inclusive)
+	From time.Time `form:"from" json:"from"`
+
+	// To End of the export window (exclusive)
+	To time.Time `form:"to" json:"to"`
+}
+
+// GetTransactionParams defines parameters for GetTransaction.
+type GetTransactionParams struct {
+	// IfNoneMatch ETags from earlier responses; `*` matches any
+	IfNoneMatch string `json:"If-None-Match,omitempty,omitzero"`
+
+	// IfModifiedSince HTTP date, usually the `Last-Modified` of an earlier response
+	IfModifiedSince string `json:"If-Modified-Since,omitempty,omitzero"`
+}
+
 // CreateVoidParams defines parameters for CreateVoid.
 type CreateVoidParams struct {
-	// IdempotencyKey Unique key for idempotent requests (max 255 chars)
+	// IdempotencyKey Unique key for idempotent requests: 1 to 255 characters from letters, digits and `-_.:~+/=`,
+	// which covers UUIDs, ULIDs and base64 tokens. Other keys are rejected with `invalid_idempotency_key`.
 	IdempotencyKey IdempotencyKeyRequired `json:"Idempotency-Key"`
 }
 
+// SetMaintenanceJSONRequestBody defines body for SetMaintenance for application/json ContentType.
+type SetMaintenanceJSONRequestBody = MaintenanceStatus
+
 // CreateAuthorizationJSONRequestBody defines body for CreateAuthorization for application/json ContentType.
 type CreateAuthorizationJSONRequestBody = CreateAuthorizationRequest
 
@@ -216,5 +618,8 @@ type CreateCaptureJSONRequestBody = CreateCaptureRequest
 // CreateRefundJSONRequestBody defines body for CreateRefund for application/json ContentType.
 type CreateRefundJSONRequestBody = CreateRefundRequest
 
+// GetTransactionStatusesJSONRequestBody defines body for GetTransactionStatuses for application/json ContentType.
+type GetTransactionStatusesJSONRequestBody = BatchStatusRequest
+
 // CreateVoidJSONRequestBody defines body for CreateVoid for application/json ContentType.
 type CreateVoidJSONRequestBody = CreateVoidRequest
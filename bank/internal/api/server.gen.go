@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/oapi-codegen/runtime"
@@ -17,6 +18,30 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List accounts with a low available balance
+	// (GET /admin/accounts/low-balance)
+	ListLowBalanceAccounts(w http.ResponseWriter, r *http.Request, params ListLowBalanceAccountsParams)
+	// Reconcile one account
+	// (POST /admin/accounts/{accountId}/reconciliation)
+	ReconcileAccount(w http.ResponseWriter, r *http.Request, accountId AccountId)
+	// Maintenance mode status
+	// (GET /admin/maintenance)
+	GetMaintenance(w http.ResponseWriter, r *http.Request)
+	// Turn maintenance mode on or off
+	// (PUT /admin/maintenance)
+	SetMaintenance(w http.ResponseWriter, r *http.Request)
+	// Reconcile every balance
+	// (POST /admin/reconciliation)
+	ReconcileBalances(w http.ResponseWriter, r *http.Request)
+	// Ledger statistics
+	// (GET /admin/stats)
+	GetAdminStats(w http.ResponseWriter, r *http.Request)
+	// Get account
+	// (GET /api/v1/accounts/{accountId})
+	GetAccount(w http.ResponseWriter, r *http.Request, accountId AccountId, params GetAccountParams)
+	// Get account balance
+	// (GET /api/v1/accounts/{accountNumber}/balance)
+	GetAccountBalance(w http.ResponseWriter, r *http.Request, accountNumber AccountNumber)
 	// Create authorization hold
 	// (POST /api/v1/authorizations)
 	CreateAuthorization(w http.ResponseWriter, r *http.Request, params CreateAuthorizationParams)
@@ -35,12 +60,30 @@ type ServerInterface interface {
 	// Get refund details
 	// (GET /api/v1/refunds/{refundId})
 	GetRefund(w http.ResponseWriter, r *http.Request, refundId RefundId)
+	// Search transactions by metadata
+	// (GET /api/v1/transactions)
+	SearchTransactions(w http.ResponseWriter, r *http.Request, params SearchTransactionsParams)
+	// Get the status of several transactions
+	// (POST /api/v1/transactions/batch-status)
+	GetTransactionStatuses(w http.ResponseWriter, r *http.Request)
+	// Export transactions as CSV
+	// (GET /api/v1/transactions/export)
+	ExportTransactions(w http.ResponseWriter, r *http.Request, params ExportTransactionsParams)
+	// Get transaction
+	// (GET /api/v1/transactions/{transactionId})
+	GetTransaction(w http.ResponseWriter, r *http.Request, transactionId TransactionId, params GetTransactionParams)
 	// Void authorization
 	// (POST /api/v1/voids)
 	CreateVoid(w http.ResponseWriter, r *http.Request, params CreateVoidParams)
-	// Health check
+	// Liveness check
 	// (GET /health)
 	GetHealth(w http.ResponseWriter, r *http.Request)
+	// Readiness check
+	// (GET /ready)
+	GetReady(w http.ResponseWriter, r *http.Request)
+	// Diagnostic status
+	// (GET /status)
+	GetStatus(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -52,6 +95,211 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
+// ListLowBalanceAccounts operation middleware
+func (siw *ServerInterfaceWrapper) ListLowBalanceAccounts(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListLowBalanceAccountsParams
+
+	// ------------- Required query parameter "threshold_cents" -------------
+
+	if paramValue := r.URL.Query().Get("threshold_cents"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "threshold_cents"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "threshold_cents", r.URL.Query(), &params.ThresholdCents)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "threshold_cents", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListLowBalanceAccounts(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReconcileAccount operation middleware
+func (siw *ServerInterfaceWrapper) ReconcileAccount(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "accountId" -------------
+	var accountId AccountId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", r.PathValue("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "accountId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReconcileAccount(w, r, accountId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMaintenance(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetMaintenance(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReconcileBalances operation middleware
+func (siw *ServerInterfaceWrapper) ReconcileBalances(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReconcileBalances(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminStats operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminStats(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminStats(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAccount operation middleware
+func (siw *ServerInterfaceWrapper) GetAccount(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "accountId" -------------
+	var accountId AccountId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", r.PathValue("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "accountId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAccountParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAccount(w, r, accountId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAccountBalance operation middleware
+func (siw *ServerInterfaceWrapper) GetAccountBalance(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "accountNumber" -------------
+	var accountNumber AccountNumber
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountNumber", r.PathValue("accountNumber"), &accountNumber, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "accountNumber", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAccountBalance(w, r, accountNumber)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // CreateAuthorization operation middleware
 func (siw *ServerInterfaceWrapper) CreateAuthorization(w http.ResponseWriter, r *http.Request) {
 
@@ -60,6 +308,14 @@ func (siw *ServerInterfaceWrapper) CreateAuthorization(w http.ResponseWriter, r
 	// Parameter object where we will unmarshal all parameters from the context
 	var params CreateAuthorizationParams
 
+	// ------------- Optional query parameter "simulate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "simulate", r.URL.Query(), &params.Simulate)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "simulate", Err: err})
+		return
+	}
+
 	headers := r.Header
 
 	// ------------- Required header parameter "Idempotency-Key" -------------
@@ -259,41 +515,54 @@ func (siw *ServerInterfaceWrapper) GetRefund(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
-// CreateVoid operation middleware
-func (siw *ServerInterfaceWrapper) CreateVoid(w http.ResponseWriter, r *http.Request) {
+// SearchTransactions operation middleware
+func (siw *ServerInterfaceWrapper) SearchTransactions(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params CreateVoidParams
+	var params SearchTransactionsParams
 
-	headers := r.Header
+	// ------------- Required query parameter "metadata_key" -------------
 
-	// ------------- Required header parameter "Idempotency-Key" -------------
-	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
-		var IdempotencyKey IdempotencyKeyRequired
-		n := len(valueList)
-		if n != 1 {
-			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
-			return
-		}
+	if paramValue := r.URL.Query().Get("metadata_key"); paramValue != "" {
 
-		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
-		if err != nil {
-			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
-			return
-		}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "metadata_key"})
+		return
+	}
 
-		params.IdempotencyKey = IdempotencyKey
+	err = runtime.BindQueryParameter("form", true, true, "metadata_key", r.URL.Query(), &params.MetadataKey)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "metadata_key", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "metadata_value" -------------
+
+	if paramValue := r.URL.Query().Get("metadata_value"); paramValue != "" {
 
 	} else {
-		err := fmt.Errorf("Header parameter Idempotency-Key is required, but not found")
-		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Idempotency-Key", Err: err})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "metadata_value"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "metadata_value", r.URL.Query(), &params.MetadataValue)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "metadata_value", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateVoid(w, r, params)
+		siw.Handler.SearchTransactions(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -303,11 +572,11 @@ func (siw *ServerInterfaceWrapper) CreateVoid(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// GetHealth operation middleware
-func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+// GetTransactionStatuses operation middleware
+func (siw *ServerInterfaceWrapper) GetTransactionStatuses(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetHealth(w, r)
+		siw.Handler.GetTransactionStatuses(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -317,56 +586,259 @@ func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// ExportTransactions operation middleware
+func (siw *ServerInterfaceWrapper) ExportTransactions(w http.ResponseWriter, r *http.Request) {
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	var err error
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportTransactionsParams
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	// ------------- Required query parameter "from" -------------
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	if paramValue := r.URL.Query().Get("from"); paramValue != "" {
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "from"})
+		return
+	}
 
-type RequiredParamError struct {
-	ParamName string
-}
+	err = runtime.BindQueryParameter("form", true, true, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+	// ------------- Required query parameter "to" -------------
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+	if paramValue := r.URL.Query().Get("to"); paramValue != "" {
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "to"})
+		return
+	}
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	err = runtime.BindQueryParameter("form", true, true, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportTransactions(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTransaction operation middleware
+func (siw *ServerInterfaceWrapper) GetTransaction(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "transactionId" -------------
+	var transactionId TransactionId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "transactionId", r.PathValue("transactionId"), &transactionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "transactionId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTransactionParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = IfNoneMatch
+
+	}
+
+	// ------------- Optional header parameter "If-Modified-Since" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Modified-Since")]; found {
+		var IfModifiedSince string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Modified-Since", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Modified-Since", valueList[0], &IfModifiedSince, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Modified-Since", Err: err})
+			return
+		}
+
+		params.IfModifiedSince = IfModifiedSince
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTransaction(w, r, transactionId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateVoid operation middleware
+func (siw *ServerInterfaceWrapper) CreateVoid(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateVoidParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKeyRequired
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = IdempotencyKey
+
+	} else {
+		err := fmt.Errorf("Header parameter Idempotency-Key is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Idempotency-Key", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateVoid(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealth(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetReady operation middleware
+func (siw *ServerInterfaceWrapper) GetReady(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetReady(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetStatus(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatus(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
 }
 
 func (e *InvalidParamFormatError) Error() string {
@@ -437,14 +909,28 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	m.HandleFunc("GET "+options.BaseURL+"/admin/accounts/low-balance", wrapper.ListLowBalanceAccounts)
+	m.HandleFunc("POST "+options.BaseURL+"/admin/accounts/{accountId}/reconciliation", wrapper.ReconcileAccount)
+	m.HandleFunc("GET "+options.BaseURL+"/admin/maintenance", wrapper.GetMaintenance)
+	m.HandleFunc("PUT "+options.BaseURL+"/admin/maintenance", wrapper.SetMaintenance)
+	m.HandleFunc("POST "+options.BaseURL+"/admin/reconciliation", wrapper.ReconcileBalances)
+	m.HandleFunc("GET "+options.BaseURL+"/admin/stats", wrapper.GetAdminStats)
+	m.HandleFunc("GET "+options.BaseURL+"/api/v1/accounts/{accountId}", wrapper.GetAccount)
+	m.HandleFunc("GET "+options.BaseURL+"/api/v1/accounts/{accountNumber}/balance", wrapper.GetAccountBalance)
 	m.HandleFunc("POST "+options.BaseURL+"/api/v1/authorizations", wrapper.CreateAuthorization)
 	m.HandleFunc("GET "+options.BaseURL+"/api/v1/authorizations/{authorizationId}", wrapper.GetAuthorization)
 	m.HandleFunc("POST "+options.BaseURL+"/api/v1/captures", wrapper.CreateCapture)
 	m.HandleFunc("GET "+options.BaseURL+"/api/v1/captures/{captureId}", wrapper.GetCapture)
 	m.HandleFunc("POST "+options.BaseURL+"/api/v1/refunds", wrapper.CreateRefund)
 	m.HandleFunc("GET "+options.BaseURL+"/api/v1/refunds/{refundId}", wrapper.GetRefund)
+	m.HandleFunc("GET "+options.BaseURL+"/api/v1/transactions", wrapper.SearchTransactions)
+	m.HandleFunc("POST "+options.BaseURL+"/api/v1/transactions/batch-status", wrapper.GetTransactionStatuses)
+	m.HandleFunc("GET "+options.BaseURL+"/api/v1/transactions/export", wrapper.ExportTransactions)
+	m.HandleFunc("GET "+options.BaseURL+"/api/v1/transactions/{transactionId}", wrapper.GetTransaction)
 	m.HandleFunc("POST "+options.BaseURL+"/api/v1/voids", wrapper.CreateVoid)
 	m.HandleFunc("GET "+options.BaseURL+"/health", wrapper.GetHealth)
+	m.HandleFunc("GET "+options.BaseURL+"/ready", wrapper.GetReady)
+	m.HandleFunc("GET "+options.BaseURL+"/status", wrapper.GetStatus)
 
 	return m
 }
@@ -457,201 +943,785 @@ type NotFoundJSONResponse ErrorResponse
 
 type PaymentRequiredJSONResponse ErrorResponse
 
-type CreateAuthorizationRequestObject struct {
-	Params CreateAuthorizationParams
-	Body   *CreateAuthorizationJSONRequestBody
+type ServiceUnavailableResponseHeaders struct {
+	RetryAfter int
 }
+type ServiceUnavailableJSONResponse struct {
+	Body ErrorResponse
 
-type CreateAuthorizationResponseObject interface {
-	VisitCreateAuthorizationResponse(w http.ResponseWriter) error
+	Headers ServiceUnavailableResponseHeaders
 }
 
-type CreateAuthorization200JSONResponse AuthorizationResponse
+type UnprocessableEntityJSONResponse ErrorResponse
 
-func (response CreateAuthorization200JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+type ListLowBalanceAccountsRequestObject struct {
+	Params ListLowBalanceAccountsParams
+}
+
+type ListLowBalanceAccountsResponseObject interface {
+	VisitListLowBalanceAccountsResponse(w http.ResponseWriter) error
+}
+
+type ListLowBalanceAccounts200JSONResponse AccountListResponse
+
+func (response ListLowBalanceAccounts200JSONResponse) VisitListLowBalanceAccountsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateAuthorization400JSONResponse struct{ BadRequestJSONResponse }
+type ListLowBalanceAccounts400JSONResponse struct{ BadRequestJSONResponse }
 
-func (response CreateAuthorization400JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+func (response ListLowBalanceAccounts400JSONResponse) VisitListLowBalanceAccountsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateAuthorization402JSONResponse struct{ PaymentRequiredJSONResponse }
+type ListLowBalanceAccounts401JSONResponse ErrorResponse
 
-func (response CreateAuthorization402JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+func (response ListLowBalanceAccounts401JSONResponse) VisitListLowBalanceAccountsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(402)
+	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateAuthorization500JSONResponse struct{ InternalErrorJSONResponse }
+type ListLowBalanceAccounts500JSONResponse struct{ InternalErrorJSONResponse }
 
-func (response CreateAuthorization500JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+func (response ListLowBalanceAccounts500JSONResponse) VisitListLowBalanceAccountsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(500)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetAuthorizationRequestObject struct {
-	AuthorizationId AuthorizationId `json:"authorizationId"`
+type ReconcileAccountRequestObject struct {
+	AccountId AccountId `json:"accountId"`
 }
 
-type GetAuthorizationResponseObject interface {
-	VisitGetAuthorizationResponse(w http.ResponseWriter) error
+type ReconcileAccountResponseObject interface {
+	VisitReconcileAccountResponse(w http.ResponseWriter) error
 }
 
-type GetAuthorization200JSONResponse AuthorizationResponse
+type ReconcileAccount200JSONResponse AccountReconciliation
 
-func (response GetAuthorization200JSONResponse) VisitGetAuthorizationResponse(w http.ResponseWriter) error {
+func (response ReconcileAccount200JSONResponse) VisitReconcileAccountResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetAuthorization404JSONResponse struct{ NotFoundJSONResponse }
+type ReconcileAccount400JSONResponse struct{ BadRequestJSONResponse }
 
-func (response GetAuthorization404JSONResponse) VisitGetAuthorizationResponse(w http.ResponseWriter) error {
+func (response ReconcileAccount400JSONResponse) VisitReconcileAccountResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateCaptureRequestObject struct {
-	Params CreateCaptureParams
-	Body   *CreateCaptureJSONRequestBody
-}
-
-type CreateCaptureResponseObject interface {
-	VisitCreateCaptureResponse(w http.ResponseWriter) error
-}
-
-type CreateCapture200JSONResponse CaptureResponse
+type ReconcileAccount401JSONResponse ErrorResponse
 
-func (response CreateCapture200JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+func (response ReconcileAccount401JSONResponse) VisitReconcileAccountResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateCapture400JSONResponse struct{ BadRequestJSONResponse }
+type ReconcileAccount404JSONResponse struct{ NotFoundJSONResponse }
 
-func (response CreateCapture400JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+func (response ReconcileAccount404JSONResponse) VisitReconcileAccountResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateCapture500JSONResponse struct{ InternalErrorJSONResponse }
+type ReconcileAccount500JSONResponse struct{ InternalErrorJSONResponse }
 
-func (response CreateCapture500JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+func (response ReconcileAccount500JSONResponse) VisitReconcileAccountResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(500)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetCaptureRequestObject struct {
-	CaptureId CaptureId `json:"captureId"`
+type GetMaintenanceRequestObject struct {
 }
 
-type GetCaptureResponseObject interface {
-	VisitGetCaptureResponse(w http.ResponseWriter) error
+type GetMaintenanceResponseObject interface {
+	VisitGetMaintenanceResponse(w http.ResponseWriter) error
 }
 
-type GetCapture200JSONResponse CaptureResponse
+type GetMaintenance200JSONResponse MaintenanceStatus
 
-func (response GetCapture200JSONResponse) VisitGetCaptureResponse(w http.ResponseWriter) error {
+func (response GetMaintenance200JSONResponse) VisitGetMaintenanceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetCapture404JSONResponse struct{ NotFoundJSONResponse }
+type GetMaintenance401JSONResponse ErrorResponse
 
-func (response GetCapture404JSONResponse) VisitGetCaptureResponse(w http.ResponseWriter) error {
+func (response GetMaintenance401JSONResponse) VisitGetMaintenanceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateRefundRequestObject struct {
-	Params CreateRefundParams
-	Body   *CreateRefundJSONRequestBody
+type SetMaintenanceRequestObject struct {
+	Body *SetMaintenanceJSONRequestBody
 }
 
-type CreateRefundResponseObject interface {
-	VisitCreateRefundResponse(w http.ResponseWriter) error
+type SetMaintenanceResponseObject interface {
+	VisitSetMaintenanceResponse(w http.ResponseWriter) error
 }
 
-type CreateRefund200JSONResponse RefundResponse
+type SetMaintenance200JSONResponse MaintenanceStatus
 
-func (response CreateRefund200JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+func (response SetMaintenance200JSONResponse) VisitSetMaintenanceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateRefund400JSONResponse struct{ BadRequestJSONResponse }
+type SetMaintenance401JSONResponse ErrorResponse
 
-func (response CreateRefund400JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+func (response SetMaintenance401JSONResponse) VisitSetMaintenanceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateRefund500JSONResponse struct{ InternalErrorJSONResponse }
+type ReconcileBalancesRequestObject struct {
+}
 
-func (response CreateRefund500JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+type ReconcileBalancesResponseObject interface {
+	VisitReconcileBalancesResponse(w http.ResponseWriter) error
+}
+
+type ReconcileBalances200JSONResponse ReconciliationReport
+
+func (response ReconcileBalances200JSONResponse) VisitReconcileBalancesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetRefundRequestObject struct {
-	RefundId RefundId `json:"refundId"`
-}
+type ReconcileBalances401JSONResponse ErrorResponse
 
-type GetRefundResponseObject interface {
+func (response ReconcileBalances401JSONResponse) VisitReconcileBalancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReconcileBalances500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response ReconcileBalances500JSONResponse) VisitReconcileBalancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminStatsRequestObject struct {
+}
+
+type GetAdminStatsResponseObject interface {
+	VisitGetAdminStatsResponse(w http.ResponseWriter) error
+}
+
+type GetAdminStats200JSONResponse AdminStatsResponse
+
+func (response GetAdminStats200JSONResponse) VisitGetAdminStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminStats401JSONResponse ErrorResponse
+
+func (response GetAdminStats401JSONResponse) VisitGetAdminStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminStats500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response GetAdminStats500JSONResponse) VisitGetAdminStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAccountRequestObject struct {
+	AccountId AccountId `json:"accountId"`
+	Params    GetAccountParams
+}
+
+type GetAccountResponseObject interface {
+	VisitGetAccountResponse(w http.ResponseWriter) error
+}
+
+type GetAccount200ResponseHeaders struct {
+	ETag string
+}
+
+type GetAccount200JSONResponse struct {
+	Body    AccountResponse
+	Headers GetAccount200ResponseHeaders
+}
+
+func (response GetAccount200JSONResponse) VisitGetAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetAccount304ResponseHeaders struct {
+	ETag string
+}
+
+type GetAccount304Response struct {
+	Headers GetAccount304ResponseHeaders
+}
+
+func (response GetAccount304Response) VisitGetAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetAccount400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response GetAccount400JSONResponse) VisitGetAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAccount404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetAccount404JSONResponse) VisitGetAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAccount500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response GetAccount500JSONResponse) VisitGetAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAccountBalanceRequestObject struct {
+	AccountNumber AccountNumber `json:"accountNumber"`
+}
+
+type GetAccountBalanceResponseObject interface {
+	VisitGetAccountBalanceResponse(w http.ResponseWriter) error
+}
+
+type GetAccountBalance200JSONResponse BalanceResponse
+
+func (response GetAccountBalance200JSONResponse) VisitGetAccountBalanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAccountBalance404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetAccountBalance404JSONResponse) VisitGetAccountBalanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAccountBalance500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response GetAccountBalance500JSONResponse) VisitGetAccountBalanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAuthorizationRequestObject struct {
+	Params CreateAuthorizationParams
+	Body   *CreateAuthorizationJSONRequestBody
+}
+
+type CreateAuthorizationResponseObject interface {
+	VisitCreateAuthorizationResponse(w http.ResponseWriter) error
+}
+
+type CreateAuthorization200JSONResponse AuthorizationResponse
+
+func (response CreateAuthorization200JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAuthorization400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response CreateAuthorization400JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAuthorization402JSONResponse struct{ PaymentRequiredJSONResponse }
+
+func (response CreateAuthorization402JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(402)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAuthorization422JSONResponse struct {
+	UnprocessableEntityJSONResponse
+}
+
+func (response CreateAuthorization422JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAuthorization500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response CreateAuthorization500JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAuthorization503JSONResponse struct{ ServiceUnavailableJSONResponse }
+
+func (response CreateAuthorization503JSONResponse) VisitCreateAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetAuthorizationRequestObject struct {
+	AuthorizationId AuthorizationId `json:"authorizationId"`
+}
+
+type GetAuthorizationResponseObject interface {
+	VisitGetAuthorizationResponse(w http.ResponseWriter) error
+}
+
+type GetAuthorization200JSONResponse AuthorizationResponse
+
+func (response GetAuthorization200JSONResponse) VisitGetAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAuthorization404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetAuthorization404JSONResponse) VisitGetAuthorizationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCaptureRequestObject struct {
+	Params CreateCaptureParams
+	Body   *CreateCaptureJSONRequestBody
+}
+
+type CreateCaptureResponseObject interface {
+	VisitCreateCaptureResponse(w http.ResponseWriter) error
+}
+
+type CreateCapture200JSONResponse CaptureResponse
+
+func (response CreateCapture200JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCapture400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response CreateCapture400JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCapture422JSONResponse struct {
+	UnprocessableEntityJSONResponse
+}
+
+func (response CreateCapture422JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCapture500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response CreateCapture500JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCapture503JSONResponse struct{ ServiceUnavailableJSONResponse }
+
+func (response CreateCapture503JSONResponse) VisitCreateCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetCaptureRequestObject struct {
+	CaptureId CaptureId `json:"captureId"`
+}
+
+type GetCaptureResponseObject interface {
+	VisitGetCaptureResponse(w http.ResponseWriter) error
+}
+
+type GetCapture200JSONResponse CaptureResponse
+
+func (response GetCapture200JSONResponse) VisitGetCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCapture404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetCapture404JSONResponse) VisitGetCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateRefundRequestObject struct {
+	Params CreateRefundParams
+	Body   *CreateRefundJSONRequestBody
+}
+
+type CreateRefundResponseObject interface {
+	VisitCreateRefundResponse(w http.ResponseWriter) error
+}
+
+type CreateRefund200JSONResponse RefundResponse
+
+func (response CreateRefund200JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateRefund400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response CreateRefund400JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateRefund422JSONResponse struct {
+	UnprocessableEntityJSONResponse
+}
+
+func (response CreateRefund422JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateRefund500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response CreateRefund500JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateRefund503JSONResponse struct{ ServiceUnavailableJSONResponse }
+
+func (response CreateRefund503JSONResponse) VisitCreateRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRefundRequestObject struct {
+	RefundId RefundId `json:"refundId"`
+}
+
+type GetRefundResponseObject interface {
 	VisitGetRefundResponse(w http.ResponseWriter) error
 }
 
-type GetRefund200JSONResponse RefundResponse
+type GetRefund200JSONResponse RefundResponse
+
+func (response GetRefund200JSONResponse) VisitGetRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRefund404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetRefund404JSONResponse) VisitGetRefundResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchTransactionsRequestObject struct {
+	Params SearchTransactionsParams
+}
+
+type SearchTransactionsResponseObject interface {
+	VisitSearchTransactionsResponse(w http.ResponseWriter) error
+}
+
+type SearchTransactions200JSONResponse TransactionListResponse
+
+func (response SearchTransactions200JSONResponse) VisitSearchTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchTransactions400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response SearchTransactions400JSONResponse) VisitSearchTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchTransactions500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response SearchTransactions500JSONResponse) VisitSearchTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTransactionStatusesRequestObject struct {
+	Body *GetTransactionStatusesJSONRequestBody
+}
+
+type GetTransactionStatusesResponseObject interface {
+	VisitGetTransactionStatusesResponse(w http.ResponseWriter) error
+}
+
+type GetTransactionStatuses200JSONResponse BatchStatusResponse
+
+func (response GetTransactionStatuses200JSONResponse) VisitGetTransactionStatusesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTransactionStatuses400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response GetTransactionStatuses400JSONResponse) VisitGetTransactionStatusesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTransactionStatuses500JSONResponse struct{ InternalErrorJSONResponse }
 
-func (response GetRefund200JSONResponse) VisitGetRefundResponse(w http.ResponseWriter) error {
+func (response GetTransactionStatuses500JSONResponse) VisitGetTransactionStatusesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportTransactionsRequestObject struct {
+	Params ExportTransactionsParams
+}
+
+type ExportTransactionsResponseObject interface {
+	VisitExportTransactionsResponse(w http.ResponseWriter) error
+}
+
+type ExportTransactions200ResponseHeaders struct {
+	ContentDisposition string
+}
+
+type ExportTransactions200TextcsvResponse struct {
+	Body          io.Reader
+	Headers       ExportTransactions200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportTransactions200TextcsvResponse) VisitExportTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
 	w.WriteHeader(200)
 
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportTransactions400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response ExportTransactions400JSONResponse) VisitExportTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetRefund404JSONResponse struct{ NotFoundJSONResponse }
+type ExportTransactions500JSONResponse struct{ InternalErrorJSONResponse }
 
-func (response GetRefund404JSONResponse) VisitGetRefundResponse(w http.ResponseWriter) error {
+func (response ExportTransactions500JSONResponse) VisitExportTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTransactionRequestObject struct {
+	TransactionId TransactionId `json:"transactionId"`
+	Params        GetTransactionParams
+}
+
+type GetTransactionResponseObject interface {
+	VisitGetTransactionResponse(w http.ResponseWriter) error
+}
+
+type GetTransaction200ResponseHeaders struct {
+	ETag         string
+	LastModified string
+}
+
+type GetTransaction200JSONResponse struct {
+	Body    TransactionResponse
+	Headers GetTransaction200ResponseHeaders
+}
+
+func (response GetTransaction200JSONResponse) VisitGetTransactionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Last-Modified", fmt.Sprint(response.Headers.LastModified))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetTransaction304ResponseHeaders struct {
+	ETag         string
+	LastModified string
+}
+
+type GetTransaction304Response struct {
+	Headers GetTransaction304ResponseHeaders
+}
+
+func (response GetTransaction304Response) VisitGetTransactionResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Last-Modified", fmt.Sprint(response.Headers.LastModified))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetTransaction400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response GetTransaction400JSONResponse) VisitGetTransactionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTransaction404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetTransaction404JSONResponse) VisitGetTransactionResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetTransaction500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response GetTransaction500JSONResponse) VisitGetTransactionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type CreateVoidRequestObject struct {
 	Params CreateVoidParams
 	Body   *CreateVoidJSONRequestBody
@@ -670,104 +1740,405 @@ func (response CreateVoid200JSONResponse) VisitCreateVoidResponse(w http.Respons
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateVoid400JSONResponse struct{ BadRequestJSONResponse }
+type CreateVoid400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response CreateVoid400JSONResponse) VisitCreateVoidResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateVoid500JSONResponse struct{ InternalErrorJSONResponse }
+
+func (response CreateVoid500JSONResponse) VisitCreateVoidResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateVoid503JSONResponse struct{ ServiceUnavailableJSONResponse }
+
+func (response CreateVoid503JSONResponse) VisitCreateVoidResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHealthRequestObject struct {
+}
+
+type GetHealthResponseObject interface {
+	VisitGetHealthResponse(w http.ResponseWriter) error
+}
+
+type GetHealth200JSONResponse HealthResponse
+
+func (response GetHealth200JSONResponse) VisitGetHealthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetReadyRequestObject struct {
+}
+
+type GetReadyResponseObject interface {
+	VisitGetReadyResponse(w http.ResponseWriter) error
+}
+
+type GetReady200JSONResponse HealthResponse
+
+func (response GetReady200JSONResponse) VisitGetReadyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetReady503JSONResponse HealthResponse
+
+func (response GetReady503JSONResponse) VisitGetReadyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetStatusRequestObject struct {
+}
+
+type GetStatusResponseObject interface {
+	VisitGetStatusResponse(w http.ResponseWriter) error
+}
+
+type GetStatus200JSONResponse StatusResponse
+
+func (response GetStatus200JSONResponse) VisitGetStatusResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetStatus503JSONResponse StatusResponse
+
+func (response GetStatus503JSONResponse) VisitGetStatusResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// List accounts with a low available balance
+	// (GET /admin/accounts/low-balance)
+	ListLowBalanceAccounts(ctx context.Context, request ListLowBalanceAccountsRequestObject) (ListLowBalanceAccountsResponseObject, error)
+	// Reconcile one account
+	// (POST /admin/accounts/{accountId}/reconciliation)
+	ReconcileAccount(ctx context.Context, request ReconcileAccountRequestObject) (ReconcileAccountResponseObject, error)
+	// Maintenance mode status
+	// (GET /admin/maintenance)
+	GetMaintenance(ctx context.Context, request GetMaintenanceRequestObject) (GetMaintenanceResponseObject, error)
+	// Turn maintenance mode on or off
+	// (PUT /admin/maintenance)
+	SetMaintenance(ctx context.Context, request SetMaintenanceRequestObject) (SetMaintenanceResponseObject, error)
+	// Reconcile every balance
+	// (POST /admin/reconciliation)
+	ReconcileBalances(ctx context.Context, request ReconcileBalancesRequestObject) (ReconcileBalancesResponseObject, error)
+	// Ledger statistics
+	// (GET /admin/stats)
+	GetAdminStats(ctx context.Context, request GetAdminStatsRequestObject) (GetAdminStatsResponseObject, error)
+	// Get account
+	// (GET /api/v1/accounts/{accountId})
+	GetAccount(ctx context.Context, request GetAccountRequestObject) (GetAccountResponseObject, error)
+	// Get account balance
+	// (GET /api/v1/accounts/{accountNumber}/balance)
+	GetAccountBalance(ctx context.Context, request GetAccountBalanceRequestObject) (GetAccountBalanceResponseObject, error)
+	// Create authorization hold
+	// (POST /api/v1/authorizations)
+	CreateAuthorization(ctx context.Context, request CreateAuthorizationRequestObject) (CreateAuthorizationResponseObject, error)
+	// Get authorization details
+	// (GET /api/v1/authorizations/{authorizationId})
+	GetAuthorization(ctx context.Context, request GetAuthorizationRequestObject) (GetAuthorizationResponseObject, error)
+	// Capture authorization
+	// (POST /api/v1/captures)
+	CreateCapture(ctx context.Context, request CreateCaptureRequestObject) (CreateCaptureResponseObject, error)
+	// Get capture details
+	// (GET /api/v1/captures/{captureId})
+	GetCapture(ctx context.Context, request GetCaptureRequestObject) (GetCaptureResponseObject, error)
+	// Refund capture
+	// (POST /api/v1/refunds)
+	CreateRefund(ctx context.Context, request CreateRefundRequestObject) (CreateRefundResponseObject, error)
+	// Get refund details
+	// (GET /api/v1/refunds/{refundId})
+	GetRefund(ctx context.Context, request GetRefundRequestObject) (GetRefundResponseObject, error)
+	// Search transactions by metadata
+	// (GET /api/v1/transactions)
+	SearchTransactions(ctx context.Context, request SearchTransactionsRequestObject) (SearchTransactionsResponseObject, error)
+	// Get the status of several transactions
+	// (POST /api/v1/transactions/batch-status)
+	GetTransactionStatuses(ctx context.Context, request GetTransactionStatusesRequestObject) (GetTransactionStatusesResponseObject, error)
+	// Export transactions as CSV
+	// (GET /api/v1/transactions/export)
+	ExportTransactions(ctx context.Context, request ExportTransactionsRequestObject) (ExportTransactionsResponseObject, error)
+	// Get transaction
+	// (GET /api/v1/transactions/{transactionId})
+	GetTransaction(ctx context.Context, request GetTransactionRequestObject) (GetTransactionResponseObject, error)
+	// Void authorization
+	// (POST /api/v1/voids)
+	CreateVoid(ctx context.Context, request CreateVoidRequestObject) (CreateVoidResponseObject, error)
+	// Liveness check
+	// (GET /health)
+	GetHealth(ctx context.Context, request GetHealthRequestObject) (GetHealthResponseObject, error)
+	// Readiness check
+	// (GET /ready)
+	GetReady(ctx context.Context, request GetReadyRequestObject) (GetReadyResponseObject, error)
+	// Diagnostic status
+	// (GET /status)
+	GetStatus(ctx context.Context, request GetStatusRequestObject) (GetStatusResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListLowBalanceAccounts operation middleware
+func (sh *strictHandler) ListLowBalanceAccounts(w http.ResponseWriter, r *http.Request, params ListLowBalanceAccountsParams) {
+	var request ListLowBalanceAccountsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListLowBalanceAccounts(ctx, request.(ListLowBalanceAccountsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListLowBalanceAccounts")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListLowBalanceAccountsResponseObject); ok {
+		if err := validResponse.VisitListLowBalanceAccountsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ReconcileAccount operation middleware
+func (sh *strictHandler) ReconcileAccount(w http.ResponseWriter, r *http.Request, accountId AccountId) {
+	var request ReconcileAccountRequestObject
+
+	request.AccountId = accountId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReconcileAccount(ctx, request.(ReconcileAccountRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReconcileAccount")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReconcileAccountResponseObject); ok {
+		if err := validResponse.VisitReconcileAccountResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetMaintenance operation middleware
+func (sh *strictHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var request GetMaintenanceRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetMaintenance(ctx, request.(GetMaintenanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetMaintenance")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetMaintenanceResponseObject); ok {
+		if err := validResponse.VisitGetMaintenanceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SetMaintenance operation middleware
+func (sh *strictHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var request SetMaintenanceRequestObject
 
-func (response CreateVoid400JSONResponse) VisitCreateVoidResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	var body SetMaintenanceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-	return json.NewEncoder(w).Encode(response)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetMaintenance(ctx, request.(SetMaintenanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetMaintenance")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetMaintenanceResponseObject); ok {
+		if err := validResponse.VisitSetMaintenanceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type CreateVoid500JSONResponse struct{ InternalErrorJSONResponse }
+// ReconcileBalances operation middleware
+func (sh *strictHandler) ReconcileBalances(w http.ResponseWriter, r *http.Request) {
+	var request ReconcileBalancesRequestObject
 
-func (response CreateVoid500JSONResponse) VisitCreateVoidResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReconcileBalances(ctx, request.(ReconcileBalancesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReconcileBalances")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetHealthRequestObject struct {
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReconcileBalancesResponseObject); ok {
+		if err := validResponse.VisitReconcileBalancesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetHealthResponseObject interface {
-	VisitGetHealthResponse(w http.ResponseWriter) error
-}
+// GetAdminStats operation middleware
+func (sh *strictHandler) GetAdminStats(w http.ResponseWriter, r *http.Request) {
+	var request GetAdminStatsRequestObject
 
-type GetHealth200JSONResponse HealthResponse
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAdminStats(ctx, request.(GetAdminStatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAdminStats")
+	}
 
-func (response GetHealth200JSONResponse) VisitGetHealthResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAdminStatsResponseObject); ok {
+		if err := validResponse.VisitGetAdminStatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetHealth503JSONResponse HealthResponse
+// GetAccount operation middleware
+func (sh *strictHandler) GetAccount(w http.ResponseWriter, r *http.Request, accountId AccountId, params GetAccountParams) {
+	var request GetAccountRequestObject
 
-func (response GetHealth503JSONResponse) VisitGetHealthResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(503)
+	request.AccountId = accountId
+	request.Params = params
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAccount(ctx, request.(GetAccountRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAccount")
+	}
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Create authorization hold
-	// (POST /api/v1/authorizations)
-	CreateAuthorization(ctx context.Context, request CreateAuthorizationRequestObject) (CreateAuthorizationResponseObject, error)
-	// Get authorization details
-	// (GET /api/v1/authorizations/{authorizationId})
-	GetAuthorization(ctx context.Context, request GetAuthorizationRequestObject) (GetAuthorizationResponseObject, error)
-	// Capture authorization
-	// (POST /api/v1/captures)
-	CreateCapture(ctx context.Context, request CreateCaptureRequestObject) (CreateCaptureResponseObject, error)
-	// Get capture details
-	// (GET /api/v1/captures/{captureId})
-	GetCapture(ctx context.Context, request GetCaptureRequestObject) (GetCaptureResponseObject, error)
-	// Refund capture
-	// (POST /api/v1/refunds)
-	CreateRefund(ctx context.Context, request CreateRefundRequestObject) (CreateRefundResponseObject, error)
-	// Get refund details
-	// (GET /api/v1/refunds/{refundId})
-	GetRefund(ctx context.Context, request GetRefundRequestObject) (GetRefundResponseObject, error)
-	// Void authorization
-	// (POST /api/v1/voids)
-	CreateVoid(ctx context.Context, request CreateVoidRequestObject) (CreateVoidResponseObject, error)
-	// Health check
-	// (GET /health)
-	GetHealth(ctx context.Context, request GetHealthRequestObject) (GetHealthResponseObject, error)
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAccountResponseObject); ok {
+		if err := validResponse.VisitGetAccountResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
-type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+// GetAccountBalance operation middleware
+func (sh *strictHandler) GetAccountBalance(w http.ResponseWriter, r *http.Request, accountNumber AccountNumber) {
+	var request GetAccountBalanceRequestObject
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	request.AccountNumber = accountNumber
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAccountBalance(ctx, request.(GetAccountBalanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAccountBalance")
+	}
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAccountBalanceResponseObject); ok {
+		if err := validResponse.VisitGetAccountBalanceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
 // CreateAuthorization operation middleware
@@ -947,6 +2318,116 @@ func (sh *strictHandler) GetRefund(w http.ResponseWriter, r *http.Request, refun
 	}
 }
 
+// SearchTransactions operation middleware
+func (sh *strictHandler) SearchTransactions(w http.ResponseWriter, r *http.Request, params SearchTransactionsParams) {
+	var request SearchTransactionsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchTransactions(ctx, request.(SearchTransactionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchTransactions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchTransactionsResponseObject); ok {
+		if err := validResponse.VisitSearchTransactionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTransactionStatuses operation middleware
+func (sh *strictHandler) GetTransactionStatuses(w http.ResponseWriter, r *http.Request) {
+	var request GetTransactionStatusesRequestObject
+
+	var body GetTransactionStatusesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTransactionStatuses(ctx, request.(GetTransactionStatusesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTransactionStatuses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTransactionStatusesResponseObject); ok {
+		if err := validResponse.VisitGetTransactionStatusesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportTransactions operation middleware
+func (sh *strictHandler) ExportTransactions(w http.ResponseWriter, r *http.Request, params ExportTransactionsParams) {
+	var request ExportTransactionsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportTransactions(ctx, request.(ExportTransactionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportTransactions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportTransactionsResponseObject); ok {
+		if err := validResponse.VisitExportTransactionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTransaction operation middleware
+func (sh *strictHandler) GetTransaction(w http.ResponseWriter, r *http.Request, transactionId TransactionId, params GetTransactionParams) {
+	var request GetTransactionRequestObject
+
+	request.TransactionId = transactionId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTransaction(ctx, request.(GetTransactionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTransaction")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTransactionResponseObject); ok {
+		if err := validResponse.VisitGetTransactionResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // CreateVoid operation middleware
 func (sh *strictHandler) CreateVoid(w http.ResponseWriter, r *http.Request, params CreateVoidParams) {
 	var request CreateVoidRequestObject
@@ -1003,3 +2484,51 @@ func (sh *strictHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
 }
+
+// GetReady operation middleware
+func (sh *strictHandler) GetReady(w http.ResponseWriter, r *http.Request) {
+	var request GetReadyRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetReady(ctx, request.(GetReadyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetReady")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetReadyResponseObject); ok {
+		if err := validResponse.VisitGetReadyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetStatus operation middleware
+func (sh *strictHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	var request GetStatusRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetStatus(ctx, request.(GetStatusRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetStatus")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetStatusResponseObject); ok {
+		if err := validResponse.VisitGetStatusResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -18,42 +18,135 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9Ra63PbuBH/VzDodSaZoSzq5Yv0Lcm1V8+lrce5y5fY1UDkSsSZBHgAKEf16H/vAOAL",
-	"FPRw/Lj6G1/YXez+9gne44hnOWfAlMSze5wTQTJQIMzd+0IlXND/EkU5u4j1oxhkJGiuH+CZ+wG6+Am9",
-	"WXKREYVIoZL5dRGGo6goaGyu4C0OMNXLcqISHGBGMsAzTDpcAizgj4IKiPFMiQICLKMEMmLlUwqEpvEf",
-	"w+Jr2JuS3vLm/t22V1+PT7geDLc/4ACrTa5FkEpQtsLbbYA/klwVAny7LV+19xmR/NRtRjXhEzeoaT/9",
-	"/i5iyHKugEWbX2BzVQvS3exvjP5RALqFDVpygWi1TCEtPEgl0ZuMfEPDyQRFCRGy3nYCJAbRbLzFsfcL",
-	"bA5uPyPfPgFbqQTPhpNJgDPKqvuBbzdXsCxY7DOWfdO2lYDlqbYSFdkTTaVJP7Wptpq3zDmTYJzxA4mv",
-	"rOb1XcSZNoa+JHme0sh4T/93qTd/35LyBwFLPMN/6TeO3rdvZf9vQnBxVTKxLF0lfiEpja1zc4EWhaQM",
-	"pEQpX9EIgV6NNaSY1gNJDbmXE65iiySINYhGnn9x9XdesPjlRLkCyQsRAWJcoaXhvQ3wJdlkwFTbx15K",
-	"M7JYLmlEtbtqJEvjLOX6nchek9KgFjwHoajFHMl4YaWFbyTLU8Cz6XQ6DbB1KTzDlKnzcYNeyhSswFjB",
-	"CetzGjtUzNv5ZBLCu3EY9mA4XfTGg3jcIz8Oznvj8fn5ZDIeh2EY7npGgCMBREE8J0a0WpaYKOgpmoF3",
-	"TSGEDkGuGL99/sn3MXzLqQD5IAZSEVUYrQErMjz7qi0s+BpifOMLXU1g+bqrq5pcUNmgtQNHPkcbDSO+",
-	"+B0ihZuE9vqMbOXeIarT4gk0BwdoPiNydlFQ8TyOgtaOgwdDor01LwwMSjp+X6eTfYjolHvmOaIMRaZg",
-	"DI4iJqOMZloPAx96IiLiOSuyBQhftSViZF+iN5+KhKG1zUYQv21zxuPJaBgOJoPB8N0oHJ2fa7ZNGTGY",
-	"ulXEKGgn7uvr+H4wCgZTXwoOcLRe7xFsDYIuy/CtBSvAkWkwHLlijB0pdoUYBWO/CMbVN/OMM72whcDB",
-	"0DAo1Ts8puuSzgaIcMgMw+GkRWgYTqctUsNwON6ltoPbxoxWZx2xXe41fvfDtI5ZjwMoepMVUqGMqChB",
-	"jkO9fTR2fZHvSHOkOCq91MHKQ6Lk8/c/R9LSUdPZuvtJLVcq7e0TxJt2Stnb3CmObAPgmOnEvNOx0vM0",
-	"cfuzxjHzfOH0gHG+C9NrTuPXCmifokx5/ZHH0M7ilJnsM9exzvSM5a0JdtVdk5d1RLRFmv26qcfnth7X",
-	"UJWSstWcNu3x/Na0x66UjKu5bSm6bxoG7nOSCiDxZl5I+7K8rQuR5pE2nfPA4h4aKM0zKo0XNiWGI5Fd",
-	"4DxqX9OyR5vb5uzGk+DcbmYHk1A1lUc7ImOybYAzkJKswC3X3q8JTckiBbQgKWERICpRqptZlRBWzTQg",
-	"RrUNDyPJitUw8wHpH0BSlezf2m6tmJgVGgMFq66Plo0lGZ8EVTB+jtL/Werzh5TaJfS6/AUsT+E/2k/y",
-	"gf3BrhlrLzpqu2YPgRvID9b6bTF9ZrdBfq/Rn69n21VFGWF8jq9f7bA3D09gP8R7KD7IeB1rVBId7r4a",
-	"Lru61xQpW/LdzPlrQqWOOQRlPLpFC8Ju0fvLCzNcze2gCK2IgjuyQcbLhM2xCqSibHV2zS4UkjQrUqJA",
-	"Ip1h3JI2qOqkwGTkABEWl3UM0uY3H8mza2YkMUJ8qITIBV/TGCRaEEkjtCxYpL8mKVUbneK1ELWUy5Tf",
-	"SXRHVcILhQSQFGWcwQYpQZgkUcXnmr1PU3T578+/ImBxzqmu60p1I8JQZy6M7Nz47JpN/or4shkz39E0",
-	"RYKwmGfpBi0JTQ1zNAlDO/PTzDSrekVC1oAo0yaBGGmFsWiDFqDuABgahGFvGIZhptdpTFBloGe08U+t",
-	"l/eXF9rOIKS13eAsPAs1wHgOjOQUz/DoLDwb2folMYDvk5z214O+YxN7psKlp+C9TEkErgVRwtMYcYZI",
-	"FJlq2JQKZ9jwFfUhjK+VN5I0Rzdf/emy+aS/5xhge2M9AqT6wOPNk40sD0wftq4XKlFAd/Q9DMMnk8Q/",
-	"+fQMUd1StxyyaRCMrTQ+JrXU/da03iwZHl/SHRdvAzw5hZU7fjeD3iLLiNjUUPHATOOerDRU3I3iG03A",
-	"D+b+feeobquFW4GxiAvRn0E9Dp/do0cLzP8vTNSD/nE4Pm6m+lTCtdDPoDrmiUERmsrTLFTG/AOBpmps",
-	"CcoFrCkvZLqpOUJs0HCGyv5737xkXxD6WM8zXkH46UyVXjjwdOfwHnhVpnpcsHl80KgQ0/HgCo6V0b1A",
-	"7N/XB90Hw8P3Iqc5n3/WkPAAaz1ZGCgV5wkAXo3b2u6A55dH4KQiHFclnM/dy2/2OfpVNRB7BX7ujiBf",
-	"2M07Lbf3sNiY5U928kqK2g0rrJWW9kGtf1/9GHHQtb8TK/W/HM/q2Cfb58ncuuzBdr3ap2nduh1M5iyC",
-	"VHdPns5hAUuus8dhT/5ix7WvwI/bs+oX9mJnguL7MYbTP92DjQz7crSxskWWHSQeclg7qMTPqM/OKNSj",
-	"UfsFKqcsRj+jF2T/GcSaRoAKRqpJcUfdpYBRAtFtS9Gl7m4MTfsnkvUol/4nHpEUxbCGlOdmjGK/xQEu",
-	"RIpnOFEqn/X7qf4u4VLN3v347kfjYCWne7/CCItLpTVTluZHtlK6beA9xHZjSDMkata7nccumbJnrUsX",
-	"H42qeNld7bZTOvR5CRgs766+6s62mhVlZN3ebP8XAAD//5hqSvvhKgAA",
+	"H4sIAAAAAAAC/+x9C3MbudHgX0HN5WrtLyOKlOXdSK7UlVbS7uriV0myv6ssfSQ0A5KIZgAGwEjmuny/",
+	"/aobwAzmRVKSpc1+X1yprDgPoNFo9Lt7vkSJzJdSMGF0dPglWlJFc2aYwl9HSSILYc5S+JEynSi+NFyK",
+	"6NDfIh8+nJ1EccTh2pKaRRRHguYsOoxo+XIcKfbPgiuWRodGFSyOdLJgOYVRzWoJD2ujuJhHX7/GfuS3",
+	"RX7FVHviY6pSIvAmkTNiFoy4mdaC4YZbB8qSGsMUjPB/x+P0y+hFPDr4+qco7oKxMAup+G8UgOpET/gA",
+	"OTshz2ZS5dQQWpjFZFwMhy+SouAp/sWe94DemGVL4HGKX4c7B3Rn9unLX77ulH/vb/H3aK9nzcd0aQrF",
+	"ulbrboXrTOhy22Um5cBbLhDG/vbrO0tZvpSGiWT1N7Y6LwFpLvaD4P8sGLlmKzKTinD/miEAPNNGH5IR",
+	"MZLsvXxJkgVVNIHzRGZK5iRjsAodk5TPudGEipRMdyaDw//3592/TuOxuF3wZEESeQOvwOHSMfnw+uzE",
+	"PnpFNft+nxh5zYQekHdmwRRAoglVjCj2D5YYlpJbbhZkysUNzXg64dXCJtdsNR2Mhd+IBaMpU9VWBDjY",
+	"+Rtbrd2QnH5+zcTcLKLDvZcv4yjnwv8exeF2/Xq083e689tw52AwwXXufPpz9xacs1kh0i4Ks3dCAlNs",
+	"ti2BKT/slvQFQ397+rpUVGia9HGM4PYapmpqg9yFsX6Fh/VSCs2Qt/9I03NLr/ArkQJIGP6ky2XGE+Q5",
+	"u//QANuXYNg/KTaLDqP/sVvJjV17V++eKiXVuZvETllf40egR8sSpSJXheaCaU0yOecJYfB2BAdRwEbQ",
+	"DId7OuD8tEQzdcNUBc9baX6ShUifDpRzpmWhEkaENGSGc3+No/d0lTNhQs70VJjRxWzGEw5MDo6SBnAu",
+	"mLrhCfsg6A3lGb3K2NNBdLlgntuSRIpZxiu+R+FKUigF0ErByLOU0TSTyTUQnWaK08wL5hnlWaHYc2Su",
+	"t1SPhZJZxoDRJteEzgxTqGHAHHxeKJYSxYziTA/IW2kWXMzhNWDzYs7SV3h35V48h793jvBvzRIpUm1Z",
+	"r+W6eAqDZ9os4cK+BLLklnJDrthMIps3agWHuuO4c2HYnCnA2dc4+iCWSiZMa9idU2G4WT3dHr0TDBCe",
+	"S1Xt1YyzLLXCykmnV2SKB21gb01JxrXRhNFk4dS7HLmnmzbQSl9zbcr5gYEruWTKcMvenNqHf3PDcr1p",
+	"PW7UakUlD6dK0RX8znjOTRey40jOZpp13vsaMulfK7j8cOW7n8oJ5RXI8ajShc+BEBKecWpx27PYCUem",
+	"YAVkdBiBYGyLojhKFZ+ZSeJ1/gbRGanwAGRUJIxkyKBZOmeqvMYFHoql4jlVK2LPWrJ6RX5jSpLbBcP7",
+	"K0LnirEojthnmi+BPQzjCjguzPf7Ubwlvia4khDwtejaQBUTsYVxYZlJlpGrwuB6M6qRGyuvvOVUX7M0",
+	"XGD0H8G/0Wg06kJ/yS8nDqF9W/FjuAcg9G8YqdkEZCGzVMewIXaIAJSDg+FwONoC4XG0AYzXrd1vTTYa",
+	"4r+tZksUo4alE2pqxJpSw3YMz1kXyjyJwRsVsj9cnHQ9vOUpWDKRcjHvW/VPIOeIYqgNpORqdY8dODjY",
+	"CiPFMr0jRhqHBBfYoO3mtvbTXRMVAbpru1UDtPP4pTkXF4YavR1friP8bWnTBzyyROZ+JynJfFlUmKuP",
+	"95+ODxE7GLllihFDr5mI4i3JLtC2u84FUEPwiJsoRqWDCVAElkwRGBX1C22oKTRZAk1Zb8U2cimwC45h",
+	"/LZg6pcwNfjr6Orcv5Cy12xhjnAcfrkHqdcOj5NX5Sh4d/Ly5ZD9ZX843GF7B1c7+6N0f4f+MPp+Z3//",
+	"++9fvtzfB0bTySMem6+wz0uumL7TBJrnRQZgdRl8BSuFZYOrgE4pRbYi5ftIQUJa9RsVqAXLAr52JWXG",
+	"qMA5kc5wSaLIkSSWSyVvWBp9aoHYJJ7m/pTDxX7fa+whwEmDV1Qr7yI1J9pOQKA/WJ/ZWqA63aZ8fq1M",
+	"O3g5fLmlSKsA2Ea1ak8fKlmtuyFM2wK0HR42SfRtZ7vTKXqg8nkfbNgRJtUubafo4OxwNmVhtKEoIu8g",
+	"/V8Oh3eDrweqd0smYGYP1jIrLFiaGQO2asjl4weqZ+u07+DYb69a1NbWuxV1sug7UGsYyb9V/n9plf8u",
+	"DOJR9HLyrHaqW0w2JoKB00+wOYXRnt9Dke85O99SH+8+ASZZXKCQDhy69UPA07ojZKNIzennM/vwCPY4",
+	"58L/3KB9wkwboew7q4rpIruDzybQjcuhi2yziuzn6QLUxbL+eOqvhbs1KATLthhztGbMR9Sp27qqn3Oz",
+	"rhqsOL6z4hourZMMUJdtWEQ9p6uiiEYQGK8DD8q5kIoUAkSInJGpB2Q6IN6dTwqBrGlqR5ukLOE5zaaE",
+	"a5D0gy0YUs4Fz4s8PKQh7dXGXQst/Uc3tDFhg/mAjKODg8HBwTiKCdWECkIzDJ0gGzbSLwECjUeG5FIb",
+	"eC6nYkXc7GSZ0YRpuIwWupuALKgOcfWKcAPrt7xZQSCEpdaHXlEWwhLFjfD9n5+NxwP47/P/9adu0lbp",
+	"VjrBs9fFQpAbG7liaU0wRPuj+r8oDsOio4N6VPRFvHWOQf0YpWxGga/5Y9QIz1y8I/t7ox8qNPq8CNyG",
+	"ATmxr2Mg4cPFyYCga4QbkvLZrAxNmwUbCye0qqFgHJClsA2JFDdMoTFqgn0zhH22ARCiqGHN/bEgN+LB",
+	"n7686Fn2zU3PftwwxWcuVgH7UdRsgWi096KO/f0a8tu4fxHvd4NQt/N7nEqIk4wuNYSC3hQaojPeMT4r",
+	"MBsCY0rcLNzVIIaU089wUMfi2YshSelKgxrjNvl5fb8ab+K0aaEsFp79gG8/tyjfjj3j6laTXAqzqLHo",
+	"0V4cOcDcj7XsxI2zYlTVhtkbvhgGA+0NDw6CofaGe/sbNafwbFqKaIBdn72ffZey/N6M2ymPOWxwDipM",
+	"Xc18/nCu3KERbEglMpI46RXOfift4fGzhTY4lRzm+7fOJnw8fOeoE0B4jizWvtNEsZxytKptbgiaAnbM",
+	"h29pXRPrzZQy0k0exXdX1xqb+DgZUf3K1qbd+yj5w/cOEZQxqlkpoOqnLyYZozewi9w4KzDE5V63f+gx",
+	"zuON5Okf9TB27eIJNfSKanZRquf1LWQ+NacpHVdeYAkG+8HNiiQLllxjpgXrNDUzalPT8g5j/xxUPmIU",
+	"X3qdpj10tJXltZQy22ROvpcygxVjeotimBmx6Z1z+5hDVM2iWffaL4xmZuHfamxRabgEqHEr6NoszMQ4",
+	"likLzSif/AeyNIqrnzc3wa/SMLJ/TNjnhLFUT3xmwg3LZMLNyt3A/Uspz1b2ifByOUFlYs0+TxQ1bFIE",
+	"GUJO8bYqVvieFeh4oUo2mthkoyC2Kc3E5kPBOdYanCSN9MZgzI47wZU6WCAPDBPOxVw/JOGs9TvVOurX",
+	"aaYYTVeTQtub7mdp21aXgHPULliZwCo2O8m5RtUjil0mY7lPlRrg/irv1MCBnZQ87b3pXw7X6WYKLwV+",
+	"5tr18G+PepfwAzOXOX+Tkge4uxMj5SSjag4rKIQHyz4ClINUhj8hHzaT1OIFVFtZmAZIPg8MobCpfBPL",
+	"pz51cB08NCfMUJ612VviDtPG/Cc8dV/jyCYvtfnX6Q1TK5/qVM+CiolmxmXqtnA03TY6/BMMdeozFZsJ",
+	"SznTms5Z3RNz1I5+aR/roMIDCUae5w4bNAPAQTVXL4cKPWoNLAlgNEvMbCuUsF5dhqhjIl1KLgwaUzlP",
+	"04zdUsVIlbsXxX2yaePuue1vLqhJNNU6Amy3aAY3tb22/33x7i1ZUuOz2ezme0vRU8SVTFcNNTA0gdru",
+	"2a6NzZ0VOnoBKsnowMUsNm6ghXz9Dlp51e8U/XZSr3/2i5avcIHXV8g9/N9dh/1Nxdx7NRoBRyLttPgx",
+	"y/5WccPqefYd0fcmKblRuxZVaRsd7nqbTNtWY7gAmdJ9L6efJ3LJxMTrSC5rpf3kdk9Bzukk6dbVL6Wh",
+	"GQmGwAxVlmJJhObAVbShyhTL7dQznMv7NTpVQTsjsH6il0wYnBB0f5iRBqDcffrGpnXisQNp5W7EdsNq",
+	"GOtYUhcR1DM7z9lSqg6LCfXcTWlOjpu7RCc4fdSqGtv5hTDQ2h+RROngPYalPcaVC89vK69qeR+bwjQO",
+	"pDhcfzcSra/gMSI2jxJWuUuExClizfmhRmWL+V/0D3nHsE47UuOH2RypqdYQ1x0Ja0M0IZjd2x6aXi3C",
+	"fW/z7GyKFSWKUdDA8BXnzHZO1QE5Ihmdz607yD6AyVe5vGEaXyxdsS7nOSZaogNdMm2LNCjP8AG7ImuZ",
+	"Dlq6Sf0gb4f4DbY2pihkdL6FnT3vtrHZMqMrHIIGWQ93sK2BYfaN/prOyRVbSfCFY2mbRedcNjC63UwQ",
+	"9AebDwfpl9V2DhedyFbBbG5/N0vu+kwl9mqL3ciYNsW7E5m7ioJGndTxBVHshmsQZ8jbuYBMe0gLvCp4",
+	"ZpABx0QqMo4KcS3krRhHNQXyxWwvGdGrTk7vnDubeHXDCWR5gDL35hvba4ZxVCxhvIkrm6nN1k8dUDPp",
+	"6iOaJG7diO4BwoUrlKQG8ZmiXuHwmbKbBi5vRoP9wXCjHl0yMw9H7De4hrnW4oId6SKiVgJwBxk1xdto",
+	"rxwoPD0VC6+swePLs4+nXRtnL9Se/XD5y+SXd69PNqIC7wbsPen1FwerW1/Q08zFvmuCSH9hTxP2cKIN",
+	"IG9OO9s2hfUhKspjJz6nLMm4YMALtRTdooiSk9Pj12dvT086UplBkmuWHpJp27s3jck0ubkpvVzw27nV",
+	"0Hk5HQupyLTlipwOyNEVCngwAKy3QCL3t1TXDEN3+hW/SY73llucM0PhoMPDNE05YIdm7wOqsSW8LXpT",
+	"bMZgs7ojSmHdsFlwjdUHM5ll8laTYkmkcFkb7SxzKLXwQTFkfpgCcHl+9Pbip9PzybsPl/aR8srZ20ak",
+	"uW+tbWWx5DPH7968f316eQqUdvp/3p+d418f352d4B+eijrt+JIl+UEDhnR89P7yw/mpGyuKo/dH55dn",
+	"R68n7uf56U8f3uKDvxyd/3z649Hx36I4Chcb/jx7G8URvHD29udOUL5tzU6FxU1pS3cox+nLjmu7rnx1",
+	"cyPTkmbaVSgISbJmtcsCk3e4Jmcnr1pFLlQxoCZBZM5Nt6tk64Pzb2LajpjsNnZRgg3HbjaP18djXTDW",
+	"5tySJVWG08yHPn/f5Mcynj/pXUwZXCC0sS5brX2fFbUpswznuMGyMsLTte9wq4UIvLgFIvainhEfQkge",
+	"ovUJldUsbWr7il7KmeyQVMAsuCaU5FCMf0XFNTl6f4bye2l7G5A5NeyWQsjEsLnLcjJMg6NvMBZnpiyI",
+	"0gSUg2Y+QCnMAMIYGZL1IhAgeHwIFAOEBIH40QMBBVI8ZZpcUc0TKLNKrICGODMYj0ybEsoZCleI3cjC",
+	"EMVoRnIp2KpWijEYi7E4yrLyrffvLi7LgIYmDu2ECtJou0Jsg4DBWLz8nyCBfV8ZcsuzjCgqUplnK+tz",
+	"ACDIy+HQtqvQAztl+caC3rDK3HHxZHLFzC1jgoyGw5294XCYO23JcIMkiFh5A/g5en8WGDSH0WgwHAy9",
+	"C5kuOViag+HgBVK8WeBZ2KVQB7rr6xB3M3m746t3Dr9Ec2a6LDRTKKHL0k9yu5CadVdqWb9js/ocKOuK",
+	"ZfKWTM1CMQ2ZcjaBHtrqZPLWRt6UNui8KScSjCqSsmXGbA0nFRBDSaQwFHHmmi7UCE1b7zLugKVMaEhR",
+	"TmvdS9b/CgBjMwM34XeayFtRQj0gQa6pFZu2xKRMDLYJtoBRaPRj96kkZmgkE4Hd9FreOtfqUVX+GTbz",
+	"+rWngZclY5yjhWuLTpTxmLwbZjEDpBnXhqWtohDXuOafBVOroHNNfVPW9q7ZwmPfXM4bzDL2a/KBxKgb",
+	"Fp/fUM1YZtjuhcmLvvahN1/oa9yLVn3Nl8uKfmzoT2nbkmQDfK4jRCeAIUTDDog+Nfr87A2H36znR1fn",
+	"jZ7OH0s6Z2Etd0zcEWyTGaIFeMr+cNgHQbmk3aBvEb4yerqOJm9sxglYSj6kXx5MAOblNvDXuxvBJLrI",
+	"0QNqTzKh9YMJeOssCjV0rlFvBRCiTzBSk/N+KXvwfd1V7R4iUndw4mPLuHTYWe87YHjNklHgAi0mjDAH",
+	"0SiimK86t0EjbvRYyEZJIxX1KkbwyAspdrCfCEaBMEdBzuegviFHtE1jtmeSPtDm+WObPXZtW/XIbtUL",
+	"8SmOWKPjS2ePqPAJosrKpz/6Kdof7m+Gv+zJ9S2OXUkbyJurbpL9JyzMFutXaZZSoSbTSiBYUnCH9VFv",
+	"i3Z/ZuZNLT3t0aivnSjRsYHHruojQALJXSLUvxAl1bb4TQNWUtoyzU2Oo2XRGWYH+mgumaC/LXbuR9xh",
+	"8gz0/Ji8/wD/d3R5/EtMTk7BSfEcC8l5ysjUkhHWdvlkkrGwuWAvhy/CaabIHCmZBp3Cps5AsAZOXhiK",
+	"iRDz8/fHJKFZpgPTYBr0Z4OCrHMMjV0ztiS3Ul2D9kpAeZ1B/I9r7CqBNWAsl2oFpj7qf1xog6vGwChM",
+	"CyufzUrjWTFUiXGGLVnyRZuskSP9CMlQj0zRdd3z6+99pFrkGbSew8qqf+WzdVko0T4ZtsejnM3WMtJt",
+	"tZJzr0Zod9bKum0u3BWvg8SlokEaekaMF0NFw1IyqiSbejzAk7Y3nUHT1BqoDbVIdvTv8FV2A3IkCMuX",
+	"ZkWmNrPFNrsjOaPCqluu1YVHCpSWnYLtWKpANNOypgeNBSpCCJ21i13ZItdkWVxlXC/sozD8FFwuZbG5",
+	"hyFnRvHkPqqUTw16TInUmSB1N3Xov5KFUKkqtWOw9oxpn2TYqaYcW2Oj6d7HksSGV98egsqiA8ddSRo0",
+	"IynViytJFfa57CQnOK3TWgHNIfmRUcUUsb18r9kK/2BYGa2xCpIqRhKaLFx2ISUzdusbasZjoSWZBp2t",
+	"piSn6IhzCTk+xVZnfL4wWSdF/8xM1bvsMam5o0PaGgULsM614Yn+L2jqWlYXLLGHhJd892bUadJudCYG",
+	"FqxVhrhxbmPHJK2r7RU+ePzxI5K3LURBorNl51wkWWHrzkFRmp5e0vnUiWXU7u1j4WRSdVvHpUSQTKMX",
+	"cgndZpVGr6NmKIvGAnvPwkE5m+28lYLtvMG4OEI3Z4bQUoy8GO5PIa0qw07B2I12UXWj7aP0hxvALa8X",
+	"4MSVkTOqMo5CzJHBKzL9j6ktnkVusOptPh4uN1rXy/pJDPD+w+EeIb7oJejnC4ioz9WEHYZ7YU3cdk9j",
+	"T0DagKe/whmBcYEmmji689T38g48tUH+MzNdZri7sp4x2DaSX3e3jjgQLehSL6RpfE7iu6olm+ho2qYH",
+	"aw7Xj6VYvtcZs2t4XEdTs3HWRjr/vSmhS9fppIhapKbfpHif0aSraxRYLn5KzBPCQN5/ojHto49/Batx",
+	"6orKVRqD9IhL0VEjl6pho0sWJewzTSArlWqrz4wFRhAboUxoQeaZurfLpbUNFEgjYnugWyQi2y+hSw+J",
+	"ha+0bmizZjmhQkisFvI1iTC4jesOyIUdyFpIpSB0OhidUy5sRf1YBAWVfeZCRyedO5+Lnm9ztAWR+8IA",
+	"6+qp6cK1sMdYN+JywLoCMB6T3SGYGc0068gg/vQ4Low1rYie2JfR3SC2i3PUMO+Slu7tnN7b/Erz2wjw",
+	"3t4W73V1yL8n34K3Xmx+q+OzCXWWZ7e7gzGFjC+8uY797X5pfEQo1Jrb0utBh7T5UaTHVdPuR4r3FWVt",
+	"oVQbNsVKUr3dDjmOu0Y0+S4h0IoSk2OUsZmYS6gKkIUG6VElNAFxDMgFMGmalWlMfhq0h6/YWOQ0ZSX3",
+	"xkhHbQ0UImzg/dLOk2Sw4q4scbGJtzXGCl5rm031qoPhAkozZpgmEkOG6MGaFVm2KoUOpD84KLG87FYW",
+	"kIxPr9lYhAbVkmqDJhV2H/CvE4zjh87uruYE036hdFzWz38bcfSoAqDRzOiJWX+zLWKX28LtygPZ/R+V",
+	"bfsz2+ChniG4+92sYPdL+RG0tQz6vgRbfbvtUZnyHYjkmzFizwvaLLgT4zYdUK+LNMADbdZb6skunQ9y",
+	"FPzclsMSX7BItGPFhucMtO8aZ3V8tXy4i8GWk7kWfr0c7Nx3kPoDMLB6S68n5l+NGuHOCAJu/H9P7uUX",
+	"X/IXf4jsjc4ztPvFf1hvLc+6J4mW3wJ8VI61NVl8M35lcdbBrrow3Sx62+juFgyT7ML3XKTSlx8RzYwm",
+	"U/8Tvwjpq2OtpzC4iW02IX8WK4hsyMmEBUcUQ5E+hXQqVcrUhGNxlkoW/KbRnt56E6QhmlEF3oTuvAC4",
+	"d1n/dkmDeKp0eT9ljyUfrvMBn7NsZc03QJjY9qNrIfA9S+/wMdzOLNcaQp8s0/UxT2FfEWhnpoRJ0CdW",
+	"/+qBo/uHpJI+2Fdp6bYVRPX7Hxz1YL395333Cpa6U9WY9Okr1elvVmHJGVQAQjOg4bAOFxdoAUK2kI3j",
+	"JhmHpWJECtALCd9jUXuHGjTk6oFZXnaMIngSX2E9w4llCULWuAWvyBXvj8UU2eohQY/aFLOMGE0Bbpda",
+	"73JJTbJYx1EUpgXYRIfyk5k94a9WVRzTj5R61NGY/4mVnq6m+z352jZhAj9YVXUAOzv5vc4SSMqgnYac",
+	"VTp1XS7c7Uixz77BTqckvTCK0Vy3BV1s8yOkItQRYewVRd/i+dcphD+hetnI6XOgxOOLjzGRWVoVnozF",
+	"TFbpPTZVZU0FSM3DzzXmFxqGnholb+2zitEUQ8l2aZDJNIMgK9H8N4ZPuA9lePc3Lc+ra6P3ilDfS82a",
+	"O1B/ZRZKFvMFoVcwaK3zphRdJ+sUp18vtJvIdrYVDG6hJ7dcpPKWPMOYu25+hyPaG+59vzMc7QxHl8Ph",
+	"If7v7z2CDzZjuxqTtcVx7VZ1aQ/I7PN6kPc2gmzkwwHeLKUN+2x2E32zIVTcstsvPvqCBBtzBhKMCRbg",
+	"u48AGEOTBZjG9bj0sZ1754TrpdTc5/lVKKpefEVmPGOAjr+Oa30YJoDG4Qj3Hf793V/Y8xcGibZtO755",
+	"+PvBvMwejYbUQvZwZ/71pfb9780ZMMAIXBA7eBO0Em7s5+Vjl99iuyjrUlsZkA8afbbGZqkQGo7wnXac",
+	"uZ4Ug1+zf0212XkjUz7jLPWpyj4ppZ4vE8LUnxPDuFkw1Z8XA+0hzmbllDsXvMybvmO6TNcwXBM+F5jl",
+	"CbC3ZsfPcwizWdu4swVc/2D8755005r/l8vL9wSYUUwKXUCts80ybRAAyqMWhGvgqe/A75YI1Nk7puNT",
+	"5NVj90sIiqMawh6QPdTQtQvhKNt1RITNAXDgwGCMXLd49SNA+4dJODK1o7qRL0OuxNoQnkhYhnKxnWHi",
+	"akKdt21AoD8DJp43n7ZBMfguvWsP7VI0nB2lnSLCsUAe7wXJKu4rPLbyUnUE6LC4gxqSF8nC216HwZdj",
+	"CK+aPoxF2YMfl+ASzhsjakNXNu3eGpV+gWcwiTYNN/c4aPnsp8zYzADl+gBhr+f7o+3S8Afwe4cfQ3hi",
+	"A7DW+KODecF9b8j4DkB1esJ9xBKg2k4/oV34rVzcuNa+6BzcdOfbNhLeqFrtDYd1Zcb59JHzLgfkxId1",
+	"bF/GlC2ZSJlIeE/moO2A95jJ540ezh3kYJ/wtXHN2uQbJmB9vjGkR50D3CIPmdRG3EGNWyGMy9D33e8s",
+	"u6HJwn6NxXaMzKzcQiMWq1pS5b7cAoSpF4VJ5a3oxOg5wvK7IhRBsB8ySZirsIAmZAFVPxEkbyU2KumB",
+	"phELoinfvNWVY3Jt+Ss68LDHousiEpcnxfZBjCsCqH3cAwgg6OoMn78Yi6peYEA+iIxfM+IOLD5v6Q/M",
+	"FhWUn0B/Fyg+sZ1gC7Ngwjg8E67HwnXpjp2ehG/fMJpp4j8koAckpN3yq90h6RaiJN4eY+DC15w+GkVu",
+	"9vXZJ6o6/XIFJfDfmjYfAFOA0gaNnnA6FxIIoV3JW1Gp6xjb7X96LRNIb2L47QFs0GOfjeKoUFl0GC2M",
+	"WR7u7mbw3EJqc/iXH/7yA+oKbqYv3ewzcMCX3X4qA8dB1zanjlv9jIKmRdX7Rw0x3Eprdt2GfG5C1xg+",
+	"M6L9dm10qwB0DYDisv32ebPXUvWGvRX1Nkypvrot5XWxDBdsH+h49XXbs9F6O9Tf2yO8Q0ilqjYqDhlE",
+	"ENqsqp8qwOBS9PXT1/8/ABwQC7JFkwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,45 @@
+// Package buildinfo exposes the version the binary was built as and how long it has been running.
+//
+// Version and Commit are injected at build time:
+//
+//	go build -ldflags "-X github.com/benx421/payment-gateway/bank/internal/buildinfo.Version=v1.4.0 \
+//	  -X github.com/benx421/payment-gateway/bank/internal/buildinfo.Commit=$(git rev-parse --short HEAD)"
+package buildinfo
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// Version is the release version, or "dev" when not injected
+var Version = "dev"
+
+// Commit is the VCS revision; when not injected Revision falls back to the Go toolchain's build info
+var Commit = ""
+
+var startedAt = time.Now()
+
+// StartedAt returns when the process started
+func StartedAt() time.Time {
+	return startedAt
+}
+
+// Uptime returns how long the process has been running
+func Uptime() time.Duration {
+	return time.Since(startedAt)
+}
+
+// Revision returns Commit, or the revision recorded by the Go toolchain, or "unknown"
+func Revision() string {
+	if Commit != "" {
+		return Commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,23 @@
+package buildinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevision_PrefersInjectedCommit(t *testing.T) {
+	original := Commit
+	t.Cleanup(func() { Commit = original })
+
+	Commit = "3f2c1ab"
+	assert.Equal(t, "3f2c1ab", Revision())
+
+	Commit = ""
+	assert.NotEmpty(t, Revision(), "falls back to build info or unknown")
+}
+
+func TestUptime_CountsFromStart(t *testing.T) {
+	assert.False(t, StartedAt().IsZero())
+	assert.Positive(t, Uptime())
+}
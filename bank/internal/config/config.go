@@ -2,86 +2,784 @@
 package config
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/cvvcrypt"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Logger   LoggerConfig
-	Database DatabaseConfig
-	App      AppConfig
+	Server      ServerConfig      `yaml:"server"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Logger      LoggerConfig      `yaml:"logger"`
+	Auth        AuthConfig        `yaml:"auth"`
+	FX          FXConfig          `yaml:"fx"`
+	Webhook     WebhookConfig     `yaml:"webhook"`
+	Metadata    MetadataConfig    `yaml:"metadata"`
+	CVV         CVVConfig         `yaml:"cvv"`
+	Seed        SeedConfig        `yaml:"seed"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Risk        RiskConfig        `yaml:"risk"`
+	App         AppConfig         `yaml:"app"`
+	Database    DatabaseConfig    `yaml:"database"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Archive     ArchiveConfig     `yaml:"archive"`
+	Reconcile   ReconcileConfig   `yaml:"reconcile"`
+	Events      EventsConfig      `yaml:"events"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
+	Port                string        `yaml:"port"`
+	TLSCertFile         string        `yaml:"tls_cert_file"`
+	TLSKeyFile          string        `yaml:"tls_key_file"`
+	TLSClientCAFile     string        `yaml:"tls_client_ca_file"` // When set, clients must present a certificate signed by this CA (mTLS)
+	RequestTimeout      time.Duration `yaml:"request_timeout"`    // Per-request deadline. Disabled when 0
+	ReadTimeout         time.Duration `yaml:"read_timeout"`
+	WriteTimeout        time.Duration `yaml:"write_timeout"`
+	IdleTimeout         time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout     time.Duration `yaml:"shutdown_timeout"`      // Drain window for in-flight requests and background work on SIGTERM
+	CompressionMinBytes int           `yaml:"compression_min_bytes"` // Smallest JSON response gzipped for clients that accept it
+	MaxConcurrent       int           `yaml:"max_concurrent"`        // In-flight requests served at once, beyond which 503 is returned. Unlimited when 0
+	Compression         bool          `yaml:"compression"`
 }
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            string
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	ConnMaxLifetime time.Duration
-	MaxOpenConns    int
-	MaxIdleConns    int
+	Host            string        `yaml:"host"`
+	Port            string        `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	DBName          string        `yaml:"name"`
+	SSLMode         string        `yaml:"sslmode"`     // disable, require, verify-ca or verify-full
+	SSLRootCert     string        `yaml:"sslrootcert"` // CA bundle that verifies the server; required by verify-ca and verify-full
+	SSLCert         string        `yaml:"sslcert"`     // Client certificate, set together with SSLKey
+	SSLKey          string        `yaml:"sslkey"`
+	ApplicationName string        `yaml:"application_name"` // Names connections in pg_stat_activity. Empty leaves it unset
+	ReplicaHost     string        `yaml:"replica_host"`     // Streaming replica that serves reads. Empty sends every read to the primary
+	ReplicaPort     string        `yaml:"replica_port"`     // Defaults to Port
+	TablePrefix     string        `yaml:"table_prefix"`     // Prepended to every table and index name, so instances can share a schema
+	TenantSchemas   []string      `yaml:"tenant_schemas"`   // Schemas a request may select with X-Tenant-ID, each with its own pool
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout"` // Bound on opening a connection, sent to the driver in whole seconds. 0 disables
+	ReplicaMaxLag   time.Duration `yaml:"replica_max_lag"` // Replay lag beyond which reads go back to the primary
+	QueryTimeout    time.Duration `yaml:"query_timeout"`   // Bound on repository operations without a deadline. 0 disables
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	MinConns        int           `yaml:"min_conns"`      // Connections opened at startup so the first requests skip the handshake
+	TxMaxRetries    int           `yaml:"tx_max_retries"` // Reruns of a transaction after a deadlock or serialization failure
+	RunMigrations   bool          `yaml:"run_migrations"` // Apply embedded schema migrations at startup
+	PgBouncer       bool          `yaml:"pgbouncer"`      // Connect through PgBouncer in transaction pooling mode
 }
 
 // AppConfig holds application-specific configuration
 type AppConfig struct {
-	FailureRate        float64
-	MinLatencyMS       int
-	MaxLatencyMS       int
-	AuthExpiryHours    int
-	AuthExpiryDuration time.Duration
+	Environment        string        `yaml:"environment"` // development, staging or production
+	FailureRate        float64       `yaml:"failure_rate"`
+	MinLatencyMS       int           `yaml:"min_latency_ms"`
+	MaxLatencyMS       int           `yaml:"max_latency_ms"`
+	AuthExpiryHours    int           `yaml:"auth_expiry_hours"`     // Hold duration when the request sets no expiry
+	MaxAuthExpiryHours int           `yaml:"max_auth_expiry_hours"` // Cap on a requested expiry
+	AuthExpiryDuration time.Duration `yaml:"-"`                     // Derived from AuthExpiryHours
+}
+
+// Environments accepted in AppConfig.Environment
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// IsProduction reports whether the service is running in production
+func (c *AppConfig) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// SeedConfig holds the development fixture loaded at startup
+type SeedConfig struct {
+	File string `yaml:"file"` // YAML or JSON fixture of accounts and transactions. Seeding is disabled when empty
+}
+
+// Enabled reports whether a seed fixture is configured
+func (c *SeedConfig) Enabled() bool {
+	return c.File != ""
+}
+
+// IdempotencyConfig controls which requests are idempotent and how they behave when the key store
+// cannot be read
+type IdempotencyConfig struct {
+	Routes   []string `yaml:"routes"`    // "METHOD /path" entries that take an Idempotency-Key, e.g. "POST /api/v1/refunds"
+	FailOpen bool     `yaml:"fail_open"` // Process requests without deduplication instead of rejecting them with 503
+}
+
+// idempotentMethods are the methods a route may be made idempotent for. Safe methods change
+// nothing, so they never consume a key.
+var idempotentMethods = []string{"POST", "PUT", "PATCH"}
+
+func (c *IdempotencyConfig) validate() []error {
+	var errs []error
+	for _, route := range c.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " ?#") {
+			errs = append(errs, fmt.Errorf("invalid idempotent route %q (want METHOD /path)", route))
+			continue
+		}
+		if !slices.Contains(idempotentMethods, method) {
+			errs = append(errs, fmt.Errorf("invalid idempotent route %q: method must be POST, PUT, or PATCH", route))
+		}
+	}
+	return errs
+}
+
+// ArchiveConfig holds settings for moving settled transactions out of the hot table
+type ArchiveConfig struct {
+	After time.Duration `yaml:"after"` // Age at which settled transactions are archived. Archiving is disabled when 0
+}
+
+// Enabled reports whether transactions are archived
+func (c *ArchiveConfig) Enabled() bool {
+	return c.After > 0
+}
+
+// ReconcileConfig holds settings for the scheduled balance reconciliation
+type ReconcileConfig struct {
+	Interval time.Duration `yaml:"interval"` // Time between reconciliations. Only run on demand when 0
+}
+
+// Enabled reports whether balances are reconciled on a schedule
+func (c *ReconcileConfig) Enabled() bool {
+	return c.Interval > 0
+}
+
+// EventsConfig holds settings for the in-process transaction event bus
+type EventsConfig struct {
+	BufferSize int `yaml:"buffer_size"` // Events queued for subscribers before new ones are dropped
+}
+
+// GRPCConfig holds settings for the gRPC server that runs alongside the HTTP API
+type GRPCConfig struct {
+	Port string `yaml:"port"` // The gRPC server is disabled when empty
+}
+
+// Enabled reports whether the gRPC server is started
+func (c *GRPCConfig) Enabled() bool {
+	return c.Port != ""
+}
+
+// TracingConfig holds OpenTelemetry trace export settings
+type TracingConfig struct {
+	Endpoint string `yaml:"endpoint"` // OTLP/HTTP collector URL, e.g. http://collector:4318. Tracing is disabled when empty
+}
+
+// Enabled reports whether spans are exported
+func (c *TracingConfig) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// AuthConfig holds API authentication configuration
+type AuthConfig struct {
+	APIKeys         []string      `yaml:"api_keys"`         // Accepted bearer tokens. Authentication is disabled when empty
+	AdminKeys       []string      `yaml:"admin_keys"`       // Bearer tokens for /admin endpoints, which are closed when empty
+	SigningSecrets  []string      `yaml:"signing_secrets"`  // <api key>:<secret> entries. Request signing is disabled when empty
+	SignatureWindow time.Duration `yaml:"signature_window"` // Maximum age of a signed request's timestamp
+	RequireNonce    bool          `yaml:"require_nonce"`    // Signed requests must carry a single-use X-Nonce
+}
+
+// Enabled reports whether API key authentication is configured
+func (c *AuthConfig) Enabled() bool {
+	return len(c.APIKeys) > 0
+}
+
+// SigningEnabled reports whether request signatures are required
+func (c *AuthConfig) SigningEnabled() bool {
+	return len(c.SigningSecrets) > 0
+}
+
+// ParseSigningSecrets returns the signing secret for each API key
+func (c *AuthConfig) ParseSigningSecrets() (map[string]string, error) {
+	secrets := make(map[string]string, len(c.SigningSecrets))
+	for _, entry := range c.SigningSecrets {
+		key, secret, ok := strings.Cut(entry, ":")
+		if !ok || key == "" || secret == "" {
+			return nil, fmt.Errorf("invalid signing secret entry: must be <api key>:<secret>")
+		}
+		secrets[key] = secret
+	}
+	return secrets, nil
+}
+
+// NonceTTL is how long a request nonce must be kept: a timestamp may be up to SignatureWindow
+// behind or ahead of the server clock, so a nonce can be replayed for twice that long
+func (c *AuthConfig) NonceTTL() time.Duration {
+	return 2 * c.SignatureWindow
+}
+
+func (c *AuthConfig) validate() []error {
+	if !c.SigningEnabled() {
+		if c.RequireNonce {
+			return []error{fmt.Errorf("request nonces require request signing")}
+		}
+		return nil
+	}
+
+	var errs []error
+	if !c.Enabled() {
+		errs = append(errs, fmt.Errorf("request signing requires API keys"))
+	}
+	if c.SignatureWindow <= 0 {
+		errs = append(errs, fmt.Errorf("signature window must be positive"))
+	}
+
+	secrets, err := c.ParseSigningSecrets()
+	if err != nil {
+		return append(errs, err)
+	}
+	for key := range secrets {
+		if !slices.Contains(c.APIKeys, key) {
+			errs = append(errs, fmt.Errorf("signing secret configured for unknown API key"))
+		}
+	}
+	for _, key := range c.APIKeys {
+		if _, ok := secrets[key]; !ok {
+			errs = append(errs, fmt.Errorf("API key has no signing secret; every key must sign when signing is enabled"))
+			break
+		}
+	}
+	return errs
+}
+
+// RateLimitConfig holds per-client request rate limiting configuration
+type RateLimitConfig struct {
+	RequestsPerSecond float64       `yaml:"requests_per_second"` // Sustained rate per client. Rate limiting is disabled when 0
+	Burst             int           `yaml:"burst"`
+	IdleTTL           time.Duration `yaml:"idle_ttl"` // How long an idle client's limiter is retained
+}
+
+// Enabled reports whether rate limiting is configured
+func (c *RateLimitConfig) Enabled() bool {
+	return c.RequestsPerSecond > 0
+}
+
+// MetricsConfig holds Prometheus metrics configuration
+type MetricsConfig struct {
+	AmountBuckets []float64 `yaml:"amount_buckets"` // Upper bounds of the transaction amount histogram, in minor units
+	Enabled       bool      `yaml:"enabled"`        // Serve GET /metrics
+}
+
+func (c *MetricsConfig) validate() []error {
+	if c.Enabled && len(c.AmountBuckets) == 0 {
+		return []error{fmt.Errorf("metrics amount buckets must not be empty")}
+	}
+	for i := 1; i < len(c.AmountBuckets); i++ {
+		if c.AmountBuckets[i] <= c.AmountBuckets[i-1] {
+			return []error{fmt.Errorf("metrics amount buckets must be strictly increasing, got %v", c.AmountBuckets)}
+		}
+	}
+	return nil
+}
+
+// WebhookConfig holds transaction status notification settings
+type WebhookConfig struct {
+	URL            string        `yaml:"url"`    // Endpoint receiving events; empty disables webhooks
+	Secret         string        `yaml:"secret"` // Shared secret for the X-Signature HMAC
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"` // Doubled after each failed attempt
+	QueueSize      int           `yaml:"queue_size"`
+}
+
+// Enabled reports whether webhook delivery is configured
+func (c *WebhookConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+func (c *WebhookConfig) validate() []error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	var errs []error
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("invalid webhook URL: %s", c.URL))
+	}
+	if c.Secret == "" {
+		errs = append(errs, fmt.Errorf("webhook secret is required when a webhook URL is set"))
+	}
+	if c.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("webhook max attempts must be at least 1, got %d", c.MaxAttempts))
+	}
+	if c.InitialBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("webhook initial backoff must be positive"))
+	}
+	if c.QueueSize < 1 {
+		errs = append(errs, fmt.Errorf("webhook queue size must be at least 1, got %d", c.QueueSize))
+	}
+	return errs
+}
+
+// CVVConfig holds the keys used to encrypt CVVs at rest
+type CVVConfig struct {
+	Keys       []string `yaml:"keys"`        // <version>:<base64 32-byte key> entries. Encryption is disabled when empty
+	KeyVersion int      `yaml:"key_version"` // Version of the key new CVVs are encrypted with
+}
+
+// Enabled reports whether CVV encryption is configured
+func (c *CVVConfig) Enabled() bool {
+	return len(c.Keys) > 0
+}
+
+// ParseKeys returns the decoded encryption key for each version
+func (c *CVVConfig) ParseKeys() (map[byte][]byte, error) {
+	keys := make(map[byte][]byte, len(c.Keys))
+	for _, entry := range c.Keys {
+		versionStr, encoded, ok := strings.Cut(entry, ":")
+		version, err := strconv.Atoi(versionStr)
+		if !ok || err != nil || version < 1 || version > 255 {
+			return nil, fmt.Errorf("invalid CVV key entry: must be <version 1-255>:<base64 key>")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid CVV key for version %d: must be 32 bytes, base64 encoded", version)
+		}
+		if _, dup := keys[byte(version)]; dup {
+			return nil, fmt.Errorf("duplicate CVV key version %d", version)
+		}
+		keys[byte(version)] = key
+	}
+	return keys, nil
+}
+
+// NewCipher builds the CVV cipher from the configured keys, or returns nil when encryption is disabled
+func (c *CVVConfig) NewCipher() (*cvvcrypt.Cipher, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+	keys, err := c.ParseKeys()
+	if err != nil {
+		return nil, err
+	}
+	return cvvcrypt.NewCipher(keys, byte(c.KeyVersion)) // #nosec G115 -- range checked by validate
+}
+
+func (c *CVVConfig) validate() []error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	keys, err := c.ParseKeys()
+	if err != nil {
+		return []error{err}
+	}
+	if c.KeyVersion < 1 || c.KeyVersion > 255 {
+		return []error{fmt.Errorf("CVV key version must be between 1 and 255, got %d", c.KeyVersion)}
+	}
+	if _, ok := keys[byte(c.KeyVersion)]; !ok {
+		return []error{fmt.Errorf("CVV key version %d has no configured key", c.KeyVersion)}
+	}
+	return nil
+}
+
+// FXConfig holds the static exchange rates used for cross-currency authorizations
+type FXConfig struct {
+	Rates []string `yaml:"rates"` // FROM/TO=rate entries, e.g. EUR/USD=1.0842
+}
+
+// FXRate is a parsed exchange rate entry
+type FXRate struct {
+	Rate *big.Rat
+	From string
+	To   string
+}
+
+// ParseRates parses the configured rate entries
+func (c *FXConfig) ParseRates() ([]FXRate, error) {
+	rates := make([]FXRate, 0, len(c.Rates))
+	for _, entry := range c.Rates {
+		pair, value, ok := strings.Cut(entry, "=")
+		from, to, okPair := strings.Cut(pair, "/")
+		if !ok || !okPair || len(from) != 3 || len(to) != 3 {
+			return nil, fmt.Errorf("invalid FX rate %q: must be FROM/TO=rate", entry)
+		}
+
+		rate, okRate := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !okRate || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid FX rate %q: rate must be a positive decimal", entry)
+		}
+
+		rates = append(rates, FXRate{
+			From: strings.ToUpper(strings.TrimSpace(from)),
+			To:   strings.ToUpper(strings.TrimSpace(to)),
+			Rate: rate,
+		})
+	}
+	return rates, nil
+}
+
+// RiskConfig holds the fraud and exposure controls applied to payments
+type RiskConfig struct {
+	DailyLimitTimezone string        `yaml:"daily_limit_timezone"`        // IANA time zone whose midnight resets daily limits
+	MaxAuthAmounts     []string      `yaml:"max_auth_amounts"`            // CURRENCY=amount entries overriding MaxAuthAmount, e.g. JPY=5000000
+	DailyCaptureLimits []string      `yaml:"daily_capture_limits"`        // CURRENCY=amount entries overriding DailyCaptureLimit
+	MaxAuthAmount      int64         `yaml:"max_auth_amount"`             // Largest single authorization in minor units. Unlimited when 0
+	DailyCaptureLimit  int64         `yaml:"daily_capture_limit"`         // Most captured per account per day in minor units. Unlimited when 0
+	VelocityWindow     time.Duration `yaml:"velocity_window"`             // Period over which an account's authorizations are counted
+	VelocityMaxAuths   int           `yaml:"velocity_max_authorizations"` // Most authorizations per account within VelocityWindow. Unlimited when 0
+}
+
+// ParseMaxAuthAmounts returns the per-currency authorization caps
+func (c *RiskConfig) ParseMaxAuthAmounts() (map[string]int64, error) {
+	return parseCurrencyAmounts(c.MaxAuthAmounts, "max auth amount")
+}
+
+// ParseDailyCaptureLimits returns the per-currency daily capture limits
+func (c *RiskConfig) ParseDailyCaptureLimits() (map[string]int64, error) {
+	return parseCurrencyAmounts(c.DailyCaptureLimits, "daily capture limit")
+}
+
+// DayLocation returns the time zone whose midnight resets daily limits
+func (c *RiskConfig) DayLocation() (*time.Location, error) {
+	return time.LoadLocation(c.DailyLimitTimezone)
+}
+
+// parseCurrencyAmounts parses CURRENCY=amount entries into positive amounts keyed by currency
+func parseCurrencyAmounts(entries []string, name string) (map[string]int64, error) {
+	amounts := make(map[string]int64, len(entries))
+	for _, entry := range entries {
+		currency, value, ok := strings.Cut(entry, "=")
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if !ok || len(currency) != 3 {
+			return nil, fmt.Errorf("invalid %s %q: must be CURRENCY=amount", name, entry)
+		}
+
+		amount, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: amount must be a positive integer", name, entry)
+		}
+		amounts[currency] = amount
+	}
+	return amounts, nil
+}
+
+func (c *RiskConfig) validate() []error {
+	var errs []error
+	if c.MaxAuthAmount < 0 {
+		errs = append(errs, fmt.Errorf("max auth amount cannot be negative, got %d", c.MaxAuthAmount))
+	}
+	if _, err := c.ParseMaxAuthAmounts(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.VelocityMaxAuths < 0 {
+		errs = append(errs, fmt.Errorf("velocity max authorizations cannot be negative, got %d", c.VelocityMaxAuths))
+	}
+	if c.VelocityMaxAuths > 0 && c.VelocityWindow <= 0 {
+		errs = append(errs, fmt.Errorf("velocity window must be positive when velocity checks are enabled"))
+	}
+	if c.DailyCaptureLimit < 0 {
+		errs = append(errs, fmt.Errorf("daily capture limit cannot be negative, got %d", c.DailyCaptureLimit))
+	}
+	if _, err := c.ParseDailyCaptureLimits(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := c.DayLocation(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid daily limit timezone %q: %w", c.DailyLimitTimezone, err))
+	}
+	return errs
+}
+
+// MetadataConfig holds transaction metadata validation settings
+type MetadataConfig struct {
+	SchemaFile string `yaml:"schema_file"` // JSON object mapping transaction types, or "default", to JSON Schemas
+	Validate   bool   `yaml:"validate"`    // Reject metadata that does not match its schema
+	MaxBytes   int    `yaml:"max_bytes"`   // Largest serialized metadata accepted
+}
+
+// defaultMetadataSchemaKey names the schema applied to transaction types without their own
+const defaultMetadataSchemaKey = "default"
+
+// metadataSchemaKeys returns the keys accepted in a metadata schema file: "default" and every transaction type
+func metadataSchemaKeys() []string {
+	keys := []string{defaultMetadataSchemaKey}
+	for _, t := range models.TransactionTypes {
+		keys = append(keys, string(t))
+	}
+	return keys
+}
+
+// ParseSchemas reads the schema file, returning its schemas keyed by transaction type or "default".
+// It returns nil when no schema file is configured so the built-in default applies.
+func (c *MetadataConfig) ParseSchemas() (map[string]*openapi3.Schema, error) {
+	if c.SchemaFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.SchemaFile) // #nosec G304 -- path comes from operator-controlled configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata schema file: %w", err)
+	}
+
+	var schemas map[string]*openapi3.Schema
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata schema file %s: %w", c.SchemaFile, err)
+	}
+	for key, schema := range schemas {
+		if key != defaultMetadataSchemaKey && !models.TransactionType(key).IsValid() {
+			return nil, fmt.Errorf("unknown metadata schema key %q (must be one of %s)", key, strings.Join(metadataSchemaKeys(), ", "))
+		}
+		if schema == nil {
+			return nil, fmt.Errorf("metadata schema for %q is empty", key)
+		}
+		if err := schema.Validate(context.Background()); err != nil {
+			return nil, fmt.Errorf("invalid metadata schema for %q: %w", key, err)
+		}
+	}
+	return schemas, nil
 }
 
 // LoggerConfig holds logging configuration
 type LoggerConfig struct {
-	Level string // debug, info, warn, error
+	Level             string `yaml:"level"`                // debug, info, warn, error
+	Format            string `yaml:"format"`               // json or text
+	AddSource         bool   `yaml:"add_source"`           // Include the source file and line in every record
+	LogBodies         bool   `yaml:"log_bodies"`           // Log request and response bodies with card details redacted
+	MaxLoggedBodySize int    `yaml:"max_logged_body_size"` // Bodies larger than this many bytes are not logged
+}
+
+// defaults returns the configuration used when neither a config file nor env vars set a value
+func defaults() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:                "8080",
+			ReadTimeout:         15 * time.Second,
+			WriteTimeout:        15 * time.Second,
+			IdleTimeout:         60 * time.Second,
+			ShutdownTimeout:     30 * time.Second,
+			RequestTimeout:      10 * time.Second,
+			Compression:         true,
+			CompressionMinBytes: 1 << 10,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "postgres",
+			Password:        "postgres",
+			DBName:          "mockbank",
+			SSLMode:         SSLModeDisable,
+			ApplicationName: "bank-api",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnectTimeout:  10 * time.Second,
+			ReplicaMaxLag:   5 * time.Second,
+			QueryTimeout:    5 * time.Second,
+			TxMaxRetries:    2,
+		},
+		App: AppConfig{
+			Environment:        EnvDevelopment,
+			FailureRate:        0.05,
+			MinLatencyMS:       100,
+			MaxLatencyMS:       2000,
+			AuthExpiryHours:    168, // 7 days
+			MaxAuthExpiryHours: 720, // 30 days
+		},
+		Logger: LoggerConfig{
+			Level:             "info",
+			Format:            LogFormatJSON,
+			MaxLoggedBodySize: 4 << 10,
+		},
+		Metrics: MetricsConfig{
+			AmountBuckets: []float64{100, 1_000, 10_000, 100_000, 1_000_000, 10_000_000},
+			Enabled:       true,
+		},
+		RateLimit: RateLimitConfig{
+			Burst:   20,
+			IdleTTL: 10 * time.Minute,
+		},
+		Auth: AuthConfig{
+			SignatureWindow: 5 * time.Minute,
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:    5,
+			InitialBackoff: time.Second,
+			QueueSize:      1000,
+		},
+		Metadata: MetadataConfig{
+			MaxBytes: 16 << 10,
+		},
+		CVV: CVVConfig{
+			KeyVersion: 1,
+		},
+		Events: EventsConfig{
+			BufferSize: 1000,
+		},
+		Risk: RiskConfig{
+			VelocityWindow:     10 * time.Minute,
+			DailyLimitTimezone: "UTC",
+		},
+		Idempotency: IdempotencyConfig{
+			Routes: []string{
+				"POST /api/v1/authorizations",
+				"POST /api/v1/captures",
+				"POST /api/v1/voids",
+				"POST /api/v1/refunds",
+			},
+		},
+	}
 }
 
-// Load loads configuration from environment variables with sensible defaults
+// Load loads configuration with sensible defaults.
+//
+// Values are resolved in order of precedence: environment variables, then the
+// YAML file named by CONFIG_FILE (if set), then built-in defaults.
 func Load() (*Config, error) {
-	authExpiryHours := getEnvAsInt("AUTH_EXPIRY_HOURS", 168) // 7 days default
+	base := defaults()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, base); err != nil {
+			return nil, err
+		}
+	}
+
+	authExpiryHours := getEnvAsInt("AUTH_EXPIRY_HOURS", base.App.AuthExpiryHours)
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "8080"),
-			ReadTimeout:  getEnvAsDuration("SERVER_READ_TIMEOUT", "15s"),
-			WriteTimeout: getEnvAsDuration("SERVER_WRITE_TIMEOUT", "15s"),
-			IdleTimeout:  getEnvAsDuration("SERVER_IDLE_TIMEOUT", "60s"),
+			Port:                getEnv("PORT", base.Server.Port),
+			ReadTimeout:         getEnvAsDuration("SERVER_READ_TIMEOUT", base.Server.ReadTimeout),
+			WriteTimeout:        getEnvAsDuration("SERVER_WRITE_TIMEOUT", base.Server.WriteTimeout),
+			IdleTimeout:         getEnvAsDuration("SERVER_IDLE_TIMEOUT", base.Server.IdleTimeout),
+			ShutdownTimeout:     getEnvAsDuration("SHUTDOWN_TIMEOUT", base.Server.ShutdownTimeout),
+			RequestTimeout:      getEnvAsDuration("REQUEST_TIMEOUT", base.Server.RequestTimeout),
+			TLSCertFile:         getEnv("TLS_CERT_FILE", base.Server.TLSCertFile),
+			TLSKeyFile:          getEnv("TLS_KEY_FILE", base.Server.TLSKeyFile),
+			TLSClientCAFile:     getEnv("TLS_CLIENT_CA_FILE", base.Server.TLSClientCAFile),
+			Compression:         getEnvAsBool("COMPRESSION_ENABLED", base.Server.Compression),
+			CompressionMinBytes: getEnvAsInt("COMPRESSION_MIN_BYTES", base.Server.CompressionMinBytes),
+			MaxConcurrent:       getEnvAsInt("MAX_CONCURRENT_REQUESTS", base.Server.MaxConcurrent),
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "5432"),
-			User:            getEnv("DB_USER", "postgres"),
-			Password:        getEnv("DB_PASSWORD", "postgres"),
-			DBName:          getEnv("DB_NAME", "mockbank"),
-			SSLMode:         getEnv("DB_SSLMODE", "disable"),
-			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
-			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", "5m"),
+			Host:            getEnv("DB_HOST", base.Database.Host),
+			Port:            getEnv("DB_PORT", base.Database.Port),
+			User:            getEnv("DB_USER", base.Database.User),
+			Password:        getEnv("DB_PASSWORD", base.Database.Password),
+			DBName:          getEnv("DB_NAME", base.Database.DBName),
+			SSLMode:         getEnv("DB_SSLMODE", base.Database.SSLMode),
+			SSLRootCert:     getEnv("DB_SSLROOTCERT", base.Database.SSLRootCert),
+			SSLCert:         getEnv("DB_SSLCERT", base.Database.SSLCert),
+			SSLKey:          getEnv("DB_SSLKEY", base.Database.SSLKey),
+			ApplicationName: getEnv("DB_APPLICATION_NAME", base.Database.ApplicationName),
+			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", base.Database.MaxOpenConns),
+			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", base.Database.MaxIdleConns),
+			MinConns:        getEnvAsInt("DB_MIN_CONNS", base.Database.MinConns),
+			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", base.Database.ConnMaxLifetime),
+			ConnectTimeout:  getEnvAsDuration("DB_CONNECT_TIMEOUT", base.Database.ConnectTimeout),
+			ReplicaHost:     getEnv("DB_REPLICA_HOST", base.Database.ReplicaHost),
+			ReplicaPort:     getEnv("DB_REPLICA_PORT", base.Database.ReplicaPort),
+			ReplicaMaxLag:   getEnvAsDuration("DB_REPLICA_MAX_LAG", base.Database.ReplicaMaxLag),
+			QueryTimeout:    getEnvAsDuration("DB_QUERY_TIMEOUT", base.Database.QueryTimeout),
+			TxMaxRetries:    getEnvAsInt("DB_TX_MAX_RETRIES", base.Database.TxMaxRetries),
+			RunMigrations:   getEnvAsBool("RUN_MIGRATIONS", base.Database.RunMigrations),
+			PgBouncer:       getEnvAsBool("DB_PGBOUNCER", base.Database.PgBouncer),
+			TablePrefix:     getEnv("DB_TABLE_PREFIX", base.Database.TablePrefix),
+			TenantSchemas:   getEnvAsSlice("DB_TENANT_SCHEMAS", base.Database.TenantSchemas),
 		},
 		App: AppConfig{
-			FailureRate:        getEnvAsFloat("FAILURE_RATE", 0.05),
-			MinLatencyMS:       getEnvAsInt("MIN_LATENCY_MS", 100),
-			MaxLatencyMS:       getEnvAsInt("MAX_LATENCY_MS", 2000),
+			Environment:        getEnv("APP_ENV", base.App.Environment),
+			FailureRate:        getEnvAsFloat("FAILURE_RATE", base.App.FailureRate),
+			MinLatencyMS:       getEnvAsInt("MIN_LATENCY_MS", base.App.MinLatencyMS),
+			MaxLatencyMS:       getEnvAsInt("MAX_LATENCY_MS", base.App.MaxLatencyMS),
 			AuthExpiryHours:    authExpiryHours,
+			MaxAuthExpiryHours: getEnvAsInt("MAX_AUTH_EXPIRY_HOURS", base.App.MaxAuthExpiryHours),
 			AuthExpiryDuration: time.Duration(authExpiryHours) * time.Hour,
 		},
 		Logger: LoggerConfig{
-			Level: getEnv("LOG_LEVEL", "info"),
+			Level:             getEnv("LOG_LEVEL", base.Logger.Level),
+			Format:            getEnv("LOG_FORMAT", base.Logger.Format),
+			AddSource:         getEnvAsBool("LOG_ADD_SOURCE", base.Logger.AddSource),
+			LogBodies:         getEnvAsBool("LOG_BODIES", base.Logger.LogBodies),
+			MaxLoggedBodySize: getEnvAsInt("LOG_BODY_MAX_BYTES", base.Logger.MaxLoggedBodySize),
+		},
+		Auth: AuthConfig{
+			APIKeys:         getEnvAsSlice("API_KEYS", base.Auth.APIKeys),
+			AdminKeys:       getEnvAsSlice("ADMIN_API_KEYS", base.Auth.AdminKeys),
+			SigningSecrets:  getEnvAsSlice("SIGNING_SECRETS", base.Auth.SigningSecrets),
+			SignatureWindow: getEnvAsDuration("SIGNATURE_WINDOW", base.Auth.SignatureWindow),
+			RequireNonce:    getEnvAsBool("SIGNING_REQUIRE_NONCE", base.Auth.RequireNonce),
+		},
+		Metrics: MetricsConfig{
+			AmountBuckets: getEnvAsFloatSlice("METRICS_AMOUNT_BUCKETS", base.Metrics.AmountBuckets),
+			Enabled:       getEnvAsBool("METRICS_ENABLED", base.Metrics.Enabled),
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond: getEnvAsFloat("RATE_LIMIT_RPS", base.RateLimit.RequestsPerSecond),
+			Burst:             getEnvAsInt("RATE_LIMIT_BURST", base.RateLimit.Burst),
+			IdleTTL:           getEnvAsDuration("RATE_LIMIT_IDLE_TTL", base.RateLimit.IdleTTL),
+		},
+		FX: FXConfig{
+			Rates: getEnvAsSlice("FX_RATES", base.FX.Rates),
+		},
+		Webhook: WebhookConfig{
+			URL:            getEnv("WEBHOOK_URL", base.Webhook.URL),
+			Secret:         getEnv("WEBHOOK_SECRET", base.Webhook.Secret),
+			MaxAttempts:    getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", base.Webhook.MaxAttempts),
+			InitialBackoff: getEnvAsDuration("WEBHOOK_INITIAL_BACKOFF", base.Webhook.InitialBackoff),
+			QueueSize:      getEnvAsInt("WEBHOOK_QUEUE_SIZE", base.Webhook.QueueSize),
+		},
+		Metadata: MetadataConfig{
+			Validate:   getEnvAsBool("METADATA_VALIDATION", base.Metadata.Validate),
+			SchemaFile: getEnv("METADATA_SCHEMA_FILE", base.Metadata.SchemaFile),
+			MaxBytes:   getEnvAsInt("METADATA_MAX_BYTES", base.Metadata.MaxBytes),
+		},
+		Seed: SeedConfig{
+			File: getEnv("SEED_FILE", base.Seed.File),
+		},
+		Archive: ArchiveConfig{
+			After: getEnvAsDuration("TRANSACTION_ARCHIVE_AFTER", base.Archive.After),
+		},
+		Reconcile: ReconcileConfig{
+			Interval: getEnvAsDuration("RECONCILE_INTERVAL", base.Reconcile.Interval),
+		},
+		Idempotency: IdempotencyConfig{
+			Routes:   getEnvAsSlice("IDEMPOTENT_ROUTES", base.Idempotency.Routes),
+			FailOpen: getEnvAsBool("IDEMPOTENCY_FAIL_OPEN", base.Idempotency.FailOpen),
+		},
+		GRPC: GRPCConfig{
+			Port: getEnv("GRPC_PORT", base.GRPC.Port),
+		},
+		Tracing: TracingConfig{
+			Endpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", base.Tracing.Endpoint),
+		},
+		Events: EventsConfig{
+			BufferSize: getEnvAsInt("EVENT_BUFFER_SIZE", base.Events.BufferSize),
+		},
+		Risk: RiskConfig{
+			MaxAuthAmount:      getEnvAsInt64("MAX_AUTH_AMOUNT", base.Risk.MaxAuthAmount),
+			MaxAuthAmounts:     getEnvAsSlice("MAX_AUTH_AMOUNTS", base.Risk.MaxAuthAmounts),
+			VelocityWindow:     getEnvAsDuration("VELOCITY_WINDOW", base.Risk.VelocityWindow),
+			VelocityMaxAuths:   getEnvAsInt("VELOCITY_MAX_AUTHS", base.Risk.VelocityMaxAuths),
+			DailyCaptureLimit:  getEnvAsInt64("DAILY_CAPTURE_LIMIT", base.Risk.DailyCaptureLimit),
+			DailyCaptureLimits: getEnvAsSlice("DAILY_CAPTURE_LIMITS", base.Risk.DailyCaptureLimits),
+			DailyLimitTimezone: getEnv("DAILY_LIMIT_TIMEZONE", base.Risk.DailyLimitTimezone),
+		},
+		CVV: CVVConfig{
+			Keys:       getEnvAsSlice("CVV_KEYS", base.CVV.Keys),
+			KeyVersion: getEnvAsInt("CVV_KEY_VERSION", base.CVV.KeyVersion),
 		},
 	}
 
@@ -92,44 +790,338 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// loadFile overlays values from a YAML file onto cfg. Keys absent from the file keep their current value.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator-controlled CONFIG_FILE
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
 // Validate checks if the configuration is valid and returns an error if not.
+//
+// All problems are reported together so misconfiguration can be fixed in one pass.
 func (c *Config) Validate() error {
-	if c.Server.Port == "" {
-		return fmt.Errorf("server port cannot be empty")
+	var errs []error
+
+	errs = append(errs, c.Server.validate()...)
+	errs = append(errs, c.Database.validate()...)
+	errs = append(errs, c.Auth.validate()...)
+	errs = append(errs, c.Webhook.validate()...)
+	errs = append(errs, c.CVV.validate()...)
+	errs = append(errs, c.Risk.validate()...)
+	errs = append(errs, c.Idempotency.validate()...)
+	errs = append(errs, c.Metrics.validate()...)
+
+	if c.App.FailureRate < 0 || c.App.FailureRate > 1 {
+		errs = append(errs, fmt.Errorf("failure rate must be between 0 and 1, got %f", c.App.FailureRate))
+	}
+	if c.App.MinLatencyMS < 0 {
+		errs = append(errs, fmt.Errorf("min latency cannot be negative"))
+	}
+	if c.App.MaxLatencyMS < c.App.MinLatencyMS {
+		errs = append(errs, fmt.Errorf("max latency (%d) must be >= min latency (%d)", c.App.MaxLatencyMS, c.App.MinLatencyMS))
+	}
+	if !slices.Contains([]string{EnvDevelopment, EnvStaging, EnvProduction}, c.App.Environment) {
+		errs = append(errs, fmt.Errorf("invalid environment: %s (must be development, staging, or production)", c.App.Environment))
+	}
+	if c.Seed.Enabled() && c.App.IsProduction() {
+		errs = append(errs, fmt.Errorf("seed data cannot be loaded in production"))
+	}
+	if c.App.AuthExpiryHours <= 0 {
+		errs = append(errs, fmt.Errorf("auth expiry hours must be positive, got %d", c.App.AuthExpiryHours))
+	}
+	if c.App.MaxAuthExpiryHours < c.App.AuthExpiryHours {
+		errs = append(errs, fmt.Errorf("max auth expiry hours (%d) must be >= auth expiry hours (%d)", c.App.MaxAuthExpiryHours, c.App.AuthExpiryHours))
 	}
 
-	if c.Database.Host == "" {
-		return fmt.Errorf("database host cannot be empty")
+	if c.Tracing.Enabled() {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid tracing endpoint: %s", c.Tracing.Endpoint))
+		}
 	}
-	if c.Database.DBName == "" {
-		return fmt.Errorf("database name cannot be empty")
+	if c.Events.BufferSize < 1 {
+		errs = append(errs, fmt.Errorf("event buffer size must be at least 1, got %d", c.Events.BufferSize))
 	}
 
-	if c.App.FailureRate < 0 || c.App.FailureRate > 1 {
-		return fmt.Errorf("failure rate must be between 0 and 1, got %f", c.App.FailureRate)
+	if c.RateLimit.RequestsPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("rate limit cannot be negative"))
+	}
+	if c.RateLimit.Enabled() && c.RateLimit.Burst < 1 {
+		errs = append(errs, fmt.Errorf("rate limit burst must be at least 1, got %d", c.RateLimit.Burst))
+	}
+	if c.RateLimit.Enabled() && c.RateLimit.IdleTTL <= 0 {
+		errs = append(errs, fmt.Errorf("rate limit idle TTL must be positive"))
 	}
 
-	if c.App.MinLatencyMS < 0 {
-		return fmt.Errorf("min latency cannot be negative")
+	if _, err := c.FX.ParseRates(); err != nil {
+		errs = append(errs, err)
 	}
-	if c.App.MaxLatencyMS < c.App.MinLatencyMS {
-		return fmt.Errorf("max latency (%d) must be >= min latency (%d)", c.App.MaxLatencyMS, c.App.MinLatencyMS)
+	if _, err := c.Metadata.ParseSchemas(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Archive.After < 0 {
+		errs = append(errs, fmt.Errorf("transaction archive age cannot be negative"))
+	}
+	if c.Reconcile.Interval < 0 {
+		errs = append(errs, fmt.Errorf("reconcile interval cannot be negative"))
+	}
+	if c.GRPC.Enabled() {
+		if err := validatePort(c.GRPC.Port); err != nil {
+			errs = append(errs, fmt.Errorf("invalid grpc port: %w", err))
+		} else if c.GRPC.Port == c.Server.Port {
+			errs = append(errs, fmt.Errorf("grpc port must differ from the server port"))
+		}
+	}
+	if c.Metadata.MaxBytes < 1 {
+		errs = append(errs, fmt.Errorf("metadata max bytes must be at least 1, got %d", c.Metadata.MaxBytes))
+	}
+
+	if c.Logger.LogBodies && c.Logger.MaxLoggedBodySize < 1 {
+		errs = append(errs, fmt.Errorf("max logged body size must be at least 1, got %d", c.Logger.MaxLoggedBodySize))
 	}
 
 	validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
 	if !validLevels[c.Logger.Level] {
-		return fmt.Errorf("invalid log level: %s (must be debug, info, warn, or error)", c.Logger.Level)
+		errs = append(errs, fmt.Errorf("invalid log level: %s (must be debug, info, warn, or error)", c.Logger.Level))
+	}
+	if c.Logger.Format != LogFormatJSON && c.Logger.Format != LogFormatText {
+		errs = append(errs, fmt.Errorf("invalid log format: %s (must be json or text)", c.Logger.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *ServerConfig) validate() []error {
+	var errs []error
+
+	if c.Port == "" {
+		errs = append(errs, fmt.Errorf("server port cannot be empty"))
+	} else if err := validatePort(c.Port); err != nil {
+		errs = append(errs, fmt.Errorf("invalid server port: %w", err))
+	}
+
+	if c.ReadTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("server read timeout must be positive"))
+	}
+	if c.WriteTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("server write timeout must be positive"))
 	}
+	if c.IdleTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("server idle timeout must be positive"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("server shutdown timeout must be positive"))
+	}
+	if c.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("request timeout cannot be negative"))
+	}
+	if c.MaxConcurrent < 0 {
+		errs = append(errs, fmt.Errorf("max concurrent requests cannot be negative, got %d", c.MaxConcurrent))
+	}
+	if c.Compression && c.CompressionMinBytes < 0 {
+		errs = append(errs, fmt.Errorf("compression min bytes cannot be negative, got %d", c.CompressionMinBytes))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, fmt.Errorf("TLS cert file and key file must be set together"))
+	}
+	if c.TLSClientCAFile != "" && !c.TLSEnabled() {
+		errs = append(errs, fmt.Errorf("TLS client CA file requires TLS cert and key files"))
+	}
+
+	return errs
+}
+
+func (c *DatabaseConfig) validate() []error {
+	var errs []error
 
+	if c.Host == "" {
+		errs = append(errs, fmt.Errorf("database host cannot be empty"))
+	}
+	if c.Port == "" {
+		errs = append(errs, fmt.Errorf("database port cannot be empty"))
+	} else if err := validatePort(c.Port); err != nil {
+		errs = append(errs, fmt.Errorf("invalid database port: %w", err))
+	}
+	if c.DBName == "" {
+		errs = append(errs, fmt.Errorf("database name cannot be empty"))
+	}
+	errs = append(errs, c.validateSSL()...)
+	if len(c.ApplicationName) > maxApplicationNameLen {
+		errs = append(errs, fmt.Errorf("database application name must be at most %d bytes, got %d", maxApplicationNameLen, len(c.ApplicationName)))
+	}
+	if c.MaxOpenConns <= 0 {
+		errs = append(errs, fmt.Errorf("database max open connections must be positive, got %d", c.MaxOpenConns))
+	}
+	if c.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("database max idle connections cannot be negative"))
+	}
+	if c.QueryTimeout < 0 {
+		errs = append(errs, fmt.Errorf("database query timeout cannot be negative"))
+	}
+	if c.ConnectTimeout < 0 {
+		errs = append(errs, fmt.Errorf("database connect timeout cannot be negative"))
+	}
+	if c.TxMaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("database transaction retries cannot be negative"))
+	}
+	if c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database max idle connections (%d) must be <= max open connections (%d)", c.MaxIdleConns, c.MaxOpenConns))
+	}
+	if c.MinConns < 0 {
+		errs = append(errs, fmt.Errorf("database min connections cannot be negative"))
+	}
+	// Warmed connections beyond the idle limit would be closed as soon as they are released
+	if c.MinConns > c.MaxIdleConns {
+		errs = append(errs, fmt.Errorf("database min connections (%d) must be <= max idle connections (%d)", c.MinConns, c.MaxIdleConns))
+	}
+	// The migration lock is held for the whole session, which PgBouncer does not pin to one server connection
+	if c.PgBouncer && c.RunMigrations {
+		errs = append(errs, fmt.Errorf("migrations cannot run through PgBouncer; run them against PostgreSQL directly"))
+	}
+	if c.ConnMaxLifetime <= 0 {
+		errs = append(errs, fmt.Errorf("database connection max lifetime must be positive"))
+	}
+	if c.TablePrefix != "" && !tablePrefixPattern.MatchString(c.TablePrefix) {
+		errs = append(errs, fmt.Errorf("invalid table prefix %q: must be a lowercase identifier of at most 23 characters", c.TablePrefix))
+	}
+	for _, schema := range c.TenantSchemas {
+		if !schemaNamePattern.MatchString(schema) {
+			errs = append(errs, fmt.Errorf("invalid tenant schema %q: must be a lowercase identifier of at most 63 characters", schema))
+		}
+	}
+	if c.ReplicaPort != "" {
+		if err := validatePort(c.ReplicaPort); err != nil {
+			errs = append(errs, fmt.Errorf("invalid database replica port: %w", err))
+		}
+	}
+	if c.ReplicaHost != "" && c.ReplicaMaxLag <= 0 {
+		errs = append(errs, fmt.Errorf("database replica max lag must be positive"))
+	}
+	// PgBouncer refuses search_path as a startup parameter
+	if c.PgBouncer && len(c.TenantSchemas) > 0 {
+		errs = append(errs, fmt.Errorf("tenant schemas cannot be used through PgBouncer"))
+	}
+
+	return errs
+}
+
+func validatePort(port string) error {
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", port)
+	}
+	if value < 1 || value > 65535 {
+		return fmt.Errorf("%d is out of range (1-65535)", value)
+	}
 	return nil
 }
 
+// SSL modes accepted in DatabaseConfig.SSLMode
+const (
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+)
+
+// validateSSL checks the SSL mode and that the certificate files it needs are readable
+func (c *DatabaseConfig) validateSSL() []error {
+	var errs []error
+
+	switch c.SSLMode {
+	case "", SSLModeDisable, SSLModeRequire: // The driver treats an empty mode as require
+	case SSLModeVerifyCA, SSLModeVerifyFull:
+		if c.SSLRootCert == "" {
+			errs = append(errs, fmt.Errorf("database sslmode %s requires a root certificate", c.SSLMode))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid database sslmode: %s (must be disable, require, verify-ca or verify-full)", c.SSLMode))
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		errs = append(errs, fmt.Errorf("database SSL cert and key must be set together"))
+	}
+
+	for _, f := range []struct{ name, path string }{
+		{"root certificate", c.SSLRootCert},
+		{"SSL cert", c.SSLCert},
+		{"SSL key", c.SSLKey},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			errs = append(errs, fmt.Errorf("database %s: %w", f.name, err))
+		}
+	}
+	return errs
+}
+
 // DSN returns the PostgreSQL connection string
 func (c *DatabaseConfig) DSN() string {
-	return fmt.Sprintf(
+	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
 	)
+	if c.SSLRootCert != "" {
+		dsn += " sslrootcert=" + quoteDSNValue(c.SSLRootCert)
+	}
+	if c.SSLCert != "" {
+		dsn += " sslcert=" + quoteDSNValue(c.SSLCert) + " sslkey=" + quoteDSNValue(c.SSLKey)
+	}
+	if c.ApplicationName != "" {
+		dsn += " application_name=" + quoteDSNValue(c.ApplicationName)
+	}
+	if c.ConnectTimeout > 0 {
+		// Rounded up, since the driver reads whole seconds and treats 0 as no timeout
+		dsn += fmt.Sprintf(" connect_timeout=%d", int64((c.ConnectTimeout+time.Second-1)/time.Second))
+	}
+	if c.PgBouncer {
+		// Sends each parameterized query as a single unnamed statement instead of preparing it
+		// in a separate round trip, so no statement outlives the transaction PgBouncer assigned
+		dsn += " binary_parameters=yes"
+	}
+	return dsn
+}
+
+// TenantDSN returns the connection string for a pool whose connections use schema as search_path
+func (c *DatabaseConfig) TenantDSN(schema string) string {
+	return c.DSN() + " search_path=" + quoteDSNValue(schema)
+}
+
+// ReplicaDSN returns the connection string for the read replica, which shares every setting
+// with the primary but its address
+func (c *DatabaseConfig) ReplicaDSN() string {
+	replica := *c
+	replica.Host = c.ReplicaHost
+	if c.ReplicaPort != "" {
+		replica.Port = c.ReplicaPort
+	}
+	return replica.DSN()
+}
+
+// schemaNamePattern matches the unquoted identifiers accepted as tenant schemas
+var schemaNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// tablePrefixPattern matches the accepted table prefixes, short enough that the longest prefixed
+// index name stays within PostgreSQL's 63-byte identifier limit
+var tablePrefixPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,22}$`)
+
+// maxApplicationNameLen is the longest application_name Postgres keeps; longer names are truncated
+const maxApplicationNameLen = 63
+
+// quoteDSNValue quotes v for a key=value connection string, so it may contain spaces and quotes
+func quoteDSNValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
 }
 
 func getEnv(key, defaultValue string) string {
@@ -151,6 +1143,30 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvAsFloat(key string, defaultValue float64) float64 {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -163,15 +1179,48 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	return value
 }
 
-func getEnvAsDuration(key, defaultValue string) time.Duration {
-	valueStr := getEnv(key, defaultValue)
-	duration, err := time.ParseDuration(valueStr)
-	if err != nil {
-		// Fallback to parsing the default if provided value is invalid
-		duration, err = time.ParseDuration(defaultValue)
+// getEnvAsFloatSlice parses a comma-separated list of numbers, falling back to defaultValue if any is invalid
+func getEnvAsFloatSlice(key string, defaultValue []float64) []float64 {
+	parts := getEnvAsSlice(key, nil)
+	if len(parts) == 0 {
+		return defaultValue
+	}
+
+	values := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
 		if err != nil {
-			return 0
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// getEnvAsSlice parses a comma-separated variable, dropping empty entries
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, part := range strings.Split(valueStr, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
 		}
 	}
+	return values
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return defaultValue
+	}
 	return duration
 }
@@ -0,0 +1,641 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			RequestTimeout:  10 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			DBName:          "mockbank",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+		},
+		App: AppConfig{
+			Environment:        EnvDevelopment,
+			FailureRate:        0.05,
+			MinLatencyMS:       100,
+			MaxLatencyMS:       2000,
+			AuthExpiryHours:    168,
+			MaxAuthExpiryHours: 720,
+		},
+		Logger:   LoggerConfig{Level: "info", Format: LogFormatJSON},
+		Metadata: MetadataConfig{MaxBytes: 16 << 10},
+		Events:   EventsConfig{BufferSize: 1000},
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+}
+
+func TestLoad_MetricsAmountBuckets(t *testing.T) {
+	t.Setenv("METRICS_AMOUNT_BUCKETS", "500, 5000,50000")
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, []float64{500, 5000, 50000}, cfg.Metrics.AmountBuckets)
+}
+
+func TestLoad_InvalidEnvFailsFast(t *testing.T) {
+	t.Setenv("PORT", "http")
+	t.Setenv("DB_MAX_OPEN_CONNS", "0")
+
+	cfg, err := Load()
+
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "invalid configuration")
+	assert.Contains(t, err.Error(), "invalid server port")
+	assert.Contains(t, err.Error(), "max open connections")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		mutate      func(*Config)
+		name        string
+		errContains []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(*Config) {},
+		},
+		{
+			name:        "port out of range",
+			mutate:      func(c *Config) { c.Server.Port = "70000" },
+			errContains: []string{"invalid server port"},
+		},
+		{
+			name:        "zero read timeout",
+			mutate:      func(c *Config) { c.Server.ReadTimeout = 0 },
+			errContains: []string{"read timeout must be positive"},
+		},
+		{
+			name:        "zero shutdown timeout",
+			mutate:      func(c *Config) { c.Server.ShutdownTimeout = 0 },
+			errContains: []string{"server shutdown timeout must be positive"},
+		},
+		{
+			name:        "negative compression threshold",
+			mutate:      func(c *Config) { c.Server.Compression = true; c.Server.CompressionMinBytes = -1 },
+			errContains: []string{"compression min bytes cannot be negative"},
+		},
+		{
+			name:        "negative max concurrent requests",
+			mutate:      func(c *Config) { c.Server.MaxConcurrent = -1 },
+			errContains: []string{"max concurrent requests cannot be negative"},
+		},
+		{
+			name:        "empty metrics amount buckets",
+			mutate:      func(c *Config) { c.Metrics = MetricsConfig{Enabled: true} },
+			errContains: []string{"metrics amount buckets must not be empty"},
+		},
+		{
+			name:        "unordered metrics amount buckets",
+			mutate:      func(c *Config) { c.Metrics.AmountBuckets = []float64{1000, 100} },
+			errContains: []string{"metrics amount buckets must be strictly increasing"},
+		},
+		{
+			name:        "idempotent route on a safe method",
+			mutate:      func(c *Config) { c.Idempotency.Routes = []string{"GET /api/v1/transactions"} },
+			errContains: []string{"method must be POST, PUT, or PATCH"},
+		},
+		{
+			name:        "idempotent route without a path",
+			mutate:      func(c *Config) { c.Idempotency.Routes = []string{"/api/v1/refunds"} },
+			errContains: []string{"invalid idempotent route"},
+		},
+		{
+			name:        "negative transaction retries",
+			mutate:      func(c *Config) { c.Database.TxMaxRetries = -1 },
+			errContains: []string{"transaction retries cannot be negative"},
+		},
+		{
+			name:        "negative query timeout",
+			mutate:      func(c *Config) { c.Database.QueryTimeout = -time.Second },
+			errContains: []string{"query timeout cannot be negative"},
+		},
+		{
+			name:        "invalid tenant schema",
+			mutate:      func(c *Config) { c.Database.TenantSchemas = []string{"tenant_a", "public; DROP TABLE accounts"} },
+			errContains: []string{`invalid tenant schema "public; DROP TABLE accounts"`},
+		},
+		{
+			name: "tenant schemas through pgbouncer",
+			mutate: func(c *Config) {
+				c.Database.TenantSchemas = []string{"tenant_a"}
+				c.Database.PgBouncer = true
+			},
+			errContains: []string{"tenant schemas cannot be used through PgBouncer"},
+		},
+		{
+			name:        "invalid table prefix",
+			mutate:      func(c *Config) { c.Database.TablePrefix = "bank-1_" },
+			errContains: []string{`invalid table prefix "bank-1_"`},
+		},
+		{
+			name: "replica without max lag",
+			mutate: func(c *Config) {
+				c.Database.ReplicaHost = "replica"
+				c.Database.ReplicaMaxLag = 0
+			},
+			errContains: []string{"replica max lag must be positive"},
+		},
+		{
+			name:        "invalid replica port",
+			mutate:      func(c *Config) { c.Database.ReplicaPort = "99999" },
+			errContains: []string{"invalid database replica port"},
+		},
+		{
+			name:        "negative connect timeout",
+			mutate:      func(c *Config) { c.Database.ConnectTimeout = -time.Second },
+			errContains: []string{"connect timeout cannot be negative"},
+		},
+		{
+			name:        "tls key without cert",
+			mutate:      func(c *Config) { c.Server.TLSKeyFile = "server.key" },
+			errContains: []string{"must be set together"},
+		},
+		{
+			name: "idle conns above open conns",
+			mutate: func(c *Config) {
+				c.Database.MaxOpenConns = 2
+				c.Database.MaxIdleConns = 5
+			},
+			errContains: []string{"max idle connections (5) must be <= max open connections (2)"},
+		},
+		{
+			name:        "min conns above idle conns",
+			mutate:      func(c *Config) { c.Database.MinConns = 10 },
+			errContains: []string{"database min connections (10) must be <= max idle connections (5)"},
+		},
+		{
+			name:        "migrations through pgbouncer",
+			mutate:      func(c *Config) { c.Database.PgBouncer = true; c.Database.RunMigrations = true },
+			errContains: []string{"migrations cannot run through PgBouncer"},
+		},
+		{
+			name:        "malformed fx rate",
+			mutate:      func(c *Config) { c.FX.Rates = []string{"EUR/USD=1.08", "GBPUSD=1.27"} },
+			errContains: []string{`invalid FX rate "GBPUSD=1.27"`},
+		},
+		{
+			name:        "non-positive fx rate",
+			mutate:      func(c *Config) { c.FX.Rates = []string{"EUR/USD=0"} },
+			errContains: []string{"rate must be a positive decimal"},
+		},
+		{
+			name:        "negative max auth amount",
+			mutate:      func(c *Config) { c.Risk.MaxAuthAmount = -1 },
+			errContains: []string{"max auth amount cannot be negative"},
+		},
+		{
+			name:        "malformed per-currency max auth amount",
+			mutate:      func(c *Config) { c.Risk.MaxAuthAmounts = []string{"EUR=500000", "JPY:100"} },
+			errContains: []string{`invalid max auth amount "JPY:100"`},
+		},
+		{
+			name:        "non-positive per-currency max auth amount",
+			mutate:      func(c *Config) { c.Risk.MaxAuthAmounts = []string{"EUR=0"} },
+			errContains: []string{"amount must be a positive integer"},
+		},
+		{
+			name:        "invalid daily capture limit",
+			mutate:      func(c *Config) { c.Risk.DailyCaptureLimits = []string{"USD"} },
+			errContains: []string{`invalid daily capture limit "USD"`},
+		},
+		{
+			name:        "unknown daily limit timezone",
+			mutate:      func(c *Config) { c.Risk.DailyLimitTimezone = "Mars/Olympus_Mons" },
+			errContains: []string{"invalid daily limit timezone"},
+		},
+		{
+			name:        "velocity checks without a window",
+			mutate:      func(c *Config) { c.Risk.VelocityMaxAuths = 5; c.Risk.VelocityWindow = 0 },
+			errContains: []string{"velocity window must be positive"},
+		},
+		{
+			name:        "negative velocity threshold",
+			mutate:      func(c *Config) { c.Risk.VelocityMaxAuths = -1 },
+			errContains: []string{"velocity max authorizations cannot be negative"},
+		},
+		{
+			name: "webhook without secret",
+			mutate: func(c *Config) {
+				c.Webhook.URL = "https://example.com/hooks"
+				c.Webhook.Secret = ""
+			},
+			errContains: []string{"webhook secret is required"},
+		},
+		{
+			name:        "webhook with invalid url",
+			mutate:      func(c *Config) { c.Webhook.URL = "ftp://example.com"; c.Webhook.Secret = "s" },
+			errContains: []string{"invalid webhook URL"},
+		},
+		{
+			name:        "missing metadata schema file",
+			mutate:      func(c *Config) { c.Metadata.SchemaFile = "/nonexistent/schemas.json" },
+			errContains: []string{"failed to read metadata schema file"},
+		},
+		{
+			name:        "non-positive metadata max bytes",
+			mutate:      func(c *Config) { c.Metadata.MaxBytes = 0 },
+			errContains: []string{"metadata max bytes must be at least 1"},
+		},
+		{
+			name:        "body logging without a size cap",
+			mutate:      func(c *Config) { c.Logger.LogBodies = true; c.Logger.MaxLoggedBodySize = 0 },
+			errContains: []string{"max logged body size must be at least 1"},
+		},
+		{
+			name:        "auth expiry cap below the default",
+			mutate:      func(c *Config) { c.App.AuthExpiryHours = 168; c.App.MaxAuthExpiryHours = 24 },
+			errContains: []string{"max auth expiry hours (24) must be >= auth expiry hours (168)"},
+		},
+		{
+			name:        "negative archive age",
+			mutate:      func(c *Config) { c.Archive.After = -time.Hour },
+			errContains: []string{"transaction archive age cannot be negative"},
+		},
+		{
+			name:        "negative reconcile interval",
+			mutate:      func(c *Config) { c.Reconcile.Interval = -time.Minute },
+			errContains: []string{"reconcile interval cannot be negative"},
+		},
+		{
+			name:        "tracing endpoint without scheme",
+			mutate:      func(c *Config) { c.Tracing.Endpoint = "collector:4318" },
+			errContains: []string{"invalid tracing endpoint"},
+		},
+		{
+			name:        "empty event buffer",
+			mutate:      func(c *Config) { c.Events.BufferSize = 0 },
+			errContains: []string{"event buffer size must be at least 1"},
+		},
+		{
+			name:        "invalid grpc port",
+			mutate:      func(c *Config) { c.GRPC.Port = "grpc" },
+			errContains: []string{"invalid grpc port"},
+		},
+		{
+			name:        "grpc port shared with the http server",
+			mutate:      func(c *Config) { c.GRPC.Port = c.Server.Port },
+			errContains: []string{"grpc port must differ from the server port"},
+		},
+		{
+			name:        "malformed cvv key",
+			mutate:      func(c *Config) { c.CVV.Keys = []string{"1:c2hvcnQ="}; c.CVV.KeyVersion = 1 },
+			errContains: []string{"invalid CVV key for version 1"},
+		},
+		{
+			name: "cvv key version without key",
+			mutate: func(c *Config) {
+				c.CVV.Keys = []string{"1:" + base64.StdEncoding.EncodeToString(make([]byte, 32))}
+				c.CVV.KeyVersion = 2
+			},
+			errContains: []string{"CVV key version 2 has no configured key"},
+		},
+		{
+			name:        "unknown environment",
+			mutate:      func(c *Config) { c.App.Environment = "prod" },
+			errContains: []string{"invalid environment: prod"},
+		},
+		{
+			name: "seed in production",
+			mutate: func(c *Config) {
+				c.App.Environment = EnvProduction
+				c.Seed.File = "seed.yaml"
+			},
+			errContains: []string{"seed data cannot be loaded in production"},
+		},
+		{
+			name: "signing without api keys",
+			mutate: func(c *Config) {
+				c.Auth.SigningSecrets = []string{"key-one:secret"}
+				c.Auth.SignatureWindow = time.Minute
+			},
+			errContains: []string{"request signing requires API keys", "unknown API key"},
+		},
+		{
+			name:        "nonces without signing",
+			mutate:      func(c *Config) { c.Auth.RequireNonce = true },
+			errContains: []string{"request nonces require request signing"},
+		},
+		{
+			name: "api key without signing secret",
+			mutate: func(c *Config) {
+				c.Auth.APIKeys = []string{"key-one", "key-two"}
+				c.Auth.SigningSecrets = []string{"key-one:secret"}
+				c.Auth.SignatureWindow = time.Minute
+			},
+			errContains: []string{"API key has no signing secret"},
+		},
+		{
+			name:        "unknown sslmode",
+			mutate:      func(c *Config) { c.Database.SSLMode = "prefer" },
+			errContains: []string{"invalid database sslmode: prefer"},
+		},
+		{
+			name:        "verify-full without root certificate",
+			mutate:      func(c *Config) { c.Database.SSLMode = SSLModeVerifyFull },
+			errContains: []string{"sslmode verify-full requires a root certificate"},
+		},
+		{
+			name: "missing root certificate file",
+			mutate: func(c *Config) {
+				c.Database.SSLMode = SSLModeVerifyCA
+				c.Database.SSLRootCert = "/nonexistent/root.crt"
+			},
+			errContains: []string{"database root certificate", "no such file"},
+		},
+		{
+			name:        "ssl cert without key",
+			mutate:      func(c *Config) { c.Database.SSLCert = "/nonexistent/client.crt" },
+			errContains: []string{"database SSL cert and key must be set together"},
+		},
+		{
+			name:        "unknown log format",
+			mutate:      func(c *Config) { c.Logger.Format = "logfmt" },
+			errContains: []string{"invalid log format"},
+		},
+		{
+			name: "multiple errors aggregated",
+			mutate: func(c *Config) {
+				c.Database.DBName = ""
+				c.App.FailureRate = 2
+				c.Logger.Level = "verbose"
+			},
+			errContains: []string{"database name cannot be empty", "failure rate", "invalid log level"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(cfg)
+
+			err := cfg.Validate()
+
+			if len(tt.errContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.errContains {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestLoggerConfig_Format(t *testing.T) {
+	t.Run("json by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		slog.New((&LoggerConfig{Level: "info"}).newHandler(&buf)).Info("hello", "key", "value")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "hello", entry["msg"])
+		assert.NotContains(t, entry, slog.SourceKey)
+	})
+
+	t.Run("text with source", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := &LoggerConfig{Level: "info", Format: LogFormatText, AddSource: true}
+		slog.New(cfg.newHandler(&buf)).Info("hello", "key", "value")
+
+		assert.Contains(t, buf.String(), "msg=hello key=value")
+		assert.Contains(t, buf.String(), "source=")
+	})
+}
+
+func TestDatabaseConfig_DSN(t *testing.T) {
+	cfg := validConfig().Database
+
+	assert.NotContains(t, cfg.DSN(), "binary_parameters")
+
+	cfg.PgBouncer = true
+	assert.Contains(t, cfg.DSN(), " binary_parameters=yes")
+}
+
+func TestDatabaseConfig_TenantDSN(t *testing.T) {
+	cfg := validConfig().Database
+
+	assert.Equal(t, cfg.DSN()+" search_path='tenant_a'", cfg.TenantDSN("tenant_a"))
+	assert.NotContains(t, cfg.DSN(), "search_path")
+}
+
+func TestDatabaseConfig_ReplicaDSN(t *testing.T) {
+	cfg := validConfig().Database
+	cfg.Host = "primary"
+	cfg.Port = "5432"
+	cfg.ReplicaHost = "replica"
+
+	assert.Contains(t, cfg.ReplicaDSN(), "host=replica port=5432 ")
+
+	cfg.ReplicaPort = "5433"
+	assert.Contains(t, cfg.ReplicaDSN(), "host=replica port=5433 ")
+	assert.Contains(t, cfg.DSN(), "host=primary port=5432 ")
+}
+
+func TestDatabaseConfig_DSNConnectTimeout(t *testing.T) {
+	cfg := validConfig().Database
+
+	assert.NotContains(t, cfg.DSN(), "connect_timeout")
+
+	cfg.ConnectTimeout = 10 * time.Second
+	assert.Contains(t, cfg.DSN(), " connect_timeout=10")
+
+	cfg.ConnectTimeout = 1500 * time.Millisecond
+	assert.Contains(t, cfg.DSN(), " connect_timeout=2")
+}
+
+func TestDatabaseConfig_SSLVerifyFull(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{}
+	for _, name := range []string{"root.crt", "client.crt", "client.key"} {
+		files[name] = filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(files[name], []byte("pem"), 0o600))
+	}
+
+	cfg := validConfig()
+	cfg.Database.SSLMode = SSLModeVerifyFull
+	cfg.Database.SSLRootCert = files["root.crt"]
+	cfg.Database.SSLCert = files["client.crt"]
+	cfg.Database.SSLKey = files["client.key"]
+
+	require.NoError(t, cfg.Validate())
+	dsn := cfg.Database.DSN()
+	assert.Contains(t, dsn, " sslmode=verify-full")
+	assert.Contains(t, dsn, " sslrootcert='"+files["root.crt"]+"'")
+	assert.Contains(t, dsn, " sslcert='"+files["client.crt"]+"' sslkey='"+files["client.key"]+"'")
+}
+
+func TestDatabaseConfig_DSNApplicationName(t *testing.T) {
+	cfg := validConfig().Database
+
+	cfg.ApplicationName = ""
+	assert.NotContains(t, cfg.DSN(), "application_name")
+
+	cfg.ApplicationName = "bank-api"
+	assert.Contains(t, cfg.DSN(), " application_name='bank-api'")
+
+	cfg.ApplicationName = `bank api's \ worker`
+	assert.Contains(t, cfg.DSN(), ` application_name='bank api\'s \\ worker'`)
+}
+
+func TestDatabaseConfig_ApplicationNameTooLong(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.ApplicationName = strings.Repeat("a", maxApplicationNameLen+1)
+
+	assert.ErrorContains(t, cfg.Validate(), "application name")
+}
+
+func TestRiskConfig_ParseMaxAuthAmounts(t *testing.T) {
+	cfg := RiskConfig{MaxAuthAmounts: []string{"eur=500000", " JPY = 50000000 "}}
+
+	amounts, err := cfg.ParseMaxAuthAmounts()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"EUR": 500000, "JPY": 50000000}, amounts)
+}
+
+func TestLoad_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+server:
+  port: "9090"
+  read_timeout: 30s
+database:
+  name: filebank
+  max_open_conns: 10
+app:
+  failure_rate: 0
+auth:
+  api_keys: [file-key]
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("DB_NAME", "envbank")
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 15*time.Second, cfg.Server.WriteTimeout, "unset keys keep defaults")
+	assert.Equal(t, "envbank", cfg.Database.DBName, "env vars override file values")
+	assert.Equal(t, 10, cfg.Database.MaxOpenConns)
+	assert.Zero(t, cfg.App.FailureRate)
+	assert.Equal(t, []string{"file-key"}, cfg.Auth.APIKeys)
+}
+
+func TestLoad_FileErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		_, err := Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read config file")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("server:\n  prot: \"9090\"\n"), 0o600))
+		t.Setenv("CONFIG_FILE", path)
+
+		_, err := Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse config file")
+	})
+}
+
+func TestMetadataConfig_ParseSchemas(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "schemas.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("no file", func(t *testing.T) {
+		schemas, err := (&MetadataConfig{}).ParseSchemas()
+		require.NoError(t, err)
+		assert.Nil(t, schemas)
+	})
+
+	t.Run("per type and default", func(t *testing.T) {
+		path := write(t, `{"default": {"type": "object"}, "REFUND": {"type": "object", "required": ["reason"]}}`)
+
+		schemas, err := (&MetadataConfig{SchemaFile: path}).ParseSchemas()
+		require.NoError(t, err)
+		assert.Len(t, schemas, 2)
+		assert.Equal(t, []string{"reason"}, schemas["REFUND"].Required)
+	})
+
+	t.Run("every transaction type", func(t *testing.T) {
+		byType := make(map[string]any)
+		for _, txnType := range models.TransactionTypes {
+			byType[string(txnType)] = map[string]any{"type": "object"}
+		}
+		content, err := json.Marshal(byType)
+		require.NoError(t, err)
+
+		schemas, err := (&MetadataConfig{SchemaFile: write(t, string(content))}).ParseSchemas()
+		require.NoError(t, err)
+		for _, txnType := range models.TransactionTypes {
+			assert.Contains(t, schemas, string(txnType))
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		path := write(t, `{"TRANSFER": {"type": "object"}}`)
+
+		_, err := (&MetadataConfig{SchemaFile: path}).ParseSchemas()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown metadata schema key "TRANSFER"`)
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		path := write(t, `{"REFUND": {"type": "unknown"}}`)
+
+		_, err := (&MetadataConfig{SchemaFile: path}).ParseSchemas()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid metadata schema for "REFUND"`)
+	})
+
+	t.Run("malformed json", func(t *testing.T) {
+		path := write(t, `{"default": `)
+
+		_, err := (&MetadataConfig{SchemaFile: path}).ParseSchemas()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse metadata schema file")
+	})
+}
@@ -1,25 +1,36 @@
 package config
 
 import (
+	"io"
 	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+)
+
+// Log output formats accepted in LoggerConfig.Format
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
 )
 
 // NewLogger creates a new structured logger based on configuration
 func (c *LoggerConfig) NewLogger() *slog.Logger {
-	var handler slog.Handler
-
-	level := parseLogLevel(c.Level)
+	return slog.New(logging.NewContextHandler(c.newHandler(os.Stdout)))
+}
 
+// newHandler returns the handler for the configured format, defaulting to JSON
+func (c *LoggerConfig) newHandler(w io.Writer) slog.Handler {
 	opts := &slog.HandlerOptions{
-		Level:     level,
-		AddSource: level == slog.LevelDebug || level == slog.LevelError,
+		Level:     parseLogLevel(c.Level),
+		AddSource: c.AddSource,
 	}
 
-	handler = slog.NewJSONHandler(os.Stdout, opts)
-
-	return slog.New(handler)
+	if c.Format == LogFormatText {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
 }
 
 func parseLogLevel(level string) slog.Level {
@@ -0,0 +1,101 @@
+// Package cvvcrypt encrypts card verification values at rest with AES-256-GCM.
+//
+// Each ciphertext is prefixed with the version of the key that sealed it, so keys can be
+// rotated by adding a new version while older ones remain available for decryption.
+package cvvcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the required key length in bytes (AES-256)
+const KeySize = 32
+
+// minSealedLen is the shortest decoded ciphertext: version byte, standard GCM nonce and tag
+const minSealedLen = 1 + 12 + 16
+
+// ErrUnknownKeyVersion indicates a ciphertext was sealed with a key that is not configured
+var ErrUnknownKeyVersion = errors.New("unknown cvv key version")
+
+// Cipher encrypts and decrypts CVVs under a set of versioned keys
+type Cipher struct {
+	aeads   map[byte]cipher.AEAD
+	current byte
+}
+
+// NewCipher creates a Cipher that decrypts with any of keys and encrypts with the current version
+func NewCipher(keys map[byte][]byte, current byte) (*Cipher, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current cvv key version %d is not configured", current)
+	}
+
+	aeads := make(map[byte]cipher.AEAD, len(keys))
+	for version, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("cvv key version %d must be %d bytes, got %d", version, KeySize, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for cvv key version %d: %w", version, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM for cvv key version %d: %w", version, err)
+		}
+		aeads[version] = aead
+	}
+
+	return &Cipher{aeads: aeads, current: current}, nil
+}
+
+// EncryptCVV seals cvv with the current key, returning base64 of version || nonce || ciphertext
+func (c *Cipher) EncryptCVV(cvv string) (string, error) {
+	aead := c.aeads[c.current]
+
+	buf := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(cvv)+aead.Overhead())
+	buf[0] = c.current
+	if _, err := rand.Read(buf[1:]); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(buf, buf[1:], []byte(cvv), buf[:1])
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptCVV opens a value produced by EncryptCVV with the key version it was sealed under
+func (c *Cipher) DecryptCVV(stored string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil || len(data) < 1 {
+		return "", fmt.Errorf("malformed encrypted cvv")
+	}
+
+	aead, ok := c.aeads[data[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %d", ErrUnknownKeyVersion, data[0])
+	}
+	if len(data) < 1+aead.NonceSize()+aead.Overhead() {
+		return "", fmt.Errorf("malformed encrypted cvv")
+	}
+
+	nonce := data[1 : 1+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, data[1+aead.NonceSize():], data[:1])
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt cvv: %w", err)
+	}
+	return string(plain), nil
+}
+
+// KeyVersion returns the key version a stored value was sealed under
+// ok is false for values that are not ciphertexts, such as CVVs written before encryption was enabled.
+func KeyVersion(stored string) (version byte, ok bool) {
+	data, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil || len(data) < minSealedLen {
+		return 0, false
+	}
+	return data[0], true
+}
@@ -0,0 +1,89 @@
+package cvvcrypt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(map[byte][]byte{1: testKey(1)}, 1)
+	require.NoError(t, err)
+
+	stored, err := c.EncryptCVV("123")
+	require.NoError(t, err)
+	assert.NotContains(t, stored, "123")
+
+	again, err := c.EncryptCVV("123")
+	require.NoError(t, err)
+	assert.NotEqual(t, stored, again, "each encryption uses a fresh nonce")
+
+	plain, err := c.DecryptCVV(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "123", plain)
+
+	version, ok := KeyVersion(stored)
+	assert.True(t, ok)
+	assert.Equal(t, byte(1), version)
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	old, err := NewCipher(map[byte][]byte{1: testKey(1)}, 1)
+	require.NoError(t, err)
+	stored, err := old.EncryptCVV("4567")
+	require.NoError(t, err)
+
+	rotated, err := NewCipher(map[byte][]byte{1: testKey(1), 2: testKey(2)}, 2)
+	require.NoError(t, err)
+
+	plain, err := rotated.DecryptCVV(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "4567", plain, "values sealed under a retired key still decrypt")
+
+	resealed, err := rotated.EncryptCVV(plain)
+	require.NoError(t, err)
+	version, _ := KeyVersion(resealed)
+	assert.Equal(t, byte(2), version)
+
+	onlyNew, err := NewCipher(map[byte][]byte{2: testKey(2)}, 2)
+	require.NoError(t, err)
+	_, err = onlyNew.DecryptCVV(stored)
+	assert.ErrorIs(t, err, ErrUnknownKeyVersion)
+}
+
+func TestCipher_DecryptRejectsTampering(t *testing.T) {
+	c, err := NewCipher(map[byte][]byte{1: testKey(1)}, 1)
+	require.NoError(t, err)
+
+	other, err := NewCipher(map[byte][]byte{1: testKey(9)}, 1)
+	require.NoError(t, err)
+	stored, err := other.EncryptCVV("123")
+	require.NoError(t, err)
+
+	_, err = c.DecryptCVV(stored)
+	assert.Error(t, err, "wrong key")
+
+	_, err = c.DecryptCVV("123")
+	assert.Error(t, err, "plaintext is not a ciphertext")
+}
+
+func TestNewCipher_Errors(t *testing.T) {
+	_, err := NewCipher(map[byte][]byte{1: testKey(1)}, 2)
+	assert.ErrorContains(t, err, "current cvv key version 2 is not configured")
+
+	_, err = NewCipher(map[byte][]byte{1: []byte("short")}, 1)
+	assert.ErrorContains(t, err, "must be 32 bytes")
+}
+
+func TestKeyVersion_Plaintext(t *testing.T) {
+	for _, cvv := range []string{"123", "1234"} {
+		_, ok := KeyVersion(cvv)
+		assert.False(t, ok, cvv)
+	}
+}
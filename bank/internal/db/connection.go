@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/benx421/payment-gateway/bank/internal/config"
 	"github.com/lib/pq"
@@ -19,15 +20,20 @@ type Executor interface {
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
-// DB wraps the database connection pool
+// DB wraps the database connection pool, plus one pool per tenant schema and an optional read replica
 type DB struct {
 	*sql.DB
-	logger *slog.Logger
+	logger      *slog.Logger
+	replica     *replica
+	tenants     map[string]*sql.DB
+	tablePrefix string // Applied to the migrations; repositories get it through repository.NewTables
+	txRetries   int
 }
 
 // Tx wraps a database transaction
 type Tx struct {
 	*sql.Tx
+	ctx    context.Context // retained only to correlate commit/rollback logs with the request
 	logger *slog.Logger
 }
 
@@ -45,44 +51,141 @@ func Connect(ctx context.Context, cfg *config.DatabaseConfig, logger *slog.Logge
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	configurePool(db, cfg)
 
-	if err := db.PingContext(ctx); err != nil {
+	pingCtx := ctx
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		pingCtx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+	if err := db.PingContext(pingCtx); err != nil {
 		logger.Error("failed to ping database", "error", err)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if cfg.MinConns > 0 {
+		warmed, err := warmup(ctx, db, cfg.MinConns)
+		if err != nil {
+			logger.Warn("connection pool warmup incomplete", "warmed", warmed, "requested", cfg.MinConns, "error", err)
+		} else {
+			logger.Info("warmed connection pool", "warmed", warmed)
+		}
+	}
+
+	tenants, err := openTenantPools(cfg)
+	if err != nil {
+		logger.Error("failed to open tenant connection pools", "error", err)
+		return nil, err
+	}
+
+	var rep *replica
+	if cfg.ReplicaHost != "" {
+		// A replica that is down only costs read capacity, so it does not stop startup
+		rep, err = openReplica(ctx, cfg, logger)
+		if err != nil {
+			closePools(tenants) //nolint:errcheck // already failing
+			logger.Error("failed to open replica connection pool", "error", err)
+			return nil, err
+		}
+		status := rep.snapshot()
+		logger.Info("connected to read replica",
+			"host", cfg.ReplicaHost,
+			"serving_reads", status.ServingReads,
+			"lag", status.Lag,
+			"error", status.Err,
+		)
+	}
+
 	logger.Info("successfully connected to database",
 		"max_open_conns", cfg.MaxOpenConns,
 		"max_idle_conns", cfg.MaxIdleConns,
 		"conn_max_lifetime", cfg.ConnMaxLifetime,
+		"connect_timeout", cfg.ConnectTimeout,
+		"tenant_schemas", len(tenants),
 	)
 
 	return &DB{
-		DB:     db,
-		logger: logger,
+		DB:          db,
+		logger:      logger,
+		replica:     rep,
+		tenants:     tenants,
+		tablePrefix: cfg.TablePrefix,
+		txRetries:   max(cfg.TxMaxRetries, 0),
 	}, nil
 }
 
+// configurePool applies the configured pool limits, which each tenant pool gets in full
+func configurePool(pool *sql.DB, cfg *config.DatabaseConfig) {
+	pool.SetMaxOpenConns(cfg.MaxOpenConns)
+	pool.SetMaxIdleConns(cfg.MaxIdleConns)
+	pool.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+}
+
+// warmup opens n connections concurrently and returns them to the idle pool, so the first requests
+// after startup do not pay for the connection handshake.
+// It returns how many connections were opened; a partial warmup leaves the pool usable.
+func warmup(ctx context.Context, db *sql.DB, n int) (int, error) {
+	conns := make([]*sql.Conn, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			// Holding each connection until all are open keeps the pool from reusing one
+			conn, err := db.Conn(ctx)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			conns[i] = conn
+			errs[i] = conn.QueryRowContext(ctx, "SELECT 1").Scan(new(int))
+		})
+	}
+	wg.Wait()
+
+	warmed := 0
+	for i, conn := range conns {
+		if conn == nil {
+			continue
+		}
+		if errs[i] == nil {
+			warmed++
+		}
+		conn.Close() //nolint:errcheck // Returns the connection to the pool
+	}
+
+	return warmed, errors.Join(errs...)
+}
+
 // Close closes the database connection and logs the closure.
 func (db *DB) Close() error {
 	db.logger.Info("closing database connection")
-	return db.DB.Close()
+	errs := []error{db.DB.Close(), closePools(db.tenants)}
+	if db.replica != nil {
+		errs = append(errs, db.replica.pool.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// TablePrefix returns the prefix applied to every table name
+func (db *DB) TablePrefix() string {
+	return db.tablePrefix
 }
 
-// BeginTx starts a new database transaction with the specified isolation level
+// BeginTx starts a new database transaction with the specified isolation level, in the schema
+// of the tenant in ctx
 func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
-	tx, err := db.DB.BeginTx(ctx, opts)
+	tx, err := db.pool(ctx).BeginTx(ctx, opts)
 	if err != nil {
-		db.logger.Error("failed to begin transaction", "error", err)
+		db.logger.ErrorContext(ctx, "failed to begin transaction", "error", err)
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	db.logger.Debug("transaction started")
+	db.logger.DebugContext(ctx, "transaction started")
 	return &Tx{
 		Tx:     tx,
+		ctx:    ctx,
 		logger: db.logger,
 	}, nil
 }
@@ -90,11 +193,11 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 // Commit commits the transaction
 func (tx *Tx) Commit() error {
 	if err := tx.Tx.Commit(); err != nil {
-		tx.logger.Error("failed to commit transaction", "error", err)
+		tx.logger.ErrorContext(tx.ctx, "failed to commit transaction", "error", err)
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	tx.logger.Debug("transaction committed")
+	tx.logger.DebugContext(tx.ctx, "transaction committed")
 	return nil
 }
 
@@ -102,14 +205,14 @@ func (tx *Tx) Commit() error {
 func (tx *Tx) Rollback() error {
 	if err := tx.Tx.Rollback(); err != nil {
 		if errors.Is(err, sql.ErrTxDone) {
-			tx.logger.Debug("transaction already closed, ignoring rollback")
+			tx.logger.DebugContext(tx.ctx, "transaction already closed, ignoring rollback")
 			return nil
 		}
-		tx.logger.Error("failed to rollback transaction", "error", err)
+		tx.logger.ErrorContext(tx.ctx, "failed to rollback transaction", "error", err)
 		return fmt.Errorf("failed to rollback transaction: %w", err)
 	}
 
-	tx.logger.Debug("transaction rolled back")
+	tx.logger.DebugContext(tx.ctx, "transaction rolled back")
 	return nil
 }
 
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// warmupConnector counts the connections it opens and refuses any beyond limit
+type warmupConnector struct {
+	opened atomic.Int32
+	limit  int32
+}
+
+func (c *warmupConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.opened.Add(1) > c.limit {
+		return nil, errors.New("too many connections")
+	}
+	return warmupConn{}, nil
+}
+
+func (c *warmupConnector) Driver() driver.Driver { return nil }
+
+// warmupConn answers every query with a single row holding 1
+type warmupConn struct{}
+
+func (warmupConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (warmupConn) Close() error                        { return nil }
+func (warmupConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (warmupConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &oneRow{}, nil
+}
+
+type oneRow struct{ done bool }
+
+func (r *oneRow) Columns() []string { return []string{"?column?"} }
+func (r *oneRow) Close() error      { return nil }
+
+func (r *oneRow) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestWarmup(t *testing.T) {
+	t.Run("opens distinct connections and keeps them idle", func(t *testing.T) {
+		connector := &warmupConnector{limit: 10}
+		sqlDB := sql.OpenDB(connector)
+		defer sqlDB.Close()
+		sqlDB.SetMaxIdleConns(5)
+
+		warmed, err := warmup(context.Background(), sqlDB, 4)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, warmed)
+		assert.Equal(t, int32(4), connector.opened.Load())
+		assert.Equal(t, 4, sqlDB.Stats().Idle)
+	})
+
+	t.Run("reports a partial warmup", func(t *testing.T) {
+		connector := &warmupConnector{limit: 2}
+		sqlDB := sql.OpenDB(connector)
+		defer sqlDB.Close()
+		sqlDB.SetMaxIdleConns(5)
+
+		warmed, err := warmup(context.Background(), sqlDB, 4)
+
+		require.Error(t, err)
+		assert.Equal(t, 2, warmed)
+		assert.Equal(t, 2, sqlDB.Stats().Idle)
+	})
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// migrationLockID is the advisory lock key serializing concurrent migration runs
+const migrationLockID = 7_316_115_204
+
+// migration is a single up migration read from the migrations filesystem
+type migration struct {
+	name    string
+	sql     string
+	version uint64
+}
+
+// Migrate applies the up migrations in fsys newer than the recorded schema version, in order,
+// returning the versions it applied. Each migration runs in its own transaction.
+//
+// The version is tracked in a schema_migrations table compatible with golang-migrate, so databases
+// migrated with its CLI continue from where they left off. With a table prefix, the migrations
+// and the version table are prefixed like every query.
+func (db *DB) Migrate(ctx context.Context, fsys fs.FS) ([]uint64, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrations {
+		migrations[i].sql = PrefixTables(migrations[i].sql, db.tablePrefix)
+	}
+
+	// Session-level advisory locks belong to a connection, so hold one for the whole run
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration connection: %w", err)
+	}
+	defer conn.Close() //nolint:errcheck // returning the connection to the pool cannot meaningfully fail
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled
+		//nolint:errcheck // the lock is also released when the connection closes
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockID)
+	}()
+
+	if _, err := conn.ExecContext(ctx, PrefixTables(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)
+	`, db.tablePrefix)); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current uint64
+	var dirty bool
+	err = conn.QueryRowContext(ctx, PrefixTables("SELECT version, dirty FROM schema_migrations LIMIT 1", db.tablePrefix)).Scan(&current, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return nil, fmt.Errorf("schema version %d is dirty; fix the database and reset the version manually", current)
+	}
+
+	var applied []uint64
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, conn, m, db.tablePrefix); err != nil {
+			return applied, err
+		}
+		db.logger.InfoContext(ctx, "applied migration", "version", m.version, "name", m.name)
+		applied = append(applied, m.version)
+	}
+
+	if len(applied) == 0 {
+		db.logger.InfoContext(ctx, "database schema is up to date", "version", current)
+	}
+	return applied, nil
+}
+
+// applyMigration runs m and records its version in a single transaction
+func applyMigration(ctx context.Context, conn *sql.Conn, m migration, prefix string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", m.name, err)
+	}
+	defer func() {
+		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
+	}()
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx, PrefixTables("DELETE FROM schema_migrations", prefix)); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx, PrefixTables("INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", prefix), m.version); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m.name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", m.name, err)
+	}
+	return nil
+}
+
+// loadMigrations reads the up migrations in fsys, sorted by version
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	paths, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(paths))
+	for _, p := range paths {
+		name := path.Base(p)
+		versionStr, _, ok := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(versionStr, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s: must start with <version>_", name)
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		migrations = append(migrations, migration{name: name, sql: string(content), version: version})
+	}
+
+	slices.SortFunc(migrations, func(a, b migration) int {
+		return cmp.Compare(a.version, b.version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s",
+				migrations[i].version, migrations[i-1].name, migrations[i].name)
+		}
+	}
+
+	return migrations, nil
+}
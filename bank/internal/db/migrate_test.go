@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/benx421/payment-gateway/bank/internal/db/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_Sorted(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000010_later.up.sql":    {Data: []byte("SELECT 10")},
+		"000002_second.up.sql":   {Data: []byte("SELECT 2")},
+		"000002_second.down.sql": {Data: []byte("SELECT -2")},
+		"000001_first.up.sql":    {Data: []byte("SELECT 1")},
+	}
+
+	got, err := loadMigrations(fsys)
+
+	require.NoError(t, err)
+	require.Len(t, got, 3, "down migrations are ignored")
+	assert.Equal(t, []uint64{1, 2, 10}, []uint64{got[0].version, got[1].version, got[2].version})
+	assert.Equal(t, "SELECT 2", got[1].sql)
+}
+
+func TestLoadMigrations_Errors(t *testing.T) {
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := loadMigrations(fstest.MapFS{"init.up.sql": {Data: []byte("SELECT 1")}})
+		assert.ErrorContains(t, err, "invalid migration file name")
+	})
+
+	t.Run("duplicate version", func(t *testing.T) {
+		_, err := loadMigrations(fstest.MapFS{
+			"000001_a.up.sql": {Data: []byte("SELECT 1")},
+			"000001_b.up.sql": {Data: []byte("SELECT 1")},
+		})
+		assert.ErrorContains(t, err, "duplicate migration version 1")
+	})
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	got, err := loadMigrations(migrations.FS)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+	for i, m := range got {
+		assert.Equal(t, uint64(i+1), m.version, "embedded migrations are numbered without gaps")
+	}
+}
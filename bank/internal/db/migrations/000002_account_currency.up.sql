@@ -0,0 +1,2 @@
+-- Record the settlement currency of each account
+ALTER TABLE accounts ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
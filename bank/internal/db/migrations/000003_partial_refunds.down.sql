@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_reference_type_unique;
+
+CREATE UNIQUE INDEX idx_transactions_reference_type_unique ON transactions(reference_id, type)
+WHERE type IN ('CAPTURE', 'VOID', 'REFUND') AND reference_id IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Serve per-account listings ordered by creation time, including keyset pagination on (created_at, id)
+CREATE INDEX idx_transactions_account_created ON transactions(account_id, created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+-- Allow several partial captures per authorization; the capture service caps their total
+DROP INDEX IF EXISTS idx_transactions_reference_type_unique;
+
+CREATE UNIQUE INDEX idx_transactions_reference_type_unique ON transactions(reference_id, type)
+WHERE type = 'VOID' AND reference_id IS NOT NULL;
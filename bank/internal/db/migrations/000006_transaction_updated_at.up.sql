@@ -0,0 +1,4 @@
+-- Track when a transaction's status last changed
+ALTER TABLE transactions ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE transactions SET updated_at = created_at;
@@ -0,0 +1 @@
+ALTER TABLE accounts ALTER COLUMN cvv TYPE VARCHAR(3);
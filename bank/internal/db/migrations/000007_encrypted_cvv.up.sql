@@ -0,0 +1,2 @@
+-- Widen cvv to hold base64 AES-GCM ciphertexts alongside legacy plaintext values
+ALTER TABLE accounts ALTER COLUMN cvv TYPE TEXT;
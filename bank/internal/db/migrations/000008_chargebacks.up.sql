@@ -0,0 +1,5 @@
+-- A capture can be disputed once; FindByReferenceID relies on this to look chargebacks up
+DROP INDEX IF EXISTS idx_transactions_reference_type_unique;
+
+CREATE UNIQUE INDEX idx_transactions_reference_type_unique ON transactions(reference_id, type)
+WHERE type IN ('VOID', 'CHARGEBACK') AND reference_id IS NOT NULL;
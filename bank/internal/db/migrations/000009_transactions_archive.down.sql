@@ -0,0 +1,9 @@
+INSERT INTO transactions (
+    id, account_id, type, amount_cents, currency,
+    reference_id, status, expires_at, metadata, created_at, updated_at
+)
+SELECT id, account_id, type, amount_cents, currency,
+       reference_id, status, expires_at, metadata, created_at, updated_at
+FROM transactions_archive;
+
+DROP TABLE IF EXISTS transactions_archive;
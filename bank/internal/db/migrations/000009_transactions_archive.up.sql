@@ -0,0 +1,18 @@
+-- Settled transactions are moved here by TransactionRepository.Archive to keep the hot table small
+CREATE TABLE transactions_archive (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    type VARCHAR(20) NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reference_id UUID,
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP,
+    metadata JSONB,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_transactions_archive_account_created ON transactions_archive(account_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_archive_reference_id ON transactions_archive(reference_id);
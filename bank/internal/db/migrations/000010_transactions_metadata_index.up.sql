@@ -0,0 +1,2 @@
+-- Serve metadata containment lookups (metadata @> ...), such as finding transactions by order_id
+CREATE INDEX IF NOT EXISTS idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
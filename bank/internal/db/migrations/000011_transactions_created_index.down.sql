@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_archive_created;
+DROP INDEX IF EXISTS idx_transactions_created;
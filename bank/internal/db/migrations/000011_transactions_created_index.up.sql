@@ -0,0 +1,3 @@
+-- Serve date range exports across all accounts
+CREATE INDEX IF NOT EXISTS idx_transactions_created ON transactions(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_created ON transactions_archive(created_at, id);
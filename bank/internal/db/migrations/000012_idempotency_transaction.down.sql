@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_idempotency_keys_transaction_id;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS transaction_id;
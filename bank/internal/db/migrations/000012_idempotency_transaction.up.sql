@@ -0,0 +1,5 @@
+-- Link a stored response to the transaction it created, so support can trace a transaction back to its request.
+-- No foreign key: transactions move to transactions_archive while their idempotency records may remain.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS transaction_id UUID;
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_transaction_id ON idempotency_keys(transaction_id)
+WHERE transaction_id IS NOT NULL;
@@ -0,0 +1,14 @@
+DROP VIEW IF EXISTS accounts_with_balance;
+
+ALTER TABLE accounts
+    ADD COLUMN balance_cents BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN available_balance_cents BIGINT NOT NULL DEFAULT 0;
+
+-- Balances in currencies other than the primary one have nowhere to go and are dropped
+UPDATE accounts a
+SET balance_cents = b.balance_cents,
+    available_balance_cents = b.available_balance_cents
+FROM balances b
+WHERE b.account_id = a.id AND b.currency = a.currency;
+
+DROP TABLE IF EXISTS balances;
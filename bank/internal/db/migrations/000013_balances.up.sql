@@ -0,0 +1,25 @@
+-- Hold balances per (account, currency) so an account can keep funds in several currencies.
+-- accounts.currency stays as the account's primary currency.
+CREATE TABLE balances (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL,
+    balance_cents BIGINT NOT NULL DEFAULT 0,
+    available_balance_cents BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, currency)
+);
+
+INSERT INTO balances (account_id, currency, balance_cents, available_balance_cents, created_at, updated_at)
+SELECT id, currency, balance_cents, available_balance_cents, created_at, updated_at
+FROM accounts;
+
+ALTER TABLE accounts DROP COLUMN balance_cents, DROP COLUMN available_balance_cents;
+
+-- Single-currency compatibility: each account with its balance in its primary currency, in the old accounts shape
+CREATE VIEW accounts_with_balance AS
+SELECT a.id, a.account_number, a.cvv, a.expiry_month, a.expiry_year,
+       b.balance_cents, b.available_balance_cents, a.currency,
+       a.created_at, GREATEST(a.updated_at, b.updated_at) AS updated_at
+FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS balance_audit;
+DROP FUNCTION IF EXISTS balance_audit_append_only();
@@ -0,0 +1,25 @@
+-- Who changed a balance and why, one row per adjustment. Written alongside the ledger, not derived from it.
+-- No foreign key to accounts so the trail outlives the account.
+CREATE TABLE balance_audit (
+    id BIGSERIAL PRIMARY KEY,
+    account_id UUID NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    balance_delta_cents BIGINT NOT NULL,
+    available_balance_delta_cents BIGINT NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_balance_audit_account ON balance_audit(account_id, created_at);
+
+CREATE FUNCTION balance_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'balance_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER balance_audit_append_only
+    BEFORE UPDATE OR DELETE ON balance_audit
+    FOR EACH ROW EXECUTE FUNCTION balance_audit_append_only();
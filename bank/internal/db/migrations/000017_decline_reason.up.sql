@@ -0,0 +1,3 @@
+-- Why a DECLINED authorization was refused, e.g. insufficient_funds. NULL for every other transaction.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS decline_reason TEXT;
+ALTER TABLE transactions_archive ADD COLUMN IF NOT EXISTS decline_reason TEXT;
@@ -0,0 +1,7 @@
+-- Nonces of signed requests, each accepted once. Rows only need to outlive the signature window.
+CREATE TABLE request_nonces (
+    nonce VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_request_nonces_created_at ON request_nonces(created_at);
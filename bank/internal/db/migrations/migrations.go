@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL schema migrations so the binary is self-contained.
+//
+// Files are named <version>_<description>.<up|down>.sql, matching golang-migrate's layout.
+package migrations
+
+import "embed"
+
+// FS holds every migration file in this directory
+//
+//go:embed *.sql
+var FS embed.FS
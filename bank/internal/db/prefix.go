@@ -0,0 +1,22 @@
+package db
+
+import "regexp"
+
+// prefixedNames matches every schema-wide name the migrations create: tables, the view, the trigger
+// function and, through their shared idx_ stem, the indexes. Constraint names derive from their
+// table, so prefixing the table covers them.
+var prefixedNames = regexp.MustCompile(`\b(?:(accounts_with_balance|accounts|balance_audit_append_only|balance_audit|balances|idempotency_keys|request_nonces|schema_migrations|transactions_archive|transactions)\b|idx_)`)
+
+// PrefixTables returns query with prefix applied to each name the migrations create, so several
+// instances with different prefixes can share one schema. An empty prefix returns query unchanged.
+func PrefixTables(query, prefix string) string {
+	if prefix == "" {
+		return query
+	}
+	return prefixedNames.ReplaceAllStringFunc(query, func(name string) string {
+		if name == "idx_" {
+			return "idx_" + prefix
+		}
+		return prefix + name
+	})
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/db/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixTables(t *testing.T) {
+	query := `SELECT t.id FROM transactions t JOIN accounts_with_balance a ON a.id = t.account_id
+		WHERE t.transaction_id IS NULL AND NOT EXISTS (SELECT 1 FROM transactions_archive)`
+
+	assert.Equal(t, query, PrefixTables(query, ""))
+	assert.Equal(t, `SELECT t.id FROM staging_transactions t JOIN staging_accounts_with_balance a ON a.id = t.account_id
+		WHERE t.transaction_id IS NULL AND NOT EXISTS (SELECT 1 FROM staging_transactions_archive)`,
+		PrefixTables(query, "staging_"))
+	assert.Equal(t, "CREATE INDEX idx_staging_transactions_created ON staging_transactions (created_at)",
+		PrefixTables("CREATE INDEX idx_transactions_created ON transactions (created_at)", "staging_"))
+}
+
+// createdName matches the name of each object a migration creates
+var createdName = regexp.MustCompile(`(?i)CREATE (?:UNIQUE )?(?:TABLE|INDEX|VIEW|FUNCTION|TRIGGER)(?: IF NOT EXISTS)? (\w+)`)
+
+func TestPrefixTables_Migrations(t *testing.T) {
+	const prefix = "abcdefghijklmnopqrstuv_" // longest accepted prefix
+
+	got, err := loadMigrations(migrations.FS)
+	require.NoError(t, err)
+
+	for _, m := range got {
+		for _, match := range createdName.FindAllStringSubmatch(PrefixTables(m.sql, prefix), -1) {
+			name := match[1]
+			assert.True(t, strings.HasPrefix(name, prefix) || strings.HasPrefix(name, "idx_"+prefix),
+				"%s creates %s without the table prefix", m.name, name)
+			assert.LessOrEqual(t, len(name), 63, "%s creates %s, longer than an identifier", m.name, name)
+		}
+	}
+}
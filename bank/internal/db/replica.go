@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/config"
+)
+
+// replicaCheckInterval is how often MonitorReplica measures replication lag
+const replicaCheckInterval = 2 * time.Second
+
+// replicaLagQuery returns the replica's replay lag in seconds. A replica that has replayed
+// everything it received is caught up even when the primary has been idle since its last commit.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// ReplicaStatus is the outcome of the latest replication lag check
+type ReplicaStatus struct {
+	CheckedAt    time.Time
+	Err          error // Set when the lag could not be measured
+	Lag          time.Duration
+	MaxLag       time.Duration
+	ServingReads bool // Whether Reader sends reads to the replica
+}
+
+// replica is a read-only pool that serves reads only while its lag is within maxLag
+type replica struct {
+	pool   *sql.DB
+	status ReplicaStatus
+	mu     sync.RWMutex
+}
+
+// openReplica opens the replica pool and measures its lag once, so reads are not routed to it
+// before its first check
+func openReplica(ctx context.Context, cfg *config.DatabaseConfig, logger *slog.Logger) (*replica, error) {
+	pool, err := sql.Open("postgres", cfg.ReplicaDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replica connection: %w", err)
+	}
+	configurePool(pool, cfg)
+
+	r := &replica{pool: pool, status: ReplicaStatus{MaxLag: cfg.ReplicaMaxLag}}
+	r.check(ctx, logger)
+	return r, nil
+}
+
+// check measures the replica's lag and decides whether it may serve reads
+func (r *replica) check(ctx context.Context, logger *slog.Logger) {
+	var seconds float64
+	err := r.pool.QueryRowContext(ctx, replicaLagQuery).Scan(&seconds)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wasServing := r.status.ServingReads
+	r.status.CheckedAt = time.Now().UTC()
+	r.status.Err = err
+	if err != nil {
+		r.status.ServingReads = false
+	} else {
+		r.status.Lag = time.Duration(seconds * float64(time.Second))
+		r.status.ServingReads = r.status.Lag <= r.status.MaxLag
+	}
+
+	switch {
+	case err != nil && wasServing:
+		logger.WarnContext(ctx, "replica check failed, reading from primary", "error", err)
+	case err == nil && wasServing && !r.status.ServingReads:
+		logger.WarnContext(ctx, "replica lag over threshold, reading from primary",
+			"lag", r.status.Lag, "max_lag", r.status.MaxLag)
+	case r.status.ServingReads && !wasServing:
+		logger.InfoContext(ctx, "replica caught up, reading from replica", "lag", r.status.Lag)
+	}
+}
+
+func (r *replica) snapshot() ReplicaStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// Reader returns where read-only queries for ctx should run: the replica while it is within its
+// lag threshold, and the primary otherwise. Tenant pools have no replica, so tenant reads
+// always go to the primary.
+func (db *DB) Reader(ctx context.Context) Executor {
+	if db.replica == nil {
+		return db
+	}
+	if _, ok := TenantFromContext(ctx); ok {
+		return db
+	}
+	if !db.replica.snapshot().ServingReads {
+		return db
+	}
+	return db.replica.pool
+}
+
+// ReplicaStatus returns the latest replica lag check, and false when no replica is configured
+func (db *DB) ReplicaStatus() (ReplicaStatus, bool) {
+	if db.replica == nil {
+		return ReplicaStatus{}, false
+	}
+	return db.replica.snapshot(), true
+}
+
+// MonitorReplica measures replica lag every replicaCheckInterval until ctx is cancelled.
+// It returns immediately when no replica is configured.
+func (db *DB) MonitorReplica(ctx context.Context) {
+	if db.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, replicaCheckInterval)
+			db.replica.check(checkCtx, db.logger)
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReplicaTestDB returns a DB whose replica reports one second of lag, the value every
+// warmupConn query returns
+func newReplicaTestDB(t *testing.T, maxLag time.Duration) (*DB, *sql.DB) {
+	t.Helper()
+	replicaPool := sql.OpenDB(&warmupConnector{limit: 10})
+	database := NewTestDB(sql.OpenDB(&warmupConnector{limit: 10}))
+	database.replica = &replica{pool: replicaPool, status: ReplicaStatus{MaxLag: maxLag}}
+	t.Cleanup(func() { database.Close() })
+	return database, replicaPool
+}
+
+func TestReader(t *testing.T) {
+	t.Run("without a replica reads use the primary", func(t *testing.T) {
+		database := NewTestDB(sql.OpenDB(&warmupConnector{limit: 10}))
+		defer database.Close()
+
+		assert.Same(t, database, database.Reader(context.Background()))
+		_, ok := database.ReplicaStatus()
+		assert.False(t, ok)
+	})
+
+	t.Run("replica within its lag threshold serves reads", func(t *testing.T) {
+		database, replicaPool := newReplicaTestDB(t, 5*time.Second)
+		database.replica.check(context.Background(), database.logger)
+
+		status, ok := database.ReplicaStatus()
+		require.True(t, ok)
+		require.NoError(t, status.Err)
+		assert.Equal(t, time.Second, status.Lag)
+		assert.True(t, status.ServingReads)
+		assert.False(t, status.CheckedAt.IsZero())
+		assert.Same(t, replicaPool, database.Reader(context.Background()))
+	})
+
+	t.Run("lagging replica sends reads to the primary", func(t *testing.T) {
+		database, _ := newReplicaTestDB(t, 500*time.Millisecond)
+		database.replica.check(context.Background(), database.logger)
+
+		status, _ := database.ReplicaStatus()
+		assert.False(t, status.ServingReads)
+		assert.Same(t, database, database.Reader(context.Background()))
+	})
+
+	t.Run("unreachable replica sends reads to the primary", func(t *testing.T) {
+		database, replicaPool := newReplicaTestDB(t, 5*time.Second)
+		database.replica.check(context.Background(), database.logger)
+		require.NoError(t, replicaPool.Close())
+
+		database.replica.check(context.Background(), database.logger)
+
+		status, _ := database.ReplicaStatus()
+		assert.Error(t, status.Err)
+		assert.False(t, status.ServingReads)
+		assert.Same(t, database, database.Reader(context.Background()))
+	})
+
+	t.Run("tenant reads use the primary", func(t *testing.T) {
+		database, _ := newReplicaTestDB(t, 5*time.Second)
+		database.tenants = map[string]*sql.DB{"tenant_a": sql.OpenDB(&warmupConnector{limit: 10})}
+		database.replica.check(context.Background(), database.logger)
+
+		ctx, err := database.WithTenant(context.Background(), "tenant_a")
+		require.NoError(t, err)
+		assert.Same(t, database, database.Reader(ctx))
+	})
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/benx421/payment-gateway/bank/internal/config"
+)
+
+// ErrUnknownTenant is returned by WithTenant for a tenant without a configured schema
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+type tenantKey struct{}
+
+// tenant is the context value set by WithTenant
+type tenant struct {
+	pool *sql.DB
+	name string
+}
+
+// WithTenant returns a context whose queries through db run in the named tenant's schema.
+// Only schemas listed in the database config are accepted, so the name never reaches SQL unchecked.
+func (db *DB) WithTenant(ctx context.Context, name string) (context.Context, error) {
+	pool, ok := db.tenants[name]
+	if !ok {
+		return ctx, ErrUnknownTenant
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant{pool: pool, name: name}), nil
+}
+
+// TenantFromContext returns the tenant set by WithTenant, if any
+func TenantFromContext(ctx context.Context) (string, bool) {
+	t, ok := ctx.Value(tenantKey{}).(tenant)
+	return t.name, ok
+}
+
+// ExecContext runs query in the schema of the tenant in ctx
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.pool(ctx).ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs query in the schema of the tenant in ctx
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.pool(ctx).QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs query in the schema of the tenant in ctx
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.pool(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// pool returns the connection pool of the tenant in ctx, or the default pool when there is none
+func (db *DB) pool(ctx context.Context) *sql.DB {
+	if t, ok := ctx.Value(tenantKey{}).(tenant); ok {
+		return t.pool
+	}
+	return db.DB
+}
+
+// openTenantPools opens a pool per tenant schema, each with search_path set on every connection,
+// so no connection is ever shared between tenants
+func openTenantPools(cfg *config.DatabaseConfig) (map[string]*sql.DB, error) {
+	pools := make(map[string]*sql.DB, len(cfg.TenantSchemas))
+	for _, schema := range cfg.TenantSchemas {
+		pool, err := sql.Open("postgres", cfg.TenantDSN(schema))
+		if err != nil {
+			closePools(pools) //nolint:errcheck // already failing
+			return nil, fmt.Errorf("failed to open pool for tenant %s: %w", schema, err)
+		}
+		configurePool(pool, cfg)
+		pools[schema] = pool
+	}
+	return pools, nil
+}
+
+func closePools(pools map[string]*sql.DB) error {
+	var errs []error
+	for _, pool := range pools {
+		errs = append(errs, pool.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTenant(t *testing.T) {
+	defaultConnector := &warmupConnector{limit: 10}
+	tenantConnector := &warmupConnector{limit: 10}
+	defaultPool := sql.OpenDB(defaultConnector)
+	tenantPool := sql.OpenDB(tenantConnector)
+
+	database := NewTestDB(defaultPool)
+	database.tenants = map[string]*sql.DB{"tenant_a": tenantPool}
+	defer database.Close()
+
+	_, err := database.WithTenant(context.Background(), "tenant_b")
+	require.ErrorIs(t, err, ErrUnknownTenant)
+
+	ctx, err := database.WithTenant(context.Background(), "tenant_a")
+	require.NoError(t, err)
+	name, ok := TenantFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "tenant_a", name)
+
+	var one int
+	require.NoError(t, database.QueryRowContext(ctx, "SELECT 1").Scan(&one))
+	assert.Equal(t, int32(1), tenantConnector.opened.Load(), "tenant queries use the tenant pool")
+	assert.Equal(t, int32(0), defaultConnector.opened.Load())
+
+	require.NoError(t, database.QueryRowContext(context.Background(), "SELECT 1").Scan(&one))
+	assert.Equal(t, int32(1), defaultConnector.opened.Load(), "queries without a tenant use the default pool")
+
+	_, ok = TenantFromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Base delay before the first retry of a transaction; later retries back off exponentially
+const retryBaseDelay = 20 * time.Millisecond
+
+// ReadCommittedTx returns options for transactions that lock every row they depend on with
+// SELECT ... FOR UPDATE before reading it, so later statements cannot observe a stale value.
+// Voids, refunds and chargebacks run at this level: each locks its parent transaction first,
+// which serializes every change derived from it.
+func ReadCommittedTx() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: sql.LevelReadCommitted}
+}
+
+// SerializableTx returns options for transactions that must behave as if they ran one at a time.
+// Authorizations and captures run at this level because they read and move an account's balances
+// and an authorization's remaining amount across several statements, where a concurrent writer
+// missed by the row locks would let the balances drift. Conflicting transactions fail with a
+// serialization error, so they should be run through WithTransaction to be retried.
+func SerializableTx() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: sql.LevelSerializable}
+}
+
+// IsRetryable reports whether err is a transient PostgreSQL failure that is expected to succeed
+// when the whole transaction is run again: a serialization failure, a detected deadlock, or a
+// lock that could not be acquired within lock_timeout
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	case "40001", // serialization_failure
+		"40P01", // deadlock_detected
+		"55P03": // lock_not_available
+		return true
+	default:
+		return false
+	}
+}
+
+// SetTxRetries sets how many times WithTransaction reruns a transaction that failed with a
+// retryable error. 0 disables retries
+func (db *DB) SetTxRetries(n int) {
+	db.txRetries = max(n, 0)
+}
+
+// WithTransaction runs fn in a transaction, committing if it returns nil and rolling back otherwise
+// When fn or the commit fails with an error for which IsRetryable holds, the transaction is rolled
+// back and run again, up to the configured number of retries, after a jittered exponential backoff.
+// fn must therefore be safe to call more than once. Errors from fn are returned unchanged.
+func (db *DB) WithTransaction(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	for attempt := 0; ; attempt++ {
+		err := db.runTx(ctx, opts, fn)
+		if err == nil || !IsRetryable(err) || attempt >= db.txRetries {
+			return err
+		}
+
+		delay := retryDelay(attempt)
+		db.logger.WarnContext(ctx, "retrying transaction after transient failure",
+			"error", err,
+			"attempt", attempt+1,
+			"delay", delay,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (db *DB) runTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// retryDelay picks a random delay of up to retryBaseDelay doubled once per earlier attempt, so
+// transactions that conflicted with each other do not collide again on their retries
+func retryDelay(attempt int) time.Duration {
+	ceiling := retryBaseDelay << min(attempt, 6)
+	return ceiling/2 + rand.N(ceiling/2) //nolint:gosec // jitter does not need a cryptographic source
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver hands out connections whose transactions record how they ended and fail their
+// commits with the queued errors, so retries can be exercised without a database
+type fakeDriver struct {
+	commitErrs []error
+	commits    int
+	rollbacks  int
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return &fakeTx{d: c.d}, nil }
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return &fakeTx{d: c.d}, nil
+}
+
+type fakeTx struct{ d *fakeDriver }
+
+func (tx *fakeTx) Commit() error {
+	if len(tx.d.commitErrs) > 0 {
+		err := tx.d.commitErrs[0]
+		tx.d.commitErrs = tx.d.commitErrs[1:]
+		return err
+	}
+	tx.d.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.d.rollbacks++
+	return nil
+}
+
+type fakeConnector struct{ d *fakeDriver }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c fakeConnector) Driver() driver.Driver                        { return c.d }
+
+func newFakeDB(t *testing.T, retries int) (*DB, *fakeDriver) {
+	t.Helper()
+
+	d := &fakeDriver{}
+	sqlDB := sql.OpenDB(fakeConnector{d: d})
+	t.Cleanup(func() { _ = sqlDB.Close() }) //nolint:errcheck // test cleanup
+
+	database := NewTestDB(sqlDB)
+	database.SetTxRetries(retries)
+	return database, d
+}
+
+var errSerialization = &pq.Error{Code: "40001", Message: "could not serialize access"}
+
+func TestIsolationOptions(t *testing.T) {
+	assert.Equal(t, sql.LevelReadCommitted, ReadCommittedTx().Isolation)
+	assert.Equal(t, sql.LevelSerializable, SerializableTx().Isolation)
+	assert.NotSame(t, SerializableTx(), SerializableTx(), "callers get their own options to modify")
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(errSerialization))
+	assert.True(t, IsRetryable(&pq.Error{Code: "40P01"}), "deadlock_detected")
+	assert.True(t, IsRetryable(&pq.Error{Code: "55P03"}), "lock_not_available")
+	assert.True(t, IsRetryable(fmt.Errorf("commit: %w", errSerialization)), "wrapped")
+
+	assert.False(t, IsRetryable(&pq.Error{Code: "23505"}), "unique_violation")
+	assert.False(t, IsRetryable(errors.New("40001")))
+	assert.False(t, IsRetryable(nil))
+}
+
+func TestWithTransaction(t *testing.T) {
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		database, d := newFakeDB(t, 2)
+
+		calls := 0
+		err := database.WithTransaction(context.Background(), opts, func(*Tx) error {
+			calls++
+			if calls < 3 {
+				return fmt.Errorf("update balance: %w", errSerialization)
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 1, d.commits)
+		assert.Equal(t, 2, d.rollbacks, "failed attempts are rolled back")
+	})
+
+	t.Run("retries failed commits", func(t *testing.T) {
+		database, d := newFakeDB(t, 1)
+		d.commitErrs = []error{errSerialization}
+
+		calls := 0
+		err := database.WithTransaction(context.Background(), opts, func(*Tx) error {
+			calls++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 1, d.commits)
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		database, d := newFakeDB(t, 2)
+
+		calls := 0
+		err := database.WithTransaction(context.Background(), opts, func(*Tx) error {
+			calls++
+			return errSerialization
+		})
+
+		assert.ErrorIs(t, err, errSerialization)
+		assert.True(t, IsRetryable(err), "callers can still report the failure as retryable")
+		assert.Equal(t, 3, calls)
+		assert.Zero(t, d.commits)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		database, _ := newFakeDB(t, 2)
+
+		calls := 0
+		errBusiness := errors.New("insufficient funds")
+		err := database.WithTransaction(context.Background(), opts, func(*Tx) error {
+			calls++
+			return errBusiness
+		})
+
+		assert.Same(t, errBusiness, err, "errors from fn are returned unchanged")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		database, _ := newFakeDB(t, 0)
+
+		calls := 0
+		err := database.WithTransaction(context.Background(), opts, func(*Tx) error {
+			calls++
+			return errSerialization
+		})
+
+		assert.ErrorIs(t, err, errSerialization)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		database, _ := newFakeDB(t, 5)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		calls := 0
+		err := database.WithTransaction(ctx, opts, func(*Tx) error {
+			calls++
+			cancel()
+			return errSerialization
+		})
+
+		assert.ErrorIs(t, err, errSerialization)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt := range 10 {
+		ceiling := retryBaseDelay << min(attempt, 6)
+		for range 20 {
+			delay := retryDelay(attempt)
+			assert.GreaterOrEqual(t, delay, ceiling/2)
+			assert.Less(t, delay, ceiling)
+		}
+	}
+	assert.Less(t, retryDelay(100), 2*time.Second, "backoff is capped")
+}
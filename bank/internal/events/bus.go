@@ -0,0 +1,158 @@
+// Package events publishes transaction lifecycle events to in-process subscribers.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+)
+
+// Type identifies a transaction lifecycle event
+type Type string
+
+// Event types
+const (
+	TypeAuthorized  Type = "authorized"
+	TypeCaptured    Type = "captured"
+	TypeVoided      Type = "voided"
+	TypeRefunded    Type = "refunded"
+	TypeChargedBack Type = "charged_back"
+)
+
+// Event reports a committed change to a transaction
+type Event struct {
+	OccurredAt  time.Time
+	Transaction *models.Transaction
+	Type        Type
+}
+
+// Handler receives published events. Handlers run on the bus worker and should return quickly.
+type Handler func(ctx context.Context, event Event)
+
+type envelope struct {
+	ctx   context.Context
+	event Event
+}
+
+// Bus fans events out to registered handlers from a background worker.
+//
+// Publish never blocks: when the buffer is full the event is dropped and logged.
+// Buffered events are held in memory only and are lost on shutdown.
+type Bus struct {
+	logger   *slog.Logger
+	queue    chan envelope
+	done     chan struct{}
+	handlers map[Type][]Handler
+	all      []Handler
+	mu       sync.RWMutex
+}
+
+// NewBus creates a Bus buffering up to size events. Run must be called to start delivering.
+func NewBus(size int, logger *slog.Logger) *Bus {
+	return &Bus{
+		logger:   logger,
+		queue:    make(chan envelope, size),
+		done:     make(chan struct{}),
+		handlers: make(map[Type][]Handler),
+	}
+}
+
+// Subscribe registers a handler for the given event types, or for every event when none are given
+func (b *Bus) Subscribe(handler Handler, types ...Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(types) == 0 {
+		b.all = append(b.all, handler)
+		return
+	}
+	for _, t := range types {
+		b.handlers[t] = append(b.handlers[t], handler)
+	}
+}
+
+// Publish queues an event for delivery.
+// The handlers see ctx's values but not its cancellation, as they run after the request ends.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	select {
+	case b.queue <- envelope{ctx: context.WithoutCancel(ctx), event: event}:
+	default:
+		b.logger.WarnContext(ctx, "event bus full, dropping event",
+			"type", event.Type,
+			"transaction_id", event.Transaction.ID,
+		)
+	}
+}
+
+// Notify publishes the event matching a transaction whose status change has been committed.
+// It lets the bus stand in wherever the services accept a notifier.
+func (b *Bus) Notify(ctx context.Context, txn *models.Transaction) {
+	eventType, ok := typeFor(txn.Type)
+	if !ok {
+		return
+	}
+	b.Publish(ctx, Event{Type: eventType, Transaction: txn})
+}
+
+// Run delivers queued events until ctx is cancelled
+func (b *Bus) Run(ctx context.Context) {
+	defer close(b.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.logger.Info("stopping event bus", "pending", len(b.queue))
+			return
+		case env := <-b.queue:
+			b.deliver(env)
+		}
+	}
+}
+
+func (b *Bus) deliver(env envelope) {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.all...), b.handlers[env.event.Type]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.call(handler, env)
+	}
+}
+
+// call runs one handler, keeping a panic from stopping delivery to the others
+func (b *Bus) call(handler Handler, env envelope) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.ErrorContext(env.ctx, "event handler panicked",
+				"type", env.event.Type,
+				"transaction_id", env.event.Transaction.ID,
+				"panic", r,
+			)
+		}
+	}()
+	handler(env.ctx, env.event)
+}
+
+func typeFor(txnType models.TransactionType) (Type, bool) {
+	switch txnType {
+	case models.TransactionTypeAuthHold:
+		return TypeAuthorized, true
+	case models.TransactionTypeCapture:
+		return TypeCaptured, true
+	case models.TransactionTypeVoid:
+		return TypeVoided, true
+	case models.TransactionTypeRefund:
+		return TypeRefunded, true
+	case models.TransactionTypeChargeback:
+		return TypeChargedBack, true
+	default:
+		return "", false
+	}
+}
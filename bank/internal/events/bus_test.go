@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func txnOfType(t models.TransactionType) *models.Transaction {
+	return &models.Transaction{ID: uuid.New(), Type: t, AmountCents: 1000, Currency: "USD"}
+}
+
+func startBus(t *testing.T, size int) *Bus {
+	t.Helper()
+
+	b := NewBus(size, testLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	go b.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-b.done
+	})
+	return b
+}
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not delivered")
+		return Event{}
+	}
+}
+
+func TestBus_NotifyPublishesTypedEvents(t *testing.T) {
+	b := startBus(t, 10)
+	received := make(chan Event, 10)
+	b.Subscribe(func(_ context.Context, event Event) { received <- event })
+
+	tests := []struct {
+		txnType  models.TransactionType
+		expected Type
+	}{
+		{models.TransactionTypeAuthHold, TypeAuthorized},
+		{models.TransactionTypeCapture, TypeCaptured},
+		{models.TransactionTypeVoid, TypeVoided},
+		{models.TransactionTypeRefund, TypeRefunded},
+		{models.TransactionTypeChargeback, TypeChargedBack},
+	}
+	for _, tt := range tests {
+		txn := txnOfType(tt.txnType)
+		b.Notify(context.Background(), txn)
+
+		event := receive(t, received)
+		assert.Equal(t, tt.expected, event.Type)
+		assert.Same(t, txn, event.Transaction)
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+}
+
+func TestBus_SubscribeFiltersByType(t *testing.T) {
+	b := startBus(t, 10)
+	captures := make(chan Event, 10)
+	everything := make(chan Event, 10)
+	b.Subscribe(func(_ context.Context, event Event) { captures <- event }, TypeCaptured)
+	b.Subscribe(func(_ context.Context, event Event) { everything <- event })
+
+	b.Notify(context.Background(), txnOfType(models.TransactionTypeVoid))
+	b.Notify(context.Background(), txnOfType(models.TransactionTypeCapture))
+
+	assert.Equal(t, TypeVoided, receive(t, everything).Type)
+	assert.Equal(t, TypeCaptured, receive(t, everything).Type)
+	assert.Equal(t, TypeCaptured, receive(t, captures).Type)
+	assert.Empty(t, captures)
+}
+
+func TestBus_HandlersOutliveRequestContext(t *testing.T) {
+	type key struct{}
+	b := startBus(t, 10)
+	received := make(chan context.Context, 1)
+	b.Subscribe(func(ctx context.Context, _ Event) { received <- ctx })
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "req-1"))
+	b.Notify(ctx, txnOfType(models.TransactionTypeCapture))
+	cancel()
+
+	select {
+	case handlerCtx := <-received:
+		require.NoError(t, handlerCtx.Err())
+		assert.Equal(t, "req-1", handlerCtx.Value(key{}))
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+}
+
+func TestBus_PanickingHandlerDoesNotStopDelivery(t *testing.T) {
+	b := startBus(t, 10)
+	received := make(chan Event, 1)
+	b.Subscribe(func(context.Context, Event) { panic("boom") })
+	b.Subscribe(func(_ context.Context, event Event) { received <- event })
+
+	b.Notify(context.Background(), txnOfType(models.TransactionTypeRefund))
+
+	assert.Equal(t, TypeRefunded, receive(t, received).Type)
+}
+
+func TestBus_DropsWhenFull(t *testing.T) {
+	// Run is not started, so nothing drains the buffer
+	b := NewBus(1, testLogger())
+
+	b.Notify(context.Background(), txnOfType(models.TransactionTypeCapture))
+	b.Notify(context.Background(), txnOfType(models.TransactionTypeVoid))
+
+	require.Len(t, b.queue, 1)
+	env := <-b.queue
+	assert.Equal(t, TypeCaptured, env.event.Type)
+}
+
+func TestBus_IgnoresUnknownTransactionTypes(t *testing.T) {
+	b := NewBus(1, testLogger())
+
+	b.Notify(context.Background(), txnOfType("TRANSFER"))
+
+	assert.Empty(t, b.queue)
+}
@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"errors"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain qualifies the service error codes attached to gRPC errors as ErrorInfo reasons
+const errorDomain = "bank"
+
+// statusCode maps a service error to the gRPC code it is reported with, following the same
+// precedence as the HTTP handlers: transient conflicts, then internal errors, then sentinels.
+// Losing an idempotency key to a concurrent call is a conflict too, since a retry replays its result.
+func statusCode(err error) codes.Code {
+	if db.IsRetryable(err) || errors.Is(err, models.ErrDuplicateIdempotencyKey) {
+		return codes.Aborted
+	}
+
+	var svcErr *service.ServiceError
+	if !errors.As(err, &svcErr) || svcErr.Code == service.ErrCodeInternalError {
+		return codes.Internal
+	}
+
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, models.ErrDuplicateTransaction):
+		return codes.AlreadyExists
+	}
+
+	switch svcErr.Code {
+	case service.ErrCodeAccountNotFound,
+		service.ErrCodeAuthNotFound,
+		service.ErrCodeCaptureNotFound,
+		service.ErrCodeRefundNotFound,
+		service.ErrCodeTransactionNotFound,
+		service.ErrCodeChargebackNotFound:
+		return codes.NotFound
+	case service.ErrCodeInsufficientFunds,
+		service.ErrCodeCardExpired,
+		service.ErrCodeAuthExpired,
+		service.ErrCodeAuthAlreadyUsed,
+		service.ErrCodeAlreadyCaptured,
+		service.ErrCodeAlreadyVoided,
+		service.ErrCodeAlreadyRefunded,
+		service.ErrCodeAlreadyChargedBack,
+		service.ErrCodeCaptureExceedsAuth,
+		service.ErrCodeVoidExceedsAuth,
+		service.ErrCodeRefundExceedsCapture,
+		service.ErrCodeChargebackExceedsCapture,
+		service.ErrCodeVelocityExceeded,
+		service.ErrCodeDailyLimitExceeded,
+		service.ErrCodeFXRateUnavailable:
+		return codes.FailedPrecondition
+	default:
+		return codes.InvalidArgument
+	}
+}
+
+// statusError converts a service error to a gRPC status error. Client errors carry the service
+// message and an ErrorInfo whose reason is the service error code, matching the HTTP error codes;
+// internal errors reveal nothing beyond their code.
+func statusError(err error) error {
+	code := statusCode(err)
+	if code == codes.Internal {
+		return status.Error(codes.Internal, "internal error")
+	}
+	if code == codes.Aborted {
+		return status.Error(codes.Aborted, "request conflicted with a concurrent request, retry later")
+	}
+
+	var svcErr *service.ServiceError
+	errors.As(err, &svcErr)
+
+	st, detailErr := status.New(code, svcErr.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: svcErr.Code,
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, svcErr.Message)
+	}
+	return st.Err()
+}
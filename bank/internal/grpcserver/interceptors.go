@@ -0,0 +1,182 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/grpcapi"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/benx421/payment-gateway/bank/internal/middleware"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	authorizationMetadata  = "authorization"
+	idempotencyKeyMetadata = "idempotency-key"
+	replayedMetadata       = "x-idempotent-replayed"
+	bearerPrefix           = "Bearer "
+)
+
+// IdempotencyRepository is the idempotency storage the idempotency interceptor reads from
+type IdempotencyRepository = middleware.IdempotencyRepository
+
+// idempotentMethods are the mutating RPCs that require an idempotency key
+var idempotentMethods = map[string]bool{
+	grpcapi.Bank_Authorize_FullMethodName: true,
+	grpcapi.Bank_Capture_FullMethodName:   true,
+	grpcapi.Bank_Void_FullMethodName:      true,
+	grpcapi.Bank_Refund_FullMethodName:    true,
+}
+
+// recoveryInterceptor turns a panicking handler into an Internal error
+func recoveryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.ErrorContext(ctx, "recovered from panic",
+					"panic", rec,
+					"method", info.FullMethod,
+					"stack", string(debug.Stack()),
+				)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// authInterceptor requires `authorization: Bearer <key>` metadata matching one of apiKeys
+func authInterceptor(apiKeys []string, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	keys := make([][]byte, 0, len(apiKeys))
+	for _, key := range apiKeys {
+		keys = append(keys, []byte(key))
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		header := firstMetadata(ctx, authorizationMetadata)
+		if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		token := strings.TrimSpace(header[len(bearerPrefix):])
+		if !middleware.ValidAPIKey(keys, []byte(token)) {
+			logger.WarnContext(ctx, "rejected grpc call with invalid api key", "method", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		return handler(logging.ContextWithActor(ctx, middleware.KeyActor(token)), req)
+	}
+}
+
+// maintenanceInterceptor fails mutating calls with Unavailable while enabled is set
+func maintenanceInterceptor(enabled *atomic.Bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if enabled.Load() && idempotentMethods[info.FullMethod] {
+			return nil, status.Error(codes.Unavailable, "writes are paused for maintenance, retry later")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// idempotencyInterceptor requires an idempotency-key on mutating calls and replays the stored
+// result of a key already used for the same method. New results are recorded in the service's
+// database transaction, as the HTTP handlers do, keyed by the full method name so gRPC and HTTP
+// keys never collide, in the idempotency table named by tables. A concurrent call that commits the
+// key first rolls this one back, which then replays that call's result.
+func idempotencyInterceptor(repo IdempotencyRepository, tables *repository.Tables, failOpen bool, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		key := firstMetadata(ctx, idempotencyKeyMetadata)
+		if key == "" {
+			return nil, status.Error(codes.InvalidArgument, "missing idempotency-key metadata")
+		}
+		if err := middleware.ValidateIdempotencyKey(key); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		cached, err := repo.Get(ctx, key, info.FullMethod)
+		if err != nil {
+			logger.WarnContext(ctx, "failed to check idempotency cache", "error", err, "fail_open", failOpen)
+			if !failOpen {
+				return nil, status.Error(codes.Unavailable, "idempotency store unavailable, retry later")
+			}
+		}
+		if cached != nil {
+			return replayIdempotentResult(ctx, cached, logger)
+		}
+
+		var duplicate bool
+		hookCtx := service.WithBeforeCommit(ctx, func(ctx context.Context, tx *db.Tx, txn *models.Transaction) error {
+			msg, err := transactionMessage(txn)
+			if err != nil {
+				return err
+			}
+			body, err := protojson.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			err = repository.NewIdempotencyRepository(tx, repository.WithIdempotencyTables(tables)).Insert(ctx, &models.IdempotencyKey{
+				Key:            key,
+				RequestPath:    info.FullMethod,
+				ResponseStatus: int(codes.OK),
+				ResponseBody:   string(body),
+				TransactionID:  &txn.ID,
+				CreatedAt:      time.Now(),
+			})
+			duplicate = errors.Is(err, models.ErrDuplicateIdempotencyKey)
+			return err
+		})
+
+		resp, err := handler(hookCtx, req)
+		if err == nil || !duplicate {
+			return resp, err
+		}
+
+		cached, getErr := repo.Get(ctx, key, info.FullMethod)
+		if getErr != nil || cached == nil {
+			logger.WarnContext(ctx, "failed to load the idempotent result of a concurrent call",
+				"error", getErr, "key", key)
+			return resp, err
+		}
+		return replayIdempotentResult(ctx, cached, logger)
+	}
+}
+
+// replayIdempotentResult decodes a stored result and marks the response as replayed
+func replayIdempotentResult(ctx context.Context, cached *models.IdempotencyKey, logger *slog.Logger) (any, error) {
+	var txn grpcapi.Transaction
+	if err := protojson.Unmarshal([]byte(cached.ResponseBody), &txn); err != nil {
+		logger.ErrorContext(ctx, "failed to decode cached idempotent response", "error", err, "key", cached.Key)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	//nolint:errcheck // Best effort: the replayed result is returned either way
+	grpc.SetHeader(ctx, metadata.Pairs(replayedMetadata, "true"))
+	return &txn, nil
+}
+
+// firstMetadata returns the first value of an incoming metadata key, or ""
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/grpcapi"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func callInterceptor(ctx context.Context, interceptor grpc.UnaryServerInterceptor, method string) (bool, any, error) {
+	called := false
+	handler := func(context.Context, any) (any, error) {
+		called = true
+		return &grpcapi.Transaction{Id: "fresh"}, nil
+	}
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return called, resp, err
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	interceptor := authInterceptor([]string{"secret-key"}, testLogger())
+
+	tests := []struct {
+		name   string
+		header string
+		code   codes.Code
+	}{
+		{"valid key", "Bearer secret-key", codes.OK},
+		{"case-insensitive scheme", "bearer secret-key", codes.OK},
+		{"wrong key", "Bearer other-key", codes.Unauthenticated},
+		{"missing scheme", "secret-key", codes.Unauthenticated},
+		{"no metadata", "", codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationMetadata, tt.header))
+			}
+
+			called, _, err := callInterceptor(ctx, interceptor, grpcapi.Bank_GetTransaction_FullMethodName)
+
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.code == codes.OK, called)
+		})
+	}
+}
+
+func TestIdempotencyInterceptor_RequiresKeyOnMutations(t *testing.T) {
+	interceptor := idempotencyInterceptor(mocks.NewMockIdempotencyRepository(t), nil, false, testLogger())
+
+	called, _, err := callInterceptor(context.Background(), interceptor, grpcapi.Bank_Capture_FullMethodName)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, called)
+
+	called, _, err = callInterceptor(context.Background(), interceptor, grpcapi.Bank_GetTransaction_FullMethodName)
+	require.NoError(t, err)
+	assert.True(t, called, "reads need no idempotency key")
+}
+
+func TestIdempotencyInterceptor_RejectsInvalidKey(t *testing.T) {
+	interceptor := idempotencyInterceptor(mocks.NewMockIdempotencyRepository(t), nil, false, testLogger())
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyMetadata, "bad key"))
+
+	called, _, err := callInterceptor(ctx, interceptor, grpcapi.Bank_Capture_FullMethodName)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, called)
+}
+
+func TestIdempotencyInterceptor_ReplaysStoredResult(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	interceptor := idempotencyInterceptor(repo, nil, false, testLogger())
+
+	body, err := protojson.Marshal(&grpcapi.Transaction{Id: "stored", Amount: 700})
+	require.NoError(t, err)
+	repo.On("Get", mock.Anything, "key-1", grpcapi.Bank_Refund_FullMethodName).
+		Return(&models.IdempotencyKey{ResponseBody: string(body)}, nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyMetadata, "key-1"))
+	called, resp, err := callInterceptor(ctx, interceptor, grpcapi.Bank_Refund_FullMethodName)
+
+	require.NoError(t, err)
+	assert.False(t, called)
+	txn, ok := resp.(*grpcapi.Transaction)
+	require.True(t, ok)
+	assert.Equal(t, "stored", txn.GetId())
+	assert.Equal(t, int64(700), txn.GetAmount())
+}
+
+func TestIdempotencyInterceptor_RunsHandlerOnMiss(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	interceptor := idempotencyInterceptor(repo, nil, false, testLogger())
+
+	repo.On("Get", mock.Anything, "key-2", grpcapi.Bank_Void_FullMethodName).Return(nil, nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyMetadata, "key-2"))
+	called, resp, err := callInterceptor(ctx, interceptor, grpcapi.Bank_Void_FullMethodName)
+
+	require.NoError(t, err)
+	assert.True(t, called)
+	txn, ok := resp.(*grpcapi.Transaction)
+	require.True(t, ok)
+	assert.Equal(t, "fresh", txn.GetId())
+}
+
+func TestIdempotencyInterceptor_StoreErrorFailsClosed(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	interceptor := idempotencyInterceptor(repo, nil, false, testLogger())
+
+	repo.On("Get", mock.Anything, "key-3", grpcapi.Bank_Void_FullMethodName).Return(nil, errors.New("connection refused"))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyMetadata, "key-3"))
+	called, _, err := callInterceptor(ctx, interceptor, grpcapi.Bank_Void_FullMethodName)
+
+	assert.False(t, called)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestIdempotencyInterceptor_StoreErrorFailsOpen(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	interceptor := idempotencyInterceptor(repo, nil, true, testLogger())
+
+	repo.On("Get", mock.Anything, "key-4", grpcapi.Bank_Void_FullMethodName).Return(nil, errors.New("connection refused"))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyMetadata, "key-4"))
+	called, _, err := callInterceptor(ctx, interceptor, grpcapi.Bank_Void_FullMethodName)
+
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestMaintenanceInterceptor(t *testing.T) {
+	enabled := &atomic.Bool{}
+	interceptor := maintenanceInterceptor(enabled)
+
+	called, _, err := callInterceptor(context.Background(), interceptor, grpcapi.Bank_Capture_FullMethodName)
+	require.NoError(t, err)
+	assert.True(t, called, "writes pass while maintenance is off")
+
+	enabled.Store(true)
+
+	called, _, err = callInterceptor(context.Background(), interceptor, grpcapi.Bank_Capture_FullMethodName)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.False(t, called)
+
+	called, _, err = callInterceptor(context.Background(), interceptor, grpcapi.Bank_GetTransaction_FullMethodName)
+	require.NoError(t, err)
+	assert.True(t, called, "reads are served during maintenance")
+}
@@ -0,0 +1,221 @@
+// Package grpcserver serves the bank's payment operations over gRPC, backed by the same services as the HTTP API.
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/grpcapi"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultCurrency applies when an authorization request omits the currency
+const defaultCurrency = "USD"
+
+// Server implements grpcapi.BankServer
+type Server struct {
+	grpcapi.UnimplementedBankServer
+
+	authService    service.Authorizer
+	captureService service.Capturer
+	voidService    service.Voider
+	refundService  service.Refunder
+	txnService     service.TransactionReader
+	logger         *slog.Logger
+}
+
+// NewServer creates a new Server with injected service dependencies
+func NewServer(
+	authService service.Authorizer,
+	captureService service.Capturer,
+	voidService service.Voider,
+	refundService service.Refunder,
+	txnService service.TransactionReader,
+	logger *slog.Logger,
+) *Server {
+	return &Server{
+		authService:    authService,
+		captureService: captureService,
+		voidService:    voidService,
+		refundService:  refundService,
+		txnService:     txnService,
+		logger:         logger,
+	}
+}
+
+// Options configures NewGRPCServer
+type Options struct {
+	// Idempotency stores the results of mutating calls by their idempotency-key metadata
+	Idempotency IdempotencyRepository
+	// Tables names the idempotency table results are recorded in; nil uses the unprefixed tables
+	Tables *repository.Tables
+	// Credentials secures the listener; nil serves plaintext
+	Credentials credentials.TransportCredentials
+	// Maintenance, while set, fails mutating calls with Unavailable. Nil never pauses writes
+	Maintenance *atomic.Bool
+	// APIKeys, when non-empty, are required as `authorization: Bearer <key>` metadata
+	APIKeys []string
+	// IdempotencyFailOpen processes mutating calls without deduplication when Idempotency cannot be
+	// read, instead of failing them with Unavailable
+	IdempotencyFailOpen bool
+}
+
+// NewGRPCServer returns a gRPC server with srv registered behind the recovery, authentication,
+// maintenance and idempotency interceptors
+func NewGRPCServer(srv *Server, opts Options) *grpc.Server {
+	interceptors := []grpc.UnaryServerInterceptor{recoveryInterceptor(srv.logger)}
+	if len(opts.APIKeys) > 0 {
+		interceptors = append(interceptors, authInterceptor(opts.APIKeys, srv.logger))
+	}
+	if opts.Maintenance != nil {
+		interceptors = append(interceptors, maintenanceInterceptor(opts.Maintenance))
+	}
+	interceptors = append(interceptors, idempotencyInterceptor(opts.Idempotency, opts.Tables, opts.IdempotencyFailOpen, srv.logger))
+
+	serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+	if opts.Credentials != nil {
+		serverOpts = append(serverOpts, grpc.Creds(opts.Credentials))
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	grpcapi.RegisterBankServer(server, srv)
+	return server
+}
+
+// Authorize places an authorization hold
+func (s *Server) Authorize(ctx context.Context, req *grpcapi.AuthorizeRequest) (*grpcapi.Transaction, error) {
+	currency := req.GetCurrency()
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	var expiresAt *time.Time
+	if req.GetExpiresAt() != nil {
+		requested := req.GetExpiresAt().AsTime()
+		expiresAt = &requested
+	}
+
+	txn, err := s.authService.Authorize(ctx, req.GetCardNumber(), req.GetCvv(), req.GetAmount(), currency, expiresAt)
+	if err != nil {
+		return nil, s.serviceError(ctx, "authorization", err)
+	}
+	return transactionMessage(txn)
+}
+
+// Capture settles all or part of an authorization
+func (s *Server) Capture(ctx context.Context, req *grpcapi.CaptureRequest) (*grpcapi.Transaction, error) {
+	authID, err := parseID("authorization_id", req.GetAuthorizationId())
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := s.captureService.Capture(ctx, authID, req.GetAmount())
+	if err != nil {
+		return nil, s.serviceError(ctx, "capture", err)
+	}
+	return transactionMessage(txn)
+}
+
+// Void cancels an uncaptured authorization, or lowers it by amount when one is given
+func (s *Server) Void(ctx context.Context, req *grpcapi.VoidRequest) (*grpcapi.Transaction, error) {
+	authID, err := parseID("authorization_id", req.GetAuthorizationId())
+	if err != nil {
+		return nil, err
+	}
+
+	var txn *models.Transaction
+	if req.GetAmount() != 0 {
+		txn, err = s.voidService.PartialVoid(ctx, authID, req.GetAmount())
+	} else {
+		txn, err = s.voidService.Void(ctx, authID)
+	}
+	if err != nil {
+		return nil, s.serviceError(ctx, "void", err)
+	}
+	return transactionMessage(txn)
+}
+
+// Refund returns all or part of a capture
+func (s *Server) Refund(ctx context.Context, req *grpcapi.RefundRequest) (*grpcapi.Transaction, error) {
+	captureID, err := parseID("capture_id", req.GetCaptureId())
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := s.refundService.Refund(ctx, captureID, req.GetAmount())
+	if err != nil {
+		return nil, s.serviceError(ctx, "refund", err)
+	}
+	return transactionMessage(txn)
+}
+
+// GetTransaction returns any ledger transaction by ID
+func (s *Server) GetTransaction(ctx context.Context, req *grpcapi.GetTransactionRequest) (*grpcapi.Transaction, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := s.txnService.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, s.serviceError(ctx, "transaction lookup", err)
+	}
+	return transactionMessage(txn)
+}
+
+// serviceError logs errors that are not the client's fault and converts err to a gRPC status
+func (s *Server) serviceError(ctx context.Context, operation string, err error) error {
+	if code := statusCode(err); code == codes.Internal {
+		s.logger.ErrorContext(ctx, "unexpected error during "+operation, "error", err)
+	} else if code == codes.Aborted {
+		s.logger.WarnContext(ctx, operation+" conflicted with a concurrent request", "error", err)
+	}
+	return statusError(err)
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: must be a UUID", field)
+	}
+	return id, nil
+}
+
+// transactionMessage converts a ledger transaction to its protobuf form
+func transactionMessage(txn *models.Transaction) (*grpcapi.Transaction, error) {
+	msg := &grpcapi.Transaction{
+		Id:            txn.ID.String(),
+		AccountId:     txn.AccountID.String(),
+		Type:          string(txn.Type),
+		Amount:        txn.AmountCents,
+		Currency:      txn.Currency,
+		Status:        string(txn.Status),
+		DeclineReason: string(txn.DeclineReason),
+		CreatedAt:     timestamppb.New(txn.CreatedAt),
+	}
+	if txn.ReferenceID != nil {
+		msg.ReferenceId = txn.ReferenceID.String()
+	}
+	if txn.ExpiresAt != nil {
+		msg.ExpiresAt = timestamppb.New(*txn.ExpiresAt)
+	}
+	if txn.Metadata != nil {
+		metadata, err := structpb.NewStruct(txn.Metadata)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+		msg.Metadata = metadata
+	}
+	return msg, nil
+}
@@ -0,0 +1,133 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/grpcapi"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestAuthorize_DefaultsCurrency(t *testing.T) {
+	mockAuth := mocks.NewMockAuthorizer(t)
+	srv := NewServer(mockAuth, nil, nil, nil, nil, testLogger())
+
+	authID := uuid.New()
+	expires := time.Now().Add(time.Hour)
+	mockAuth.On("Authorize", mock.Anything, "4111111111111111", "123", int64(5000), "USD", (*time.Time)(nil)).
+		Return(&models.Transaction{
+			ID:          authID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 5000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			ExpiresAt:   &expires,
+			Metadata:    map[string]any{"order": "42"},
+			CreatedAt:   time.Now(),
+		}, nil)
+
+	resp, err := srv.Authorize(context.Background(), &grpcapi.AuthorizeRequest{
+		CardNumber: "4111111111111111",
+		Cvv:        "123",
+		Amount:     5000,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, authID.String(), resp.GetId())
+	assert.Equal(t, int64(5000), resp.GetAmount())
+	assert.Empty(t, resp.GetReferenceId())
+	assert.Equal(t, expires.Unix(), resp.GetExpiresAt().AsTime().Unix())
+	assert.Equal(t, "42", resp.GetMetadata().AsMap()["order"])
+}
+
+func TestCapture_InvalidID(t *testing.T) {
+	srv := NewServer(nil, nil, nil, nil, nil, testLogger())
+
+	_, err := srv.Capture(context.Background(), &grpcapi.CaptureRequest{AuthorizationId: "auth_123", Amount: 100})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRefund_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		err          error
+		name         string
+		reason       string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "capture not found",
+			err:          &service.ServiceError{Code: service.ErrCodeCaptureNotFound, Message: "capture not found", Err: models.ErrTransactionNotFound},
+			expectedCode: codes.NotFound,
+			reason:       service.ErrCodeCaptureNotFound,
+		},
+		{
+			name:         "refund exceeds capture",
+			err:          &service.ServiceError{Code: service.ErrCodeRefundExceedsCapture, Message: "too much"},
+			expectedCode: codes.FailedPrecondition,
+			reason:       service.ErrCodeRefundExceedsCapture,
+		},
+		{
+			name:         "invalid amount",
+			err:          &service.ServiceError{Code: service.ErrCodeInvalidAmount, Message: "amount must be positive"},
+			expectedCode: codes.InvalidArgument,
+			reason:       service.ErrCodeInvalidAmount,
+		},
+		{
+			name:         "serialization failure",
+			err:          fmt.Errorf("refund: %w", &pq.Error{Code: "40001"}),
+			expectedCode: codes.Aborted,
+		},
+		{
+			name:         "idempotency key committed concurrently",
+			err:          &service.ServiceError{Code: service.ErrCodeInternalError, Err: models.ErrDuplicateIdempotencyKey},
+			expectedCode: codes.Aborted,
+		},
+		{
+			name:         "internal error",
+			err:          &service.ServiceError{Code: service.ErrCodeInternalError, Message: "db password leaked"},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRefund := mocks.NewMockRefunder(t)
+			srv := NewServer(nil, nil, nil, mockRefund, nil, testLogger())
+
+			mockRefund.On("Refund", mock.Anything, mock.Anything, int64(100)).Return(nil, tt.err)
+
+			_, err := srv.Refund(context.Background(), &grpcapi.RefundRequest{CaptureId: uuid.New().String(), Amount: 100})
+
+			st := status.Convert(err)
+			assert.Equal(t, tt.expectedCode, st.Code())
+			assert.NotContains(t, st.Message(), "password")
+
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.GetReason()
+				}
+			}
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/google/uuid"
+)
+
+// GetAccount handles GET /api/v1/accounts/{accountId}
+func (h *Handler) GetAccount(
+	ctx context.Context,
+	request api.GetAccountRequestObject,
+) (api.GetAccountResponseObject, error) {
+	id, err := uuid.Parse(request.AccountId)
+	if err != nil {
+		//nolint:nilerr // Returning 400 response object, not propagating error
+		return api.GetAccount400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, "invalid account ID format"),
+		}, nil
+	}
+
+	account, err := h.accountService.GetAccountByID(ctx, id)
+	if err != nil {
+		if errorStatus(err) == http.StatusNotFound {
+			return api.GetAccount404JSONResponse{
+				NotFoundJSONResponse: notFound(api.ErrorCodeAccountNotFound, "account not found"),
+			}, nil
+		}
+		h.logger.ErrorContext(ctx, "unexpected error during account lookup", "error", err)
+		return api.GetAccount500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	tracing.SetAccountID(ctx, account.ID)
+	etag := accountETag(account)
+	if etagMatches(request.Params.IfNoneMatch, etag) {
+		return api.GetAccount304Response{Headers: api.GetAccount304ResponseHeaders{ETag: etag}}, nil
+	}
+
+	return api.GetAccount200JSONResponse{
+		Body:    toAccountResponse(account),
+		Headers: api.GetAccount200ResponseHeaders{ETag: etag},
+	}, nil
+}
+
+// toAccountResponse converts an account to its API form with the card number masked
+func toAccountResponse(account *models.Account) api.AccountResponse {
+	public := account.ToPublic()
+	return api.AccountResponse{
+		Id:                    account.ID,
+		AccountNumber:         public.AccountNumber,
+		BalanceCents:          public.BalanceCents,
+		AvailableBalanceCents: public.AvailableBalanceCents,
+		PendingCents:          public.PendingCents,
+		Currency:              public.Currency,
+		CreatedAt:             account.CreatedAt,
+		UpdatedAt:             account.UpdatedAt,
+	}
+}
+
+// accountETag identifies an account's state by when it last changed; any balance change bumps updated_at
+func accountETag(account *models.Account) string {
+	return `"` + strconv.FormatInt(account.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header lists etag or is *.
+// Comparison is weak, as RFC 9110 requires for If-None-Match, so W/ prefixes are ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAccountBalance handles GET /api/v1/accounts/{accountNumber}/balance
+func (h *Handler) GetAccountBalance(
+	ctx context.Context,
+	request api.GetAccountBalanceRequestObject,
+) (api.GetAccountBalanceResponseObject, error) {
+	account, err := h.accountService.GetAccount(ctx, request.AccountNumber)
+	if err != nil {
+		if errorStatus(err) == http.StatusNotFound {
+			return api.GetAccountBalance404JSONResponse{
+				NotFoundJSONResponse: notFound(api.ErrorCodeAccountNotFound, "account not found"),
+			}, nil
+		}
+		h.logger.ErrorContext(ctx, "unexpected error during balance lookup", "error", err)
+		return api.GetAccountBalance500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	public := account.ToPublic()
+	tracing.SetAccountID(ctx, account.ID)
+	return api.GetAccountBalance200JSONResponse{
+		AccountNumber:         public.AccountNumber,
+		BalanceCents:          public.BalanceCents,
+		AvailableBalanceCents: public.AvailableBalanceCents,
+		PendingCents:          public.PendingCents,
+		Currency:              public.Currency,
+	}, nil
+}
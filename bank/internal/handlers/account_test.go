@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountBalance_Success(t *testing.T) {
+	mockAccount := mocks.NewMockAccountReader(t)
+	handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+
+	mockAccount.On("GetAccount", mock.Anything, "4111111111111111").
+		Return(&models.Account{
+			AccountNumber:         "4111111111111111",
+			CVV:                   "123",
+			Currency:              "USD",
+			BalanceCents:          1000000,
+			AvailableBalanceCents: 990000,
+		}, nil)
+
+	req := api.GetAccountBalanceRequestObject{AccountNumber: "4111111111111111"}
+	resp, err := handler.GetAccountBalance(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.GetAccountBalance200JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, "************1111", successResp.AccountNumber)
+	assert.Equal(t, int64(1000000), successResp.BalanceCents)
+	assert.Equal(t, int64(990000), successResp.AvailableBalanceCents)
+	assert.Equal(t, int64(10000), successResp.PendingCents)
+	assert.Equal(t, "USD", successResp.Currency)
+
+	body, err := json.Marshal(successResp)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "cvv")
+	assert.NotContains(t, string(body), "123")
+}
+
+func TestGetAccountBalance_NotFound(t *testing.T) {
+	mockAccount := mocks.NewMockAccountReader(t)
+	handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+
+	mockAccount.On("GetAccount", mock.Anything, mock.Anything).
+		Return(nil, &service.ServiceError{Code: service.ErrCodeAccountNotFound, Err: models.ErrAccountNotFound})
+
+	req := api.GetAccountBalanceRequestObject{AccountNumber: "4000000000000000"}
+	resp, err := handler.GetAccountBalance(context.Background(), req)
+
+	require.NoError(t, err)
+	notFoundResp, ok := resp.(api.GetAccountBalance404JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.ErrorCodeAccountNotFound, notFoundResp.Error.Code)
+}
+
+func TestGetAccountBalance_InternalError(t *testing.T) {
+	mockAccount := mocks.NewMockAccountReader(t)
+	handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+
+	mockAccount.On("GetAccount", mock.Anything, mock.Anything).
+		Return(nil, &service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")})
+
+	req := api.GetAccountBalanceRequestObject{AccountNumber: "4111111111111111"}
+	resp, err := handler.GetAccountBalance(context.Background(), req)
+
+	require.NoError(t, err)
+	errResp, ok := resp.(api.GetAccountBalance500JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.ErrorCodeInternalError, errResp.Error.Code)
+}
+
+func TestGetAccount_Success(t *testing.T) {
+	mockAccount := mocks.NewMockAccountReader(t)
+	handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+
+	accountID := uuid.New()
+	account := &models.Account{
+		ID:                    accountID,
+		AccountNumber:         "4111111111111111",
+		CVV:                   "123",
+		Currency:              "USD",
+		BalanceCents:          1000000,
+		AvailableBalanceCents: 990000,
+		UpdatedAt:             time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	mockAccount.On("GetAccountByID", mock.Anything, accountID).Return(account, nil)
+
+	resp, err := handler.GetAccount(context.Background(), api.GetAccountRequestObject{AccountId: accountID.String()})
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.GetAccount200JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, accountID, successResp.Body.Id)
+	assert.Equal(t, "************1111", successResp.Body.AccountNumber)
+	assert.Equal(t, int64(10000), successResp.Body.PendingCents)
+	assert.Equal(t, accountETag(account), successResp.Headers.ETag)
+
+	body, err := json.Marshal(successResp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "123", "CVV must never be returned")
+	assert.NotContains(t, string(body), "4111111111111111")
+}
+
+func TestGetAccount_IfNoneMatch(t *testing.T) {
+	accountID := uuid.New()
+	account := &models.Account{ID: accountID, Currency: "USD", UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	etag := accountETag(account)
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		notModified bool
+	}{
+		{"current etag", etag, true},
+		{"weak current etag in a list", `"stale", W/` + etag, true},
+		{"wildcard", "*", true},
+		{"stale etag", `"stale"`, false},
+		{"no header", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAccount := mocks.NewMockAccountReader(t)
+			handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+			mockAccount.On("GetAccountByID", mock.Anything, accountID).Return(account, nil)
+
+			req := api.GetAccountRequestObject{AccountId: accountID.String(), Params: api.GetAccountParams{IfNoneMatch: tt.ifNoneMatch}}
+			resp, err := handler.GetAccount(context.Background(), req)
+
+			require.NoError(t, err)
+			if tt.notModified {
+				notModified, ok := resp.(api.GetAccount304Response)
+				require.True(t, ok)
+				assert.Equal(t, etag, notModified.Headers.ETag)
+				return
+			}
+			_, ok := resp.(api.GetAccount200JSONResponse)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestGetAccount_ChangeUpdatesETag(t *testing.T) {
+	before := &models.Account{UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	after := &models.Account{UpdatedAt: before.UpdatedAt.Add(time.Microsecond)}
+
+	assert.NotEqual(t, accountETag(before), accountETag(after))
+}
+
+func TestGetAccount_Errors(t *testing.T) {
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewHandler(nil, nil, nil, nil, mocks.NewMockAccountReader(t), nil, nil, nil, testLogger())
+
+		resp, err := handler.GetAccount(context.Background(), api.GetAccountRequestObject{AccountId: "not-a-uuid"})
+
+		require.NoError(t, err)
+		_, ok := resp.(api.GetAccount400JSONResponse)
+		assert.True(t, ok)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockAccount := mocks.NewMockAccountReader(t)
+		handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+		mockAccount.On("GetAccountByID", mock.Anything, mock.Anything).
+			Return(nil, &service.ServiceError{Code: service.ErrCodeAccountNotFound, Err: models.ErrAccountNotFound})
+
+		resp, err := handler.GetAccount(context.Background(), api.GetAccountRequestObject{AccountId: uuid.NewString()})
+
+		require.NoError(t, err)
+		notFoundResp, ok := resp.(api.GetAccount404JSONResponse)
+		require.True(t, ok)
+		assert.Equal(t, api.ErrorCodeAccountNotFound, notFoundResp.Error.Code)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockAccount := mocks.NewMockAccountReader(t)
+		handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+		mockAccount.On("GetAccountByID", mock.Anything, mock.Anything).
+			Return(nil, &service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")})
+
+		resp, err := handler.GetAccount(context.Background(), api.GetAccountRequestObject{AccountId: uuid.NewString()})
+
+		require.NoError(t, err)
+		_, ok := resp.(api.GetAccount500JSONResponse)
+		assert.True(t, ok)
+	})
+}
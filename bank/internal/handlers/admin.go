@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/google/uuid"
+)
+
+// GetAdminStats handles GET /admin/stats
+func (h *Handler) GetAdminStats(
+	ctx context.Context,
+	_ api.GetAdminStatsRequestObject,
+) (api.GetAdminStatsResponseObject, error) {
+	stats, err := h.txnService.Stats(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "unexpected error during stats lookup", "error", err)
+		return api.GetAdminStats500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	resp := api.GetAdminStats200JSONResponse{
+		Accounts:     stats.Accounts,
+		ComputedAt:   stats.ComputedAt,
+		Transactions: make([]api.TransactionCount, 0, len(stats.Transactions)),
+	}
+	for _, c := range stats.Transactions {
+		resp.Transactions = append(resp.Transactions, api.TransactionCount{
+			Type:   string(c.Type),
+			Status: string(c.Status),
+			Count:  c.Count,
+		})
+	}
+	return resp, nil
+}
+
+// GetMaintenance handles GET /admin/maintenance
+func (h *Handler) GetMaintenance(
+	_ context.Context,
+	_ api.GetMaintenanceRequestObject,
+) (api.GetMaintenanceResponseObject, error) {
+	return api.GetMaintenance200JSONResponse{Enabled: h.maintenance.Load()}, nil
+}
+
+// SetMaintenance handles PUT /admin/maintenance
+func (h *Handler) SetMaintenance(
+	ctx context.Context,
+	request api.SetMaintenanceRequestObject,
+) (api.SetMaintenanceResponseObject, error) {
+	enabled := request.Body.Enabled
+	if h.maintenance.Swap(enabled) != enabled {
+		h.logger.WarnContext(ctx, "maintenance mode changed", "enabled", enabled)
+	}
+	return api.SetMaintenance200JSONResponse{Enabled: enabled}, nil
+}
+
+// ReconcileBalances handles POST /admin/reconciliation
+func (h *Handler) ReconcileBalances(
+	ctx context.Context,
+	_ api.ReconcileBalancesRequestObject,
+) (api.ReconcileBalancesResponseObject, error) {
+	checkedAt := time.Now().UTC()
+	drifts, err := h.accountService.ReconcileBalances(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "unexpected error during balance reconciliation", "error", err)
+		return api.ReconcileBalances500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	resp := api.ReconcileBalances200JSONResponse{
+		CheckedAt: checkedAt,
+		Drifts:    make([]api.BalanceDrift, 0, len(drifts)),
+	}
+	for _, d := range drifts {
+		h.logger.ErrorContext(ctx, "balance differs from its ledger",
+			"account_id", d.AccountID,
+			"currency", d.Currency,
+			"balance_cents", d.BalanceCents,
+			"ledger_cents", d.LedgerCents,
+			"available_balance_cents", d.AvailableBalanceCents,
+			"ledger_available_cents", d.LedgerAvailableCents,
+		)
+		resp.Drifts = append(resp.Drifts, api.BalanceDrift{
+			AccountId:             d.AccountID,
+			Currency:              d.Currency,
+			BalanceCents:          d.BalanceCents,
+			AvailableBalanceCents: d.AvailableBalanceCents,
+			LedgerCents:           d.LedgerCents,
+			LedgerAvailableCents:  d.LedgerAvailableCents,
+			DriftCents:            d.DriftCents(),
+			AvailableDriftCents:   d.AvailableDriftCents(),
+		})
+	}
+	return resp, nil
+}
+
+// ReconcileAccount handles POST /admin/accounts/{accountId}/reconciliation
+func (h *Handler) ReconcileAccount(
+	ctx context.Context,
+	request api.ReconcileAccountRequestObject,
+) (api.ReconcileAccountResponseObject, error) {
+	id, err := uuid.Parse(request.AccountId)
+	if err != nil {
+		//nolint:nilerr // Returning 400 response object, not propagating error
+		return api.ReconcileAccount400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, "invalid account ID format"),
+		}, nil
+	}
+
+	drift, err := h.accountService.ReconcileAccount(ctx, id)
+	if err != nil {
+		if errorStatus(err) == http.StatusNotFound {
+			return api.ReconcileAccount404JSONResponse{
+				NotFoundJSONResponse: notFound(api.ErrorCodeAccountNotFound, "account not found"),
+			}, nil
+		}
+		h.logger.ErrorContext(ctx, "unexpected error during account reconciliation", "error", err)
+		return api.ReconcileAccount500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	if drift != 0 {
+		h.logger.ErrorContext(ctx, "balance differs from its ledger", "account_id", id, "drift_cents", drift)
+	}
+	return api.ReconcileAccount200JSONResponse{AccountId: id, DriftCents: drift}, nil
+}
+
+// Limits on the accounts returned by one low balance listing
+const (
+	defaultLowBalanceLimit = 20
+	maxLowBalanceLimit     = 100
+)
+
+// ListLowBalanceAccounts handles GET /admin/accounts/low-balance
+func (h *Handler) ListLowBalanceAccounts(
+	ctx context.Context,
+	request api.ListLowBalanceAccountsRequestObject,
+) (api.ListLowBalanceAccountsResponseObject, error) {
+	params := request.Params
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultLowBalanceLimit
+	}
+	if limit < 1 || limit > maxLowBalanceLimit {
+		return api.ListLowBalanceAccounts400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, fmt.Sprintf("limit must be between 1 and %d", maxLowBalanceLimit)),
+		}, nil
+	}
+	if params.Offset < 0 {
+		return api.ListLowBalanceAccounts400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, "offset cannot be negative"),
+		}, nil
+	}
+
+	accounts, err := h.accountService.ListLowBalance(ctx, params.ThresholdCents, limit, params.Offset)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "unexpected error during low balance listing", "error", err)
+		return api.ListLowBalanceAccounts500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	resp := api.ListLowBalanceAccounts200JSONResponse{
+		Accounts: make([]api.AccountResponse, 0, len(accounts)),
+		Limit:    limit,
+		Offset:   params.Offset,
+	}
+	for _, account := range accounts {
+		resp.Accounts = append(resp.Accounts, toAccountResponse(account))
+	}
+	return resp, nil
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAdminStats_Success(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	computedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockTxn.On("Stats", mock.Anything).Return(&models.LedgerStats{
+		ComputedAt: computedAt,
+		Accounts:   4,
+		Transactions: []models.TransactionCount{
+			{Type: models.TransactionTypeAuthHold, Status: models.TransactionStatusActive, Count: 3},
+			{Type: models.TransactionTypeCapture, Status: models.TransactionStatusCompleted, Count: 2},
+		},
+	}, nil)
+
+	resp, err := handler.GetAdminStats(context.Background(), api.GetAdminStatsRequestObject{})
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.GetAdminStats200JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, 4, successResp.Accounts)
+	assert.Equal(t, computedAt, successResp.ComputedAt)
+	assert.Equal(t, []api.TransactionCount{
+		{Type: "AUTH_HOLD", Status: "ACTIVE", Count: 3},
+		{Type: "CAPTURE", Status: "COMPLETED", Count: 2},
+	}, successResp.Transactions)
+}
+
+func TestGetAdminStats_InternalError(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("Stats", mock.Anything).
+		Return(nil, &service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")})
+
+	resp, err := handler.GetAdminStats(context.Background(), api.GetAdminStatsRequestObject{})
+
+	require.NoError(t, err)
+	_, ok := resp.(api.GetAdminStats500JSONResponse)
+	assert.True(t, ok)
+}
+
+func TestMaintenance_Toggle(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
+	handler.maintenance = &atomic.Bool{}
+
+	resp, err := handler.GetMaintenance(context.Background(), api.GetMaintenanceRequestObject{})
+	require.NoError(t, err)
+	assert.Equal(t, api.GetMaintenance200JSONResponse{Enabled: false}, resp)
+
+	setResp, err := handler.SetMaintenance(context.Background(), api.SetMaintenanceRequestObject{
+		Body: &api.MaintenanceStatus{Enabled: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, api.SetMaintenance200JSONResponse{Enabled: true}, setResp)
+	assert.True(t, handler.maintenance.Load())
+
+	resp, err = handler.GetMaintenance(context.Background(), api.GetMaintenanceRequestObject{})
+	require.NoError(t, err)
+	assert.Equal(t, api.GetMaintenance200JSONResponse{Enabled: true}, resp)
+}
+
+func TestReconcileBalances_ReportsDrifts(t *testing.T) {
+	mockAccount := mocks.NewMockAccountReader(t)
+	handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+
+	accountID := uuid.New()
+	mockAccount.On("ReconcileBalances", mock.Anything).Return([]*models.BalanceDrift{
+		{AccountID: accountID, Currency: "USD", BalanceCents: 1500, AvailableBalanceCents: 1200, LedgerCents: 1000, LedgerAvailableCents: 1000},
+	}, nil)
+
+	resp, err := handler.ReconcileBalances(context.Background(), api.ReconcileBalancesRequestObject{})
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.ReconcileBalances200JSONResponse)
+	require.True(t, ok)
+	assert.False(t, successResp.CheckedAt.IsZero())
+	assert.Equal(t, []api.BalanceDrift{
+		{
+			AccountId: accountID, Currency: "USD", BalanceCents: 1500, AvailableBalanceCents: 1200,
+			LedgerCents: 1000, LedgerAvailableCents: 1000, DriftCents: 500, AvailableDriftCents: 200,
+		},
+	}, successResp.Drifts)
+}
+
+func TestReconcileBalances_NoDriftsIsEmptyList(t *testing.T) {
+	mockAccount := mocks.NewMockAccountReader(t)
+	handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+	mockAccount.On("ReconcileBalances", mock.Anything).Return([]*models.BalanceDrift{}, nil)
+
+	resp, err := handler.ReconcileBalances(context.Background(), api.ReconcileBalancesRequestObject{})
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.ReconcileBalances200JSONResponse)
+	require.True(t, ok)
+	assert.NotNil(t, successResp.Drifts, "drifts must encode as [] rather than null")
+	assert.Empty(t, successResp.Drifts)
+}
+
+func TestReconcileAccount(t *testing.T) {
+	t.Run("drift", func(t *testing.T) {
+		mockAccount := mocks.NewMockAccountReader(t)
+		handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+		accountID := uuid.New()
+		mockAccount.On("ReconcileAccount", mock.Anything, accountID).Return(int64(-250), nil)
+
+		resp, err := handler.ReconcileAccount(context.Background(), api.ReconcileAccountRequestObject{AccountId: accountID.String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, api.ReconcileAccount200JSONResponse{AccountId: accountID, DriftCents: -250}, resp)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewHandler(nil, nil, nil, nil, mocks.NewMockAccountReader(t), nil, nil, nil, testLogger())
+
+		resp, err := handler.ReconcileAccount(context.Background(), api.ReconcileAccountRequestObject{AccountId: "not-a-uuid"})
+
+		require.NoError(t, err)
+		_, ok := resp.(api.ReconcileAccount400JSONResponse)
+		assert.True(t, ok)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockAccount := mocks.NewMockAccountReader(t)
+		handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+		mockAccount.On("ReconcileAccount", mock.Anything, mock.Anything).
+			Return(int64(0), &service.ServiceError{Code: service.ErrCodeAccountNotFound, Err: models.ErrAccountNotFound})
+
+		resp, err := handler.ReconcileAccount(context.Background(), api.ReconcileAccountRequestObject{AccountId: uuid.NewString()})
+
+		require.NoError(t, err)
+		_, ok := resp.(api.ReconcileAccount404JSONResponse)
+		assert.True(t, ok)
+	})
+}
+
+func TestListLowBalanceAccounts(t *testing.T) {
+	t.Run("masks card numbers and applies the default page", func(t *testing.T) {
+		mockAccount := mocks.NewMockAccountReader(t)
+		handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+
+		account := &models.Account{
+			ID:                    uuid.New(),
+			AccountNumber:         "4111111111111111",
+			Currency:              "USD",
+			BalanceCents:          900,
+			AvailableBalanceCents: 400,
+		}
+		mockAccount.On("ListLowBalance", mock.Anything, int64(1000), defaultLowBalanceLimit, 0).
+			Return([]*models.Account{account}, nil)
+
+		resp, err := handler.ListLowBalanceAccounts(context.Background(), api.ListLowBalanceAccountsRequestObject{
+			Params: api.ListLowBalanceAccountsParams{ThresholdCents: 1000},
+		})
+
+		require.NoError(t, err)
+		successResp, ok := resp.(api.ListLowBalanceAccounts200JSONResponse)
+		require.True(t, ok)
+		assert.Equal(t, defaultLowBalanceLimit, successResp.Limit)
+		assert.Equal(t, 0, successResp.Offset)
+		require.Len(t, successResp.Accounts, 1)
+		assert.Equal(t, account.ID, successResp.Accounts[0].Id)
+		assert.Equal(t, "************1111", successResp.Accounts[0].AccountNumber)
+		assert.Equal(t, int64(400), successResp.Accounts[0].AvailableBalanceCents)
+		assert.Equal(t, int64(500), successResp.Accounts[0].PendingCents)
+	})
+
+	t.Run("passes the requested page through", func(t *testing.T) {
+		mockAccount := mocks.NewMockAccountReader(t)
+		handler := NewHandler(nil, nil, nil, nil, mockAccount, nil, nil, nil, testLogger())
+		mockAccount.On("ListLowBalance", mock.Anything, int64(0), 5, 10).Return([]*models.Account{}, nil)
+
+		resp, err := handler.ListLowBalanceAccounts(context.Background(), api.ListLowBalanceAccountsRequestObject{
+			Params: api.ListLowBalanceAccountsParams{ThresholdCents: 0, Limit: 5, Offset: 10},
+		})
+
+		require.NoError(t, err)
+		successResp, ok := resp.(api.ListLowBalanceAccounts200JSONResponse)
+		require.True(t, ok)
+		assert.Empty(t, successResp.Accounts)
+		assert.NotNil(t, successResp.Accounts)
+	})
+
+	for name, params := range map[string]api.ListLowBalanceAccountsParams{
+		"limit too large": {ThresholdCents: 1000, Limit: maxLowBalanceLimit + 1},
+		"negative limit":  {ThresholdCents: 1000, Limit: -1},
+		"negative offset": {ThresholdCents: 1000, Offset: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler(nil, nil, nil, nil, mocks.NewMockAccountReader(t), nil, nil, nil, testLogger())
+
+			resp, err := handler.ListLowBalanceAccounts(context.Background(), api.ListLowBalanceAccountsRequestObject{Params: params})
+
+			require.NoError(t, err)
+			_, ok := resp.(api.ListLowBalanceAccounts400JSONResponse)
+			assert.True(t, ok)
+		})
+	}
+}
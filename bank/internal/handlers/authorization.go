@@ -2,35 +2,75 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/benx421/payment-gateway/bank/internal/validate"
 )
 
+// defaultCurrency applies when an authorization request omits the currency
+const defaultCurrency = "USD"
+
 // CreateAuthorization handles POST /api/v1/authorizations
 func (h *Handler) CreateAuthorization(
 	ctx context.Context,
 	request api.CreateAuthorizationRequestObject,
 ) (api.CreateAuthorizationResponseObject, error) {
+	currency := request.Body.Currency
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	amount, fieldErrs := validateAuthorization(request.Body, currency)
+	if len(fieldErrs) > 0 {
+		return api.CreateAuthorization422JSONResponse{UnprocessableEntityJSONResponse: validationFailed(fieldErrs)}, nil
+	}
+
+	var expiresAt *time.Time
+	if !request.Body.ExpiresAt.IsZero() {
+		expiresAt = &request.Body.ExpiresAt
+	}
+
+	if request.Params.Simulate {
+		txn, err := h.authService.SimulateAuthorization(
+			ctx,
+			request.Body.CardNumber,
+			request.Body.Cvv,
+			amount,
+			currency,
+			expiresAt,
+		)
+		if err != nil {
+			return h.handleAuthorizationError(ctx, err)
+		}
+
+		resp := authorizationResponse(txn)
+		resp.Simulated = true
+		tracing.SetAccountID(ctx, txn.AccountID)
+		return api.CreateAuthorization200JSONResponse(resp), nil
+	}
+
+	ctx = recordIdempotentResponse(ctx, h.tables, authorizationResponse)
 	txn, err := h.authService.Authorize(
 		ctx,
 		request.Body.CardNumber,
 		request.Body.Cvv,
-		request.Body.Amount,
+		amount,
+		currency,
+		expiresAt,
 	)
 
 	if err != nil {
-		return h.handleAuthorizationError(err)
+		return h.handleAuthorizationError(ctx, err)
 	}
 
-	return api.CreateAuthorization200JSONResponse{
-		AuthorizationId: formatAuthorizationID(txn.ID),
-		Status:          api.Approved,
-		Amount:          txn.AmountCents,
-		Currency:        txn.Currency,
-		ExpiresAt:       *txn.ExpiresAt,
-		CreatedAt:       txn.CreatedAt,
-	}, nil
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.CreateAuthorization200JSONResponse(authorizationResponse(txn)), nil
 }
 
 // GetAuthorization handles GET /api/v1/authorizations/{authorizationId}
@@ -42,10 +82,7 @@ func (h *Handler) GetAuthorization(
 	if err != nil {
 		//nolint:nilerr // Returning 404 response object, not propagating error
 		return api.GetAuthorization404JSONResponse{
-			NotFoundJSONResponse: api.NotFoundJSONResponse{
-				Error:   api.ErrorCodeNotFound,
-				Message: "authorization not found",
-			},
+			NotFoundJSONResponse: notFound(api.ErrorCodeNotFound, "authorization not found"),
 		}, nil
 	}
 
@@ -53,58 +90,93 @@ func (h *Handler) GetAuthorization(
 	if err != nil {
 		//nolint:nilerr // Returning 404 response object, not propagating error
 		return api.GetAuthorization404JSONResponse{
-			NotFoundJSONResponse: api.NotFoundJSONResponse{
-				Error:   api.ErrorCodeNotFound,
-				Message: "authorization not found",
-			},
+			NotFoundJSONResponse: notFound(api.ErrorCodeNotFound, "authorization not found"),
 		}, nil
 	}
 
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.GetAuthorization200JSONResponse(authorizationResponse(txn)), nil
+}
+
+// validateAuthorization checks the fields of an authorization request and returns the requested
+// amount in minor units, from amount or amount_decimal. Whether the card and CVV match an account
+// is left to the service.
+func validateAuthorization(body *api.CreateAuthorizationJSONRequestBody, currency string) (int64, []validate.FieldError) {
+	var v validate.Validator
+	v.Required("card_number", body.CardNumber)
+	v.Digits("card_number", body.CardNumber, 13, 19)
+	v.Required("cvv", body.Cvv)
+	v.Digits("cvv", body.Cvv, 3, 4)
+	// The account's stored expiry is what is checked, so these are only validated when sent
+	if body.ExpiryMonth != 0 {
+		v.Between("expiry_month", int64(body.ExpiryMonth), 1, 12)
+	}
+	if body.ExpiryYear != 0 {
+		v.Between("expiry_year", int64(body.ExpiryYear), 2024, 2099)
+	}
+	v.Check(models.Currencies.IsValid(currency), "currency", "must be a supported ISO 4217 currency code")
+
+	amount := body.Amount
+	switch {
+	case body.AmountDecimal != "" && body.Amount != 0:
+		v.Check(false, "amount_decimal", "cannot be set together with amount")
+	case body.AmountDecimal != "":
+		var err error
+		amount, err = models.ParseAmount(body.AmountDecimal, currency)
+		// An unknown currency is already reported against currency
+		v.Check(err == nil || errors.Is(err, models.ErrInvalidCurrency), "amount_decimal", fmt.Sprintf(
+			"must be a decimal number with at most %d decimal places", models.Currencies.MinorUnits(currency)))
+	default:
+		v.Positive("amount", body.Amount)
+	}
+
+	return amount, v.Errors()
+}
+
+func authorizationResponse(txn *models.Transaction) api.AuthorizationResponse {
 	expiresAt := time.Time{}
 	if txn.ExpiresAt != nil {
 		expiresAt = *txn.ExpiresAt
 	}
 
-	return api.GetAuthorization200JSONResponse{
+	return api.AuthorizationResponse{
 		AuthorizationId: formatAuthorizationID(txn.ID),
 		Status:          api.Approved,
 		Amount:          txn.AmountCents,
 		Currency:        txn.Currency,
 		ExpiresAt:       expiresAt,
 		CreatedAt:       txn.CreatedAt,
-	}, nil
+	}
 }
 
 // handleAuthorizationError maps service errors to appropriate HTTP responses
 func (h *Handler) handleAuthorizationError(
+	ctx context.Context,
 	err error,
 ) (api.CreateAuthorizationResponseObject, error) {
+	status := errorStatus(err)
+	if status == http.StatusServiceUnavailable {
+		h.logger.WarnContext(ctx, "authorization conflicted with a concurrent request", "error", err)
+		return api.CreateAuthorization503JSONResponse{ServiceUnavailableJSONResponse: transactionConflict()}, nil
+	}
+
 	svcErr := extractServiceError(err)
-	if svcErr == nil {
-		h.logger.Error("unexpected error during authorization", "error", err)
+	if svcErr == nil || status == http.StatusInternalServerError {
+		h.logger.ErrorContext(ctx, "unexpected error during authorization", "error", err)
 		return api.CreateAuthorization500JSONResponse{
-			InternalErrorJSONResponse: api.InternalErrorJSONResponse{
-				Error:   api.ErrorCodeInternalError,
-				Message: "internal error",
-			},
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
 		}, nil
 	}
 
 	errorCode := mapServiceErrorToCode(svcErr.Code)
 
-	if isPaymentRequiredError(svcErr.Code) {
+	if status == http.StatusPaymentRequired {
 		return api.CreateAuthorization402JSONResponse{
-			PaymentRequiredJSONResponse: api.PaymentRequiredJSONResponse{
-				Error:   errorCode,
-				Message: svcErr.Message,
-			},
+			PaymentRequiredJSONResponse: paymentRequired(errorCode, svcErr.Message),
 		}, nil
 	}
 
 	return api.CreateAuthorization400JSONResponse{
-		BadRequestJSONResponse: api.BadRequestJSONResponse{
-			Error:   errorCode,
-			Message: svcErr.Message,
-		},
+		BadRequestJSONResponse: badRequest(errorCode, svcErr.Message),
 	}, nil
 }
@@ -23,12 +23,12 @@ func testLogger() *slog.Logger {
 
 func TestCreateAuthorization_Success(t *testing.T) {
 	mockAuth := mocks.NewMockAuthorizer(t)
-	handler := NewHandler(mockAuth, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(mockAuth, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	txnID := uuid.New()
 	expiresAt := time.Now().Add(24 * time.Hour)
 
-	mockAuth.On("Authorize", mock.Anything, "4111111111111111", "123", int64(10000)).
+	mockAuth.On("Authorize", mock.Anything, "4111111111111111", "123", int64(10000), "USD", (*time.Time)(nil)).
 		Return(&models.Transaction{
 			ID:          txnID,
 			AmountCents: 10000,
@@ -52,6 +52,128 @@ func TestCreateAuthorization_Success(t *testing.T) {
 	require.True(t, ok, "expected 200 response")
 	assert.Equal(t, api.Approved, successResp.Status)
 	assert.Equal(t, int64(10000), successResp.Amount)
+	assert.False(t, successResp.Simulated)
+}
+
+func TestCreateAuthorization_SimulatedWithExpiry(t *testing.T) {
+	mockAuth := mocks.NewMockAuthorizer(t)
+	handler := NewHandler(mockAuth, nil, nil, nil, nil, nil, nil, nil, testLogger())
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	mockAuth.On("SimulateAuthorization", mock.Anything, "4111111111111111", "123", int64(10000), "USD", &expiresAt).
+		Return(&models.Transaction{
+			ID:          uuid.New(),
+			AmountCents: 10000,
+			Currency:    "USD",
+			ExpiresAt:   &expiresAt,
+			CreatedAt:   time.Now(),
+		}, nil)
+
+	req := api.CreateAuthorizationRequestObject{
+		Params: api.CreateAuthorizationParams{Simulate: true},
+		Body: &api.CreateAuthorizationJSONRequestBody{
+			CardNumber: "4111111111111111",
+			Cvv:        "123",
+			Amount:     10000,
+			ExpiresAt:  expiresAt,
+		},
+	}
+
+	resp, err := handler.CreateAuthorization(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.CreateAuthorization200JSONResponse)
+	require.True(t, ok, "expected 200 response")
+	assert.True(t, successResp.Simulated)
+	mockAuth.AssertNotCalled(t, "Authorize", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateAuthorization_DecimalAmount(t *testing.T) {
+	mockAuth := mocks.NewMockAuthorizer(t)
+	handler := NewHandler(mockAuth, nil, nil, nil, nil, nil, nil, nil, testLogger())
+
+	mockAuth.On("Authorize", mock.Anything, "4111111111111111", "123", int64(1234), "BHD", (*time.Time)(nil)).
+		Return(&models.Transaction{ID: uuid.New(), AmountCents: 1234, Currency: "BHD", CreatedAt: time.Now()}, nil)
+
+	resp, err := handler.CreateAuthorization(context.Background(), api.CreateAuthorizationRequestObject{
+		Body: &api.CreateAuthorizationJSONRequestBody{
+			CardNumber:    "4111111111111111",
+			Cvv:           "123",
+			AmountDecimal: "1.234",
+			Currency:      "BHD",
+		},
+	})
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.CreateAuthorization200JSONResponse)
+	require.True(t, ok, "expected 200 response")
+	assert.Equal(t, int64(1234), successResp.Amount)
+}
+
+func TestCreateAuthorization_ValidationErrors(t *testing.T) {
+	valid := func() *api.CreateAuthorizationJSONRequestBody {
+		return &api.CreateAuthorizationJSONRequestBody{CardNumber: "4111111111111111", Cvv: "123", Amount: 1000}
+	}
+
+	tests := []struct {
+		body       func() *api.CreateAuthorizationJSONRequestBody
+		name       string
+		wantFields []api.FieldError
+	}{
+		{
+			name: "too many decimal places",
+			body: func() *api.CreateAuthorizationJSONRequestBody {
+				b := valid()
+				b.Amount, b.AmountDecimal = 0, "0.005"
+				return b
+			},
+			wantFields: []api.FieldError{{Field: "amount_decimal", Message: "must be a decimal number with at most 2 decimal places"}},
+		},
+		{
+			name: "both amounts",
+			body: func() *api.CreateAuthorizationJSONRequestBody {
+				b := valid()
+				b.AmountDecimal = "10.00"
+				return b
+			},
+			wantFields: []api.FieldError{{Field: "amount_decimal", Message: "cannot be set together with amount"}},
+		},
+		{
+			name: "unknown currency",
+			body: func() *api.CreateAuthorizationJSONRequestBody {
+				b := valid()
+				b.Amount, b.AmountDecimal, b.Currency = 0, "10.00", "XXX"
+				return b
+			},
+			wantFields: []api.FieldError{{Field: "currency", Message: "must be a supported ISO 4217 currency code"}},
+		},
+		{
+			name: "every field reported",
+			body: func() *api.CreateAuthorizationJSONRequestBody {
+				return &api.CreateAuthorizationJSONRequestBody{CardNumber: "4111-1111", ExpiryMonth: 13}
+			},
+			wantFields: []api.FieldError{
+				{Field: "card_number", Message: "must be 13 to 19 digits"},
+				{Field: "cvv", Message: "is required"},
+				{Field: "expiry_month", Message: "must be between 1 and 12"},
+				{Field: "amount", Message: "must be positive"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(mocks.NewMockAuthorizer(t), nil, nil, nil, nil, nil, nil, nil, testLogger())
+
+			resp, err := handler.CreateAuthorization(context.Background(), api.CreateAuthorizationRequestObject{Body: tt.body()})
+
+			require.NoError(t, err)
+			invalidResp, ok := resp.(api.CreateAuthorization422JSONResponse)
+			require.True(t, ok, "expected 422 response")
+			assert.Equal(t, api.ErrorCodeValidationFailed, invalidResp.Error.Code)
+			assert.Equal(t, tt.wantFields, invalidResp.Error.Fields)
+		})
+	}
 }
 
 func TestCreateAuthorization_ServiceErrors(t *testing.T) {
@@ -67,6 +189,18 @@ func TestCreateAuthorization_ServiceErrors(t *testing.T) {
 			expectedStatus: 400,
 			expectedCode:   api.ErrorCodeInvalidCard,
 		},
+		{
+			name:           "missing FX rate returns 400",
+			serviceErr:     &service.ServiceError{Code: service.ErrCodeFXRateUnavailable, Message: "no rate"},
+			expectedStatus: 400,
+			expectedCode:   api.ErrorCodeFxRateUnavailable,
+		},
+		{
+			name:           "invalid expiry returns 400",
+			serviceErr:     &service.ServiceError{Code: service.ErrCodeInvalidExpiry, Message: "invalid expiry"},
+			expectedStatus: 400,
+			expectedCode:   api.ErrorCodeInvalidExpiry,
+		},
 		{
 			name:           "insufficient funds returns 402",
 			serviceErr:     &service.ServiceError{Code: service.ErrCodeInsufficientFunds, Message: "insufficient"},
@@ -78,9 +212,9 @@ func TestCreateAuthorization_ServiceErrors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockAuth := mocks.NewMockAuthorizer(t)
-			handler := NewHandler(mockAuth, nil, nil, nil, nil, testLogger())
+			handler := NewHandler(mockAuth, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
-			mockAuth.On("Authorize", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mockAuth.On("Authorize", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 				Return(nil, tt.serviceErr)
 
 			req := api.CreateAuthorizationRequestObject{
@@ -98,11 +232,11 @@ func TestCreateAuthorization_ServiceErrors(t *testing.T) {
 			case 400:
 				badResp, ok := resp.(api.CreateAuthorization400JSONResponse)
 				require.True(t, ok)
-				assert.Equal(t, tt.expectedCode, badResp.Error)
+				assert.Equal(t, tt.expectedCode, badResp.Error.Code)
 			case 402:
 				payResp, ok := resp.(api.CreateAuthorization402JSONResponse)
 				require.True(t, ok)
-				assert.Equal(t, tt.expectedCode, payResp.Error)
+				assert.Equal(t, tt.expectedCode, payResp.Error.Code)
 			}
 		})
 	}
@@ -110,7 +244,7 @@ func TestCreateAuthorization_ServiceErrors(t *testing.T) {
 
 func TestGetAuthorization_Success(t *testing.T) {
 	mockAuth := mocks.NewMockAuthorizer(t)
-	handler := NewHandler(mockAuth, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(mockAuth, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	txnID := uuid.New()
 	expiresAt := time.Now().Add(24 * time.Hour)
@@ -137,7 +271,7 @@ func TestGetAuthorization_Success(t *testing.T) {
 
 func TestGetAuthorization_NotFound(t *testing.T) {
 	mockAuth := mocks.NewMockAuthorizer(t)
-	handler := NewHandler(mockAuth, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(mockAuth, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	txnID := uuid.New()
 	mockAuth.On("GetAuthorization", mock.Anything, txnID).
@@ -152,11 +286,11 @@ func TestGetAuthorization_NotFound(t *testing.T) {
 	require.NoError(t, err)
 	notFoundResp, ok := resp.(api.GetAuthorization404JSONResponse)
 	require.True(t, ok)
-	assert.Equal(t, api.ErrorCodeNotFound, notFoundResp.Error)
+	assert.Equal(t, api.ErrorCodeNotFound, notFoundResp.Error.Code)
 }
 
 func TestGetAuthorization_InvalidIDFormat(t *testing.T) {
-	handler := NewHandler(nil, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	req := api.GetAuthorizationRequestObject{
 		AuthorizationId: "invalid-format",
@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/benx421/payment-gateway/bank/internal/validate"
+	"github.com/google/uuid"
 )
 
 // CreateCapture handles POST /api/v1/captures
@@ -11,30 +16,19 @@ func (h *Handler) CreateCapture(
 	ctx context.Context,
 	request api.CreateCaptureRequestObject,
 ) (api.CreateCaptureResponseObject, error) {
-	authID, err := parseAuthorizationID(request.Body.AuthorizationId)
-	if err != nil {
-		//nolint:nilerr // Returning 400 response object, not propagating error
-		return api.CreateCapture400JSONResponse{
-			BadRequestJSONResponse: api.BadRequestJSONResponse{
-				Error:   api.ErrorCodeAuthorizationNotFound,
-				Message: "invalid authorization ID format",
-			},
-		}, nil
+	authID, fieldErrs := validateCapture(request.Body)
+	if len(fieldErrs) > 0 {
+		return api.CreateCapture422JSONResponse{UnprocessableEntityJSONResponse: validationFailed(fieldErrs)}, nil
 	}
 
+	ctx = recordIdempotentResponse(ctx, h.tables, captureResponse)
 	txn, err := h.captureService.Capture(ctx, authID, request.Body.Amount)
 	if err != nil {
-		return h.handleCaptureError(err)
+		return h.handleCaptureError(ctx, err)
 	}
 
-	return api.CreateCapture200JSONResponse{
-		CaptureId:       formatCaptureID(txn.ID),
-		AuthorizationId: formatAuthorizationID(*txn.ReferenceID),
-		Status:          api.Captured,
-		Amount:          txn.AmountCents,
-		Currency:        txn.Currency,
-		CapturedAt:      txn.CreatedAt,
-	}, nil
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.CreateCapture200JSONResponse(captureResponse(txn)), nil
 }
 
 // GetCapture handles GET /api/v1/captures/{captureId}
@@ -46,10 +40,7 @@ func (h *Handler) GetCapture(
 	if err != nil {
 		//nolint:nilerr // Returning 404 response object, not propagating error
 		return api.GetCapture404JSONResponse{
-			NotFoundJSONResponse: api.NotFoundJSONResponse{
-				Error:   api.ErrorCodeNotFound,
-				Message: "capture not found",
-			},
+			NotFoundJSONResponse: notFound(api.ErrorCodeNotFound, "capture not found"),
 		}, nil
 	}
 
@@ -57,42 +48,54 @@ func (h *Handler) GetCapture(
 	if err != nil {
 		//nolint:nilerr // Returning 404 response object, not propagating error
 		return api.GetCapture404JSONResponse{
-			NotFoundJSONResponse: api.NotFoundJSONResponse{
-				Error:   api.ErrorCodeNotFound,
-				Message: "capture not found",
-			},
+			NotFoundJSONResponse: notFound(api.ErrorCodeNotFound, "capture not found"),
 		}, nil
 	}
 
-	return api.GetCapture200JSONResponse{
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.GetCapture200JSONResponse(captureResponse(txn)), nil
+}
+
+// validateCapture checks the fields of a capture request and returns the authorization's ID
+func validateCapture(body *api.CreateCaptureJSONRequestBody) (uuid.UUID, []validate.FieldError) {
+	var v validate.Validator
+	v.Required("authorization_id", body.AuthorizationId)
+	authID, err := parseAuthorizationID(body.AuthorizationId)
+	v.Check(err == nil, "authorization_id", "must be "+PrefixAuthorization+" followed by a UUID")
+	v.Positive("amount", body.Amount)
+	return authID, v.Errors()
+}
+
+func captureResponse(txn *models.Transaction) api.CaptureResponse {
+	return api.CaptureResponse{
 		CaptureId:       formatCaptureID(txn.ID),
 		AuthorizationId: formatAuthorizationID(*txn.ReferenceID),
 		Status:          api.Captured,
 		Amount:          txn.AmountCents,
 		Currency:        txn.Currency,
 		CapturedAt:      txn.CreatedAt,
-	}, nil
+	}
 }
 
 // handleCaptureError maps service errors to appropriate HTTP responses
-func (h *Handler) handleCaptureError(err error) (api.CreateCaptureResponseObject, error) {
+func (h *Handler) handleCaptureError(ctx context.Context, err error) (api.CreateCaptureResponseObject, error) {
+	status := errorStatus(err)
+	if status == http.StatusServiceUnavailable {
+		h.logger.WarnContext(ctx, "capture conflicted with a concurrent request", "error", err)
+		return api.CreateCapture503JSONResponse{ServiceUnavailableJSONResponse: transactionConflict()}, nil
+	}
+
 	svcErr := extractServiceError(err)
-	if svcErr == nil {
-		h.logger.Error("unexpected error during capture", "error", err)
+	if svcErr == nil || status == http.StatusInternalServerError {
+		h.logger.ErrorContext(ctx, "unexpected error during capture", "error", err)
 		return api.CreateCapture500JSONResponse{
-			InternalErrorJSONResponse: api.InternalErrorJSONResponse{
-				Error:   api.ErrorCodeInternalError,
-				Message: "internal error",
-			},
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
 		}, nil
 	}
 
 	errorCode := mapServiceErrorToCode(svcErr.Code)
 
 	return api.CreateCapture400JSONResponse{
-		BadRequestJSONResponse: api.BadRequestJSONResponse{
-			Error:   errorCode,
-			Message: svcErr.Message,
-		},
+		BadRequestJSONResponse: badRequest(errorCode, svcErr.Message),
 	}, nil
 }
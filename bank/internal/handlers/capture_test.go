@@ -17,7 +17,7 @@ import (
 
 func TestCreateCapture_Success(t *testing.T) {
 	mockCapture := mocks.NewMockCapturer(t)
-	handler := NewHandler(nil, mockCapture, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, mockCapture, nil, nil, nil, nil, nil, nil, testLogger())
 
 	authID := uuid.New()
 	captureID := uuid.New()
@@ -55,12 +55,13 @@ func TestCreateCapture_ServiceErrors(t *testing.T) {
 		{"auth not found", &service.ServiceError{Code: service.ErrCodeAuthNotFound}, api.ErrorCodeAuthorizationNotFound},
 		{"auth expired", &service.ServiceError{Code: service.ErrCodeAuthExpired}, api.ErrorCodeAuthorizationExpired},
 		{"already captured", &service.ServiceError{Code: service.ErrCodeAlreadyCaptured}, api.ErrorCodeAlreadyCaptured},
+		{"capture exceeds authorization", &service.ServiceError{Code: service.ErrCodeCaptureExceedsAuth}, api.ErrorCodeCaptureExceedsAuthorization},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCapture := mocks.NewMockCapturer(t)
-			handler := NewHandler(nil, mockCapture, nil, nil, nil, testLogger())
+			handler := NewHandler(nil, mockCapture, nil, nil, nil, nil, nil, nil, testLogger())
 
 			mockCapture.On("Capture", mock.Anything, mock.Anything, mock.Anything).
 				Return(nil, tt.serviceErr)
@@ -77,13 +78,13 @@ func TestCreateCapture_ServiceErrors(t *testing.T) {
 
 			badResp, ok := resp.(api.CreateCapture400JSONResponse)
 			require.True(t, ok)
-			assert.Equal(t, tt.expectedCode, badResp.Error)
+			assert.Equal(t, tt.expectedCode, badResp.Error.Code)
 		})
 	}
 }
 
 func TestCreateCapture_InvalidIDFormat(t *testing.T) {
-	handler := NewHandler(nil, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	req := api.CreateCaptureRequestObject{
 		Body: &api.CreateCaptureJSONRequestBody{
@@ -95,14 +96,17 @@ func TestCreateCapture_InvalidIDFormat(t *testing.T) {
 	resp, err := handler.CreateCapture(context.Background(), req)
 
 	require.NoError(t, err)
-	badResp, ok := resp.(api.CreateCapture400JSONResponse)
+	invalidResp, ok := resp.(api.CreateCapture422JSONResponse)
 	require.True(t, ok)
-	assert.Equal(t, api.ErrorCodeAuthorizationNotFound, badResp.Error)
+	assert.Equal(t, api.ErrorCodeValidationFailed, invalidResp.Error.Code)
+	assert.Equal(t, []api.FieldError{
+		{Field: "authorization_id", Message: "must be auth_ followed by a UUID"},
+	}, invalidResp.Error.Fields)
 }
 
 func TestGetCapture_Success(t *testing.T) {
 	mockCapture := mocks.NewMockCapturer(t)
-	handler := NewHandler(nil, mockCapture, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, mockCapture, nil, nil, nil, nil, nil, nil, testLogger())
 
 	authID := uuid.New()
 	captureID := uuid.New()
@@ -126,7 +130,7 @@ func TestGetCapture_Success(t *testing.T) {
 
 func TestGetCapture_NotFound(t *testing.T) {
 	mockCapture := mocks.NewMockCapturer(t)
-	handler := NewHandler(nil, mockCapture, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, mockCapture, nil, nil, nil, nil, nil, nil, testLogger())
 
 	captureID := uuid.New()
 	mockCapture.On("GetCapture", mock.Anything, captureID).
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+)
+
+// exportPath streams its response, so it is exempt from the request timeout
+const exportPath = "/api/v1/transactions/export"
+
+// exportFilenameLayout formats the export window in the attachment filename
+const exportFilenameLayout = "20060102T150405Z"
+
+var exportHeader = []string{
+	"id", "created_at", "updated_at", "account_id", "card_number",
+	"type", "status", "amount", "currency", "reference_id",
+}
+
+// ExportTransactions handles GET /api/v1/transactions/export
+func (h *Handler) ExportTransactions(
+	ctx context.Context,
+	request api.ExportTransactionsRequestObject,
+) (api.ExportTransactionsResponseObject, error) {
+	from, to := request.Params.From.UTC(), request.Params.To.UTC()
+	if !from.Before(to) {
+		return api.ExportTransactions400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, "from must be before to"),
+		}, nil
+	}
+
+	return transactionExport{ctx: ctx, svc: h.txnService, logger: h.logger, from: from, to: to}, nil
+}
+
+// transactionExport writes the CSV as rows are read from the database rather than buffering the export
+type transactionExport struct {
+	from   time.Time
+	to     time.Time
+	ctx    context.Context
+	svc    service.TransactionReader
+	logger *slog.Logger
+}
+
+func (e transactionExport) VisitExportTransactionsResponse(w http.ResponseWriter) error {
+	// An export can outlast the server's write timeout; the request context still bounds it
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		e.logger.WarnContext(e.ctx, "export keeps the server write timeout", "error", err)
+	}
+
+	out := &exportWriter{
+		w: w,
+		filename: fmt.Sprintf("transactions_%s_%s.csv",
+			e.from.Format(exportFilenameLayout), e.to.Format(exportFilenameLayout)),
+	}
+	cw := csv.NewWriter(out)
+	if err := cw.Write(exportHeader); err != nil {
+		return err
+	}
+
+	err := e.svc.ExportTransactions(e.ctx, e.from, e.to, func(txn *models.ExportedTransaction) error {
+		return cw.Write(exportRecord(txn))
+	})
+	if err == nil {
+		cw.Flush()
+		err = cw.Error()
+	}
+	if err == nil {
+		return nil
+	}
+
+	if !out.started {
+		e.logger.ErrorContext(e.ctx, "unexpected error during transaction export", "error", err)
+		respond.Error(w, http.StatusInternalServerError, api.ErrorCodeInternalError, "internal error")
+		return nil
+	}
+	// The 200 and part of the CSV are already out, so the only way to signal failure is a truncated response
+	e.logger.ErrorContext(e.ctx, "transaction export aborted", "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+func exportRecord(txn *models.ExportedTransaction) []string {
+	var referenceID string
+	if txn.ReferenceID != nil {
+		referenceID = txn.ReferenceID.String()
+	}
+	return []string{
+		txn.ID.String(),
+		txn.CreatedAt.UTC().Format(time.RFC3339Nano),
+		txn.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		txn.AccountID.String(),
+		txn.MaskedAccountNumber,
+		string(txn.Type),
+		string(txn.Status),
+		strconv.FormatInt(txn.AmountCents, 10),
+		txn.Currency,
+		referenceID,
+	}
+}
+
+// exportWriter sends the CSV headers with the first bytes of the body,
+// so an export that fails before producing any rows can still answer with an error
+type exportWriter struct {
+	w        http.ResponseWriter
+	filename string
+	started  bool
+}
+
+func (ew *exportWriter) Write(b []byte) (int, error) {
+	if !ew.started {
+		ew.started = true
+		ew.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		ew.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ew.filename))
+		ew.w.WriteHeader(http.StatusOK)
+	}
+	return ew.w.Write(b)
+}
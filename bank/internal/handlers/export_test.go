@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	exportFrom = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	exportTo   = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+)
+
+func exportRequest() api.ExportTransactionsRequestObject {
+	return api.ExportTransactionsRequestObject{Params: api.ExportTransactionsParams{From: exportFrom, To: exportTo}}
+}
+
+func TestExportTransactions_StreamsCSV(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	authID := uuid.New()
+	captured := &models.ExportedTransaction{
+		Transaction: models.Transaction{
+			ID:          uuid.New(),
+			AccountID:   uuid.New(),
+			Type:        models.TransactionTypeCapture,
+			Status:      models.TransactionStatusCompleted,
+			AmountCents: 5000,
+			Currency:    "USD",
+			ReferenceID: &authID,
+			CreatedAt:   exportFrom.Add(time.Hour),
+			UpdatedAt:   exportFrom.Add(time.Hour),
+		},
+		MaskedAccountNumber: "************1111",
+	}
+	mockTxn.On("ExportTransactions", mock.Anything, exportFrom, exportTo, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func(*models.ExportedTransaction) error)
+			require.NoError(t, fn(captured))
+		}).
+		Return(nil)
+
+	resp, err := handler.ExportTransactions(context.Background(), exportRequest())
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, resp.VisitExportTransactionsResponse(rec))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="transactions_20260101T000000Z_20260102T000000Z.csv"`,
+		rec.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, exportHeader, records[0])
+	assert.Equal(t, []string{
+		captured.ID.String(),
+		"2026-01-01T01:00:00Z",
+		"2026-01-01T01:00:00Z",
+		captured.AccountID.String(),
+		"************1111",
+		"CAPTURE",
+		"COMPLETED",
+		"5000",
+		"USD",
+		authID.String(),
+	}, records[1])
+}
+
+func TestExportTransactions_EmptyRangeHasHeaderRow(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("ExportTransactions", mock.Anything, exportFrom, exportTo, mock.Anything).Return(nil)
+
+	resp, err := handler.ExportTransactions(context.Background(), exportRequest())
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, resp.VisitExportTransactionsResponse(rec))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{exportHeader}, records)
+}
+
+func TestExportTransactions_InvalidRange(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil, mocks.NewMockTransactionReader(t), nil, nil, testLogger())
+
+	req := api.ExportTransactionsRequestObject{Params: api.ExportTransactionsParams{From: exportTo, To: exportFrom}}
+	resp, err := handler.ExportTransactions(context.Background(), req)
+
+	require.NoError(t, err)
+	badResp, ok := resp.(api.ExportTransactions400JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.ErrorCodeInvalidRequest, badResp.Error.Code)
+}
+
+func TestExportTransactions_ErrorBeforeRowsIsInternalError(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("ExportTransactions", mock.Anything, exportFrom, exportTo, mock.Anything).
+		Return(&service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")})
+
+	resp, err := handler.ExportTransactions(context.Background(), exportRequest())
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, resp.VisitExportTransactionsResponse(rec))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+}
+
+func TestExportTransactions_ErrorMidStreamAbortsResponse(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("ExportTransactions", mock.Anything, exportFrom, exportTo, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func(*models.ExportedTransaction) error)
+			// Enough rows to fill the CSV writer's buffer and reach the client
+			for range 100 {
+				require.NoError(t, fn(&models.ExportedTransaction{MaskedAccountNumber: "************1111"}))
+			}
+		}).
+		Return(&service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection reset")})
+
+	resp, err := handler.ExportTransactions(context.Background(), exportRequest())
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		_ = resp.VisitExportTransactionsResponse(rec) //nolint:errcheck // the response aborts with a panic
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
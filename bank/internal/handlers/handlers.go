@@ -3,7 +3,9 @@ package handlers
 
 import (
 	"log/slog"
+	"sync/atomic"
 
+	"github.com/benx421/payment-gateway/bank/internal/repository"
 	"github.com/benx421/payment-gateway/bank/internal/service"
 )
 
@@ -13,7 +15,12 @@ type Handler struct {
 	captureService service.Capturer
 	voidService    service.Voider
 	refundService  service.Refunder
+	accountService service.AccountReader
+	txnService     service.TransactionReader
 	healthChecker  service.HealthChecker
+	ready          *atomic.Bool
+	maintenance    *atomic.Bool       // Set by NewRouter; pauses writes while true
+	tables         *repository.Tables // Set by NewRouter; nil uses the unprefixed tables
 	logger         *slog.Logger
 }
 
@@ -23,7 +30,10 @@ func NewHandler(
 	captureService service.Capturer,
 	voidService service.Voider,
 	refundService service.Refunder,
+	accountService service.AccountReader,
+	txnService service.TransactionReader,
 	healthChecker service.HealthChecker,
+	ready *atomic.Bool,
 	logger *slog.Logger,
 ) *Handler {
 	return &Handler{
@@ -31,7 +41,10 @@ func NewHandler(
 		captureService: captureService,
 		voidService:    voidService,
 		refundService:  refundService,
+		accountService: accountService,
+		txnService:     txnService,
 		healthChecker:  healthChecker,
+		ready:          ready,
 		logger:         logger,
 	}
 }
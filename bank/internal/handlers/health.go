@@ -5,24 +5,102 @@ import (
 	"time"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/buildinfo"
 )
 
 // GetHealth handles GET /health
+//
+// This is a liveness probe: it succeeds whenever the process can serve requests.
 func (h *Handler) GetHealth(
-	ctx context.Context,
-	request api.GetHealthRequestObject,
+	_ context.Context,
+	_ api.GetHealthRequestObject,
 ) (api.GetHealthResponseObject, error) {
+	return api.GetHealth200JSONResponse{
+		Status: api.Healthy,
+	}, nil
+}
+
+// GetReady handles GET /ready
+//
+// This is a readiness probe: it fails while the server is draining or the database is unreachable.
+func (h *Handler) GetReady(
+	ctx context.Context,
+	_ api.GetReadyRequestObject,
+) (api.GetReadyResponseObject, error) {
+	if h.ready == nil || !h.ready.Load() {
+		return api.GetReady503JSONResponse{
+			Status: api.Unhealthy,
+		}, nil
+	}
+
 	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
 	if err := h.healthChecker.PingContext(pingCtx); err != nil {
-		h.logger.Error("health check failed: database unreachable", "error", err)
-		return api.GetHealth503JSONResponse{
+		h.logger.ErrorContext(ctx, "readiness check failed: database unreachable", "error", err)
+		return api.GetReady503JSONResponse{
 			Status: api.Unhealthy,
 		}, nil
 	}
 
-	return api.GetHealth200JSONResponse{
+	return api.GetReady200JSONResponse{
 		Status: api.Healthy,
 	}, nil
 }
+
+// GetStatus handles GET /status
+//
+// This is a diagnostic endpoint for operators; unlike the probes it is behind API key auth.
+func (h *Handler) GetStatus(
+	ctx context.Context,
+	_ api.GetStatusRequestObject,
+) (api.GetStatusResponseObject, error) {
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	pingErr := h.healthChecker.PingContext(pingCtx)
+	stats := h.healthChecker.Stats()
+
+	resp := api.StatusResponse{
+		Status:        api.Healthy,
+		Version:       buildinfo.Version,
+		Commit:        buildinfo.Revision(),
+		StartedAt:     buildinfo.StartedAt(),
+		UptimeSeconds: int64(buildinfo.Uptime().Seconds()),
+		Database: api.DatabaseStatus{
+			Status:    api.Healthy,
+			LatencyMs: time.Since(start).Milliseconds(),
+			Pool: api.PoolStats{
+				MaxOpenConnections: stats.MaxOpenConnections,
+				OpenConnections:    stats.OpenConnections,
+				InUse:              stats.InUse,
+				Idle:               stats.Idle,
+				WaitCount:          stats.WaitCount,
+				WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+			},
+		},
+	}
+
+	if replica, ok := h.healthChecker.ReplicaStatus(); ok {
+		resp.Database.Replica = api.ReplicaStatus{
+			ServingReads: replica.ServingReads,
+			LagMs:        replica.Lag.Milliseconds(),
+			MaxLagMs:     replica.MaxLag.Milliseconds(),
+			CheckedAt:    replica.CheckedAt,
+		}
+		if replica.Err != nil {
+			resp.Database.Replica.Error = replica.Err.Error()
+		}
+	}
+
+	if pingErr != nil {
+		h.logger.WarnContext(ctx, "status check failed: database unreachable", "error", pingErr)
+		resp.Status = api.Unhealthy
+		resp.Database.Status = api.Unhealthy
+		resp.Database.Error = pingErr.Error()
+		return api.GetStatus503JSONResponse(resp), nil
+	}
+
+	return api.GetStatus200JSONResponse(resp), nil
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubHealthChecker struct {
+	err     error
+	replica *db.ReplicaStatus
+	stats   sql.DBStats
+}
+
+func (s stubHealthChecker) PingContext(_ context.Context) error {
+	return s.err
+}
+
+func (s stubHealthChecker) Stats() sql.DBStats {
+	return s.stats
+}
+
+func (s stubHealthChecker) ReplicaStatus() (db.ReplicaStatus, bool) {
+	if s.replica == nil {
+		return db.ReplicaStatus{}, false
+	}
+	return *s.replica, true
+}
+
+func TestGetHealth_AlwaysHealthy(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, stubHealthChecker{err: errors.New("db down")}, nil, testLogger())
+
+	resp, err := handler.GetHealth(context.Background(), api.GetHealthRequestObject{})
+
+	require.NoError(t, err)
+	healthResp, ok := resp.(api.GetHealth200JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.Healthy, healthResp.Status)
+}
+
+func TestGetReady(t *testing.T) {
+	tests := []struct {
+		pingErr       error
+		name          string
+		ready         bool
+		expectHealthy bool
+	}{
+		{name: "ready and database reachable", ready: true, expectHealthy: true},
+		{name: "not ready", ready: false},
+		{name: "database unreachable", ready: true, pingErr: errors.New("db down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready := &atomic.Bool{}
+			ready.Store(tt.ready)
+			handler := NewHandler(nil, nil, nil, nil, nil, nil, stubHealthChecker{err: tt.pingErr}, ready, testLogger())
+
+			resp, err := handler.GetReady(context.Background(), api.GetReadyRequestObject{})
+			require.NoError(t, err)
+
+			if tt.expectHealthy {
+				_, ok := resp.(api.GetReady200JSONResponse)
+				assert.True(t, ok, "expected 200 response")
+			} else {
+				_, ok := resp.(api.GetReady503JSONResponse)
+				assert.True(t, ok, "expected 503 response")
+			}
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	stats := sql.DBStats{MaxOpenConnections: 25, OpenConnections: 4, InUse: 1, Idle: 3, WaitCount: 2, WaitDuration: 1500 * time.Millisecond}
+
+	t.Run("database reachable", func(t *testing.T) {
+		handler := NewHandler(nil, nil, nil, nil, nil, nil, stubHealthChecker{stats: stats}, nil, testLogger())
+
+		resp, err := handler.GetStatus(context.Background(), api.GetStatusRequestObject{})
+		require.NoError(t, err)
+
+		status, ok := resp.(api.GetStatus200JSONResponse)
+		require.True(t, ok, "expected 200 response")
+		assert.Equal(t, api.Healthy, status.Status)
+		assert.NotEmpty(t, status.Version)
+		assert.NotEmpty(t, status.Commit)
+		assert.False(t, status.StartedAt.IsZero())
+		assert.Equal(t, api.Healthy, status.Database.Status)
+		assert.Empty(t, status.Database.Error)
+		assert.Equal(t, api.PoolStats{
+			MaxOpenConnections: 25,
+			OpenConnections:    4,
+			InUse:              1,
+			Idle:               3,
+			WaitCount:          2,
+			WaitDurationMs:     1500,
+		}, status.Database.Pool)
+	})
+
+	t.Run("database unreachable", func(t *testing.T) {
+		handler := NewHandler(nil, nil, nil, nil, nil, nil, stubHealthChecker{err: errors.New("db down"), stats: stats}, nil, testLogger())
+
+		resp, err := handler.GetStatus(context.Background(), api.GetStatusRequestObject{})
+		require.NoError(t, err)
+
+		status, ok := resp.(api.GetStatus503JSONResponse)
+		require.True(t, ok, "expected 503 response")
+		assert.Equal(t, api.Unhealthy, status.Status)
+		assert.Equal(t, api.Unhealthy, status.Database.Status)
+		assert.Equal(t, "db down", status.Database.Error)
+		assert.Equal(t, 4, status.Database.Pool.OpenConnections)
+	})
+
+	t.Run("lagging replica does not fail the check", func(t *testing.T) {
+		checkedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		replica := &db.ReplicaStatus{CheckedAt: checkedAt, Lag: 7200 * time.Millisecond, MaxLag: 5 * time.Second}
+		handler := NewHandler(nil, nil, nil, nil, nil, nil, stubHealthChecker{stats: stats, replica: replica}, nil, testLogger())
+
+		resp, err := handler.GetStatus(context.Background(), api.GetStatusRequestObject{})
+		require.NoError(t, err)
+
+		status, ok := resp.(api.GetStatus200JSONResponse)
+		require.True(t, ok, "expected 200 response")
+		assert.Equal(t, api.ReplicaStatus{
+			CheckedAt:    checkedAt,
+			LagMs:        7200,
+			MaxLagMs:     5000,
+			ServingReads: false,
+		}, status.Database.Replica)
+	})
+
+	t.Run("no replica configured", func(t *testing.T) {
+		handler := NewHandler(nil, nil, nil, nil, nil, nil, stubHealthChecker{stats: stats}, nil, testLogger())
+
+		resp, err := handler.GetStatus(context.Background(), api.GetStatusRequestObject{})
+		require.NoError(t, err)
+
+		status, ok := resp.(api.GetStatus200JSONResponse)
+		require.True(t, ok, "expected 200 response")
+		assert.True(t, status.Database.Replica.CheckedAt.IsZero())
+	})
+}
@@ -3,10 +3,17 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
 	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/validate"
 	"github.com/google/uuid"
 )
 
@@ -68,10 +75,24 @@ func mapServiceErrorToCode(code string) api.ErrorCode {
 		return api.ErrorCodeInvalidCvv
 	case service.ErrCodeInvalidAmount:
 		return api.ErrorCodeInvalidAmount
+	case service.ErrCodeAmountExceedsLimit:
+		return api.ErrorCodeAmountExceedsLimit
+	case service.ErrCodeVelocityExceeded:
+		return api.ErrorCodeVelocityExceeded
+	case service.ErrCodeDailyLimitExceeded:
+		return api.ErrorCodeDailyLimitExceeded
+	case service.ErrCodeInvalidCurrency:
+		return api.ErrorCodeInvalidCurrency
+	case service.ErrCodeFXRateUnavailable:
+		return api.ErrorCodeFxRateUnavailable
 	case service.ErrCodeCardExpired:
 		return api.ErrorCodeCardExpired
+	case service.ErrCodeInvalidExpiry:
+		return api.ErrorCodeInvalidExpiry
 	case service.ErrCodeInsufficientFunds:
 		return api.ErrorCodeInsufficientFunds
+	case service.ErrCodeAccountNotFound:
+		return api.ErrorCodeAccountNotFound
 	case service.ErrCodeAuthNotFound:
 		return api.ErrorCodeAuthorizationNotFound
 	case service.ErrCodeAuthExpired:
@@ -86,15 +107,119 @@ func mapServiceErrorToCode(code string) api.ErrorCode {
 		return api.ErrorCodeAlreadyRefunded
 	case service.ErrCodeAmountMismatch:
 		return api.ErrorCodeAmountMismatch
+	case service.ErrCodeRefundExceedsCapture:
+		return api.ErrorCodeRefundExceedsCapture
+	case service.ErrCodeCaptureExceedsAuth:
+		return api.ErrorCodeCaptureExceedsAuthorization
+	case service.ErrCodeVoidExceedsAuth:
+		return api.ErrorCodeVoidExceedsAuthorization
 	case service.ErrCodeCaptureNotFound:
 		return api.ErrorCodeCaptureNotFound
+	case service.ErrCodeRefundNotFound:
+		return api.ErrorCodeRefundNotFound
+	case service.ErrCodeTransactionNotFound:
+		return api.ErrorCodeTransactionNotFound
 	default:
 		return api.ErrorCodeInternalError
 	}
 }
 
-func isPaymentRequiredError(code string) bool {
-	return code == service.ErrCodeInsufficientFunds
+func badRequest(code api.ErrorCode, message string) api.BadRequestJSONResponse {
+	return api.BadRequestJSONResponse(respond.NewError(code, message))
+}
+
+// validationFailed reports every invalid request field in one response
+func validationFailed(fieldErrs []validate.FieldError) api.UnprocessableEntityJSONResponse {
+	resp := respond.NewError(api.ErrorCodeValidationFailed, "request validation failed")
+	resp.Error.Fields = make([]api.FieldError, len(fieldErrs))
+	for i, e := range fieldErrs {
+		resp.Error.Fields[i] = api.FieldError{Field: e.Field, Message: e.Message}
+	}
+	return api.UnprocessableEntityJSONResponse(resp)
+}
+
+func paymentRequired(code api.ErrorCode, message string) api.PaymentRequiredJSONResponse {
+	return api.PaymentRequiredJSONResponse(respond.NewError(code, message))
+}
+
+func notFound(code api.ErrorCode, message string) api.NotFoundJSONResponse {
+	return api.NotFoundJSONResponse(respond.NewError(code, message))
+}
+
+func internalError(code api.ErrorCode, message string) api.InternalErrorJSONResponse {
+	return api.InternalErrorJSONResponse(respond.NewError(code, message))
+}
+
+// retryAfterSeconds is the Retry-After sent with 503s for transactions that conflicted with another
+const retryAfterSeconds = 1
+
+// transactionConflict reports a transaction that was rolled back after conflicting with a concurrent one
+func transactionConflict() api.ServiceUnavailableJSONResponse {
+	return api.ServiceUnavailableJSONResponse{
+		Body:    respond.NewError(api.ErrorCodeTransactionConflict, "request conflicted with a concurrent request, retry later"),
+		Headers: api.ServiceUnavailableResponseHeaders{RetryAfter: retryAfterSeconds},
+	}
+}
+
+// errorStatus maps an error to the HTTP status it should be reported with.
+// Transient database conflicts, including an idempotency key committed first by a concurrent request,
+// are retryable and internal service errors stay internal whatever
+// they wrap. Otherwise sentinel errors take precedence; other service errors are client errors.
+func errorStatus(err error) int {
+	if db.IsRetryable(err) || errors.Is(err, models.ErrDuplicateIdempotencyKey) {
+		return http.StatusServiceUnavailable
+	}
+
+	svcErr := extractServiceError(err)
+	if svcErr != nil && svcErr.Code == service.ErrCodeInternalError {
+		return http.StatusInternalServerError
+	}
+
+	switch {
+	case errors.Is(err, models.ErrInsufficientFunds):
+		return http.StatusPaymentRequired
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrDuplicateTransaction):
+		return http.StatusConflict
+	}
+
+	switch {
+	case svcErr == nil:
+		return http.StatusInternalServerError
+	case svcErr.Code == service.ErrCodeInsufficientFunds:
+		return http.StatusPaymentRequired
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// requestErrorHandler reports malformed parameters and bodies in the shared error envelope
+func requestErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	var headerErr *api.RequiredHeaderError
+	if errors.As(err, &headerErr) && headerErr.ParamName == "Idempotency-Key" {
+		respond.Error(w, http.StatusBadRequest, api.ErrorCodeMissingIdempotencyKey, err.Error())
+		return
+	}
+	respond.Error(w, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err.Error())
+}
+
+// responseErrorHandler reports errors returned by handlers, which are never expected,
+// without leaking their details to the client
+func responseErrorHandler(logger *slog.Logger) func(http.ResponseWriter, *http.Request, error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		status := errorStatus(err)
+		code := api.ErrorCodeInternalError
+		switch status {
+		case http.StatusNotFound:
+			code = api.ErrorCodeNotFound
+		case http.StatusServiceUnavailable:
+			code = api.ErrorCodeTransactionConflict
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+		}
+		logger.ErrorContext(r.Context(), "handler returned error", "error", err, "status", status)
+		respond.Error(w, status, code, http.StatusText(status))
+	}
 }
 
 func extractServiceError(err error) *service.ServiceError {
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err      error
+		name     string
+		expected int
+	}{
+		{
+			name:     "insufficient funds",
+			err:      &service.ServiceError{Code: service.ErrCodeInsufficientFunds, Err: service.ErrInsufficientFunds},
+			expected: http.StatusPaymentRequired,
+		},
+		{
+			name:     "wrapped account not found",
+			err:      fmt.Errorf("lookup: %w", models.ErrAccountNotFound),
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "transaction not found in service error",
+			err:      &service.ServiceError{Code: service.ErrCodeCaptureNotFound, Err: models.ErrTransactionNotFound},
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "duplicate transaction",
+			err:      fmt.Errorf("create: %w", models.ErrDuplicateTransaction),
+			expected: http.StatusConflict,
+		},
+		{
+			name:     "business rule violation",
+			err:      &service.ServiceError{Code: service.ErrCodeAuthAlreadyUsed},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "internal service error",
+			err:      &service.ServiceError{Code: service.ErrCodeInternalError},
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:     "internal service error wrapping a sentinel",
+			err:      &service.ServiceError{Code: service.ErrCodeInternalError, Err: models.ErrTransactionNotFound},
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:     "deadlock",
+			err:      &service.ServiceError{Code: service.ErrCodeInternalError, Err: &pq.Error{Code: "40P01"}},
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "serialization failure",
+			err:      fmt.Errorf("commit: %w", &pq.Error{Code: "40001"}),
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "idempotency key committed concurrently",
+			err:      &service.ServiceError{Code: service.ErrCodeInternalError, Err: models.ErrDuplicateIdempotencyKey},
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "unexpected error",
+			err:      errors.New("boom"),
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, errorStatus(tt.err))
+		})
+	}
+}
+
+func TestRequestErrorHandler(t *testing.T) {
+	tests := []struct {
+		err          error
+		name         string
+		expectedCode api.ErrorCode
+	}{
+		{
+			name:         "missing idempotency key",
+			err:          &api.RequiredHeaderError{ParamName: "Idempotency-Key"},
+			expectedCode: api.ErrorCodeMissingIdempotencyKey,
+		},
+		{
+			name:         "malformed body",
+			err:          errors.New("can't decode JSON body"),
+			expectedCode: api.ErrorCodeInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			requestErrorHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/captures", nil), tt.err)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var body api.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedCode, body.Error.Code)
+			assert.NotEmpty(t, body.Error.Message)
+		})
+	}
+}
+
+func TestResponseErrorHandler_HidesDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	responseErrorHandler(testLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/captures/x", nil), errors.New("pq: secret detail"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret detail")
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/middleware"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+)
+
+// recordIdempotentResponse arranges for the success response of an idempotent request to be stored
+// through an idempotency repository bound to the service's database transaction, so the record and
+// the transaction row commit together and a rollback discards both. A key already committed by a
+// concurrent request fails the insert, rolling this request back so the middleware can replay the
+// committed response instead. tables names the idempotency table, and render builds the 200 body
+// from the transaction the service wrote.
+func recordIdempotentResponse[T any](ctx context.Context, tables *repository.Tables, render func(*models.Transaction) T) context.Context {
+	req, ok := middleware.IdempotentRequestFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return service.WithBeforeCommit(ctx, func(ctx context.Context, tx *db.Tx, txn *models.Transaction) error {
+		// Encoded the way the generated server writes the response, so replays are byte-for-byte identical
+		var body bytes.Buffer
+		if err := json.NewEncoder(&body).Encode(render(txn)); err != nil {
+			return fmt.Errorf("failed to encode idempotent response: %w", err)
+		}
+
+		err := repository.NewIdempotencyRepository(tx, repository.WithIdempotencyTables(tables)).Insert(ctx, &models.IdempotencyKey{
+			Key:            req.Key,
+			RequestPath:    req.RequestPath,
+			ResponseStatus: http.StatusOK,
+			ResponseBody:   body.String(),
+			TransactionID:  &txn.ID,
+			CreatedAt:      time.Now(),
+		})
+		if errors.Is(err, models.ErrDuplicateIdempotencyKey) {
+			req.MarkDuplicate()
+		}
+		if err != nil {
+			return err
+		}
+
+		req.MarkRecorded()
+		return nil
+	})
+}
@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/benx421/payment-gateway/bank/internal/validate"
+	"github.com/google/uuid"
 )
 
 // CreateRefund handles POST /api/v1/refunds
@@ -11,30 +16,19 @@ func (h *Handler) CreateRefund(
 	ctx context.Context,
 	request api.CreateRefundRequestObject,
 ) (api.CreateRefundResponseObject, error) {
-	captureID, err := parseCaptureID(request.Body.CaptureId)
-	if err != nil {
-		//nolint:nilerr // Returning 400 response object, not propagating error
-		return api.CreateRefund400JSONResponse{
-			BadRequestJSONResponse: api.BadRequestJSONResponse{
-				Error:   api.ErrorCodeCaptureNotFound,
-				Message: "invalid capture ID format",
-			},
-		}, nil
+	captureID, fieldErrs := validateRefund(request.Body)
+	if len(fieldErrs) > 0 {
+		return api.CreateRefund422JSONResponse{UnprocessableEntityJSONResponse: validationFailed(fieldErrs)}, nil
 	}
 
+	ctx = recordIdempotentResponse(ctx, h.tables, refundResponse)
 	txn, err := h.refundService.Refund(ctx, captureID, request.Body.Amount)
 	if err != nil {
-		return h.handleRefundError(err)
+		return h.handleRefundError(ctx, err)
 	}
 
-	return api.CreateRefund200JSONResponse{
-		RefundId:   formatRefundID(txn.ID),
-		CaptureId:  formatCaptureID(*txn.ReferenceID),
-		Status:     api.Refunded,
-		Amount:     txn.AmountCents,
-		Currency:   txn.Currency,
-		RefundedAt: txn.CreatedAt,
-	}, nil
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.CreateRefund200JSONResponse(refundResponse(txn)), nil
 }
 
 // GetRefund handles GET /api/v1/refunds/{refundId}
@@ -46,10 +40,7 @@ func (h *Handler) GetRefund(
 	if err != nil {
 		//nolint:nilerr // Returning 404 response object, not propagating error
 		return api.GetRefund404JSONResponse{
-			NotFoundJSONResponse: api.NotFoundJSONResponse{
-				Error:   api.ErrorCodeNotFound,
-				Message: "refund not found",
-			},
+			NotFoundJSONResponse: notFound(api.ErrorCodeNotFound, "refund not found"),
 		}, nil
 	}
 
@@ -57,42 +48,54 @@ func (h *Handler) GetRefund(
 	if err != nil {
 		//nolint:nilerr // Returning 404 response object, not propagating error
 		return api.GetRefund404JSONResponse{
-			NotFoundJSONResponse: api.NotFoundJSONResponse{
-				Error:   api.ErrorCodeNotFound,
-				Message: "refund not found",
-			},
+			NotFoundJSONResponse: notFound(api.ErrorCodeNotFound, "refund not found"),
 		}, nil
 	}
 
-	return api.GetRefund200JSONResponse{
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.GetRefund200JSONResponse(refundResponse(txn)), nil
+}
+
+// validateRefund checks the fields of a refund request and returns the capture's ID
+func validateRefund(body *api.CreateRefundJSONRequestBody) (uuid.UUID, []validate.FieldError) {
+	var v validate.Validator
+	v.Required("capture_id", body.CaptureId)
+	captureID, err := parseCaptureID(body.CaptureId)
+	v.Check(err == nil, "capture_id", "must be "+PrefixCapture+" followed by a UUID")
+	v.Positive("amount", body.Amount)
+	return captureID, v.Errors()
+}
+
+func refundResponse(txn *models.Transaction) api.RefundResponse {
+	return api.RefundResponse{
 		RefundId:   formatRefundID(txn.ID),
 		CaptureId:  formatCaptureID(*txn.ReferenceID),
 		Status:     api.Refunded,
 		Amount:     txn.AmountCents,
 		Currency:   txn.Currency,
 		RefundedAt: txn.CreatedAt,
-	}, nil
+	}
 }
 
 // handleRefundError maps service errors to appropriate HTTP responses
-func (h *Handler) handleRefundError(err error) (api.CreateRefundResponseObject, error) {
+func (h *Handler) handleRefundError(ctx context.Context, err error) (api.CreateRefundResponseObject, error) {
+	status := errorStatus(err)
+	if status == http.StatusServiceUnavailable {
+		h.logger.WarnContext(ctx, "refund conflicted with a concurrent request", "error", err)
+		return api.CreateRefund503JSONResponse{ServiceUnavailableJSONResponse: transactionConflict()}, nil
+	}
+
 	svcErr := extractServiceError(err)
-	if svcErr == nil {
-		h.logger.Error("unexpected error during refund", "error", err)
+	if svcErr == nil || status == http.StatusInternalServerError {
+		h.logger.ErrorContext(ctx, "unexpected error during refund", "error", err)
 		return api.CreateRefund500JSONResponse{
-			InternalErrorJSONResponse: api.InternalErrorJSONResponse{
-				Error:   api.ErrorCodeInternalError,
-				Message: "internal error",
-			},
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
 		}, nil
 	}
 
 	errorCode := mapServiceErrorToCode(svcErr.Code)
 
 	return api.CreateRefund400JSONResponse{
-		BadRequestJSONResponse: api.BadRequestJSONResponse{
-			Error:   errorCode,
-			Message: svcErr.Message,
-		},
+		BadRequestJSONResponse: badRequest(errorCode, svcErr.Message),
 	}, nil
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/benx421/payment-gateway/bank/internal/service"
 	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -17,7 +19,7 @@ import (
 
 func TestCreateRefund_Success(t *testing.T) {
 	mockRefund := mocks.NewMockRefunder(t)
-	handler := NewHandler(nil, nil, nil, mockRefund, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, mockRefund, nil, nil, nil, nil, testLogger())
 
 	captureID := uuid.New()
 	refundID := uuid.New()
@@ -60,7 +62,7 @@ func TestCreateRefund_ServiceErrors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRefund := mocks.NewMockRefunder(t)
-			handler := NewHandler(nil, nil, nil, mockRefund, nil, testLogger())
+			handler := NewHandler(nil, nil, nil, mockRefund, nil, nil, nil, nil, testLogger())
 
 			mockRefund.On("Refund", mock.Anything, mock.Anything, mock.Anything).
 				Return(nil, tt.serviceErr)
@@ -77,13 +79,45 @@ func TestCreateRefund_ServiceErrors(t *testing.T) {
 
 			badResp, ok := resp.(api.CreateRefund400JSONResponse)
 			require.True(t, ok)
-			assert.Equal(t, tt.expectedCode, badResp.Error)
+			assert.Equal(t, tt.expectedCode, badResp.Error.Code)
 		})
 	}
 }
 
+func TestCreateRefund_TransactionConflict(t *testing.T) {
+	mockRefund := mocks.NewMockRefunder(t)
+	handler := NewHandler(nil, nil, nil, mockRefund, nil, nil, nil, nil, testLogger())
+
+	captureID := uuid.New()
+	mockRefund.On("Refund", mock.Anything, captureID, int64(5000)).
+		Return(nil, &service.ServiceError{
+			Code:    service.ErrCodeInternalError,
+			Message: "transaction failed",
+			Err:     &pq.Error{Code: "40001"},
+		})
+
+	req := api.CreateRefundRequestObject{
+		Body: &api.CreateRefundJSONRequestBody{
+			CaptureId: "cap_" + captureID.String(),
+			Amount:    5000,
+		},
+	}
+
+	resp, err := handler.CreateRefund(context.Background(), req)
+
+	require.NoError(t, err)
+	conflictResp, ok := resp.(api.CreateRefund503JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.ErrorCodeTransactionConflict, conflictResp.Body.Error.Code)
+	assert.Equal(t, retryAfterSeconds, conflictResp.Headers.RetryAfter)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, conflictResp.VisitCreateRefundResponse(rec))
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
 func TestCreateRefund_InvalidIDFormat(t *testing.T) {
-	handler := NewHandler(nil, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	req := api.CreateRefundRequestObject{
 		Body: &api.CreateRefundJSONRequestBody{CaptureId: "invalid", Amount: 5000},
@@ -92,14 +126,31 @@ func TestCreateRefund_InvalidIDFormat(t *testing.T) {
 	resp, err := handler.CreateRefund(context.Background(), req)
 
 	require.NoError(t, err)
-	badResp, ok := resp.(api.CreateRefund400JSONResponse)
+	invalidResp, ok := resp.(api.CreateRefund422JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.ErrorCodeValidationFailed, invalidResp.Error.Code)
+	assert.Equal(t, []api.FieldError{{Field: "capture_id", Message: "must be cap_ followed by a UUID"}}, invalidResp.Error.Fields)
+}
+
+func TestCreateRefund_ValidationErrors(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
+
+	resp, err := handler.CreateRefund(context.Background(), api.CreateRefundRequestObject{
+		Body: &api.CreateRefundJSONRequestBody{Amount: -5},
+	})
+
+	require.NoError(t, err)
+	invalidResp, ok := resp.(api.CreateRefund422JSONResponse)
 	require.True(t, ok)
-	assert.Equal(t, api.ErrorCodeCaptureNotFound, badResp.Error)
+	assert.Equal(t, []api.FieldError{
+		{Field: "capture_id", Message: "is required"},
+		{Field: "amount", Message: "must be positive"},
+	}, invalidResp.Error.Fields)
 }
 
 func TestGetRefund_Success(t *testing.T) {
 	mockRefund := mocks.NewMockRefunder(t)
-	handler := NewHandler(nil, nil, nil, mockRefund, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, mockRefund, nil, nil, nil, nil, testLogger())
 
 	captureID := uuid.New()
 	refundID := uuid.New()
@@ -123,7 +174,7 @@ func TestGetRefund_Success(t *testing.T) {
 
 func TestGetRefund_NotFound(t *testing.T) {
 	mockRefund := mocks.NewMockRefunder(t)
-	handler := NewHandler(nil, nil, nil, mockRefund, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, mockRefund, nil, nil, nil, nil, testLogger())
 
 	refundID := uuid.New()
 	mockRefund.On("GetRefund", mock.Anything, refundID).
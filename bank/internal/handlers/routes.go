@@ -1,41 +1,219 @@
 package handlers
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
 	"github.com/benx421/payment-gateway/bank/internal/config"
 	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
 	"github.com/benx421/payment-gateway/bank/internal/middleware"
+	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/benx421/payment-gateway/bank/internal/repository"
 	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // NewRouter creates and configures the HTTP router with all routes and middleware.
+//
+// The ready flag backs the /ready probe and is owned by the caller, as is the maintenance flag,
+// which pauses writes while set.
+// The notifier is optional and receives committed authorizations, captures, voids and refunds.
 func NewRouter(
 	database *db.DB,
 	cfg *config.Config,
+	ready *atomic.Bool,
+	maintenance *atomic.Bool,
+	notifier service.Notifier,
 	logger *slog.Logger,
 ) http.Handler {
-	authService := service.NewAuthorizationService(database, cfg.App.AuthExpiryHours)
-	captureService := service.NewCaptureService(database)
-	voidService := service.NewVoidService(database)
-	refundService := service.NewRefundService(database)
-
-	handler := NewHandler(authService, captureService, voidService, refundService, database, logger)
-	strictHandler := api.NewStrictHandler(handler, nil)
+	svc := NewServices(database, cfg, notifier)
+	handler := NewHandler(svc.Auth, svc.Capture, svc.Void, svc.Refund, svc.Account, svc.Transaction, database, ready, logger)
+	tables := repository.NewTables(cfg.Database.TablePrefix)
+	handler.maintenance = maintenance
+	handler.tables = tables
+	strictHandler := api.NewStrictHandlerWithOptions(handler, []api.StrictMiddlewareFunc{traceOperation}, api.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  requestErrorHandler,
+		ResponseErrorHandlerFunc: responseErrorHandler(logger),
+	})
 
 	mux := http.NewServeMux()
 	api.RegisterDocsRoutes(mux)
-	api.HandlerFromMux(strictHandler, mux)
+	api.HandlerWithOptions(strictHandler, api.StdHTTPServerOptions{
+		BaseRouter:       mux,
+		ErrorHandlerFunc: requestErrorHandler,
+	})
+
+	if cfg.Metrics.Enabled {
+		registry := metrics.NewRegistry(database.DB, cfg.Database.DBName)
+		mux.Handle("GET /metrics", metrics.Handler(registry))
+	}
 
 	var finalHandler http.Handler = mux
 
 	finalHandler = middleware.FailureInjection(&cfg.App, logger)(finalHandler)
 
-	idempotencyRepo := repository.NewIdempotencyRepository(database)
-	finalHandler = middleware.Idempotency(idempotencyRepo, logger)(finalHandler)
+	idempotencyRepo := repository.NewIdempotencyRepository(database, repository.WithIdempotencyTables(tables))
+	finalHandler = middleware.Idempotency(idempotencyRepo, cfg.Idempotency.Routes, cfg.Idempotency.FailOpen, logger)(finalHandler)
+	finalHandler = middleware.Maintenance(maintenance)(finalHandler)
+
+	// Outside idempotency, so cached responses are stored in and replayed from the tenant's schema
+	if len(cfg.Database.TenantSchemas) > 0 {
+		finalHandler = middleware.Tenant(database)(finalHandler)
+	}
+
+	if cfg.Server.RequestTimeout > 0 {
+		finalHandler = middleware.Timeout(cfg.Server.RequestTimeout, logger, exportPath)(finalHandler)
+	}
+
+	if cfg.RateLimit.Enabled() {
+		finalHandler = middleware.RateLimit(
+			cfg.RateLimit.RequestsPerSecond,
+			cfg.RateLimit.Burst,
+			cfg.RateLimit.IdleTTL,
+			logger,
+		)(finalHandler)
+	}
+
+	if cfg.Auth.SigningEnabled() {
+		secrets, _ := cfg.Auth.ParseSigningSecrets() //nolint:errcheck // validated by config.Load
+		var nonces middleware.NonceStore
+		if cfg.Auth.RequireNonce {
+			nonces = repository.NewNonceRepository(database, repository.WithNonceTables(tables))
+		}
+		finalHandler = middleware.RequestSigning(secrets, cfg.Auth.SignatureWindow, nonces, logger)(finalHandler)
+	}
+
+	if cfg.Auth.Enabled() {
+		finalHandler = middleware.APIKeyAuth(cfg.Auth.APIKeys, logger)(finalHandler)
+	}
+
+	finalHandler = middleware.AdminAuth(cfg.Auth.AdminKeys, logger)(finalHandler)
+
+	// Inside metrics, so rejected requests are still counted by route and status
+	if cfg.Server.MaxConcurrent > 0 {
+		finalHandler = middleware.ConcurrencyLimit(cfg.Server.MaxConcurrent, logger)(finalHandler)
+	}
+
+	if cfg.Metrics.Enabled {
+		finalHandler = middleware.Metrics(mux)(finalHandler)
+	}
+
+	if cfg.Logger.LogBodies {
+		finalHandler = middleware.BodyLogging(cfg.Logger.MaxLoggedBodySize, logger)(finalHandler)
+	}
+
+	// Outside body logging, which must see the uncompressed JSON to redact it
+	if cfg.Server.Compression {
+		finalHandler = middleware.Compression(cfg.Server.CompressionMinBytes)(finalHandler)
+	}
+
+	finalHandler = middleware.RequestID()(finalHandler)
+	finalHandler = middleware.Tracing(mux)(finalHandler)
+
+	// Outermost, so a panic in any middleware is recovered too
+	finalHandler = middleware.Recovery(logger)(finalHandler)
 
 	return finalHandler
 }
+
+// Services are the service layer instances behind the HTTP and gRPC APIs
+type Services struct {
+	Auth        *service.AuthorizationService
+	Capture     *service.CaptureService
+	Void        *service.VoidService
+	Refund      *service.RefundService
+	Account     *service.AccountService
+	Transaction *service.TransactionService
+	Transfer    *service.TransferService
+	Chargeback  *service.ChargebackService
+}
+
+// NewServices builds the services from configuration.
+// The notifier is optional and receives committed authorizations, captures, voids, refunds and chargebacks.
+func NewServices(database *db.DB, cfg *config.Config, notifier service.Notifier) *Services {
+	txnOpts := transactionOptions(&cfg.Metadata)
+	cvvCipher, _ := cfg.CVV.NewCipher() //nolint:errcheck // validated by config.Load
+	limits := authorizationLimits(&cfg.Risk)
+	return &Services{
+		Auth:        service.NewAuthorizationService(database, notifier, holdExpiry(&cfg.App), limits, newFXProvider(&cfg.FX), cvvCipher, txnOpts...),
+		Capture:     service.NewCaptureService(database, notifier, captureLimits(&cfg.Risk), txnOpts...),
+		Void:        service.NewVoidService(database, notifier, txnOpts...),
+		Refund:      service.NewRefundService(database, notifier, txnOpts...),
+		Account:     service.NewAccountService(database),
+		Transaction: service.NewTransactionService(database, txnOpts...),
+		Transfer:    service.NewTransferService(database, txnOpts...),
+		Chargeback:  service.NewChargebackService(database, notifier, txnOpts...),
+	}
+}
+
+// holdExpiry converts the configured authorization hold durations
+func holdExpiry(cfg *config.AppConfig) service.HoldExpiry {
+	return service.HoldExpiry{
+		Default: cfg.AuthExpiryDuration,
+		Max:     time.Duration(cfg.MaxAuthExpiryHours) * time.Hour,
+	}
+}
+
+// authorizationLimits converts the configured risk controls.
+// Per-currency caps were already validated when the configuration was loaded.
+func authorizationLimits(cfg *config.RiskConfig) service.AuthorizationLimits {
+	byCurrency, _ := cfg.ParseMaxAuthAmounts() //nolint:errcheck // validated by config.Load
+	return service.AuthorizationLimits{
+		MaxAmount:           cfg.MaxAuthAmount,
+		MaxAmountByCurrency: byCurrency,
+		VelocityWindow:      cfg.VelocityWindow,
+		VelocityMax:         cfg.VelocityMaxAuths,
+	}
+}
+
+// captureLimits converts the configured daily capture limits.
+// Per-currency limits and the time zone were already validated when the configuration was loaded.
+func captureLimits(cfg *config.RiskConfig) service.CaptureLimits {
+	byCurrency, _ := cfg.ParseDailyCaptureLimits() //nolint:errcheck // validated by config.Load
+	loc, _ := cfg.DayLocation()                    //nolint:errcheck // validated by config.Load
+	return service.CaptureLimits{
+		DailyMax:           cfg.DailyCaptureLimit,
+		DailyMaxByCurrency: byCurrency,
+		DayLocation:        loc,
+	}
+}
+
+// newFXProvider builds the exchange rate table from configuration.
+// Rates were already validated when the configuration was loaded.
+func newFXProvider(cfg *config.FXConfig) *service.StaticFXProvider {
+	fx := service.NewStaticFXProvider()
+	rates, _ := cfg.ParseRates() //nolint:errcheck // validated by config.Load
+	for _, r := range rates {
+		fx.SetRate(r.From, r.To, r.Rate)
+	}
+	return fx
+}
+
+// transactionOptions builds the transaction repository options enabled by configuration.
+// Schema files were already validated when the configuration was loaded, so validation is never
+// silently dropped: a schema rejected here is a bug, not a configuration error.
+func transactionOptions(cfg *config.MetadataConfig) []repository.TransactionOption {
+	opts := []repository.TransactionOption{repository.WithMaxMetadataBytes(cfg.MaxBytes)}
+	if !cfg.Validate {
+		return opts
+	}
+
+	parsed, _ := cfg.ParseSchemas() //nolint:errcheck // validated by config.Load
+	byType := make(map[models.TransactionType]*openapi3.Schema, len(parsed))
+	for key, schema := range parsed {
+		if key != "default" {
+			byType[models.TransactionType(key)] = schema
+		}
+	}
+
+	schemas, err := repository.NewMetadataSchemas(parsed["default"], byType)
+	if err != nil {
+		panic(fmt.Sprintf("metadata schemas are validated by config.Load: %v", err))
+	}
+	return append(opts, repository.WithMetadataSchemas(schemas))
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/benx421/payment-gateway/bank/internal/handlers")
+
+// traceOperation wraps each strict handler call in a span named after its OpenAPI operation
+func traceOperation(f api.StrictHandlerFunc, operationID string) api.StrictHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error) {
+		ctx, span := tracer.Start(ctx, operationID)
+		response, err := f(ctx, w, r, request)
+		tracing.End(span, err)
+		return response, err
+	}
+}
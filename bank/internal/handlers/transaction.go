@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/google/uuid"
+)
+
+// GetTransaction handles GET /api/v1/transactions/{transactionId}
+func (h *Handler) GetTransaction(
+	ctx context.Context,
+	request api.GetTransactionRequestObject,
+) (api.GetTransactionResponseObject, error) {
+	id, err := uuid.Parse(request.TransactionId)
+	if err != nil {
+		//nolint:nilerr // Returning 400 response object, not propagating error
+		return api.GetTransaction400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, "invalid transaction ID format"),
+		}, nil
+	}
+
+	txn, err := h.txnService.GetTransaction(ctx, id)
+	if err != nil {
+		if errorStatus(err) == http.StatusNotFound {
+			return api.GetTransaction404JSONResponse{
+				NotFoundJSONResponse: notFound(api.ErrorCodeTransactionNotFound, "transaction not found"),
+			}, nil
+		}
+		h.logger.ErrorContext(ctx, "unexpected error during transaction lookup", "error", err)
+		return api.GetTransaction500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	tracing.SetAccountID(ctx, txn.AccountID)
+	etag := transactionETag(txn)
+	lastModified := txn.UpdatedAt.UTC().Format(http.TimeFormat)
+	if notModified(request.Params.IfNoneMatch, request.Params.IfModifiedSince, etag, txn.UpdatedAt) {
+		return api.GetTransaction304Response{
+			Headers: api.GetTransaction304ResponseHeaders{ETag: etag, LastModified: lastModified},
+		}, nil
+	}
+
+	return api.GetTransaction200JSONResponse{
+		Body:    toTransactionResponse(txn),
+		Headers: api.GetTransaction200ResponseHeaders{ETag: etag, LastModified: lastModified},
+	}, nil
+}
+
+// transactionETag changes whenever the transaction is updated
+func transactionETag(txn *models.Transaction) string {
+	return `"` + txn.ID.String() + "-" + strconv.FormatInt(txn.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// notModified evaluates the conditional headers of a GET for a resource with etag, last updated at
+// updatedAt. As RFC 9110 requires, If-Modified-Since is ignored when If-None-Match is sent, and an
+// unparsable date is ignored.
+func notModified(ifNoneMatch, ifModifiedSince, etag string, updatedAt time.Time) bool {
+	if ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have whole second precision
+	return !updatedAt.Truncate(time.Second).After(since)
+}
+
+// Limits on the transactions returned by one search
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchTransactions handles GET /api/v1/transactions
+func (h *Handler) SearchTransactions(
+	ctx context.Context,
+	request api.SearchTransactionsRequestObject,
+) (api.SearchTransactionsResponseObject, error) {
+	params := request.Params
+	if params.MetadataKey == "" || len(params.MetadataKey) > 255 {
+		return api.SearchTransactions400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, "metadata_key must be 1 to 255 characters"),
+		}, nil
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 1 || limit > maxSearchLimit {
+		return api.SearchTransactions400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)),
+		}, nil
+	}
+
+	txns, err := h.txnService.FindByMetadata(ctx, params.MetadataKey, params.MetadataValue, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "unexpected error during transaction search", "error", err)
+		return api.SearchTransactions500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	resp := api.SearchTransactions200JSONResponse{Transactions: make([]api.TransactionResponse, 0, len(txns))}
+	for _, txn := range txns {
+		resp.Transactions = append(resp.Transactions, toTransactionResponse(txn))
+	}
+	return resp, nil
+}
+
+// maxBatchStatusIDs caps the transactions looked up by one batch status request
+const maxBatchStatusIDs = 100
+
+// GetTransactionStatuses handles POST /api/v1/transactions/batch-status
+func (h *Handler) GetTransactionStatuses(
+	ctx context.Context,
+	request api.GetTransactionStatusesRequestObject,
+) (api.GetTransactionStatusesResponseObject, error) {
+	ids := request.Body.Ids
+	if len(ids) == 0 || len(ids) > maxBatchStatusIDs {
+		return api.GetTransactionStatuses400JSONResponse{
+			BadRequestJSONResponse: badRequest(api.ErrorCodeInvalidRequest, fmt.Sprintf("ids must hold 1 to %d transaction IDs", maxBatchStatusIDs)),
+		}, nil
+	}
+
+	txns, err := h.txnService.GetTransactions(ctx, ids)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "unexpected error during batch status lookup", "error", err)
+		return api.GetTransactionStatuses500JSONResponse{
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
+		}, nil
+	}
+
+	resp := api.GetTransactionStatuses200JSONResponse{Results: make([]api.TransactionStatusResult, 0, len(ids))}
+	for _, id := range ids {
+		result := api.TransactionStatusResult{Id: id}
+		if txn, ok := txns[id]; ok {
+			result.Found = true
+			result.Type = api.TransactionStatusResultType(txn.Type)
+			result.Status = api.TransactionStatusResultStatus(txn.Status)
+			result.UpdatedAt = txn.UpdatedAt
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+func toTransactionResponse(txn *models.Transaction) api.TransactionResponse {
+	var referenceID uuid.UUID
+	if txn.ReferenceID != nil {
+		referenceID = *txn.ReferenceID
+	}
+	var expiresAt time.Time
+	if txn.ExpiresAt != nil {
+		expiresAt = *txn.ExpiresAt
+	}
+
+	return api.TransactionResponse{
+		Id:            txn.ID,
+		AccountId:     txn.AccountID,
+		Type:          api.TransactionResponseType(txn.Type),
+		Status:        api.TransactionResponseStatus(txn.Status),
+		Amount:        txn.AmountCents,
+		Currency:      txn.Currency,
+		ReferenceId:   referenceID,
+		ExpiresAt:     expiresAt,
+		DeclineReason: string(txn.DeclineReason),
+		Metadata:      txn.Metadata,
+		CreatedAt:     txn.CreatedAt,
+		UpdatedAt:     txn.UpdatedAt,
+	}
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransaction_Success(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	txnID := uuid.New()
+	authID := uuid.New()
+	mockTxn.On("GetTransaction", mock.Anything, txnID).
+		Return(&models.Transaction{
+			ID:          txnID,
+			AccountID:   uuid.New(),
+			Type:        models.TransactionTypeCapture,
+			Status:      models.TransactionStatusCompleted,
+			AmountCents: 10000,
+			Currency:    "USD",
+			ReferenceID: &authID,
+			Metadata:    map[string]any{"order_id": "ord_123"},
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}, nil)
+
+	req := api.GetTransactionRequestObject{TransactionId: txnID.String()}
+	resp, err := handler.GetTransaction(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.GetTransaction200JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, txnID, successResp.Body.Id)
+	assert.Equal(t, api.TransactionResponseTypeCAPTURE, successResp.Body.Type)
+	assert.Equal(t, api.TransactionResponseStatusCOMPLETED, successResp.Body.Status)
+	assert.Equal(t, authID, successResp.Body.ReferenceId)
+	assert.Equal(t, "ord_123", successResp.Body.Metadata["order_id"])
+	assert.NotEmpty(t, successResp.Headers.ETag)
+	assert.NotEmpty(t, successResp.Headers.LastModified)
+
+	body, err := json.Marshal(successResp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "expires_at", "unset optional fields are omitted")
+}
+
+func TestGetTransaction_ConditionalRequests(t *testing.T) {
+	txnID := uuid.New()
+	txn := &models.Transaction{
+		ID:          txnID,
+		Type:        models.TransactionTypeAuthHold,
+		Status:      models.TransactionStatusActive,
+		AmountCents: 500,
+		Currency:    "USD",
+		UpdatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 600_000_000, time.UTC),
+	}
+	etag := transactionETag(txn)
+	lastModified := "Fri, 02 Jan 2026 03:04:05 GMT"
+
+	tests := []struct {
+		name            string
+		ifNoneMatch     string
+		ifModifiedSince string
+		notModified     bool
+	}{
+		{"current etag", etag, "", true},
+		{"weak current etag in a list", `"stale", W/` + etag, "", true},
+		{"stale etag", `"stale"`, "", false},
+		{"stale etag overrides a current date", `"stale"`, lastModified, false},
+		{"last modified date", "", lastModified, true},
+		{"later date", "", "Sat, 03 Jan 2026 00:00:00 GMT", true},
+		{"earlier date", "", "Fri, 02 Jan 2026 03:04:04 GMT", false},
+		{"unparsable date", "", "yesterday", false},
+		{"no headers", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTxn := mocks.NewMockTransactionReader(t)
+			handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+			mockTxn.On("GetTransaction", mock.Anything, txnID).Return(txn, nil)
+
+			req := api.GetTransactionRequestObject{
+				TransactionId: txnID.String(),
+				Params:        api.GetTransactionParams{IfNoneMatch: tt.ifNoneMatch, IfModifiedSince: tt.ifModifiedSince},
+			}
+			resp, err := handler.GetTransaction(context.Background(), req)
+
+			require.NoError(t, err)
+			if tt.notModified {
+				notModified, ok := resp.(api.GetTransaction304Response)
+				require.True(t, ok)
+				assert.Equal(t, etag, notModified.Headers.ETag)
+				assert.Equal(t, lastModified, notModified.Headers.LastModified)
+				return
+			}
+			_, ok := resp.(api.GetTransaction200JSONResponse)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestTransactionETag_ChangesWithUpdate(t *testing.T) {
+	id := uuid.New()
+	before := &models.Transaction{ID: id, UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	after := &models.Transaction{ID: id, UpdatedAt: before.UpdatedAt.Add(time.Microsecond)}
+	other := &models.Transaction{ID: uuid.New(), UpdatedAt: before.UpdatedAt}
+
+	assert.NotEqual(t, transactionETag(before), transactionETag(after))
+	assert.NotEqual(t, transactionETag(before), transactionETag(other))
+}
+
+func TestGetTransaction_InvalidID(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
+
+	req := api.GetTransactionRequestObject{TransactionId: "not-a-uuid"}
+	resp, err := handler.GetTransaction(context.Background(), req)
+
+	require.NoError(t, err)
+	badResp, ok := resp.(api.GetTransaction400JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.ErrorCodeInvalidRequest, badResp.Error.Code)
+}
+
+func TestGetTransaction_Errors(t *testing.T) {
+	tests := []struct {
+		serviceErr error
+		checkResp  func(t *testing.T, resp api.GetTransactionResponseObject)
+		name       string
+	}{
+		{
+			name:       "not found",
+			serviceErr: &service.ServiceError{Code: service.ErrCodeTransactionNotFound, Err: models.ErrTransactionNotFound},
+			checkResp: func(t *testing.T, resp api.GetTransactionResponseObject) {
+				notFoundResp, ok := resp.(api.GetTransaction404JSONResponse)
+				require.True(t, ok)
+				assert.Equal(t, api.ErrorCodeTransactionNotFound, notFoundResp.Error.Code)
+			},
+		},
+		{
+			name:       "internal error",
+			serviceErr: &service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")},
+			checkResp: func(t *testing.T, resp api.GetTransactionResponseObject) {
+				_, ok := resp.(api.GetTransaction500JSONResponse)
+				require.True(t, ok)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTxn := mocks.NewMockTransactionReader(t)
+			handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+			mockTxn.On("GetTransaction", mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			req := api.GetTransactionRequestObject{TransactionId: uuid.New().String()}
+			resp, err := handler.GetTransaction(context.Background(), req)
+
+			require.NoError(t, err)
+			tt.checkResp(t, resp)
+		})
+	}
+}
+
+func TestSearchTransactions_Success(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	txnID := uuid.New()
+	mockTxn.On("FindByMetadata", mock.Anything, "order_id", "ord_123", defaultSearchLimit).
+		Return([]*models.Transaction{{
+			ID:          txnID,
+			AccountID:   uuid.New(),
+			Type:        models.TransactionTypeAuthHold,
+			Status:      models.TransactionStatusActive,
+			AmountCents: 5000,
+			Currency:    "USD",
+			Metadata:    map[string]any{"order_id": "ord_123"},
+		}}, nil)
+
+	req := api.SearchTransactionsRequestObject{Params: api.SearchTransactionsParams{MetadataKey: "order_id", MetadataValue: "ord_123"}}
+	resp, err := handler.SearchTransactions(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.SearchTransactions200JSONResponse)
+	require.True(t, ok)
+	require.Len(t, successResp.Transactions, 1)
+	assert.Equal(t, txnID, successResp.Transactions[0].Id)
+	assert.Equal(t, api.TransactionResponseTypeAUTHHOLD, successResp.Transactions[0].Type)
+	assert.Equal(t, "ord_123", successResp.Transactions[0].Metadata["order_id"])
+}
+
+func TestSearchTransactions_NoMatchesIsEmptyList(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("FindByMetadata", mock.Anything, "order_id", "ord_none", 5).Return(nil, nil)
+
+	req := api.SearchTransactionsRequestObject{Params: api.SearchTransactionsParams{MetadataKey: "order_id", MetadataValue: "ord_none", Limit: 5}}
+	resp, err := handler.SearchTransactions(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.SearchTransactions200JSONResponse)
+	require.True(t, ok)
+	body, err := json.Marshal(successResp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"transactions":[]}`, string(body))
+}
+
+func TestSearchTransactions_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params api.SearchTransactionsParams
+	}{
+		{"missing key", api.SearchTransactionsParams{MetadataValue: "ord_123"}},
+		{"negative limit", api.SearchTransactionsParams{MetadataKey: "order_id", Limit: -1}},
+		{"limit above max", api.SearchTransactionsParams{MetadataKey: "order_id", Limit: maxSearchLimit + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(nil, nil, nil, nil, nil, mocks.NewMockTransactionReader(t), nil, nil, testLogger())
+
+			resp, err := handler.SearchTransactions(context.Background(), api.SearchTransactionsRequestObject{Params: tt.params})
+
+			require.NoError(t, err)
+			badResp, ok := resp.(api.SearchTransactions400JSONResponse)
+			require.True(t, ok)
+			assert.Equal(t, api.ErrorCodeInvalidRequest, badResp.Error.Code)
+		})
+	}
+}
+
+func TestSearchTransactions_InternalError(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("FindByMetadata", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, &service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")})
+
+	req := api.SearchTransactionsRequestObject{Params: api.SearchTransactionsParams{MetadataKey: "order_id", MetadataValue: "ord_123"}}
+	resp, err := handler.SearchTransactions(context.Background(), req)
+
+	require.NoError(t, err)
+	_, ok := resp.(api.SearchTransactions500JSONResponse)
+	assert.True(t, ok)
+}
+
+func TestGetTransactionStatuses_MarksMissingIDs(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	foundID, missingID := uuid.New(), uuid.New()
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockTxn.On("GetTransactions", mock.Anything, []uuid.UUID{missingID, foundID}).
+		Return(map[uuid.UUID]*models.Transaction{foundID: {
+			ID:        foundID,
+			Type:      models.TransactionTypeCapture,
+			Status:    models.TransactionStatusCompleted,
+			UpdatedAt: updatedAt,
+		}}, nil)
+
+	req := api.GetTransactionStatusesRequestObject{Body: &api.BatchStatusRequest{Ids: []uuid.UUID{missingID, foundID}}}
+	resp, err := handler.GetTransactionStatuses(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.GetTransactionStatuses200JSONResponse)
+	require.True(t, ok)
+	require.Len(t, successResp.Results, 2)
+	assert.Equal(t, api.TransactionStatusResult{Id: missingID}, successResp.Results[0])
+	assert.Equal(t, api.TransactionStatusResult{
+		Id:        foundID,
+		Found:     true,
+		Type:      api.TransactionStatusResultTypeCAPTURE,
+		Status:    api.TransactionStatusResultStatusCOMPLETED,
+		UpdatedAt: updatedAt,
+	}, successResp.Results[1])
+}
+
+func TestGetTransactionStatuses_InvalidBatchSize(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []uuid.UUID
+	}{
+		{"empty", nil},
+		{"above max", make([]uuid.UUID, maxBatchStatusIDs+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(nil, nil, nil, nil, nil, mocks.NewMockTransactionReader(t), nil, nil, testLogger())
+
+			req := api.GetTransactionStatusesRequestObject{Body: &api.BatchStatusRequest{Ids: tt.ids}}
+			resp, err := handler.GetTransactionStatuses(context.Background(), req)
+
+			require.NoError(t, err)
+			badResp, ok := resp.(api.GetTransactionStatuses400JSONResponse)
+			require.True(t, ok)
+			assert.Equal(t, api.ErrorCodeInvalidRequest, badResp.Error.Code)
+		})
+	}
+}
+
+func TestGetTransactionStatuses_InternalError(t *testing.T) {
+	mockTxn := mocks.NewMockTransactionReader(t)
+	handler := NewHandler(nil, nil, nil, nil, nil, mockTxn, nil, nil, testLogger())
+
+	mockTxn.On("GetTransactions", mock.Anything, mock.Anything).
+		Return(nil, &service.ServiceError{Code: service.ErrCodeInternalError, Err: errors.New("connection refused")})
+
+	req := api.GetTransactionStatusesRequestObject{Body: &api.BatchStatusRequest{Ids: []uuid.UUID{uuid.New()}}}
+	resp, err := handler.GetTransactionStatuses(context.Background(), req)
+
+	require.NoError(t, err)
+	_, ok := resp.(api.GetTransactionStatuses500JSONResponse)
+	assert.True(t, ok)
+}
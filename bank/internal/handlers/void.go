@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
 )
 
 // CreateVoid handles POST /api/v1/voids
@@ -15,44 +18,59 @@ func (h *Handler) CreateVoid(
 	if err != nil {
 		//nolint:nilerr // Returning 400 response object, not propagating error
 		return api.CreateVoid400JSONResponse{
-			BadRequestJSONResponse: api.BadRequestJSONResponse{
-				Error:   api.ErrorCodeAuthorizationNotFound,
-				Message: "invalid authorization ID format",
-			},
+			BadRequestJSONResponse: badRequest(api.ErrorCodeAuthorizationNotFound, "invalid authorization ID format"),
 		}, nil
 	}
 
-	txn, err := h.voidService.Void(ctx, authID)
+	ctx = recordIdempotentResponse(ctx, h.tables, voidResponse)
+	var txn *models.Transaction
+	if request.Body.Amount != 0 {
+		txn, err = h.voidService.PartialVoid(ctx, authID, request.Body.Amount)
+	} else {
+		txn, err = h.voidService.Void(ctx, authID)
+	}
 	if err != nil {
-		return h.handleVoidError(err)
+		return h.handleVoidError(ctx, err)
 	}
 
-	return api.CreateVoid200JSONResponse{
+	tracing.SetAccountID(ctx, txn.AccountID)
+	return api.CreateVoid200JSONResponse(voidResponse(txn)), nil
+}
+
+func voidResponse(txn *models.Transaction) api.VoidResponse {
+	resp := api.VoidResponse{
 		VoidId:          formatVoidID(txn.ID),
 		AuthorizationId: formatAuthorizationID(*txn.ReferenceID),
 		Status:          api.Voided,
 		VoidedAt:        txn.CreatedAt,
-	}, nil
+	}
+	if txn.Type == models.TransactionTypePartialVoid {
+		// The partial void records the authorization's remaining amount in its own metadata
+		resp.Status = api.PartiallyVoided
+		resp.Amount = txn.AmountCents
+		resp.RemainingAmount = txn.AuthorizedAmount()
+	}
+	return resp
 }
 
-func (h *Handler) handleVoidError(err error) (api.CreateVoidResponseObject, error) {
+func (h *Handler) handleVoidError(ctx context.Context, err error) (api.CreateVoidResponseObject, error) {
+	status := errorStatus(err)
+	if status == http.StatusServiceUnavailable {
+		h.logger.WarnContext(ctx, "void conflicted with a concurrent request", "error", err)
+		return api.CreateVoid503JSONResponse{ServiceUnavailableJSONResponse: transactionConflict()}, nil
+	}
+
 	svcErr := extractServiceError(err)
-	if svcErr == nil {
-		h.logger.Error("unexpected error during void", "error", err)
+	if svcErr == nil || status == http.StatusInternalServerError {
+		h.logger.ErrorContext(ctx, "unexpected error during void", "error", err)
 		return api.CreateVoid500JSONResponse{
-			InternalErrorJSONResponse: api.InternalErrorJSONResponse{
-				Error:   api.ErrorCodeInternalError,
-				Message: "internal error",
-			},
+			InternalErrorJSONResponse: internalError(api.ErrorCodeInternalError, "internal error"),
 		}, nil
 	}
 
 	errorCode := mapServiceErrorToCode(svcErr.Code)
 
 	return api.CreateVoid400JSONResponse{
-		BadRequestJSONResponse: api.BadRequestJSONResponse{
-			Error:   errorCode,
-			Message: svcErr.Message,
-		},
+		BadRequestJSONResponse: badRequest(errorCode, svcErr.Message),
 	}, nil
 }
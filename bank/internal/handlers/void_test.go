@@ -17,7 +17,7 @@ import (
 
 func TestCreateVoid_Success(t *testing.T) {
 	mockVoid := mocks.NewMockVoider(t)
-	handler := NewHandler(nil, nil, mockVoid, nil, nil, testLogger())
+	handler := NewHandler(nil, nil, mockVoid, nil, nil, nil, nil, nil, testLogger())
 
 	authID := uuid.New()
 	voidID := uuid.New()
@@ -41,6 +41,36 @@ func TestCreateVoid_Success(t *testing.T) {
 	assert.Equal(t, api.Voided, successResp.Status)
 }
 
+func TestCreateVoid_PartialVoid(t *testing.T) {
+	mockVoid := mocks.NewMockVoider(t)
+	handler := NewHandler(nil, nil, mockVoid, nil, nil, nil, nil, nil, testLogger())
+
+	authID := uuid.New()
+
+	mockVoid.On("PartialVoid", mock.Anything, authID, int64(2500)).
+		Return(&models.Transaction{
+			ID:          uuid.New(),
+			Type:        models.TransactionTypePartialVoid,
+			AmountCents: 2500,
+			ReferenceID: &authID,
+			Metadata:    map[string]any{models.MetadataKeyAuthorizedAmount: int64(7500)},
+			CreatedAt:   time.Now(),
+		}, nil)
+
+	req := api.CreateVoidRequestObject{
+		Body: &api.CreateVoidJSONRequestBody{AuthorizationId: "auth_" + authID.String(), Amount: 2500},
+	}
+
+	resp, err := handler.CreateVoid(context.Background(), req)
+
+	require.NoError(t, err)
+	successResp, ok := resp.(api.CreateVoid200JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, api.PartiallyVoided, successResp.Status)
+	assert.Equal(t, int64(2500), successResp.Amount)
+	assert.Equal(t, int64(7500), successResp.RemainingAmount)
+}
+
 func TestCreateVoid_ServiceErrors(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -50,12 +80,13 @@ func TestCreateVoid_ServiceErrors(t *testing.T) {
 		{"auth not found", &service.ServiceError{Code: service.ErrCodeAuthNotFound}, api.ErrorCodeAuthorizationNotFound},
 		{"already voided", &service.ServiceError{Code: service.ErrCodeAlreadyVoided}, api.ErrorCodeAlreadyVoided},
 		{"already captured", &service.ServiceError{Code: service.ErrCodeAuthAlreadyUsed}, api.ErrorCodeAuthorizationAlreadyUsed},
+		{"void exceeds authorization", &service.ServiceError{Code: service.ErrCodeVoidExceedsAuth}, api.ErrorCodeVoidExceedsAuthorization},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockVoid := mocks.NewMockVoider(t)
-			handler := NewHandler(nil, nil, mockVoid, nil, nil, testLogger())
+			handler := NewHandler(nil, nil, mockVoid, nil, nil, nil, nil, nil, testLogger())
 
 			mockVoid.On("Void", mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
 
@@ -68,13 +99,13 @@ func TestCreateVoid_ServiceErrors(t *testing.T) {
 
 			badResp, ok := resp.(api.CreateVoid400JSONResponse)
 			require.True(t, ok)
-			assert.Equal(t, tt.expectedCode, badResp.Error)
+			assert.Equal(t, tt.expectedCode, badResp.Error.Code)
 		})
 	}
 }
 
 func TestCreateVoid_InvalidIDFormat(t *testing.T) {
-	handler := NewHandler(nil, nil, nil, nil, nil, testLogger())
+	handler := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, testLogger())
 
 	req := api.CreateVoidRequestObject{
 		Body: &api.CreateVoidJSONRequestBody{AuthorizationId: "invalid"},
@@ -85,5 +116,5 @@ func TestCreateVoid_InvalidIDFormat(t *testing.T) {
 	require.NoError(t, err)
 	badResp, ok := resp.(api.CreateVoid400JSONResponse)
 	require.True(t, ok)
-	assert.Equal(t, api.ErrorCodeAuthorizationNotFound, badResp.Error)
+	assert.Equal(t, api.ErrorCodeAuthorizationNotFound, badResp.Error.Code)
 }
@@ -0,0 +1,76 @@
+// Package logging provides context-aware logging helpers for the bank API.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	actorKey
+)
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or an empty string if none is set
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string) //nolint:errcheck // missing value yields empty string
+	return requestID
+}
+
+// ContextWithActor returns a copy of ctx carrying the identity of the caller, e.g. the API key used
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the caller identity stored in ctx, or an empty string if none is set
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorKey).(string) //nolint:errcheck // missing value yields empty string
+	return actor
+}
+
+// ContextHandler is a slog.Handler that enriches records with values carried in the context.
+//
+// Log calls must use the *Context variants (InfoContext, ErrorContext, ...) for the
+// request ID and actor to be attached.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps the given handler with context enrichment
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+// Handle adds the request ID and actor from ctx to the record before delegating
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if actor := ActorFromContext(ctx); actor != "" {
+		record.AddAttrs(slog.String("actor", actor))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a new ContextHandler whose underlying handler has the given attributes
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a new ContextHandler whose underlying handler has the given group
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDFromContext(t *testing.T) {
+	assert.Empty(t, RequestIDFromContext(context.Background()))
+
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	assert.Equal(t, "req-123", RequestIDFromContext(ctx))
+}
+
+func TestActorFromContext(t *testing.T) {
+	assert.Empty(t, ActorFromContext(context.Background()))
+
+	ctx := ContextWithActor(ContextWithRequestID(context.Background(), "req-123"), "api_key:abc")
+	assert.Equal(t, "api_key:abc", ActorFromContext(ctx))
+	assert.Equal(t, "req-123", RequestIDFromContext(ctx), "actor must not shadow the request ID")
+}
+
+func TestContextHandler_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := ContextWithActor(ContextWithRequestID(context.Background(), "req-456"), "api_key:abc")
+	logger.With("component", "test").InfoContext(ctx, "hello")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-456", entry["request_id"])
+	assert.Equal(t, "api_key:abc", entry["actor"])
+	assert.Equal(t, "test", entry["component"])
+}
+
+func TestContextHandler_NoRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.InfoContext(context.Background(), "hello")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotContains(t, entry, "request_id")
+}
@@ -0,0 +1,134 @@
+// Package metrics defines the Prometheus collectors exposed by the bank API.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bank"
+
+// DefaultAmountBuckets bound TransactionAmount in minor units: 1.00 to 100,000.00 of a
+// two-decimal currency, a factor of ten apart
+var DefaultAmountBuckets = []float64{100, 1_000, 10_000, 100_000, 1_000_000, 10_000_000}
+
+// Application collectors. They are package-level so repositories and middleware
+// can record without threading a registry through every constructor.
+var (
+	// TransactionsTotal counts committed ledger entries by transaction type and status. An entry
+	// moved to a new status, such as an authorization voided or fully captured, counts again
+	// under that status.
+	TransactionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transactions_total",
+			Help:      "Number of transactions committed to the ledger or moved to a new status.",
+		},
+		[]string{"type", "status"},
+	)
+
+	// RequestDuration observes HTTP request latency, by method, route pattern and status code
+	RequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	// InFlightRequests is the number of requests holding a concurrency limiter slot
+	InFlightRequests = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "in_flight_requests",
+			Help:      "Requests being served under the concurrency limit.",
+		},
+	)
+
+	// OverloadedRequests counts requests rejected because the concurrency limit was reached
+	OverloadedRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "overloaded_requests_total",
+			Help:      "Requests rejected with 503 because the concurrency limit was reached.",
+		},
+	)
+
+	// DBOperationDuration observes the latency of repository operations, by table, operation
+	// (the repository method, e.g. FindByID) and outcome, ok or error. A lookup that finds nothing is ok.
+	DBOperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "operation_duration_seconds",
+			Help:      "Repository operation latency in seconds.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"table", "operation", "outcome"},
+	)
+
+	// TransactionAmount observes the amounts of transactions written, by currency and type.
+	// SetAmountBuckets replaces it to change its buckets.
+	TransactionAmount = newTransactionAmount(DefaultAmountBuckets)
+
+	// BalanceDrifts is the number of balances that disagreed with their ledger at the last
+	// full reconciliation
+	BalanceDrifts = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "balance_drifts",
+			Help:      "Balances that differed from their ledger at the last reconciliation.",
+		},
+	)
+)
+
+func newTransactionAmount(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transaction_amount",
+			Help:      "Transaction amounts in minor units of their currency.",
+			Buckets:   buckets,
+		},
+		[]string{"currency", "type"},
+	)
+}
+
+// SetAmountBuckets replaces TransactionAmount with a histogram using buckets, which must be
+// strictly increasing. Call it at startup, before NewRegistry and before any amount is observed.
+func SetAmountBuckets(buckets []float64) {
+	TransactionAmount = newTransactionAmount(buckets)
+}
+
+// NewRegistry creates a registry holding the application collectors, Go runtime
+// metrics and connection pool statistics for the given database.
+func NewRegistry(database *sql.DB, dbName string) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(database, dbName),
+		TransactionsTotal,
+		RequestDuration,
+		InFlightRequests,
+		OverloadedRequests,
+		DBOperationDuration,
+		TransactionAmount,
+		BalanceDrifts,
+	)
+	return registry
+}
+
+// Handler returns an HTTP handler that serves the registry in the Prometheus exposition format
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
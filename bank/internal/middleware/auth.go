@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+const bearerPrefix = "Bearer "
+
+// authExemptPaths defines which paths are reachable without an API key
+var authExemptPaths = []string{
+	"/health",
+	"/live",
+	"/ready",
+}
+
+// adminPathPrefix marks operator endpoints, which are authenticated with admin keys instead of API keys
+const adminPathPrefix = "/admin/"
+
+// APIKeyAuth creates middleware that requires a valid `Authorization: Bearer <key>` header.
+//
+// Keys are compared in constant time. Exempt paths are served without authentication,
+// and admin paths are left to AdminAuth.
+func APIKeyAuth(apiKeys []string, logger *slog.Logger) func(http.Handler) http.Handler {
+	keys := make([][]byte, 0, len(apiKeys))
+	for _, key := range apiKeys {
+		keys = append(keys, []byte(key))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isAuthExemptPath(r.URL.Path) || isAdminPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				writeUnauthorized(w, "missing bearer token")
+				return
+			}
+
+			if !ValidAPIKey(keys, []byte(token)) {
+				logger.WarnContext(r.Context(), "rejected request with invalid api key",
+					"path", r.URL.Path,
+					"method", r.Method,
+				)
+				writeUnauthorized(w, "invalid api key")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(logging.ContextWithActor(r.Context(), KeyActor(token))))
+		})
+	}
+}
+
+// AdminAuth creates middleware that requires one of adminKeys as the bearer token on admin paths.
+//
+// Admin paths are rejected outright when no admin keys are configured. Other paths pass through.
+func AdminAuth(adminKeys []string, logger *slog.Logger) func(http.Handler) http.Handler {
+	keys := make([][]byte, 0, len(adminKeys))
+	for _, key := range adminKeys {
+		keys = append(keys, []byte(key))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAdminPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				writeUnauthorized(w, "missing bearer token")
+				return
+			}
+
+			if !ValidAPIKey(keys, []byte(token)) {
+				logger.WarnContext(r.Context(), "rejected admin request with invalid key",
+					"path", r.URL.Path,
+					"method", r.Method,
+				)
+				writeUnauthorized(w, "invalid admin key")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isAdminPath(path string) bool {
+	return strings.HasPrefix(path, adminPathPrefix)
+}
+
+func isAuthExemptPath(path string) bool {
+	for _, exempt := range authExemptPaths {
+		if path == exempt {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(bearerPrefix):])
+	return token, token != ""
+}
+
+// KeyActor identifies the caller holding an API key, for logs and the audit trail, without
+// revealing the key
+func KeyActor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "api_key:" + hex.EncodeToString(sum[:6])
+}
+
+// ValidAPIKey checks the token against every key so timing does not reveal which key matched
+func ValidAPIKey(keys [][]byte, token []byte) bool {
+	valid := 0
+	for _, key := range keys {
+		valid |= subtle.ConstantTimeCompare(key, token)
+	}
+	return valid == 1
+}
+
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="bank"`)
+	respond.Error(w, http.StatusUnauthorized, api.ErrorCodeUnauthorized, message)
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		authHeader     string
+		expectedStatus int
+	}{
+		{
+			name:           "valid key",
+			path:           "/api/v1/authorizations",
+			authHeader:     "Bearer key-two",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "case-insensitive scheme",
+			path:           "/api/v1/authorizations",
+			authHeader:     "bearer key-one",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing header",
+			path:           "/api/v1/authorizations",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong scheme",
+			path:           "/api/v1/authorizations",
+			authHeader:     "Basic key-one",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "empty token",
+			path:           "/api/v1/authorizations",
+			authHeader:     "Bearer ",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid key",
+			path:           "/api/v1/authorizations",
+			authHeader:     "Bearer key-three",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "health is exempt",
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "live is exempt",
+			path:           "/live",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "admin paths are left to AdminAuth",
+			path:           "/admin/stats",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware := APIKeyAuth([]string{"key-one", "key-two"}, testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			middleware(testHandler(http.StatusOK, "ok")).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				var body api.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, api.ErrorCode("unauthorized"), body.Error.Code)
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_SetsActor(t *testing.T) {
+	middleware := APIKeyAuth([]string{"key-one", "key-two"}, testLogger())
+
+	var actor string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = logging.ActorFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	req.Header.Set("Authorization", "Bearer key-two")
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, KeyActor("key-two"), actor)
+	assert.Regexp(t, `^api_key:[0-9a-f]{12}$`, actor)
+	assert.NotContains(t, actor, "key-two", "the key itself must not be recorded")
+	assert.NotEqual(t, KeyActor("key-one"), actor)
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		authHeader     string
+		adminKeys      []string
+		expectedStatus int
+	}{
+		{
+			name:           "valid admin key",
+			adminKeys:      []string{"admin-one"},
+			path:           "/admin/stats",
+			authHeader:     "Bearer admin-one",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing header",
+			adminKeys:      []string{"admin-one"},
+			path:           "/admin/stats",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "api key is not an admin key",
+			adminKeys:      []string{"admin-one"},
+			path:           "/admin/stats",
+			authHeader:     "Bearer key-one",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "closed without admin keys",
+			path:           "/admin/stats",
+			authHeader:     "Bearer admin-one",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "other paths pass through",
+			path:           "/api/v1/authorizations",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware := AdminAuth(tt.adminKeys, testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			middleware(testHandler(http.StatusOK, "ok")).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
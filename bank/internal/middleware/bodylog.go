@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// redactedValue replaces the value of every sensitive field in a logged body
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are matched against JSON keys lowercased with underscores removed,
+// so card_number and cardNumber are both caught
+var sensitiveFields = map[string]bool{
+	"cvv":           true,
+	"accountnumber": true,
+	"cardnumber":    true,
+}
+
+// bodyRecorder keeps the status and the first limit+1 bytes of a response
+type bodyRecorder struct {
+	http.ResponseWriter
+	body       bytes.Buffer
+	limit      int
+	statusCode int
+}
+
+func (br *bodyRecorder) WriteHeader(code int) {
+	br.statusCode = code
+	br.ResponseWriter.WriteHeader(code)
+}
+
+func (br *bodyRecorder) Write(b []byte) (int, error) {
+	if room := br.limit + 1 - br.body.Len(); room > 0 {
+		br.body.Write(b[:min(room, len(b))])
+	}
+	return br.ResponseWriter.Write(b)
+}
+
+func (br *bodyRecorder) Unwrap() http.ResponseWriter {
+	return br.ResponseWriter
+}
+
+// BodyLogging creates middleware that logs request and response bodies with card details redacted.
+//
+// Bodies are logged only when they are JSON no larger than maxBytes; anything else is summarised,
+// since a truncated or unparseable body cannot be redacted reliably. The request body is
+// re-buffered so downstream handlers read it unchanged.
+func BodyLogging(maxBytes int, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var requestBody []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				prefix, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBytes)+1))
+				if err != nil {
+					logger.WarnContext(r.Context(), "failed to read request body for logging", "error", err)
+				}
+				requestBody = prefix
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(prefix), r.Body), r.Body}
+			}
+
+			recorder := &bodyRecorder{ResponseWriter: w, limit: maxBytes, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			logger.InfoContext(r.Context(), "http exchange",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", recorder.statusCode,
+				"request_body", loggableBody(requestBody, maxBytes),
+				"response_body", loggableBody(recorder.body.Bytes(), maxBytes),
+			)
+		})
+	}
+}
+
+// loggableBody returns body with sensitive fields redacted, or a placeholder when it cannot be redacted
+func loggableBody(body []byte, maxBytes int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if len(body) > maxBytes {
+		return fmt.Sprintf("[omitted: larger than %d bytes]", maxBytes)
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Sprintf("[omitted: %d bytes of non-JSON]", len(body))
+	}
+
+	redacted, err := json.Marshal(redact(decoded))
+	if err != nil {
+		return fmt.Sprintf("[omitted: %v]", err)
+	}
+	return string(redacted)
+}
+
+// redact masks the values of sensitive keys at any depth of a decoded JSON value
+func redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if sensitiveFields[strings.ReplaceAll(strings.ToLower(key), "_", "")] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redact(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWithBodyLogging(t *testing.T, maxBytes int, requestBody string, handler http.Handler) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", strings.NewReader(requestBody))
+	rec := httptest.NewRecorder()
+	BodyLogging(maxBytes, logger)(handler).ServeHTTP(rec, req)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	return rec, entry
+}
+
+func TestBodyLogging_RedactsCardDetails(t *testing.T) {
+	const requestBody = `{"card_number":"4111111111111111","cvv":"123","amount":1000,"nested":{"cardNumber":"4242"}}`
+
+	var seen string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		seen = string(body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"account_number":"************1111","balance_cents":500}`)) //nolint:errcheck // test handler
+	})
+
+	rec, entry := serveWithBodyLogging(t, 1024, requestBody, handler)
+
+	assert.Equal(t, requestBody, seen, "downstream handler must read the original body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+
+	logged := entry["request_body"].(string) + entry["response_body"].(string)
+	assert.NotContains(t, logged, "4111111111111111")
+	assert.NotContains(t, logged, "123\"")
+	assert.NotContains(t, logged, "4242")
+	assert.NotContains(t, logged, "************1111")
+	assert.Contains(t, entry["request_body"], `"amount":1000`)
+	assert.Contains(t, entry["request_body"], `"cvv":"[REDACTED]"`)
+	assert.Contains(t, entry["response_body"], `"balance_cents":500`)
+}
+
+func TestBodyLogging_OmitsOversizedAndNonJSONBodies(t *testing.T) {
+	requestBody := `{"card_number":"4111111111111111","padding":"` + strings.Repeat("x", 64) + `"}`
+
+	var seen string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		seen = string(body)
+		w.Write([]byte("cvv=123")) //nolint:errcheck // test handler
+	})
+
+	_, entry := serveWithBodyLogging(t, 32, requestBody, handler)
+
+	assert.Equal(t, requestBody, seen, "bodies over the cap must still reach the handler whole")
+	assert.Equal(t, "[omitted: larger than 32 bytes]", entry["request_body"])
+	assert.Equal(t, "[omitted: 7 bytes of non-JSON]", entry["response_body"])
+}
@@ -3,24 +3,22 @@ package middleware
 
 import (
 	"crypto/rand"
-	"encoding/json"
 	"log/slog"
 	"math/big"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/api"
 	"github.com/benx421/payment-gateway/bank/internal/config"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
 )
 
-type chaosErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
-
 var excludedPaths = []string{
 	"/health",
+	"/ready",
 	"/docs",
+	"/metrics",
 }
 
 // FailureInjection creates middleware that injects latency and random failures
@@ -36,7 +34,7 @@ func FailureInjection(cfg *config.AppConfig, logger *slog.Logger) func(http.Hand
 			injectLatency(cfg.MinLatencyMS, cfg.MaxLatencyMS)
 
 			if shouldInjectFailure(cfg.FailureRate) {
-				logger.Debug("injecting random failure",
+				logger.DebugContext(r.Context(), "injecting random failure",
 					"path", r.URL.Path,
 					"method", r.Method,
 				)
@@ -98,14 +96,5 @@ func shouldInjectFailure(failureRate float64) bool {
 }
 
 func writeFailureResponse(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-
-	resp := chaosErrorResponse{
-		Error:   "internal_error",
-		Message: "Random failure injection",
-	}
-
-	//nolint:errcheck // Best effort response writing in chaos injection
-	json.NewEncoder(w).Encode(resp)
+	respond.Error(w, http.StatusInternalServerError, api.ErrorCodeInternalError, "Random failure injection")
 }
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// gzipWriter holds back the start of a response until it knows whether the response is worth compressing
+type gzipWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	buf         bytes.Buffer
+	minBytes    int
+	statusCode  int
+	decided     bool
+	wroteHeader bool
+}
+
+func (gw *gzipWriter) WriteHeader(code int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+	gw.statusCode = code
+}
+
+func (gw *gzipWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.decided {
+		if gw.gz != nil {
+			return gw.gz.Write(b)
+		}
+		return gw.ResponseWriter.Write(b)
+	}
+
+	gw.buf.Write(b)
+	if gw.buf.Len() >= gw.minBytes {
+		if err := gw.decide(gw.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (gw *gzipWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// compressible reports whether the response is JSON that is not already encoded
+func (gw *gzipWriter) compressible() bool {
+	header := gw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if gw.statusCode < http.StatusOK || gw.statusCode == http.StatusNoContent || gw.statusCode == http.StatusNotModified {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// decide sends the headers and the buffered start of the body, compressing from here on when compress is set
+func (gw *gzipWriter) decide(compress bool) error {
+	gw.decided = true
+	if compress {
+		gw.Header().Set("Content-Encoding", "gzip")
+		gw.Header().Del("Content-Length")
+		gw.gz = gzipWriters.Get().(*gzip.Writer) //nolint:errcheck // the pool only holds *gzip.Writer
+		gw.gz.Reset(gw.ResponseWriter)
+	}
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+
+	if gw.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if gw.gz != nil {
+		_, err = gw.gz.Write(gw.buf.Bytes())
+	} else {
+		_, err = gw.ResponseWriter.Write(gw.buf.Bytes())
+	}
+	gw.buf.Reset()
+	return err
+}
+
+// close sends whatever is still held back and finishes the gzip stream
+func (gw *gzipWriter) close() error {
+	if !gw.decided {
+		if !gw.wroteHeader {
+			return nil
+		}
+		// The whole body fit under the threshold, so it goes out as is
+		if err := gw.decide(false); err != nil {
+			return err
+		}
+	}
+	if gw.gz == nil {
+		return nil
+	}
+	err := gw.gz.Close()
+	gw.gz.Reset(nil)
+	gzipWriters.Put(gw.gz)
+	gw.gz = nil
+	return err
+}
+
+// Compression creates middleware that gzips JSON responses of at least minBytes for clients that
+// accept it. Smaller responses and ones that already carry a Content-Encoding are sent unchanged.
+func Compression(minBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipWriter{ResponseWriter: w, minBytes: minBytes, statusCode: http.StatusOK}
+			defer gw.close() //nolint:errcheck // The client has gone if the final write fails
+
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, honouring q=0 as a refusal
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(strings.TrimSpace(name), "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		return q > 0
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jsonHandler(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body)) //nolint:errcheck // test handler
+	})
+}
+
+func serveCompressed(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	Compression(64)(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompression_GzipsLargeJSON(t *testing.T) {
+	body := `{"data":"` + strings.Repeat("x", 200) + `"}`
+
+	rec := serveCompressed(jsonHandler(http.StatusOK, body), "gzip, deflate")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	assert.Less(t, rec.Body.Len(), len(body))
+
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestCompression_GzipsAcrossSeveralWrites(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		for range 10 {
+			w.Write([]byte(`{"chunk":"0123456789"}`)) //nolint:errcheck // test handler
+		}
+	})
+
+	rec := serveCompressed(handler, "gzip")
+
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat(`{"chunk":"0123456789"}`, 10), string(decoded))
+}
+
+func TestCompression_LeavesResponsesUnchanged(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("x", 200) + `"}`
+
+	tests := []struct {
+		handler        http.Handler
+		name           string
+		acceptEncoding string
+		body           string
+	}{
+		{
+			name:           "small response",
+			handler:        jsonHandler(http.StatusOK, `{"ok":true}`),
+			acceptEncoding: "gzip",
+			body:           `{"ok":true}`,
+		},
+		{
+			name:    "client does not accept gzip",
+			handler: jsonHandler(http.StatusOK, large),
+			body:    large,
+		},
+		{
+			name:           "gzip refused with q=0",
+			handler:        jsonHandler(http.StatusOK, large),
+			acceptEncoding: "gzip;q=0, identity",
+			body:           large,
+		},
+		{
+			name: "not JSON",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(large)) //nolint:errcheck // test handler
+			}),
+			acceptEncoding: "gzip",
+			body:           large,
+		},
+		{
+			name: "already encoded",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Encoding", "br")
+				w.Write([]byte(large)) //nolint:errcheck // test handler
+			}),
+			acceptEncoding: "gzip",
+			body:           large,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCompressed(tt.handler, tt.acceptEncoding)
+
+			assert.NotEqual(t, "gzip", rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestCompression_KeepsStatusWithoutBody(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := serveCompressed(handler, "gzip")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Zero(t, rec.Body.Len())
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("br, GZIP;q=0.5"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br, deflate"))
+	assert.False(t, acceptsGzip("gzip;q=0"))
+}
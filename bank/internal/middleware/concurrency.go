@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// overloadedRetryAfter is the Retry-After, in seconds, sent with requests rejected by ConcurrencyLimit
+const overloadedRetryAfter = 1
+
+// concurrencyExemptPaths are served outside the limit, so probes and metrics scrapes keep working
+// while the API is saturated
+var concurrencyExemptPaths = []string{
+	"/health",
+	"/live",
+	"/ready",
+	"/status",
+	"/metrics",
+}
+
+// ConcurrencyLimit creates middleware that serves at most limit requests at once and rejects the rest
+// with 503 rather than queueing them on the database pool.
+//
+// Health probes and /metrics are exempt. Requests are never queued for a slot.
+func ConcurrencyLimit(limit int, logger *slog.Logger) func(http.Handler) http.Handler {
+	slots := make(chan struct{}, limit)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isConcurrencyExemptPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case slots <- struct{}{}:
+			default:
+				metrics.OverloadedRequests.Inc()
+				logger.WarnContext(r.Context(), "concurrency limit reached",
+					"path", r.URL.Path,
+					"method", r.Method,
+					"limit", limit,
+				)
+				w.Header().Set("Retry-After", strconv.Itoa(overloadedRetryAfter))
+				respond.Error(w, http.StatusServiceUnavailable, api.ErrorCodeOverloaded, "server is busy, retry later")
+				return
+			}
+
+			metrics.InFlightRequests.Inc()
+			defer func() {
+				metrics.InFlightRequests.Dec()
+				<-slots
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isConcurrencyExemptPath(path string) bool {
+	for _, exempt := range concurrencyExemptPaths {
+		if path == exempt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler holds each request until release is closed
+func blockingHandler(entered chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestConcurrencyLimit_RejectsWhenSaturated(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	handler := ConcurrencyLimit(1, testLogger())(blockingHandler(entered, release))
+
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil))
+		done <- rec.Code
+	}()
+	<-entered
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, api.ErrorCodeOverloaded, body.Error.Code)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestConcurrencyLimit_ReleasesSlot(t *testing.T) {
+	handler := ConcurrencyLimit(1, testLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range 3 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/accounts/123", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestConcurrencyLimit_ExemptPaths(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	blocked := blockingHandler(entered, release)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/authorizations", blocked)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := ConcurrencyLimit(1, testLogger())(mux)
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil))
+		close(done)
+	}()
+	<-entered
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	close(release)
+	<-done
+}
@@ -4,24 +4,49 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/api"
 	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
 )
 
 const idempotencyKeyHeader = "Idempotency-Key"
 
-// idempotentPaths defines which paths require idempotency handling
-//
-// Only mutating operations (POST) need idempotency
-var idempotentPaths = []string{
-	"/api/v1/authorizations",
-	"/api/v1/captures",
-	"/api/v1/voids",
-	"/api/v1/refunds",
+// MaxIdempotencyKeyLength is the longest idempotency key accepted
+const MaxIdempotencyKeyLength = 255
+
+// ValidateIdempotencyKey checks that key is 1 to MaxIdempotencyKeyLength characters drawn from
+// letters, digits and -_.:~+/=, which covers UUIDs, ULIDs and base64 tokens
+func ValidateIdempotencyKey(key string) error {
+	if key == "" {
+		return errors.New("idempotency key must not be empty")
+	}
+	if len(key) > MaxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key must be at most %d characters", MaxIdempotencyKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if !isIdempotencyKeyChar(key[i]) {
+			return fmt.Errorf("idempotency key contains invalid character %q", key[i])
+		}
+	}
+	return nil
+}
+
+func isIdempotencyKeyChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("-_.:~+/=", c) >= 0
+	}
 }
 
 // IdempotencyRepository defines the interface for idempotency storage
@@ -30,34 +55,89 @@ type IdempotencyRepository interface {
 	Store(ctx context.Context, idemKey *models.IdempotencyKey) error
 }
 
+// IdempotentRequest identifies an idempotent request that missed the cache
+// Handlers that can write its response record in the same database transaction as the change it
+// describes do so and call MarkRecorded, so a rolled back change never leaves a cached response
+// behind and a committed one is never left without one. A handler whose record lost to a
+// concurrent request with the same key calls MarkDuplicate, and its response is replaced with
+// the one that request committed.
+type IdempotentRequest struct {
+	Key         string
+	RequestPath string
+	recorded    atomic.Bool
+	duplicate   atomic.Bool
+}
+
+// MarkRecorded reports that the response record has been written, so the middleware must not store it
+func (r *IdempotentRequest) MarkRecorded() {
+	r.recorded.Store(true)
+}
+
+// MarkDuplicate reports that the key was recorded by a concurrent request, so the middleware must
+// replay that request's response
+func (r *IdempotentRequest) MarkDuplicate() {
+	r.duplicate.Store(true)
+}
+
+type idempotentRequestKey struct{}
+
+// IdempotentRequestFromContext returns the idempotent request being served, if any
+func IdempotentRequestFromContext(ctx context.Context) (*IdempotentRequest, bool) {
+	req, ok := ctx.Value(idempotentRequestKey{}).(*IdempotentRequest)
+	return req, ok
+}
+
+// responseCapture buffers a response until the middleware knows whether to send it or replay the
+// response of a concurrent request in its place
 type responseCapture struct {
-	http.ResponseWriter
+	header     http.Header
 	body       bytes.Buffer
 	statusCode int
 }
 
-func newResponseCapture(w http.ResponseWriter) *responseCapture {
+func newResponseCapture() *responseCapture {
 	return &responseCapture{
-		ResponseWriter: w,
-		statusCode:     http.StatusOK, // Default if WriteHeader not called
+		header:     make(http.Header),
+		statusCode: http.StatusOK, // Default if WriteHeader not called
 	}
 }
 
+func (rc *responseCapture) Header() http.Header {
+	return rc.header
+}
+
 func (rc *responseCapture) WriteHeader(code int) {
 	rc.statusCode = code
-	rc.ResponseWriter.WriteHeader(code)
 }
 
 func (rc *responseCapture) Write(b []byte) (int, error) {
-	rc.body.Write(b) // Capture for caching
-	return rc.ResponseWriter.Write(b)
+	return rc.body.Write(b)
+}
+
+// flush sends the buffered response to w
+func (rc *responseCapture) flush(w http.ResponseWriter) {
+	for name, values := range rc.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(rc.statusCode)
+	//nolint:errcheck // Best effort response writing
+	w.Write(rc.body.Bytes())
 }
 
-// Idempotency creates middleware that handles idempotent request caching.
-func Idempotency(repo IdempotencyRepository, logger *slog.Logger) func(http.Handler) http.Handler {
+// Idempotency creates middleware that handles idempotent request caching for routes, given as
+// "METHOD /path" entries. Safe methods bypass the key store even when a route names them.
+// When the key store cannot be read, requests are rejected with 503 unless failOpen is set,
+// in which case they are processed without deduplication.
+func Idempotency(repo IdempotencyRepository, routes []string, failOpen bool, logger *slog.Logger) func(http.Handler) http.Handler {
+	idempotentRoutes := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		idempotentRoutes[method+" "+normalizeRequestPath(path)] = true
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !requiresIdempotency(r) {
+			if !requiresIdempotency(r, idempotentRoutes) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -68,35 +148,60 @@ func Idempotency(repo IdempotencyRepository, logger *slog.Logger) func(http.Hand
 				next.ServeHTTP(w, r)
 				return
 			}
+			if err := ValidateIdempotencyKey(idempotencyKey); err != nil {
+				respond.Error(w, http.StatusBadRequest, api.ErrorCodeInvalidIdempotencyKey, err.Error())
+				return
+			}
 
 			requestPath := normalizeRequestPath(r.URL.Path)
 			ctx := r.Context()
 
 			cached, err := repo.Get(ctx, idempotencyKey, requestPath)
 			if err != nil {
-				logger.Error("failed to check idempotency cache", "error", err)
-				next.ServeHTTP(w, r)
+				logger.WarnContext(ctx, "failed to check idempotency cache", "error", err, "fail_open", failOpen)
+				if failOpen {
+					next.ServeHTTP(w, r)
+					return
+				}
+				respond.Error(w, http.StatusServiceUnavailable, api.ErrorCodeIdempotencyUnavailable,
+					"idempotency store unavailable, retry later")
 				return
 			}
 
 			if cached != nil {
-				logger.Debug("returning cached idempotent response",
+				logger.DebugContext(ctx, "returning cached idempotent response",
 					"key", idempotencyKey,
 					"path", requestPath,
 					"status", cached.ResponseStatus,
 				)
-				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("X-Idempotent-Replayed", "true")
-				w.WriteHeader(cached.ResponseStatus)
-				//nolint:errcheck // Best effort response writing
-				w.Write([]byte(cached.ResponseBody))
+				replayResponse(w, cached)
 				return
 			}
 
-			capture := newResponseCapture(w)
-			next.ServeHTTP(capture, r)
+			pending := &IdempotentRequest{Key: idempotencyKey, RequestPath: requestPath}
+			capture := newResponseCapture()
+			next.ServeHTTP(capture, r.WithContext(context.WithValue(ctx, idempotentRequestKey{}, pending)))
 
-			if shouldCacheResponse(capture.statusCode) {
+			// A concurrent request committed the key first and this one was rolled back
+			if pending.duplicate.Load() {
+				cached, err := repo.Get(ctx, idempotencyKey, requestPath)
+				if err == nil && cached != nil {
+					logger.DebugContext(ctx, "returning idempotent response of a concurrent request",
+						"key", idempotencyKey,
+						"path", requestPath,
+					)
+					replayResponse(w, cached)
+					return
+				}
+				logger.WarnContext(ctx, "failed to load the idempotent response of a concurrent request",
+					"error", err,
+					"key", idempotencyKey,
+				)
+			}
+			capture.flush(w)
+
+			// Responses not already recorded alongside their change are stored after the fact
+			if shouldCacheResponse(capture.statusCode) && !pending.recorded.Load() {
 				idemKey := &models.IdempotencyKey{
 					Key:            idempotencyKey,
 					RequestPath:    requestPath,
@@ -106,7 +211,7 @@ func Idempotency(repo IdempotencyRepository, logger *slog.Logger) func(http.Hand
 				}
 
 				if err := repo.Store(ctx, idemKey); err != nil {
-					logger.Error("failed to store idempotency key",
+					logger.ErrorContext(ctx, "failed to store idempotency key",
 						"error", err,
 						"key", idempotencyKey,
 					)
@@ -116,17 +221,37 @@ func Idempotency(repo IdempotencyRepository, logger *slog.Logger) func(http.Hand
 	}
 }
 
-func requiresIdempotency(r *http.Request) bool {
-	if r.Method != http.MethodPost {
+// replayResponse writes a stored idempotent response
+func replayResponse(w http.ResponseWriter, cached *models.IdempotencyKey) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Idempotent-Replayed", "true")
+	w.WriteHeader(cached.ResponseStatus)
+	//nolint:errcheck // Best effort response writing
+	w.Write([]byte(cached.ResponseBody))
+}
+
+func requiresIdempotency(r *http.Request, routes map[string]bool) bool {
+	if isSafeMethod(r.Method) || isSimulation(r) {
 		return false
 	}
+	return routes[r.Method+" "+normalizeRequestPath(r.URL.Path)]
+}
 
-	for _, path := range idempotentPaths {
-		if r.URL.Path == path {
-			return true
-		}
+// isSafeMethod reports whether method is read-only, so a request with it never consumes a key
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
 	}
-	return false
+}
+
+// isSimulation reports whether r asks for a simulated authorization, which changes nothing and
+// so must neither replay nor be replayed in place of the real request
+func isSimulation(r *http.Request) bool {
+	simulate, err := strconv.ParseBool(r.URL.Query().Get("simulate"))
+	return err == nil && simulate
 }
 
 func normalizeRequestPath(urlPath string) string {
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/benx421/payment-gateway/bank/internal/models"
@@ -14,6 +15,13 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var testRoutes = []string{
+	"POST /api/v1/authorizations",
+	"POST /api/v1/captures",
+	"POST /api/v1/voids",
+	"POST /api/v1/refunds",
+}
+
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
@@ -27,7 +35,7 @@ func testHandler(status int, body string) http.Handler {
 
 func TestIdempotency_GETRequestsBypassed(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	handlerCalled := false
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -46,9 +54,75 @@ func TestIdempotency_GETRequestsBypassed(t *testing.T) {
 	repo.AssertNotCalled(t, "Store")
 }
 
+func TestIdempotency_SafeMethodsBypassed(t *testing.T) {
+	routes := append([]string{"GET /api/v1/authorizations", "HEAD /api/v1/authorizations"}, testRoutes...)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace} {
+		t.Run(method, func(t *testing.T) {
+			repo := mocks.NewMockIdempotencyRepository(t)
+			middleware := Idempotency(repo, routes, false, testLogger())
+
+			req := httptest.NewRequest(method, "/api/v1/authorizations", nil)
+			req.Header.Set("Idempotency-Key", "test-key")
+			rec := httptest.NewRecorder()
+
+			middleware(testHandler(http.StatusOK, `{}`)).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			repo.AssertNotCalled(t, "Get")
+			repo.AssertNotCalled(t, "Store")
+		})
+	}
+}
+
+func TestIdempotency_ConfiguredRoutes(t *testing.T) {
+	routes := []string{"PUT /admin/maintenance", "PATCH /api/v1/accounts/acc-1/"}
+
+	t.Run("configured PUT is cached", func(t *testing.T) {
+		repo := mocks.NewMockIdempotencyRepository(t)
+		repo.On("Get", mock.Anything, "put-key", "/admin/maintenance").Return(nil, nil)
+		repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/maintenance", nil)
+		req.Header.Set("Idempotency-Key", "put-key")
+		rec := httptest.NewRecorder()
+
+		Idempotency(repo, routes, false, testLogger())(testHandler(http.StatusOK, `{}`)).ServeHTTP(rec, req)
+
+		repo.AssertCalled(t, "Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey"))
+	})
+
+	t.Run("configured PATCH matches without its trailing slash", func(t *testing.T) {
+		repo := mocks.NewMockIdempotencyRepository(t)
+		repo.On("Get", mock.Anything, "patch-key", "/api/v1/accounts/acc-1").Return(nil, nil)
+		repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/accounts/acc-1", nil)
+		req.Header.Set("Idempotency-Key", "patch-key")
+		rec := httptest.NewRecorder()
+
+		Idempotency(repo, routes, false, testLogger())(testHandler(http.StatusOK, `{}`)).ServeHTTP(rec, req)
+
+		repo.AssertCalled(t, "Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey"))
+	})
+
+	t.Run("other methods on a configured path are bypassed", func(t *testing.T) {
+		repo := mocks.NewMockIdempotencyRepository(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/maintenance", nil)
+		req.Header.Set("Idempotency-Key", "post-key")
+		rec := httptest.NewRecorder()
+
+		Idempotency(repo, routes, false, testLogger())(testHandler(http.StatusOK, `{}`)).ServeHTTP(rec, req)
+
+		repo.AssertNotCalled(t, "Get")
+		repo.AssertNotCalled(t, "Store")
+	})
+}
+
 func TestIdempotency_NonIdempotentPathBypassed(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	handlerCalled := false
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -67,9 +141,24 @@ func TestIdempotency_NonIdempotentPathBypassed(t *testing.T) {
 	repo.AssertNotCalled(t, "Store")
 }
 
+func TestIdempotency_SimulationsBypassed(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations?simulate=true", nil)
+	req.Header.Set("Idempotency-Key", "test-key")
+	rec := httptest.NewRecorder()
+
+	middleware(testHandler(http.StatusOK, `{"simulated":true}`)).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertNotCalled(t, "Get")
+	repo.AssertNotCalled(t, "Store")
+}
+
 func TestIdempotency_MissingKeyPassesThrough(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	handlerCalled := false
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -88,12 +177,81 @@ func TestIdempotency_MissingKeyPassesThrough(t *testing.T) {
 	repo.AssertNotCalled(t, "Store")
 }
 
+func TestValidateIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		valid bool
+	}{
+		{"single character", "a", true},
+		{"uuid", "3f2504e0-4f89-41d3-9a0c-0305e82c3301", true},
+		{"base64 token", "dGVzdC1rZXk+/w==", true},
+		{"scoped key", "order_42:attempt.1~retry", true},
+		{"max length", strings.Repeat("k", MaxIdempotencyKeyLength), true},
+		{"empty", "", false},
+		{"one over max length", strings.Repeat("k", MaxIdempotencyKeyLength+1), false},
+		{"space", "my key", false},
+		{"control character", "key\x00", false},
+		{"non-ascii", "clé", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIdempotencyKey(tt.key)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestIdempotency_InvalidKeyRejectedBeforeLookup(t *testing.T) {
+	for _, key := range []string{strings.Repeat("k", MaxIdempotencyKeyLength+1), "bad key"} {
+		repo := mocks.NewMockIdempotencyRepository(t)
+		middleware := Idempotency(repo, testRoutes, false, testLogger())
+
+		handlerCalled := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerCalled = true
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+
+		middleware(handler).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid_idempotency_key")
+		assert.False(t, handlerCalled)
+		repo.AssertNotCalled(t, "Get")
+	}
+}
+
+func TestIdempotency_MaxLengthKeyAccepted(t *testing.T) {
+	key := strings.Repeat("k", MaxIdempotencyKeyLength)
+	repo := mocks.NewMockIdempotencyRepository(t)
+	repo.On("Get", mock.Anything, key, "/api/v1/authorizations").Return(nil, nil)
+	repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(nil)
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	req.Header.Set("Idempotency-Key", key)
+	rec := httptest.NewRecorder()
+
+	middleware(testHandler(http.StatusCreated, `{"id":"123"}`)).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
 func TestIdempotency_FirstRequestCached(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
 	repo.On("Get", mock.Anything, "unique-key-123", "/api/v1/authorizations").Return(nil, nil)
 	repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(nil)
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 	handler := testHandler(http.StatusOK, `{"status":"success"}`)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
@@ -121,7 +279,7 @@ func TestIdempotency_SecondRequestReturnsCached(t *testing.T) {
 	}
 	repo.On("Get", mock.Anything, "duplicate-key", "/api/v1/authorizations").Return(cached, nil)
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	callCount := 0
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -147,7 +305,7 @@ func TestIdempotency_SameKeyDifferentPathsAreSeparate(t *testing.T) {
 	repo.On("Get", mock.Anything, "shared-key", mock.Anything).Return(nil, nil)
 	repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(nil)
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -179,7 +337,7 @@ func TestIdempotency_5xxResponsesNotCached(t *testing.T) {
 	repo.On("Get", mock.Anything, "error-key", "/api/v1/authorizations").Return(nil, nil)
 	// Store should NOT be called for 5xx responses
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -200,7 +358,7 @@ func TestIdempotency_4xxResponsesNotCached(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
 	repo.On("Get", mock.Anything, "bad-request-key", "/api/v1/authorizations").Return(nil, nil)
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -221,7 +379,7 @@ func TestIdempotency_RepoGetErrorFailsOpen(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
 	repo.On("Get", mock.Anything, "test-key", "/api/v1/authorizations").Return(nil, errors.New("database connection failed"))
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, true, testLogger())
 
 	handlerCalled := false
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -239,12 +397,35 @@ func TestIdempotency_RepoGetErrorFailsOpen(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestIdempotency_RepoGetErrorFailsClosed(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	repo.On("Get", mock.Anything, "test-key", "/api/v1/authorizations").Return(nil, errors.New("database connection failed"))
+
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
+
+	handlerCalled := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	req.Header.Set("Idempotency-Key", "test-key")
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.False(t, handlerCalled, "handler should not run when duplicates cannot be detected")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "idempotency_unavailable")
+}
+
 func TestIdempotency_RepoStoreErrorDoesNotAffectResponse(t *testing.T) {
 	repo := mocks.NewMockIdempotencyRepository(t)
 	repo.On("Get", mock.Anything, "test-key", "/api/v1/authorizations").Return(nil, nil)
 	repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(errors.New("failed to store"))
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 	handler := testHandler(http.StatusOK, `{"status":"success"}`)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
@@ -272,7 +453,7 @@ func TestIdempotency_AllIdempotentPaths(t *testing.T) {
 			repo.On("Get", mock.Anything, "test-key", path).Return(nil, nil)
 			repo.On("Store", mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(nil)
 
-			middleware := Idempotency(repo, testLogger())
+			middleware := Idempotency(repo, testRoutes, false, testLogger())
 			handler := testHandler(http.StatusOK, `{"path":"`+path+`"}`)
 
 			req := httptest.NewRequest(http.MethodPost, path, nil)
@@ -297,7 +478,7 @@ func TestIdempotency_CachedResponseHasCorrectContentType(t *testing.T) {
 	}
 	repo.On("Get", mock.Anything, "content-type-key", "/api/v1/authorizations").Return(cached, nil)
 
-	middleware := Idempotency(repo, testLogger())
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
 	handler := testHandler(http.StatusOK, `{"status":"success"}`)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
@@ -308,3 +489,62 @@ func TestIdempotency_CachedResponseHasCorrectContentType(t *testing.T) {
 
 	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
 }
+
+func TestIdempotency_ResponseRecordedByHandlerNotStoredAgain(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	repo.On("Get", mock.Anything, "tx-key", "/api/v1/refunds").Return(nil, nil)
+
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pending, ok := IdempotentRequestFromContext(r.Context())
+		if assert.True(t, ok, "idempotent request should be on the context") {
+			assert.Equal(t, "tx-key", pending.Key)
+			assert.Equal(t, "/api/v1/refunds", pending.RequestPath)
+			pending.MarkRecorded()
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/refunds", nil)
+	req.Header.Set("Idempotency-Key", "tx-key")
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertNotCalled(t, "Store")
+}
+
+func TestIdempotency_DuplicateReplaysConcurrentResponse(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	repo.On("Get", mock.Anything, "race-key", "/api/v1/authorizations").Return(nil, nil).Once()
+	repo.On("Get", mock.Anything, "race-key", "/api/v1/authorizations").Return(&models.IdempotencyKey{
+		Key:            "race-key",
+		RequestPath:    "/api/v1/authorizations",
+		ResponseStatus: http.StatusOK,
+		ResponseBody:   `{"authorization_id":"first"}`,
+	}, nil).Once()
+
+	middleware := Idempotency(repo, testRoutes, false, testLogger())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pending, ok := IdempotentRequestFromContext(r.Context())
+		if assert.True(t, ok, "idempotent request should be on the context") {
+			pending.MarkDuplicate()
+		}
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":{"code":"transaction_conflict"}}`)) //nolint:errcheck // test handler
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	req.Header.Set("Idempotency-Key", "race-key")
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"authorization_id":"first"}`, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get("X-Idempotent-Replayed"))
+	assert.Empty(t, rec.Header().Get("Retry-After"), "headers of the rolled back response should be dropped")
+	repo.AssertNotCalled(t, "Store")
+}
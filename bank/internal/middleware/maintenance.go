@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// maintenanceRetryAfter is the Retry-After, in seconds, sent with writes rejected during maintenance
+const maintenanceRetryAfter = 60
+
+// readOnlyPostPaths are POST endpoints that only read, so they stay available during maintenance
+var readOnlyPostPaths = []string{
+	"/api/v1/transactions/batch-status",
+}
+
+// Maintenance creates middleware that rejects writes with 503 while enabled is set.
+//
+// Safe methods, read-only POST endpoints and admin paths are served as usual, so the flag can
+// be cleared through the admin API.
+func Maintenance(enabled *atomic.Bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled.Load() || !isWrite(r) || isAdminPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(maintenanceRetryAfter))
+			respond.Error(w, http.StatusServiceUnavailable, api.ErrorCodeMaintenance,
+				"writes are paused for maintenance, retry later")
+		})
+	}
+}
+
+func isWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	case http.MethodPost:
+		for _, path := range readOnlyPostPaths {
+			if r.URL.Path == path {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveMaintenance(enabled bool, method, path string) (*httptest.ResponseRecorder, bool) {
+	flag := &atomic.Bool{}
+	flag.Store(enabled)
+
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	Maintenance(flag)(handler).ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec, called
+}
+
+func TestMaintenance_RejectsWrites(t *testing.T) {
+	rec, called := serveMaintenance(true, http.MethodPost, "/api/v1/authorizations")
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, api.ErrorCodeMaintenance, body.Error.Code)
+}
+
+func TestMaintenance_AllowsReadsAndAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{"get", http.MethodGet, "/api/v1/transactions"},
+		{"head", http.MethodHead, "/health"},
+		{"read-only post", http.MethodPost, "/api/v1/transactions/batch-status"},
+		{"admin toggle", http.MethodPut, "/admin/maintenance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, called := serveMaintenance(true, tt.method, tt.path)
+			assert.True(t, called)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestMaintenance_DisabledPassesThrough(t *testing.T) {
+	rec, called := serveMaintenance(false, http.MethodPost, "/api/v1/captures")
+
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+)
+
+// unmatchedRoute labels requests that match no registered pattern, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	sr.statusCode = code
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// Metrics creates middleware that records request durations.
+//
+// Routes are labelled with the mux pattern rather than the raw path so that IDs
+// in the URL do not create a new time series per request.
+func Metrics(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			route := unmatchedRoute
+			if _, pattern := mux.Handler(r); pattern != "" {
+				route = pattern
+			}
+
+			metrics.RequestDuration.
+				WithLabelValues(r.Method, route, strconv.Itoa(recorder.statusCode)).
+				Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestSampleCount(t *testing.T, method, route, status string) uint64 {
+	t.Helper()
+
+	observer, err := metrics.RequestDuration.GetMetricWithLabelValues(method, route, status)
+	require.NoError(t, err)
+
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMetrics_RecordsRoutePattern(t *testing.T) {
+	const route = "GET /api/v1/captures/{captureId}"
+
+	mux := http.NewServeMux()
+	mux.Handle(route, testHandler(http.StatusNotFound, ""))
+	handler := Metrics(mux)(mux)
+
+	before := requestSampleCount(t, http.MethodGet, route, "404")
+
+	for _, path := range []string{"/api/v1/captures/cap_123", "/api/v1/captures/cap_456"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, before+2, requestSampleCount(t, http.MethodGet, route, "404"),
+		"requests for different IDs should share one series")
+}
+
+func TestMetrics_UnmatchedRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := Metrics(mux)(mux)
+
+	before := requestSampleCount(t, http.MethodGet, unmatchedRoute, "404")
+
+	req := httptest.NewRequest(http.MethodGet, "/does-not-exist", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, before+1, requestSampleCount(t, http.MethodGet, unmatchedRoute, "404"))
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// rateLimitedPathPrefix limits throttling to API operations, leaving probes and docs untouched
+const rateLimitedPathPrefix = "/api/"
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// limiterStore keeps one token bucket per client and evicts buckets idle for longer than idleTTL
+type limiterStore struct {
+	limiters  map[string]*limiterEntry
+	lastSweep time.Time
+	now       func() time.Time
+	limit     rate.Limit
+	burst     int
+	idleTTL   time.Duration
+	mu        sync.Mutex
+}
+
+func newLimiterStore(requestsPerSecond float64, burst int, idleTTL time.Duration) *limiterStore {
+	return &limiterStore{
+		limiters:  make(map[string]*limiterEntry),
+		lastSweep: time.Now(),
+		now:       time.Now,
+		limit:     rate.Limit(requestsPerSecond),
+		burst:     burst,
+		idleTTL:   idleTTL,
+	}
+}
+
+func (s *limiterStore) get(key string) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= s.idleTTL {
+		s.evictIdle(now)
+	}
+
+	entry, ok := s.limiters[key]
+	if !ok {
+		entry = &limiterEntry{limiter: rate.NewLimiter(s.limit, s.burst)}
+		s.limiters[key] = entry
+	}
+	entry.lastSeen = now
+
+	return entry.limiter
+}
+
+// evictIdle must be called with mu held
+func (s *limiterStore) evictIdle(now time.Time) {
+	for key, entry := range s.limiters {
+		if now.Sub(entry.lastSeen) >= s.idleTTL {
+			delete(s.limiters, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+func (s *limiterStore) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.limiters)
+}
+
+// RateLimit creates middleware that applies a token-bucket limit per API key validated by
+// APIKeyAuth, falling back to the client IP for requests without one. Unvalidated bearer tokens
+// are ignored, so clients cannot mint a fresh bucket per request.
+//
+// Requests over the limit receive 429 with a Retry-After header.
+func RateLimit(requestsPerSecond float64, burst int, idleTTL time.Duration, logger *slog.Logger) func(http.Handler) http.Handler {
+	store := newLimiterStore(requestsPerSecond, burst, idleTTL)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, rateLimitedPathPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limiter := store.get(rateLimitKey(r))
+
+			reservation := limiter.Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				reservation.Cancel()
+
+				logger.WarnContext(r.Context(), "rate limit exceeded",
+					"path", r.URL.Path,
+					"method", r.Method,
+					"retry_after", delay,
+				)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(delay)))
+				respond.Error(w, http.StatusTooManyRequests, api.ErrorCodeRateLimited, "too many requests")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the client by the actor APIKeyAuth authenticated, or else by IP
+func rateLimitKey(r *http.Request) string {
+	if actor := logging.ActorFromContext(r.Context()); actor != "" {
+		return "actor:" + actor
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func retryAfterSeconds(delay time.Duration) int {
+	if delay == rate.InfDuration {
+		return 1
+	}
+	return int(math.Ceil(delay.Seconds()))
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_RejectsOverBurst(t *testing.T) {
+	middleware := RateLimit(1, 2, time.Minute, testLogger())
+	handler := middleware(testHandler(http.StatusOK, "ok"))
+
+	statuses := make([]int, 0, 3)
+	for range 3 {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		statuses = append(statuses, rec.Code)
+
+		if rec.Code == http.StatusTooManyRequests {
+			assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+		}
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses)
+}
+
+func TestRateLimit_SeparateBucketsPerClient(t *testing.T) {
+	middleware := RateLimit(1, 1, time.Minute, testLogger())
+	handler := middleware(testHandler(http.StatusOK, "ok"))
+
+	clients := []func(*http.Request) *http.Request{
+		func(r *http.Request) *http.Request { r.RemoteAddr = "10.0.0.1:1234"; return r },
+		func(r *http.Request) *http.Request { r.RemoteAddr = "10.0.0.2:1234"; return r },
+		func(r *http.Request) *http.Request {
+			return r.WithContext(logging.ContextWithActor(r.Context(), KeyActor("key-one")))
+		},
+		func(r *http.Request) *http.Request {
+			return r.WithContext(logging.ContextWithActor(r.Context(), KeyActor("key-two")))
+		},
+	}
+
+	for _, setClient := range clients {
+		req := setClient(httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestRateLimit_UnvalidatedTokensShareIPBucket(t *testing.T) {
+	middleware := RateLimit(1, 1, time.Minute, testLogger())
+	handler := middleware(testHandler(http.StatusOK, "ok"))
+
+	statuses := make([]int, 0, 2)
+	for _, token := range []string{"fresh-token-1", "fresh-token-2"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		statuses = append(statuses, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, statuses)
+}
+
+func TestRateLimit_KeyedOnAuthenticatedActor(t *testing.T) {
+	handler := APIKeyAuth([]string{"valid-key"}, testLogger())(
+		RateLimit(1, 1, time.Minute, testLogger())(testHandler(http.StatusOK, "ok")))
+
+	statuses := make([]int, 0, 2)
+	for _, remoteAddr := range []string{"10.0.0.1:1234", "10.0.0.2:1234"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer valid-key")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		statuses = append(statuses, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, statuses,
+		"one API key should share a bucket across addresses")
+}
+
+func TestRateLimit_NonAPIPathsBypassed(t *testing.T) {
+	middleware := RateLimit(1, 1, time.Minute, testLogger())
+	handler := middleware(testHandler(http.StatusOK, "ok"))
+
+	for range 5 {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestLimiterStore_EvictsIdleEntries(t *testing.T) {
+	store := newLimiterStore(1, 1, time.Minute)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.get("ip:10.0.0.1")
+	store.get("ip:10.0.0.2")
+	assert.Equal(t, 2, store.size())
+
+	now = now.Add(2 * time.Minute)
+	store.get("ip:10.0.0.3")
+
+	assert.Equal(t, 1, store.size(), "idle limiters should be evicted on the next sweep")
+}
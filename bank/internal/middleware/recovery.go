@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// Recovery creates middleware that recovers from panics in downstream handlers,
+// logs the stack trace and responds with a 500 JSON error.
+//
+// http.ErrAbortHandler is re-panicked so the server can abort the response as intended.
+// Recovery is meant to wrap every other middleware, RequestID included, so it logs the request ID
+// RequestID set on the response when the request context it sees has none.
+func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				//nolint:errorlint // http.ErrAbortHandler is a sentinel panic value, not a wrapped error
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				ctx := r.Context()
+				if requestID := w.Header().Get(requestIDHeader); requestID != "" && logging.RequestIDFromContext(ctx) == "" {
+					ctx = logging.ContextWithRequestID(ctx, requestID)
+				}
+				logger.ErrorContext(ctx, "recovered from panic",
+					"panic", rec,
+					"path", r.URL.Path,
+					"method", r.Method,
+					"stack", string(debug.Stack()),
+				)
+				respond.Error(w, http.StatusInternalServerError, api.ErrorCodeInternalError, "internal error")
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
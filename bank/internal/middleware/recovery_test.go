@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery_PassesThrough(t *testing.T) {
+	middleware := Recovery(testLogger())
+	handler := testHandler(http.StatusOK, `{"status":"ok"}`)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestRecovery_PanicReturns500(t *testing.T) {
+	middleware := Recovery(testLogger())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	assert.NotPanics(t, func() {
+		middleware(handler).ServeHTTP(rec, req)
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, api.ErrorCode("internal_error"), body.Error.Code)
+}
+
+func TestRecovery_ErrAbortHandlerRepanics(t *testing.T) {
+	middleware := Recovery(testLogger())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		middleware(handler).ServeHTTP(rec, req)
+	})
+}
+
+func TestRecovery_LogsRequestIDSetInside(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(&logs, nil)))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	chain := Recovery(logger)(RequestID()(handler))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	req.Header.Set(requestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+
+	chain.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "req-123", rec.Header().Get(requestIDHeader))
+	assert.Contains(t, logs.String(), `"request_id":"req-123"`)
+}
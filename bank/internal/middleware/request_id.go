@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs to keep log lines sane
+const maxRequestIDLength = 128
+
+// RequestID creates middleware that propagates a request ID through the request context.
+//
+// The ID is taken from the X-Request-ID header or generated when absent,
+// and is echoed back in the response header.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(requestIDHeader)
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = uuid.NewString()
+			}
+
+			w.Header().Set(requestIDHeader, requestID)
+
+			ctx := logging.ContextWithRequestID(r.Context(), requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
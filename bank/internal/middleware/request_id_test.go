@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID_UsesIncomingHeader(t *testing.T) {
+	var ctxRequestID string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = logging.RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	req.Header.Set("X-Request-ID", "gateway-req-1")
+	rec := httptest.NewRecorder()
+
+	RequestID()(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, "gateway-req-1", ctxRequestID)
+	assert.Equal(t, "gateway-req-1", rec.Header().Get("X-Request-ID"))
+}
+
+func TestRequestID_GeneratesWhenMissing(t *testing.T) {
+	var ctxRequestID string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = logging.RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	RequestID()(handler).ServeHTTP(rec, req)
+
+	_, err := uuid.Parse(ctxRequestID)
+	assert.NoError(t, err, "generated request ID should be a UUID")
+	assert.Equal(t, ctxRequestID, rec.Header().Get("X-Request-ID"))
+}
+
+func TestRequestID_ReplacesOversizedHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	req.Header.Set("X-Request-ID", strings.Repeat("a", 500))
+	rec := httptest.NewRecorder()
+
+	RequestID()(testHandler(http.StatusOK, "")).ServeHTTP(rec, req)
+
+	_, err := uuid.Parse(rec.Header().Get("X-Request-ID"))
+	assert.NoError(t, err)
+}
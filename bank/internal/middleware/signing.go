@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// Request signing headers
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Timestamp"
+	NonceHeader     = "X-Nonce"
+)
+
+// maxNonceLength is the longest nonce accepted, matching the request_nonces column
+const maxNonceLength = 255
+
+// NonceStore records the nonces of signed requests so each is accepted once
+type NonceStore interface {
+	Use(ctx context.Context, nonce string) (bool, error)
+}
+
+// maxSignedBodyBytes bounds how much of a request body is buffered for verification
+const maxSignedBodyBytes = 1 << 20
+
+// RequestSigning creates middleware that verifies an HMAC-SHA256 signature on every API request.
+//
+// The client is identified by its bearer token and signs the X-Timestamp value (unix seconds)
+// concatenated with the raw body, sending the hex digest in X-Signature. Requests whose timestamp
+// falls outside window of the server clock are rejected to limit replay. The body is buffered and
+// restored so downstream handlers can read it. Must run after APIKeyAuth has validated the token.
+//
+// When nonces is set, clients must also send a unique X-Nonce, signed between the timestamp and
+// the body, and a nonce is rejected once it has been used, closing the replay gap within window.
+func RequestSigning(
+	secrets map[string]string,
+	window time.Duration,
+	nonces NonceStore,
+	logger *slog.Logger,
+) func(http.Handler) http.Handler {
+	return requestSigning(secrets, window, nonces, time.Now, logger)
+}
+
+func requestSigning(
+	secrets map[string]string,
+	window time.Duration,
+	nonces NonceStore,
+	now func() time.Time,
+	logger *slog.Logger,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, _ := bearerToken(r)
+			secret, ok := secrets[token]
+			if !ok {
+				writeUnauthorized(w, "no signing secret for client")
+				return
+			}
+
+			timestamp := r.Header.Get(TimestampHeader)
+			signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				writeUnauthorized(w, "missing or invalid timestamp")
+				return
+			}
+			if skew := now().Sub(time.Unix(signedAt, 0)); skew > window || skew < -window {
+				logger.WarnContext(r.Context(), "rejected request with stale signature",
+					"path", r.URL.Path,
+					"skew", skew,
+				)
+				writeUnauthorized(w, "timestamp outside allowed window")
+				return
+			}
+
+			nonce := r.Header.Get(NonceHeader)
+			if nonces != nil && (nonce == "" || len(nonce) > maxNonceLength) {
+				writeUnauthorized(w, "missing or invalid nonce")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodyBytes))
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					respond.Error(w, http.StatusRequestEntityTooLarge, api.ErrorCodeRequestTooLarge, "request body too large")
+					return
+				}
+				respond.Error(w, http.StatusBadRequest, api.ErrorCodeInvalidRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if !validSignature(secret, timestamp+nonce, body, r.Header.Get(SignatureHeader)) {
+				logger.WarnContext(r.Context(), "rejected request with invalid signature",
+					"path", r.URL.Path,
+					"method", r.Method,
+				)
+				writeUnauthorized(w, "invalid signature")
+				return
+			}
+
+			// Checked after the signature so unsigned requests cannot burn a client's nonces
+			if nonces != nil {
+				fresh, err := nonces.Use(r.Context(), nonce)
+				if err != nil {
+					logger.ErrorContext(r.Context(), "failed to record request nonce", "error", err)
+					respond.Error(w, http.StatusInternalServerError, api.ErrorCodeInternalError, "failed to verify request")
+					return
+				}
+				if !fresh {
+					logger.WarnContext(r.Context(), "rejected replayed request",
+						"path", r.URL.Path,
+						"method", r.Method,
+					)
+					writeUnauthorized(w, "nonce already used")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validSignature compares the provided hex digest, optionally prefixed with "sha256=", in constant time
+func validSignature(secret, timestamp string, body []byte, provided string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(provided, "sha256="))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, computeSignature(secret, timestamp, body))
+}
+
+func computeSignature(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
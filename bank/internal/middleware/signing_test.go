@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestSigning(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	secrets := map[string]string{"key-one": "secret-one"}
+	body := `{"amount":1000}`
+	fresh := strconv.FormatInt(now.Unix(), 10)
+
+	sign := func(secret, timestamp, body string) string {
+		return hex.EncodeToString(computeSignature(secret, timestamp, []byte(body)))
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		timestamp      string
+		signature      string
+		expectedStatus int
+	}{
+		{
+			name:           "valid signature",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			timestamp:      fresh,
+			signature:      sign("secret-one", fresh, body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "prefixed signature",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			timestamp:      fresh,
+			signature:      "sha256=" + sign("secret-one", fresh, body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong secret",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			timestamp:      fresh,
+			signature:      sign("secret-two", fresh, body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "signature over different timestamp",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			timestamp:      fresh,
+			signature:      sign("secret-one", strconv.FormatInt(now.Unix()-1, 10), body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "stale timestamp",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			timestamp:      strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10),
+			signature:      sign("secret-one", strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10), body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "future timestamp",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			timestamp:      strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10),
+			signature:      sign("secret-one", strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10), body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing timestamp",
+			path:           "/api/v1/authorizations",
+			token:          "key-one",
+			signature:      sign("secret-one", "", body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown client",
+			path:           "/api/v1/authorizations",
+			token:          "key-two",
+			timestamp:      fresh,
+			signature:      sign("secret-one", fresh, body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "non-api path is not checked",
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware := requestSigning(secrets, 5*time.Minute, nil, func() time.Time { return now }, testLogger())
+
+			var seenBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body) //nolint:errcheck // test handler
+				seenBody = string(b)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.timestamp != "" {
+				req.Header.Set(TimestampHeader, tt.timestamp)
+			}
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+
+			middleware(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, body, seenBody, "handler should see the original body")
+			}
+		})
+	}
+}
+
+func TestRequestSigning_Nonce(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := `{"amount":1000}`
+
+	tests := []struct {
+		setupStore     func(*mocks.MockNonceStore)
+		name           string
+		nonce          string
+		signedNonce    string
+		expectedStatus int
+	}{
+		{
+			name:        "fresh nonce",
+			nonce:       "nonce-1",
+			signedNonce: "nonce-1",
+			setupStore: func(s *mocks.MockNonceStore) {
+				s.On("Use", mock.Anything, "nonce-1").Return(true, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "replayed nonce",
+			nonce:       "nonce-1",
+			signedNonce: "nonce-1",
+			setupStore: func(s *mocks.MockNonceStore) {
+				s.On("Use", mock.Anything, "nonce-1").Return(false, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing nonce",
+			setupStore:     func(*mocks.MockNonceStore) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "nonce too long",
+			nonce:          strings.Repeat("n", maxNonceLength+1),
+			signedNonce:    strings.Repeat("n", maxNonceLength+1),
+			setupStore:     func(*mocks.MockNonceStore) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "nonce not covered by the signature",
+			nonce:          "nonce-2",
+			signedNonce:    "nonce-1",
+			setupStore:     func(*mocks.MockNonceStore) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "store failure",
+			nonce:       "nonce-1",
+			signedNonce: "nonce-1",
+			setupStore: func(s *mocks.MockNonceStore) {
+				s.On("Use", mock.Anything, "nonce-1").Return(false, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewMockNonceStore(t)
+			tt.setupStore(store)
+			middleware := requestSigning(map[string]string{"key-one": "secret-one"}, 5*time.Minute, store,
+				func() time.Time { return now }, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer key-one")
+			req.Header.Set(TimestampHeader, timestamp)
+			if tt.nonce != "" {
+				req.Header.Set(NonceHeader, tt.nonce)
+			}
+			req.Header.Set(SignatureHeader,
+				hex.EncodeToString(computeSignature("secret-one", timestamp+tt.signedNonce, []byte(body))))
+			rec := httptest.NewRecorder()
+
+			middleware(testHandler(http.StatusOK, "ok")).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequestSigning_BodyTooLarge(t *testing.T) {
+	now := time.Now()
+	middleware := requestSigning(map[string]string{"key-one": "s"}, time.Minute, nil, func() time.Time { return now }, testLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", strings.NewReader(strings.Repeat("a", maxSignedBodyBytes+1)))
+	req.Header.Set("Authorization", "Bearer key-one")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	rec := httptest.NewRecorder()
+
+	middleware(testHandler(http.StatusOK, "ok")).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
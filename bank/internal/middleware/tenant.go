@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// TenantHeader names the tenant whose schema a request operates on
+const TenantHeader = "X-Tenant-ID"
+
+// TenantRouter binds a context to a tenant's schema, rejecting tenants it does not know
+type TenantRouter interface {
+	WithTenant(ctx context.Context, name string) (context.Context, error)
+}
+
+// Tenant creates middleware that routes each request carrying X-Tenant-ID to that tenant's schema.
+//
+// Requests without the header use the default schema. Unknown tenants are rejected with 400 before
+// any query runs, so a request never falls back to another tenant's data.
+func Tenant(router TenantRouter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.Header.Get(TenantHeader)
+			if name == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, err := router.WithTenant(r.Context(), name)
+			if err != nil {
+				respond.Error(w, http.StatusBadRequest, api.ErrorCodeInvalidRequest, "unknown tenant")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type tenantTestKey struct{}
+
+func TestTenant(t *testing.T) {
+	tests := []struct {
+		setupRouter    func(*mocks.MockTenantRouter)
+		expectedTenant any
+		name           string
+		tenant         string
+		expectedStatus int
+	}{
+		{
+			name:   "known tenant",
+			tenant: "tenant_a",
+			setupRouter: func(r *mocks.MockTenantRouter) {
+				r.On("WithTenant", mock.Anything, "tenant_a").
+					Return(func(ctx context.Context, name string) (context.Context, error) {
+						return context.WithValue(ctx, tenantTestKey{}, name), nil
+					})
+			},
+			expectedTenant: "tenant_a",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "unknown tenant",
+			tenant: "tenant_b",
+			setupRouter: func(r *mocks.MockTenantRouter) {
+				r.On("WithTenant", mock.Anything, "tenant_b").Return(context.Background(), errors.New("unknown tenant"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no tenant header",
+			setupRouter:    func(*mocks.MockTenantRouter) {},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mocks.NewMockTenantRouter(t)
+			tt.setupRouter(router)
+
+			var seenTenant any
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seenTenant = r.Context().Value(tenantTestKey{})
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions", nil)
+			if tt.tenant != "" {
+				req.Header.Set(TenantHeader, tt.tenant)
+			}
+			rec := httptest.NewRecorder()
+
+			Tenant(router)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedTenant, seenTenant)
+		})
+	}
+}
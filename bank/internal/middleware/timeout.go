@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/benx421/payment-gateway/bank/internal/respond"
+)
+
+// timeoutWriter buffers the handler's response so it can be discarded if the deadline passes first
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	buf         bytes.Buffer
+	mu          sync.Mutex
+	code        int
+	timedOut    bool
+	wroteHeader bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.buf.Write(b)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.writeHeaderLocked(code)
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	tw.wroteHeader = true
+	tw.code = code
+}
+
+// handlerPanic carries a panic out of the handler goroutine along with that goroutine's stack
+type handlerPanic struct {
+	value any
+	stack []byte
+}
+
+// Timeout creates middleware that bounds each request with a deadline.
+//
+// The request context is cancelled at the deadline so repository calls abort, and
+// the client receives a 503 JSON error if the handler has not finished by then.
+// Responses are buffered, so streaming endpoints must be listed in exemptPrefixes.
+// A handler panic is logged with the handler's stack and re-panicked with its original value, so
+// Recovery still recognizes http.ErrAbortHandler.
+func Timeout(timeout time.Duration, logger *slog.Logger, exemptPrefixes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasAnyPrefix(r.URL.Path, exemptPrefixes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			done := make(chan struct{})
+			panicChan := make(chan handlerPanic, 1)
+			tw := &timeoutWriter{
+				w:      w,
+				header: make(http.Header),
+			}
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicChan <- handlerPanic{value: p, stack: debug.Stack()}
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case p := <-panicChan:
+				//nolint:errorlint // http.ErrAbortHandler is a sentinel panic value, not a wrapped error
+				if p.value != http.ErrAbortHandler {
+					logger.ErrorContext(ctx, "handler panicked",
+						"panic", p.value,
+						"path", r.URL.Path,
+						"method", r.Method,
+						"stack", string(p.stack),
+					)
+				}
+				panic(p.value)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				dst := w.Header()
+				for key, values := range tw.header {
+					dst[key] = values
+				}
+				if !tw.wroteHeader {
+					tw.code = http.StatusOK
+				}
+				w.WriteHeader(tw.code)
+				//nolint:errcheck // Best effort response writing
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				tw.timedOut = true
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					logger.WarnContext(ctx, "request timed out",
+						"path", r.URL.Path,
+						"method", r.Method,
+						"timeout", timeout,
+					)
+					respond.Error(w, http.StatusServiceUnavailable, api.ErrorCodeTimeout, "request timed out")
+				}
+			}
+		})
+	}
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
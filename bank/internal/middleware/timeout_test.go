@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout_FastHandlerPassesThrough(t *testing.T) {
+	middleware := Timeout(time.Second, testLogger())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true}`)) //nolint:errcheck // test helper
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `{"ok":true}`, rec.Body.String())
+}
+
+func TestTimeout_SlowHandlerReturns503(t *testing.T) {
+	middleware := Timeout(20*time.Millisecond, testLogger())
+
+	ctxCancelled := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(ctxCancelled)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, api.ErrorCode("timeout"), body.Error.Code)
+
+	select {
+	case <-ctxCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+}
+
+func TestTimeout_ExemptPrefixNotBounded(t *testing.T) {
+	middleware := Timeout(time.Millisecond, testLogger(), "/api/v1/exports")
+
+	var hasDeadline bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/exports/transactions", nil)
+	rec := httptest.NewRecorder()
+
+	middleware(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, hasDeadline)
+}
+
+func TestTimeout_PanicPropagates(t *testing.T) {
+	middleware := Timeout(time.Second, testLogger())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		middleware(handler).ServeHTTP(rec, req)
+	})
+}
+
+func TestTimeout_ErrAbortHandlerPropagates(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	chain := Recovery(testLogger())(Timeout(time.Second, testLogger())(handler))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authorizations", nil)
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		chain.ServeHTTP(rec, req)
+	})
+}
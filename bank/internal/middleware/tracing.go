@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Tracing creates middleware that records a server span for each request, continuing the trace
+// named in the incoming traceparent header.
+//
+// Spans are named after the mux pattern rather than the raw path, matching the Metrics route labels.
+func Tracing(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, "http.request",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				if _, pattern := mux.Handler(r); pattern != "" {
+					return pattern
+				}
+				return r.Method + " " + unmatchedRoute
+			}),
+		)
+	}
+}
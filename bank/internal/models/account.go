@@ -2,20 +2,105 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 // Account represents a customer account with card details and balance
+// Card details are excluded from JSON so an Account can never leak them if encoded by mistake.
 type Account struct {
+	CreatedAt             time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
+	AccountNumber         string    `db:"account_number" json:"-"`
+	CVV                   string    `db:"cvv" json:"-"`
+	Currency              string    `db:"currency" json:"currency"`
+	BalanceCents          int64     `db:"balance_cents" json:"balance_cents"`
+	AvailableBalanceCents int64     `db:"available_balance_cents" json:"available_balance_cents"`
+	ExpiryMonth           int       `db:"expiry_month" json:"-"`
+	ExpiryYear            int       `db:"expiry_year" json:"-"`
+	ID                    uuid.UUID `db:"id" json:"id"`
+}
+
+// Balance is an account's funds in one currency
+// Every account has a balance in its primary currency, mirrored in Account.BalanceCents and
+// Account.AvailableBalanceCents; it may hold balances in other currencies too.
+type Balance struct {
 	CreatedAt             time.Time `db:"created_at"`
 	UpdatedAt             time.Time `db:"updated_at"`
-	AccountNumber         string    `db:"account_number"`
-	CVV                   string    `db:"cvv"`
+	Currency              string    `db:"currency"`
 	BalanceCents          int64     `db:"balance_cents"`
 	AvailableBalanceCents int64     `db:"available_balance_cents"`
-	ExpiryMonth           int       `db:"expiry_month"`
-	ExpiryYear            int       `db:"expiry_year"`
-	ID                    uuid.UUID `db:"id"`
+	AccountID             uuid.UUID `db:"account_id"`
+}
+
+// PendingCents is the part of the balance reserved by authorization holds
+// It is never negative, even if the available balance drifts above the ledger balance.
+func (a *Account) PendingCents() int64 {
+	return max(a.BalanceCents-a.AvailableBalanceCents, 0)
+}
+
+// PublicAccount is the subset of an account that is safe to return to clients
+type PublicAccount struct {
+	AccountNumber         string `json:"account_number"` // masked to the last four digits
+	Currency              string `json:"currency"`
+	BalanceCents          int64  `json:"balance_cents"`
+	AvailableBalanceCents int64  `json:"available_balance_cents"`
+	PendingCents          int64  `json:"pending_cents"`
+}
+
+// ToPublic returns the client-safe view of the account with its card number masked
+func (a *Account) ToPublic() PublicAccount {
+	return PublicAccount{
+		AccountNumber:         MaskAccountNumber(a.AccountNumber),
+		Currency:              a.Currency,
+		BalanceCents:          a.BalanceCents,
+		AvailableBalanceCents: a.AvailableBalanceCents,
+		PendingCents:          a.PendingCents(),
+	}
+}
+
+// BalanceBreakdown splits an account's ledger balance into settled and pending funds
+//
+// PendingCents is derived from the balances while OutstandingHoldsCents is summed from the
+// active authorizations themselves, so the two differ only if the balances have drifted from
+// the ledger.
+type BalanceBreakdown struct {
+	Currency              string
+	BalanceCents          int64
+	AvailableBalanceCents int64
+	PendingCents          int64 // BalanceCents - AvailableBalanceCents, floored at zero
+	OutstandingHoldsCents int64 // Uncaptured remainder of active holds, in the account currency
+	OutstandingHolds      int   // Number of active authorizations
+	AccountID             uuid.UUID
+}
+
+// BalanceDrift compares a stored balance with the balance its ledger entries add up to
+type BalanceDrift struct {
+	Currency              string
+	BalanceCents          int64 // Stored in balances
+	AvailableBalanceCents int64 // Stored in balances
+	LedgerCents           int64 // Opening balance plus the settled transactions
+	LedgerAvailableCents  int64 // LedgerCents less the outstanding authorization holds
+	AccountID             uuid.UUID
+}
+
+// DriftCents is how far the stored balance is above the ledger one; zero when they agree
+func (d *BalanceDrift) DriftCents() int64 {
+	return d.BalanceCents - d.LedgerCents
+}
+
+// AvailableDriftCents is how far the stored available balance is above the ledger one; zero when they agree
+func (d *BalanceDrift) AvailableDriftCents() int64 {
+	return d.AvailableBalanceCents - d.LedgerAvailableCents
+}
+
+// MaskAccountNumber replaces all but the last four digits of an account number with asterisks
+func MaskAccountNumber(accountNumber string) string {
+	const visible = 4
+	if len(accountNumber) <= visible {
+		return strings.Repeat("*", len(accountNumber))
+	}
+	return strings.Repeat("*", len(accountNumber)-visible) + accountNumber[len(accountNumber)-visible:]
 }
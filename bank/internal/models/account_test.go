@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAccount() *Account {
+	return &Account{
+		ID:                    uuid.New(),
+		AccountNumber:         "4111111111111111",
+		CVV:                   "123",
+		Currency:              "USD",
+		BalanceCents:          1000000,
+		AvailableBalanceCents: 990000,
+		ExpiryMonth:           12,
+		ExpiryYear:            2030,
+	}
+}
+
+func TestAccount_JSONOmitsCardDetails(t *testing.T) {
+	data, err := json.Marshal(testAccount())
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	for _, key := range []string{"cvv", "CVV", "account_number", "AccountNumber", "expiry_month", "expiry_year"} {
+		assert.NotContains(t, fields, key)
+	}
+	assert.NotContains(t, string(data), "4111111111111111")
+	assert.Equal(t, float64(990000), fields["available_balance_cents"])
+}
+
+func TestAccount_ToPublic(t *testing.T) {
+	public := testAccount().ToPublic()
+
+	assert.Equal(t, PublicAccount{
+		AccountNumber:         "************1111",
+		Currency:              "USD",
+		BalanceCents:          1000000,
+		AvailableBalanceCents: 990000,
+		PendingCents:          10000,
+	}, public)
+
+	data, err := json.Marshal(public)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.NotContains(t, fields, "cvv")
+	assert.Len(t, fields, 5)
+}
+
+func TestAccount_PendingCents(t *testing.T) {
+	account := testAccount()
+	assert.Equal(t, int64(10000), account.PendingCents())
+
+	account.AvailableBalanceCents = account.BalanceCents + 1
+	assert.Equal(t, int64(0), account.PendingCents(), "pending must not go negative")
+}
+
+func TestMaskAccountNumber(t *testing.T) {
+	assert.Equal(t, "************1111", MaskAccountNumber("4111111111111111"))
+	assert.Equal(t, "*********0000", MaskAccountNumber("4000000000000"))
+	assert.Equal(t, "***", MaskAccountNumber("123"))
+}
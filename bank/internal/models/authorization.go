@@ -0,0 +1,26 @@
+package models
+
+import "encoding/json"
+
+// MetadataKeyAuthorizedAmount is the transaction metadata key holding an authorization's amount
+// after partial voids, in the authorization currency
+const MetadataKeyAuthorizedAmount = "authorized_amount"
+
+// AuthorizedAmount returns how much of an authorization can still be captured in total, which is
+// its amount less any partial voids recorded in the metadata
+func (t *Transaction) AuthorizedAmount() int64 {
+	switch v := t.Metadata[MetadataKeyAuthorizedAmount].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		// Metadata read back from the database decodes numbers as float64
+		return int64(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+	}
+	return t.AmountCents
+}
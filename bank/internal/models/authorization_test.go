@@ -0,0 +1,19 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_AuthorizedAmount(t *testing.T) {
+	auth := &Transaction{AmountCents: 10000, Currency: "USD"}
+	assert.Equal(t, int64(10000), auth.AuthorizedAmount())
+
+	auth.Metadata = map[string]any{MetadataKeyAuthorizedAmount: int64(7000)}
+	assert.Equal(t, int64(7000), auth.AuthorizedAmount())
+
+	// Metadata scanned from JSONB decodes numbers as float64
+	auth.Metadata = map[string]any{MetadataKeyAuthorizedAmount: float64(4000)}
+	assert.Equal(t, int64(4000), auth.AuthorizedAmount())
+}
@@ -0,0 +1,32 @@
+package models
+
+import "encoding/json"
+
+// MetadataKeyChargeback is the transaction metadata key holding the chargeback details
+const MetadataKeyChargeback = "chargeback"
+
+// ChargebackDetails records why a capture was disputed and the fee charged to the merchant for it
+// The fee is in the currency of the disputed capture and does not move the cardholder's balance
+type ChargebackDetails struct {
+	Reason   string `json:"reason,omitempty"`
+	FeeCents int64  `json:"fee_cents"`
+}
+
+// ChargebackDetails returns the chargeback details recorded in the transaction metadata, if any
+func (t *Transaction) ChargebackDetails() (*ChargebackDetails, bool) {
+	raw, ok := t.Metadata[MetadataKeyChargeback]
+	if !ok || raw == nil {
+		return nil, false
+	}
+
+	// Metadata read back from the database is a generic map, so round-trip through JSON
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var details ChargebackDetails
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil, false
+	}
+	return &details, true
+}
@@ -0,0 +1,98 @@
+package models
+
+// Currency describes an ISO-4217 currency
+type Currency struct {
+	Code string
+	Name string
+	// MinorUnits is the number of decimal places between the major and minor unit:
+	// 2 for USD cents, 0 for JPY, 3 for BHD fils
+	MinorUnits int
+}
+
+// CurrencyRegistry maps ISO-4217 codes to their currencies
+type CurrencyRegistry map[string]Currency
+
+// defaultMinorUnits applies to codes missing from the registry
+const defaultMinorUnits = 2
+
+// Currencies holds the currencies the ledger accepts
+var Currencies = newCurrencyRegistry(
+	Currency{Code: "AED", Name: "UAE Dirham", MinorUnits: 2},
+	Currency{Code: "ARS", Name: "Argentine Peso", MinorUnits: 2},
+	Currency{Code: "AUD", Name: "Australian Dollar", MinorUnits: 2},
+	Currency{Code: "BHD", Name: "Bahraini Dinar", MinorUnits: 3},
+	Currency{Code: "BRL", Name: "Brazilian Real", MinorUnits: 2},
+	Currency{Code: "CAD", Name: "Canadian Dollar", MinorUnits: 2},
+	Currency{Code: "CHF", Name: "Swiss Franc", MinorUnits: 2},
+	Currency{Code: "CLP", Name: "Chilean Peso", MinorUnits: 0},
+	Currency{Code: "CNY", Name: "Yuan Renminbi", MinorUnits: 2},
+	Currency{Code: "COP", Name: "Colombian Peso", MinorUnits: 2},
+	Currency{Code: "CZK", Name: "Czech Koruna", MinorUnits: 2},
+	Currency{Code: "DKK", Name: "Danish Krone", MinorUnits: 2},
+	Currency{Code: "EGP", Name: "Egyptian Pound", MinorUnits: 2},
+	Currency{Code: "EUR", Name: "Euro", MinorUnits: 2},
+	Currency{Code: "GBP", Name: "Pound Sterling", MinorUnits: 2},
+	Currency{Code: "HKD", Name: "Hong Kong Dollar", MinorUnits: 2},
+	Currency{Code: "HUF", Name: "Forint", MinorUnits: 2},
+	Currency{Code: "IDR", Name: "Rupiah", MinorUnits: 2},
+	Currency{Code: "ILS", Name: "New Israeli Sheqel", MinorUnits: 2},
+	Currency{Code: "INR", Name: "Indian Rupee", MinorUnits: 2},
+	Currency{Code: "ISK", Name: "Iceland Krona", MinorUnits: 0},
+	Currency{Code: "JOD", Name: "Jordanian Dinar", MinorUnits: 3},
+	Currency{Code: "JPY", Name: "Yen", MinorUnits: 0},
+	Currency{Code: "KES", Name: "Kenyan Shilling", MinorUnits: 2},
+	Currency{Code: "KRW", Name: "Won", MinorUnits: 0},
+	Currency{Code: "KWD", Name: "Kuwaiti Dinar", MinorUnits: 3},
+	Currency{Code: "MAD", Name: "Moroccan Dirham", MinorUnits: 2},
+	Currency{Code: "MXN", Name: "Mexican Peso", MinorUnits: 2},
+	Currency{Code: "MYR", Name: "Malaysian Ringgit", MinorUnits: 2},
+	Currency{Code: "NGN", Name: "Naira", MinorUnits: 2},
+	Currency{Code: "NOK", Name: "Norwegian Krone", MinorUnits: 2},
+	Currency{Code: "NZD", Name: "New Zealand Dollar", MinorUnits: 2},
+	Currency{Code: "OMR", Name: "Rial Omani", MinorUnits: 3},
+	Currency{Code: "PEN", Name: "Sol", MinorUnits: 2},
+	Currency{Code: "PHP", Name: "Philippine Peso", MinorUnits: 2},
+	Currency{Code: "PKR", Name: "Pakistan Rupee", MinorUnits: 2},
+	Currency{Code: "PLN", Name: "Zloty", MinorUnits: 2},
+	Currency{Code: "QAR", Name: "Qatari Rial", MinorUnits: 2},
+	Currency{Code: "RON", Name: "Romanian Leu", MinorUnits: 2},
+	Currency{Code: "SAR", Name: "Saudi Riyal", MinorUnits: 2},
+	Currency{Code: "SEK", Name: "Swedish Krona", MinorUnits: 2},
+	Currency{Code: "SGD", Name: "Singapore Dollar", MinorUnits: 2},
+	Currency{Code: "THB", Name: "Baht", MinorUnits: 2},
+	Currency{Code: "TND", Name: "Tunisian Dinar", MinorUnits: 3},
+	Currency{Code: "TRY", Name: "Turkish Lira", MinorUnits: 2},
+	Currency{Code: "TWD", Name: "New Taiwan Dollar", MinorUnits: 2},
+	Currency{Code: "UAH", Name: "Hryvnia", MinorUnits: 2},
+	Currency{Code: "USD", Name: "US Dollar", MinorUnits: 2},
+	Currency{Code: "VND", Name: "Dong", MinorUnits: 0},
+	Currency{Code: "ZAR", Name: "Rand", MinorUnits: 2},
+)
+
+func newCurrencyRegistry(currencies ...Currency) CurrencyRegistry {
+	r := make(CurrencyRegistry, len(currencies))
+	for _, c := range currencies {
+		r[c.Code] = c
+	}
+	return r
+}
+
+// Lookup returns the currency registered under code
+func (r CurrencyRegistry) Lookup(code string) (Currency, bool) {
+	c, ok := r[code]
+	return c, ok
+}
+
+// IsValid reports whether code is a registered ISO-4217 currency code
+func (r CurrencyRegistry) IsValid(code string) bool {
+	_, ok := r[code]
+	return ok
+}
+
+// MinorUnits returns the number of decimal places of code, or 2 when it is not registered
+func (r CurrencyRegistry) MinorUnits(code string) int {
+	if c, ok := r[code]; ok {
+		return c.MinorUnits
+	}
+	return defaultMinorUnits
+}
@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestCurrencyRegistry_IsValid(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"USD", true},
+		{"JPY", true},
+		{"BHD", true},
+		{"usd", false},
+		{"XYZ", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := Currencies.IsValid(tt.code); got != tt.want {
+				t.Errorf("IsValid(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyRegistry_MinorUnits(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{"USD", 2},
+		{"EUR", 2},
+		{"JPY", 0},
+		{"KRW", 0},
+		{"BHD", 3},
+		{"KWD", 3},
+		{"XYZ", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := Currencies.MinorUnits(tt.code); got != tt.want {
+				t.Errorf("MinorUnits(%q) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
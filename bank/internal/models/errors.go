@@ -1,12 +1,58 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Domain errors that can be returned by repositories
 var (
 	// ErrDuplicateTransaction indicates a transaction with the same reference_id and type already exists
 	ErrDuplicateTransaction = errors.New("duplicate transaction")
 
+	// ErrDuplicateIdempotencyKey indicates a response is already stored under the idempotency key and path
+	ErrDuplicateIdempotencyKey = errors.New("duplicate idempotency key")
+
 	// ErrNotFound indicates the requested entity was not found
 	ErrNotFound = errors.New("not found")
+
+	// ErrAccountNotFound indicates no account matched the lookup; it also matches ErrNotFound
+	ErrAccountNotFound = fmt.Errorf("account %w", ErrNotFound)
+
+	// ErrBalanceNotFound indicates an account holds no balance in the requested currency; it also matches ErrNotFound
+	ErrBalanceNotFound = fmt.Errorf("balance %w", ErrNotFound)
+
+	// ErrTransactionNotFound indicates no transaction matched the lookup; it also matches ErrNotFound
+	ErrTransactionNotFound = fmt.Errorf("transaction %w", ErrNotFound)
+
+	// ErrInvalidMetadata indicates transaction metadata does not conform to its configured schema
+	ErrInvalidMetadata = errors.New("invalid metadata")
+
+	// ErrMetadataTooLarge indicates serialized transaction metadata exceeds the configured size limit
+	ErrMetadataTooLarge = errors.New("metadata too large")
+
+	// ErrInvalidAmount indicates a transaction amount that is not a positive number of minor units
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrInvalidCurrency indicates a currency that is not a known ISO-4217 code
+	ErrInvalidCurrency = errors.New("invalid currency")
+
+	// ErrInvalidTransactionType indicates a transaction type outside the TransactionType constants
+	ErrInvalidTransactionType = errors.New("invalid transaction type")
+
+	// ErrInvalidTransactionStatus indicates a transaction status outside the TransactionStatus constants
+	ErrInvalidTransactionStatus = errors.New("invalid transaction status")
+
+	// ErrInsufficientFunds indicates an account's available balance cannot cover an amount
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrQueryTimeout indicates a database operation was abandoned because its deadline expired
+	ErrQueryTimeout = errors.New("query timeout")
+
+	// ErrBalanceConflict indicates a balance kept changing between being read and being conditionally
+	// updated, until the update ran out of attempts
+	ErrBalanceConflict = errors.New("balance changed concurrently")
+
+	// ErrCurrencyMismatch indicates arithmetic was attempted between amounts in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
 )
@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+// MetadataKeyFX is the transaction metadata key holding the FX conversion details
+const MetadataKeyFX = "fx"
+
+// FXConversion records how a transaction amount was converted into the account currency
+type FXConversion struct {
+	OriginalCurrency  string `json:"original_currency"`
+	ConvertedCurrency string `json:"converted_currency"`
+	Rate              string `json:"rate"`
+	OriginalAmount    int64  `json:"original_amount"`
+	ConvertedAmount   int64  `json:"converted_amount"`
+}
+
+// FXConversion returns the conversion recorded in the transaction metadata, if any
+func (t *Transaction) FXConversion() (*FXConversion, bool) {
+	raw, ok := t.Metadata[MetadataKeyFX]
+	if !ok || raw == nil {
+		return nil, false
+	}
+
+	// Metadata read back from the database is a generic map, so round-trip through JSON
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var conv FXConversion
+	if err := json.Unmarshal(data, &conv); err != nil || conv.ConvertedCurrency == "" {
+		return nil, false
+	}
+	return &conv, true
+}
+
+// SettlementAmount returns the amount applied to the account balance,
+// which is the converted amount for cross-currency transactions
+func (t *Transaction) SettlementAmount() Money {
+	if conv, ok := t.FXConversion(); ok {
+		return NewMoney(conv.ConvertedAmount, conv.ConvertedCurrency)
+	}
+	return t.Amount()
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_SettlementAmount(t *testing.T) {
+	plain := &Transaction{AmountCents: 1000, Currency: "USD"}
+	assert.Equal(t, NewMoney(1000, "USD"), plain.SettlementAmount())
+
+	// Metadata scanned from JSONB decodes numbers as float64
+	converted := &Transaction{
+		AmountCents: 1000,
+		Currency:    "EUR",
+		Metadata: map[string]any{
+			MetadataKeyFX: map[string]any{
+				"original_amount":    float64(1000),
+				"original_currency":  "EUR",
+				"converted_amount":   float64(1080),
+				"converted_currency": "USD",
+				"rate":               "1.080000",
+			},
+		},
+	}
+	assert.Equal(t, NewMoney(1080, "USD"), converted.SettlementAmount())
+}
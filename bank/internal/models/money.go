@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Money is an amount in minor units of a single currency, e.g. cents for USD or yen for JPY.
+//
+// Arithmetic between values of different currencies is rejected with ErrCurrencyMismatch.
+type Money struct {
+	Currency string
+	Cents    int64
+}
+
+// NewMoney creates a Money value
+func NewMoney(cents int64, currency string) Money {
+	return Money{Cents: cents, Currency: currency}
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: m.Cents + other.Cents, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: m.Cents - other.Cents, Currency: m.Currency}, nil
+}
+
+// Neg returns the amount with its sign flipped
+func (m Money) Neg() Money {
+	return Money{Cents: -m.Cents, Currency: m.Currency}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Cents == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Cents < 0
+}
+
+// String formats the amount in major units using the currency's minor units,
+// e.g. "12.34 USD", "1234 JPY" or "1.234 BHD"
+func (m Money) String() string {
+	sign := ""
+	minor := m.Cents
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	units := Currencies.MinorUnits(m.Currency)
+	if units == 0 {
+		return fmt.Sprintf("%s%d %s", sign, minor, m.Currency)
+	}
+	scale := int64(math.Pow10(units))
+	return fmt.Sprintf("%s%d.%0*d %s", sign, minor/scale, units, minor%scale, m.Currency)
+}
+
+// ParseAmount converts a decimal amount in major units, e.g. "12.34", to minor units of currency
+// without going through floating point. The amount must be unsigned digits with at most the
+// currency's minor units after the point: "0.005" is rejected for USD rather than rounded, while
+// it is 5 fils in BHD.
+func ParseAmount(s, currency string) (int64, error) {
+	c, ok := Currencies.Lookup(currency)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if !isDigits(whole) || (hasPoint && !isDigits(frac)) {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, s)
+	}
+	if len(frac) > c.MinorUnits {
+		return 0, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidAmount, s, c.MinorUnits, currency)
+	}
+
+	// Pad the fraction to the currency's precision so the digits read as one integer of minor units
+	digits := whole + frac + strings.Repeat("0", c.MinorUnits-len(frac))
+	var minor int64
+	for i := 0; i < len(digits); i++ {
+		d := int64(digits[i] - '0')
+		if minor > (math.MaxInt64-d)/10 {
+			return 0, fmt.Errorf("%w: %q is too large", ErrInvalidAmount, s)
+		}
+		minor = minor*10 + d
+	}
+	return minor, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) checkCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
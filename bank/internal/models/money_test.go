@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := NewMoney(1250, "USD")
+	b := NewMoney(300, "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1550, "USD"), sum)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(-950, "USD"), diff)
+	assert.True(t, diff.IsNegative())
+
+	assert.Equal(t, NewMoney(-1250, "USD"), a.Neg())
+	assert.True(t, NewMoney(0, "USD").IsZero())
+}
+
+func TestMoney_CurrencyMismatch(t *testing.T) {
+	usd := NewMoney(100, "USD")
+	eur := NewMoney(100, "EUR")
+
+	_, err := usd.Add(eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = usd.Sub(eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		expected string
+		money    Money
+	}{
+		{"12.34 USD", NewMoney(1234, "USD")},
+		{"0.05 USD", NewMoney(5, "USD")},
+		{"-12.34 EUR", NewMoney(-1234, "EUR")},
+		{"0.00 USD", NewMoney(0, "USD")},
+		{"1234 JPY", NewMoney(1234, "JPY")},
+		{"-5 JPY", NewMoney(-5, "JPY")},
+		{"1.234 BHD", NewMoney(1234, "BHD")},
+		{"0.005 KWD", NewMoney(5, "KWD")},
+		{"12.34 XTS", NewMoney(1234, "XTS")},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.money.String())
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		wantErr  error
+		input    string
+		currency string
+		expected int64
+	}{
+		{input: "12.34", currency: "USD", expected: 1234},
+		{input: "12", currency: "USD", expected: 1200},
+		{input: "12.3", currency: "USD", expected: 1230},
+		{input: "0.01", currency: "USD", expected: 1},
+		{input: "0.29", currency: "USD", expected: 29}, // 0.29 * 100 is 28.999... as a float64
+		{input: "1.15", currency: "USD", expected: 115},
+		{input: "007.50", currency: "EUR", expected: 750},
+		{input: "0.005", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "0.005", currency: "BHD", expected: 5},
+		{input: "12.340", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "1234", currency: "JPY", expected: 1234},
+		{input: "1234.5", currency: "JPY", wantErr: ErrInvalidAmount},
+		{input: "92233720368547758.07", currency: "USD", expected: 9223372036854775807},
+		{input: "92233720368547758.08", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: ".50", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "1.", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "-1.00", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "+1.00", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "1,000.00", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: " 1.00", currency: "USD", wantErr: ErrInvalidAmount},
+		{input: "1.00", currency: "XXX", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+" "+tt.currency, func(t *testing.T) {
+			got, err := ParseAmount(tt.input, tt.currency)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,12 +13,35 @@ type TransactionType string
 
 // Transaction type constants
 const (
-	TransactionTypeAuthHold TransactionType = "AUTH_HOLD" // Authorization hold (funds reserved)
-	TransactionTypeCapture  TransactionType = "CAPTURE"   // Capture authorized funds
-	TransactionTypeVoid     TransactionType = "VOID"      // Void/cancel authorization
-	TransactionTypeRefund   TransactionType = "REFUND"    // Refund captured funds
+	TransactionTypeAuthHold    TransactionType = "AUTH_HOLD"    // Authorization hold (funds reserved)
+	TransactionTypeCapture     TransactionType = "CAPTURE"      // Capture authorized funds
+	TransactionTypeVoid        TransactionType = "VOID"         // Void/cancel authorization
+	TransactionTypePartialVoid TransactionType = "PARTIAL_VOID" // Reduction of an authorization that stays active
+	TransactionTypeRefund      TransactionType = "REFUND"       // Refund captured funds
+	TransactionTypeChargeback  TransactionType = "CHARGEBACK"   // Disputed capture reversed by the cardholder's bank
+	TransactionTypeTransferOut TransactionType = "TRANSFER_OUT" // Funds sent to another account
+	TransactionTypeTransferIn  TransactionType = "TRANSFER_IN"  // Funds received from another account
+	TransactionTypeFunding     TransactionType = "FUNDING"      // Balance an account was opened with
 )
 
+// TransactionTypes lists every TransactionType constant
+var TransactionTypes = []TransactionType{
+	TransactionTypeAuthHold,
+	TransactionTypeCapture,
+	TransactionTypeVoid,
+	TransactionTypePartialVoid,
+	TransactionTypeRefund,
+	TransactionTypeChargeback,
+	TransactionTypeTransferOut,
+	TransactionTypeTransferIn,
+	TransactionTypeFunding,
+}
+
+// IsValid reports whether t is one of the TransactionType constants
+func (t TransactionType) IsValid() bool {
+	return slices.Contains(TransactionTypes, t)
+}
+
 // TransactionStatus represents the status of a transaction
 type TransactionStatus string
 
@@ -25,27 +50,131 @@ const (
 	TransactionStatusActive    TransactionStatus = "ACTIVE"    // Transaction is active (auth holds)
 	TransactionStatusCompleted TransactionStatus = "COMPLETED" // Transaction completed successfully
 	TransactionStatusExpired   TransactionStatus = "EXPIRED"   // Transaction expired (auth timeout)
+	TransactionStatusVoided    TransactionStatus = "VOIDED"    // Authorization cancelled by a void
+	TransactionStatusDeclined  TransactionStatus = "DECLINED"  // Authorization refused; see DeclineReason
+)
+
+// IsValid reports whether s is one of the TransactionStatus constants
+func (s TransactionStatus) IsValid() bool {
+	switch s {
+	case TransactionStatusActive, TransactionStatusCompleted, TransactionStatusExpired, TransactionStatusVoided,
+		TransactionStatusDeclined:
+		return true
+	default:
+		return false
+	}
+}
+
+// DeclineReason explains why a DECLINED transaction was refused
+type DeclineReason string
+
+// Decline reasons recorded for refused authorizations
+const (
+	DeclineReasonInsufficientFunds DeclineReason = "insufficient_funds"
+	DeclineReasonCVVMismatch       DeclineReason = "cvv_mismatch"
+	DeclineReasonExpiredCard       DeclineReason = "expired_card"
+	DeclineReasonVelocityExceeded  DeclineReason = "velocity_exceeded"
 )
 
 // Transaction represents a ledger entry for account activity
 type Transaction struct {
-	CreatedAt   time.Time         `db:"created_at"`
-	Metadata    map[string]any    `db:"metadata"`
-	ReferenceID *uuid.UUID        `db:"reference_id"`
-	ExpiresAt   *time.Time        `db:"expires_at"`
-	Currency    string            `db:"currency"`
-	Type        TransactionType   `db:"type"`
-	Status      TransactionStatus `db:"status"`
-	AmountCents int64             `db:"amount_cents"`
-	ID          uuid.UUID         `db:"id"`
-	AccountID   uuid.UUID         `db:"account_id"`
+	CreatedAt     time.Time         `db:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at"`
+	Metadata      map[string]any    `db:"metadata"`
+	ReferenceID   *uuid.UUID        `db:"reference_id"`
+	ExpiresAt     *time.Time        `db:"expires_at"`
+	ArchivedAt    *time.Time        `db:"archived_at"` // Set only on transactions read from the archive
+	Currency      string            `db:"currency"`
+	Type          TransactionType   `db:"type"`
+	Status        TransactionStatus `db:"status"`
+	DeclineReason DeclineReason     `db:"decline_reason"` // Set only on DECLINED transactions
+	AmountCents   int64             `db:"amount_cents"`
+	ID            uuid.UUID         `db:"id"`
+	AccountID     uuid.UUID         `db:"account_id"`
+}
+
+// Validate checks the fields every ledger entry needs: a positive amount, a known ISO-4217 currency,
+// a known type and status, and a decline reason exactly when the status is DECLINED. Errors wrap
+// ErrInvalidAmount, ErrInvalidCurrency, ErrInvalidTransactionType or ErrInvalidTransactionStatus.
+func (t *Transaction) Validate() error {
+	if t.AmountCents <= 0 {
+		return fmt.Errorf("%w: %d must be greater than 0", ErrInvalidAmount, t.AmountCents)
+	}
+	if !Currencies.IsValid(t.Currency) {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, t.Currency)
+	}
+	if !t.Type.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidTransactionType, t.Type)
+	}
+	if !t.Status.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidTransactionStatus, t.Status)
+	}
+	if (t.Status == TransactionStatusDeclined) != (t.DeclineReason != "") {
+		return fmt.Errorf("%w: decline reason %q on a %s transaction", ErrInvalidTransactionStatus, t.DeclineReason, t.Status)
+	}
+	return nil
+}
+
+// Amount returns the transaction amount as Money
+func (t *Transaction) Amount() Money {
+	return NewMoney(t.AmountCents, t.Currency)
+}
+
+// TransactionTotals counts the transactions of one type and status and sums their amounts
+type TransactionTotals struct {
+	Type        TransactionType
+	Status      TransactionStatus
+	Count       int64
+	AmountCents int64 // In the account currency
+}
+
+// LedgerStats counts accounts and live transactions at a point in time
+type LedgerStats struct {
+	ComputedAt   time.Time
+	Transactions []TransactionCount // One entry per type and status present, ordered by type then status
+	Accounts     int
+}
+
+// TransactionCount is the number of live transactions of one type and status
+type TransactionCount struct {
+	Type   TransactionType
+	Status TransactionStatus
+	Count  int
+}
+
+// TransactionSummary totals an account's transactions created within a period
+type TransactionSummary struct {
+	From      time.Time           // Zero when the period has no lower bound
+	To        time.Time           // Zero when the period has no upper bound
+	Totals    []TransactionTotals // One entry per type and status present, ordered by type then status
+	AccountID uuid.UUID
+}
+
+// ByType adds up the totals of one transaction type across all statuses
+func (s *TransactionSummary) ByType(t TransactionType) TransactionTotals {
+	totals := TransactionTotals{Type: t}
+	for _, entry := range s.Totals {
+		if entry.Type == t {
+			totals.Count += entry.Count
+			totals.AmountCents += entry.AmountCents
+		}
+	}
+	return totals
+}
+
+// ExportedTransaction is a transaction as written to reconciliation exports, with the masked card
+// number of its account
+type ExportedTransaction struct {
+	MaskedAccountNumber string
+	Transaction
 }
 
 // IdempotencyKey tracks processed requests to prevent duplicate transactions
 type IdempotencyKey struct {
-	CreatedAt      time.Time `db:"created_at"`
-	Key            string    `db:"key"`
-	RequestPath    string    `db:"request_path"`
-	ResponseBody   string    `db:"response_body"`
-	ResponseStatus int       `db:"response_status"`
+	CreatedAt      time.Time  `db:"created_at"`
+	TransactionID  *uuid.UUID `db:"transaction_id"` // Transaction the request created; nil for failed requests
+	Key            string     `db:"key"`
+	RequestPath    string     `db:"request_path"`
+	ResponseBody   string     `db:"response_body"`
+	ResponseStatus int        `db:"response_status"`
 }
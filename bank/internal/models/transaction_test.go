@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_Validate(t *testing.T) {
+	valid := func() *Transaction {
+		return &Transaction{
+			Type:        TransactionTypeCapture,
+			Status:      TransactionStatusCompleted,
+			AmountCents: 1,
+			Currency:    "USD",
+		}
+	}
+
+	assert.NoError(t, valid().Validate())
+
+	tests := []struct {
+		mutate  func(*Transaction)
+		wantErr error
+		name    string
+	}{
+		{name: "zero amount", mutate: func(tx *Transaction) { tx.AmountCents = 0 }, wantErr: ErrInvalidAmount},
+		{name: "negative amount", mutate: func(tx *Transaction) { tx.AmountCents = -100 }, wantErr: ErrInvalidAmount},
+		{name: "empty currency", mutate: func(tx *Transaction) { tx.Currency = "" }, wantErr: ErrInvalidCurrency},
+		{name: "lowercase currency", mutate: func(tx *Transaction) { tx.Currency = "usd" }, wantErr: ErrInvalidCurrency},
+		{name: "unknown currency", mutate: func(tx *Transaction) { tx.Currency = "ABC" }, wantErr: ErrInvalidCurrency},
+		{name: "unknown type", mutate: func(tx *Transaction) { tx.Type = "TRANSFER" }, wantErr: ErrInvalidTransactionType},
+		{name: "empty status", mutate: func(tx *Transaction) { tx.Status = "" }, wantErr: ErrInvalidTransactionStatus},
+		{
+			name:    "declined without reason",
+			mutate:  func(tx *Transaction) { tx.Status = TransactionStatusDeclined },
+			wantErr: ErrInvalidTransactionStatus,
+		},
+		{
+			name:    "reason without decline",
+			mutate:  func(tx *Transaction) { tx.DeclineReason = DeclineReasonCVVMismatch },
+			wantErr: ErrInvalidTransactionStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := valid()
+			tt.mutate(tx)
+			assert.ErrorIs(t, tx.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestTransactionSummary_ByType(t *testing.T) {
+	summary := TransactionSummary{Totals: []TransactionTotals{
+		{Type: TransactionTypeAuthHold, Status: TransactionStatusActive, Count: 1, AmountCents: 2000},
+		{Type: TransactionTypeAuthHold, Status: TransactionStatusCompleted, Count: 2, AmountCents: 7000},
+		{Type: TransactionTypeCapture, Status: TransactionStatusCompleted, Count: 1, AmountCents: 3000},
+	}}
+
+	assert.Equal(t, TransactionTotals{Type: TransactionTypeAuthHold, Count: 3, AmountCents: 9000}, summary.ByType(TransactionTypeAuthHold))
+	assert.Equal(t, TransactionTotals{Type: TransactionTypeRefund}, summary.ByType(TransactionTypeRefund))
+}
@@ -4,109 +4,381 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/benx421/payment-gateway/bank/internal/cvvcrypt"
 	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
 	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/google/uuid"
 )
 
 // AccountRepository defines the interface for account data access
 type AccountRepository interface {
+	Create(ctx context.Context, account *models.Account) error
+	CreateWithFunding(ctx context.Context, account *models.Account) (*models.Transaction, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Account, error)
 	FindByAccountNumber(ctx context.Context, accountNumber string) (*models.Account, error)
 	FindByAccountNumberForUpdate(ctx context.Context, accountNumber string) (*models.Account, error)
-	AdjustBalances(ctx context.Context, accountID uuid.UUID, balanceDelta, availableBalanceDelta int64) error
+	ListLowBalance(ctx context.Context, thresholdCents int64, limit, offset int) ([]*models.Account, error)
+	AdjustBalances(ctx context.Context, accountID uuid.UUID, balanceDelta, availableBalanceDelta models.Money, reason string) (balance, available models.Money, err error)
+	AdjustBalancesIfAvailable(ctx context.Context, accountID uuid.UUID, expectedAvailable, balanceDelta, availableBalanceDelta models.Money, reason string) (balance, available models.Money, updated bool, err error)
+	AdjustBalancesBatch(ctx context.Context, adjustments []BalanceAdjustment) error
+	OpenBalance(ctx context.Context, accountID uuid.UUID, currency string) error
+	FindBalances(ctx context.Context, accountID uuid.UUID) ([]*models.Balance, error)
+	FindBalanceBreakdown(ctx context.Context, accountID uuid.UUID) (*models.BalanceBreakdown, error)
+	FindBalanceDrift(ctx context.Context, accountID uuid.UUID) (*models.BalanceDrift, error)
+	FindBalanceDrifts(ctx context.Context) ([]*models.BalanceDrift, error)
+}
+
+// BalanceAdjustment is one account's deltas in an AdjustBalancesBatch call
+type BalanceAdjustment struct {
+	Reason                string // Recorded in the balance audit trail
+	BalanceDelta          models.Money
+	AvailableBalanceDelta models.Money
+	AccountID             uuid.UUID
+}
+
+// MissingAccountsError reports the accounts an AdjustBalancesBatch call referenced that do not exist,
+// or that hold no balance in the adjusted currency. It matches models.ErrAccountNotFound
+type MissingAccountsError struct {
+	AccountIDs []uuid.UUID
+}
+
+func (e *MissingAccountsError) Error() string {
+	ids := make([]string, len(e.AccountIDs))
+	for i, id := range e.AccountIDs {
+		ids[i] = id.String()
+	}
+	return fmt.Sprintf("%s: %s", models.ErrAccountNotFound, strings.Join(ids, ", "))
+}
+
+func (e *MissingAccountsError) Unwrap() error {
+	return models.ErrAccountNotFound
+}
+
+// maxBatchAdjustments keeps a batch within PostgreSQL's limit of 65535 bind parameters:
+// five per adjustment plus the actor and request ID
+const maxBatchAdjustments = (65535 - 2) / 5
+
+// anonymousActor is audited for balance changes made outside an authenticated request
+const anonymousActor = "anonymous"
+
+// openingBalanceReason is audited for the funds an account is created with
+const openingBalanceReason = "opening balance"
+
+// auditActor returns the caller recorded in the balance audit trail for ctx
+func auditActor(ctx context.Context) string {
+	if actor := logging.ActorFromContext(ctx); actor != "" {
+		return actor
+	}
+	return anonymousActor
 }
 
 // accountRepository implements AccountRepository
 type accountRepository struct {
-	exec db.Executor
+	exec      db.Executor
+	sql       *accountStatements
+	cvvCipher *cvvcrypt.Cipher
+}
+
+// accountStatements are the accountRepository queries with a table prefix applied
+type accountStatements struct {
+	create                       string
+	findByID                     string
+	findByAccountNumber          string
+	findByAccountNumberForUpdate string
+	listLowBalance               string
+	adjustBalances               string
+	adjustBalancesIfAvailable    string
+	balanceExists                string
+	accountExists                string
+	adjustBalancesBatch          string
+	openBalance                  string
+	findBalances                 string
+	findBalanceBreakdown         string
+	findBalanceDrift             string
+	findBalanceDrifts            string
+}
+
+// newAccountStatements builds the accountRepository queries for tables named with prefix
+func newAccountStatements(prefix string) accountStatements {
+	return accountStatements{
+		create:                       db.PrefixTables(createAccountQuery, prefix),
+		findByID:                     db.PrefixTables(findAccountByIDQuery, prefix),
+		findByAccountNumber:          db.PrefixTables(findAccountByNumberQuery, prefix),
+		findByAccountNumberForUpdate: db.PrefixTables(findAccountByNumberForUpdateQuery, prefix),
+		listLowBalance:               db.PrefixTables(listLowBalanceQuery, prefix),
+		adjustBalances:               db.PrefixTables(adjustBalancesQuery, prefix),
+		adjustBalancesIfAvailable:    db.PrefixTables(adjustBalancesIfAvailableQuery, prefix),
+		balanceExists:                db.PrefixTables(balanceExistsQuery, prefix),
+		accountExists:                db.PrefixTables(accountExistsQuery, prefix),
+		adjustBalancesBatch:          db.PrefixTables(adjustBalancesBatchQuery, prefix),
+		openBalance:                  db.PrefixTables(openBalanceQuery, prefix),
+		findBalances:                 db.PrefixTables(findBalancesQuery, prefix),
+		findBalanceBreakdown:         db.PrefixTables(findBalanceBreakdownQuery, prefix),
+		findBalanceDrift:             db.PrefixTables(findBalanceDriftQuery, prefix),
+		findBalanceDrifts:            db.PrefixTables(findBalanceDriftsQuery, prefix),
+	}
+}
+
+// AccountOption configures optional AccountRepository behaviour
+type AccountOption func(*accountRepository)
+
+// WithCVVCipher makes Create store CVVs encrypted with cvvCipher
+func WithCVVCipher(cvvCipher *cvvcrypt.Cipher) AccountOption {
+	return func(r *accountRepository) {
+		r.cvvCipher = cvvCipher
+	}
+}
+
+// WithAccountTables makes the repository query the tables of tables instead of the unprefixed ones
+func WithAccountTables(tables *Tables) AccountOption {
+	return func(r *accountRepository) {
+		if tables != nil {
+			r.sql = &tables.account
+		}
+	}
 }
 
 // NewAccountRepository creates a new AccountRepository
 // The exec parameter can be either *db.DB or *db.Tx, allowing the repository
 // to work with or without transactions
-func NewAccountRepository(exec db.Executor) AccountRepository {
-	return &accountRepository{exec: exec}
+func NewAccountRepository(exec db.Executor, opts ...AccountOption) AccountRepository {
+	r := &accountRepository{exec: exec, sql: &defaultTables.account}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return &tracedAccountRepository{next: r}
+}
+
+// Create inserts a new account into the database
+// When a CVV cipher is configured the CVV is stored encrypted; account.CVV is left as supplied.
+// A non-zero opening balance is recorded in balance_audit so the trail sums to the stored balance.
+func (r *accountRepository) Create(ctx context.Context, account *models.Account) error {
+	return r.create(ctx, account, nil)
+}
+
+// CreateWithFunding inserts a new account like Create and records its opening balance as a completed
+// FUNDING transaction in the same statement, so the ledger is complete from the account's inception.
+// The opening balance must be positive; the funding transaction is returned.
+func (r *accountRepository) CreateWithFunding(ctx context.Context, account *models.Account) (*models.Transaction, error) {
+	funding := &models.Transaction{
+		ID:          uuid.New(),
+		Type:        models.TransactionTypeFunding,
+		Status:      models.TransactionStatusCompleted,
+		AmountCents: account.BalanceCents,
+		Currency:    account.Currency,
+	}
+	if err := funding.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.create(ctx, account, funding); err != nil {
+		return nil, err
+	}
+
+	funding.AccountID = account.ID
+	funding.CreatedAt = account.CreatedAt
+	funding.UpdatedAt = account.UpdatedAt
+	return funding, nil
+}
+
+// The account and its primary currency balance are inserted by one statement, so neither exists without the other
+const createAccountQuery = `
+	WITH account AS (
+		INSERT INTO accounts (id, account_number, cvv, expiry_month, expiry_year, currency)
+		VALUES ($1, $2, $3, $4, $5, $8)
+		RETURNING id, currency, created_at, updated_at
+	),
+	balance AS (
+		INSERT INTO balances (account_id, currency, balance_cents, available_balance_cents, created_at, updated_at)
+		SELECT id, currency, $6, $7, created_at, updated_at
+		FROM account
+		RETURNING account_id, currency, balance_cents, available_balance_cents
+	),
+	audited AS (
+		INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents,
+		                           actor, reason, request_id)
+		SELECT account_id, currency, balance_cents, available_balance_cents, $9, $10, NULLIF($11, '')
+		FROM balance
+		WHERE balance_cents <> 0 OR available_balance_cents <> 0
+	),
+	funding AS (
+		INSERT INTO transactions (id, account_id, type, amount_cents, currency, status, created_at, updated_at)
+		SELECT $12::uuid, id, $13, $6, currency, $14, created_at, updated_at
+		FROM account
+		WHERE $12::uuid IS NOT NULL
+	)
+	SELECT created_at, updated_at FROM account
+`
+
+// create inserts account, its balance and its opening audit row, plus funding when it is not nil
+func (r *accountRepository) create(ctx context.Context, account *models.Account, funding *models.Transaction) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+
+	var fundingID *uuid.UUID
+	if funding != nil {
+		fundingID = &funding.ID
+	}
+
+	storedCVV := account.CVV
+	if r.cvvCipher != nil {
+		encrypted, err := r.cvvCipher.EncryptCVV(account.CVV)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt cvv: %w", err)
+		}
+		storedCVV = encrypted
+	}
+
+	err := r.exec.QueryRowContext(ctx, r.sql.create,
+		account.ID,
+		account.AccountNumber,
+		storedCVV,
+		account.ExpiryMonth,
+		account.ExpiryYear,
+		account.BalanceCents,
+		account.AvailableBalanceCents,
+		account.Currency,
+		auditActor(ctx),
+		openingBalanceReason,
+		logging.RequestIDFromContext(ctx),
+		fundingID,
+		models.TransactionTypeFunding,
+		models.TransactionStatusCompleted,
+	).Scan(&account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create account: %w", queryError(ctx, err))
+	}
+
+	return nil
 }
 
+const findAccountByIDQuery = `
+	SELECT ` + accountColumns + `
+	FROM accounts_with_balance
+	WHERE id = $1
+`
+
 // FindByID retrieves an account by its UUID
 func (r *accountRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Account, error) {
-	query := `
-		SELECT id, account_number, cvv, expiry_month, expiry_year,
-		       balance_cents, available_balance_cents, created_at, updated_at
-		FROM accounts
-		WHERE id = $1
-	`
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	var account models.Account
-	err := r.exec.QueryRowContext(ctx, query, id).Scan(
-		&account.ID,
-		&account.AccountNumber,
-		&account.CVV,
-		&account.ExpiryMonth,
-		&account.ExpiryYear,
-		&account.BalanceCents,
-		&account.AvailableBalanceCents,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
+	account, err := scanAccount(r.exec.QueryRowContext(ctx, r.sql.findByID, id))
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("account not found: %w", err)
+		return nil, fmt.Errorf("failed to find account by id: %w", models.ErrAccountNotFound)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find account by id: %w", err)
+		return nil, fmt.Errorf("failed to find account by id: %w", queryError(ctx, err))
 	}
 
-	return &account, nil
+	return account, nil
 }
 
+const findAccountByNumberQuery = `
+	SELECT ` + accountColumns + `
+	FROM accounts_with_balance
+	WHERE account_number = $1
+`
+
 // FindByAccountNumber retrieves an account by its account number (card number)
 func (r *accountRepository) FindByAccountNumber(ctx context.Context, accountNumber string) (*models.Account, error) {
-	query := `
-		SELECT id, account_number, cvv, expiry_month, expiry_year,
-		       balance_cents, available_balance_cents, created_at, updated_at
-		FROM accounts
-		WHERE account_number = $1
-	`
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	var account models.Account
-	err := r.exec.QueryRowContext(ctx, query, accountNumber).Scan(
-		&account.ID,
-		&account.AccountNumber,
-		&account.CVV,
-		&account.ExpiryMonth,
-		&account.ExpiryYear,
-		&account.BalanceCents,
-		&account.AvailableBalanceCents,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
+	account, err := scanAccount(r.exec.QueryRowContext(ctx, r.sql.findByAccountNumber, accountNumber))
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("account not found: %w", err)
+		return nil, fmt.Errorf("failed to find account by account number: %w", models.ErrAccountNotFound)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find account by account number: %w", err)
+		return nil, fmt.Errorf("failed to find account by account number: %w", queryError(ctx, err))
 	}
 
-	return &account, nil
+	return account, nil
 }
 
+const findAccountByNumberForUpdateQuery = `
+	SELECT ` + accountColumns + `
+	FROM accounts_with_balance
+	WHERE account_number = $1
+	FOR UPDATE
+`
+
 // FindByAccountNumberForUpdate retrieves an account by its account number with row-level lock
+// Both the account row and its primary currency balance row are locked.
 func (r *accountRepository) FindByAccountNumberForUpdate(ctx context.Context, accountNumber string) (*models.Account, error) {
-	query := `
-		SELECT id, account_number, cvv, expiry_month, expiry_year,
-		       balance_cents, available_balance_cents, created_at, updated_at
-		FROM accounts
-		WHERE account_number = $1
-		FOR UPDATE
-	`
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	account, err := scanAccount(r.exec.QueryRowContext(ctx, r.sql.findByAccountNumberForUpdate, accountNumber))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to find and lock account: %w", models.ErrAccountNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find and lock account: %w", queryError(ctx, err))
+	}
+
+	return account, nil
+}
+
+// accountColumns are the accounts_with_balance columns scanAccount reads
+const accountColumns = `id, account_number, cvv, expiry_month, expiry_year,
+		       balance_cents, available_balance_cents, currency, created_at, updated_at`
+
+const listLowBalanceQuery = `
+	SELECT ` + accountColumns + `
+	FROM accounts_with_balance
+	WHERE available_balance_cents < $1
+	ORDER BY available_balance_cents, id
+	LIMIT $2 OFFSET $3
+`
+
+// ListLowBalance returns a page of accounts whose available balance in their primary currency is
+// below thresholdCents, lowest first
+func (r *accountRepository) ListLowBalance(ctx context.Context, thresholdCents int64, limit, offset int) ([]*models.Account, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if limit <= 0 || offset < 0 {
+		return nil, fmt.Errorf("invalid pagination: limit must be positive and offset non-negative")
+	}
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.listLowBalance, thresholdCents, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list low balance accounts: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	accounts := []*models.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", queryError(ctx, err))
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list low balance accounts: %w", queryError(ctx, err))
+	}
 
+	return accounts, nil
+}
+
+// scanAccount reads accountColumns from a *sql.Row or the current row of a *sql.Rows
+func scanAccount(row interface{ Scan(dest ...any) error }) (*models.Account, error) {
 	var account models.Account
-	err := r.exec.QueryRowContext(ctx, query, accountNumber).Scan(
+	if err := row.Scan(
 		&account.ID,
 		&account.AccountNumber,
 		&account.CVV,
@@ -114,42 +386,573 @@ func (r *accountRepository) FindByAccountNumberForUpdate(ctx context.Context, ac
 		&account.ExpiryYear,
 		&account.BalanceCents,
 		&account.AvailableBalanceCents,
+		&account.Currency,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+const adjustBalancesQuery = `
+	WITH updated AS (
+		UPDATE balances
+		SET balance_cents = balance_cents + $3,
+		    available_balance_cents = available_balance_cents + $4,
+		    updated_at = NOW()
+		WHERE account_id = $1 AND currency = $2
+		RETURNING balance_cents, available_balance_cents
+	),
+	audited AS (
+		INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents,
+		                           actor, reason, request_id)
+		SELECT $1, $2, $3, $4, $5, $6, NULLIF($7, '')
+		FROM updated
 	)
+	SELECT balance_cents, available_balance_cents FROM updated
+`
+
+// AdjustBalances atomically adjusts the account's balance and available balance in the deltas'
+// currency and returns the updated balances, read from the same statement that changed them.
+// Both deltas must be in the same currency. If the account holds no balance in that currency the
+// error matches models.ErrBalanceNotFound; see OpenBalance.
+// The same statement appends the change to balance_audit with reason and the actor and request ID
+// carried in ctx.
+func (r *accountRepository) AdjustBalances(
+	ctx context.Context,
+	accountID uuid.UUID,
+	balanceDelta, availableBalanceDelta models.Money,
+	reason string,
+) (balance, available models.Money, err error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if balanceDelta.Currency != availableBalanceDelta.Currency {
+		return models.Money{}, models.Money{}, fmt.Errorf("failed to adjust account balances: %w", models.ErrCurrencyMismatch)
+	}
+	currency := balanceDelta.Currency
 
+	var balanceCents, availableCents int64
+	err = r.exec.QueryRowContext(ctx, r.sql.adjustBalances, accountID, currency, balanceDelta.Cents, availableBalanceDelta.Cents,
+		auditActor(ctx), reason, logging.RequestIDFromContext(ctx)).
+		Scan(&balanceCents, &availableCents)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("account not found: %w", err)
+		return models.Money{}, models.Money{}, fmt.Errorf("failed to adjust account balances: %w",
+			r.missingBalance(ctx, accountID, currency))
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find and lock account: %w", err)
+		return models.Money{}, models.Money{}, fmt.Errorf("failed to adjust account balances: %w", queryError(ctx, err))
 	}
 
-	return &account, nil
+	return models.NewMoney(balanceCents, currency), models.NewMoney(availableCents, currency), nil
+}
+
+const adjustBalancesIfAvailableQuery = `
+	WITH updated AS (
+		UPDATE balances
+		SET balance_cents = balance_cents + $3,
+		    available_balance_cents = available_balance_cents + $4,
+		    updated_at = NOW()
+		WHERE account_id = $1 AND currency = $2 AND available_balance_cents = $8
+		RETURNING balance_cents, available_balance_cents
+	),
+	audited AS (
+		INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents,
+		                           actor, reason, request_id, outside_ledger)
+		SELECT $1, $2, $3, $4, $5, $6, NULLIF($7, ''), TRUE
+		FROM updated
+	)
+	SELECT balance_cents, available_balance_cents FROM updated
+`
+
+const balanceExistsQuery = `SELECT EXISTS (SELECT 1 FROM balances WHERE account_id = $1 AND currency = $2)`
+
+// AdjustBalancesIfAvailable is AdjustBalances conditioned on the available balance still being
+// expectedAvailable. When another writer has changed it since it was read, nothing is changed and
+// updated is false; see UpdateBalanceOptimistically.
+// No transaction records the change, so it is audited as made outside the ledger, and reconciliation
+// counts it as part of the ledger rather than as drift.
+func (r *accountRepository) AdjustBalancesIfAvailable(
+	ctx context.Context,
+	accountID uuid.UUID,
+	expectedAvailable, balanceDelta, availableBalanceDelta models.Money,
+	reason string,
+) (balance, available models.Money, updated bool, err error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if balanceDelta.Currency != availableBalanceDelta.Currency || expectedAvailable.Currency != balanceDelta.Currency {
+		return models.Money{}, models.Money{}, false, fmt.Errorf("failed to adjust account balances: %w", models.ErrCurrencyMismatch)
+	}
+	currency := balanceDelta.Currency
+
+	var balanceCents, availableCents int64
+	err = r.exec.QueryRowContext(ctx, r.sql.adjustBalancesIfAvailable, accountID, currency, balanceDelta.Cents, availableBalanceDelta.Cents,
+		auditActor(ctx), reason, logging.RequestIDFromContext(ctx), expectedAvailable.Cents).
+		Scan(&balanceCents, &availableCents)
+	if err == sql.ErrNoRows {
+		// Either the balance moved on, which the caller retries, or there is none to update
+		var exists bool
+		err = r.exec.QueryRowContext(ctx, r.sql.balanceExists, accountID, currency).Scan(&exists)
+		if err != nil {
+			return models.Money{}, models.Money{}, false, fmt.Errorf("failed to adjust account balances: %w", queryError(ctx, err))
+		}
+		if exists {
+			return models.Money{}, models.Money{}, false, nil
+		}
+		return models.Money{}, models.Money{}, false, fmt.Errorf("failed to adjust account balances: %w",
+			r.missingBalance(ctx, accountID, currency))
+	}
+	if err != nil {
+		return models.Money{}, models.Money{}, false, fmt.Errorf("failed to adjust account balances: %w", queryError(ctx, err))
+	}
+
+	return models.NewMoney(balanceCents, currency), models.NewMoney(availableCents, currency), true, nil
+}
+
+// DefaultBalanceUpdateAttempts is how many times UpdateBalanceOptimistically tries a balance
+// update before giving up on a contended balance
+const DefaultBalanceUpdateAttempts = 5
+
+// BalanceUpdate computes the deltas to apply to an account's current balance in one currency.
+// It may be called once per attempt and should not have side effects.
+type BalanceUpdate func(current *models.Balance) (balanceDelta, availableBalanceDelta models.Money, err error)
+
+// UpdateBalanceOptimistically reads the account's balance in currency, computes deltas from it with
+// update, and applies them only if the available balance has not changed in between, retrying with
+// a fresh read when it has. Unlike locking the balance with SELECT ... FOR UPDATE, readers and
+// other writers are never blocked, which suits balances updated by many concurrent requests.
+// The change is an adjustment outside the transaction ledger; see AdjustBalancesIfAvailable.
+// After attempts lost races it gives up with models.ErrBalanceConflict. Errors from update are
+// returned unchanged.
+func UpdateBalanceOptimistically(
+	ctx context.Context,
+	repo AccountRepository,
+	accountID uuid.UUID,
+	currency string,
+	attempts int,
+	reason string,
+	update BalanceUpdate,
+) (*models.Balance, error) {
+	for range max(attempts, 1) {
+		current, err := findBalance(ctx, repo, accountID, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		balanceDelta, availableDelta, err := update(current)
+		if err != nil {
+			return nil, err
+		}
+
+		balance, available, updated, err := repo.AdjustBalancesIfAvailable(ctx, accountID,
+			models.NewMoney(current.AvailableBalanceCents, currency), balanceDelta, availableDelta, reason)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			current.BalanceCents = balance.Cents
+			current.AvailableBalanceCents = available.Cents
+			return current, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to update balance after %d attempts: %w", max(attempts, 1), models.ErrBalanceConflict)
+}
+
+// findBalance returns the account's balance in currency
+func findBalance(ctx context.Context, repo AccountRepository, accountID uuid.UUID, currency string) (*models.Balance, error) {
+	balances, err := repo.FindBalances(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range balances {
+		if b.Currency == currency {
+			return b, nil
+		}
+	}
+	if len(balances) == 0 {
+		return nil, models.ErrAccountNotFound
+	}
+	return nil, fmt.Errorf("%w: account holds no %s", models.ErrBalanceNotFound, currency)
+}
+
+const accountExistsQuery = `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1)`
+
+// missingBalance explains why no balance row matched: either the account does not exist,
+// or it holds nothing in currency
+func (r *accountRepository) missingBalance(ctx context.Context, accountID uuid.UUID, currency string) error {
+	var exists bool
+	err := r.exec.QueryRowContext(ctx, r.sql.accountExists, accountID).Scan(&exists)
+	if err != nil {
+		return queryError(ctx, err)
+	}
+	if !exists {
+		return models.ErrAccountNotFound
+	}
+	return fmt.Errorf("%w: account holds no %s", models.ErrBalanceNotFound, currency)
 }
 
-// AdjustBalances atomically adjusts the balance and available balance by the given deltas
-func (r *accountRepository) AdjustBalances(ctx context.Context, accountID uuid.UUID, balanceDelta, availableBalanceDelta int64) error {
-	query := `
-		UPDATE accounts
-		SET balance_cents = balance_cents + $2,
-		    available_balance_cents = available_balance_cents + $3,
+// adjustBalancesBatchQuery takes the VALUES rows of the adjustments in place of %s.
+// The update and audit only run when every balance exists, so a partial batch is never applied.
+const adjustBalancesBatchQuery = `
+	WITH input (id, currency, balance_delta, available_delta, reason) AS (
+		VALUES %s
+	),
+	deltas AS (
+		SELECT id, currency, SUM(balance_delta) AS balance_delta, SUM(available_delta) AS available_delta
+		FROM input
+		GROUP BY id, currency
+	),
+	missing AS (
+		SELECT DISTINCT d.id
+		FROM deltas d
+		LEFT JOIN balances b ON b.account_id = d.id AND b.currency = d.currency
+		WHERE b.account_id IS NULL
+	),
+	updated AS (
+		UPDATE balances b
+		SET balance_cents = b.balance_cents + d.balance_delta,
+		    available_balance_cents = b.available_balance_cents + d.available_delta,
 		    updated_at = NOW()
-		WHERE id = $1
-	`
+		FROM deltas d
+		WHERE b.account_id = d.id AND b.currency = d.currency
+		  AND NOT EXISTS (SELECT 1 FROM missing)
+		RETURNING b.account_id
+	),
+	audited AS (
+		INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents,
+		                           actor, reason, request_id, outside_ledger)
+		SELECT id, currency, balance_delta, available_delta, $1, reason, NULLIF($2, ''), TRUE
+		FROM input
+		WHERE NOT EXISTS (SELECT 1 FROM missing)
+	)
+	SELECT id FROM missing ORDER BY id
+`
+
+// AdjustBalancesBatch applies every adjustment in a single UPDATE, or none of them
+// Each adjustment applies to the account's balance in its deltas' currency. If any account is
+// missing, or holds no balance in that currency, nothing is changed and a *MissingAccountsError
+// lists the offending IDs. Adjustments to the same balance are summed; each is audited separately.
+// No transaction records them, so like AdjustBalancesIfAvailable they are audited as made outside the
+// ledger, which reconciliation counts rather than reporting as drift.
+func (r *accountRepository) AdjustBalancesBatch(ctx context.Context, adjustments []BalanceAdjustment) error {
+	if len(adjustments) == 0 {
+		return nil
+	}
+	if len(adjustments) > maxBatchAdjustments {
+		return fmt.Errorf("failed to adjust account balances: batch of %d exceeds the limit of %d", len(adjustments), maxBatchAdjustments)
+	}
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	values := make([]string, len(adjustments))
+	args := []any{auditActor(ctx), logging.RequestIDFromContext(ctx)}
+	for i, adj := range adjustments {
+		if adj.BalanceDelta.Currency != adj.AvailableBalanceDelta.Currency {
+			return fmt.Errorf("failed to adjust account balances for %s: %w", adj.AccountID, models.ErrCurrencyMismatch)
+		}
+		n := 2 + i*5
+		values[i] = fmt.Sprintf("($%d::uuid, $%d::varchar, $%d::bigint, $%d::bigint, $%d::text)", n+1, n+2, n+3, n+4, n+5)
+		args = append(args, adj.AccountID, adj.BalanceDelta.Currency, adj.BalanceDelta.Cents, adj.AvailableBalanceDelta.Cents, adj.Reason)
+	}
 
-	result, err := r.exec.ExecContext(ctx, query, accountID, balanceDelta, availableBalanceDelta)
+	query := fmt.Sprintf(r.sql.adjustBalancesBatch, strings.Join(values, ", "))
+	rows, err := r.exec.QueryContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to adjust account balances: %w", err)
+		return fmt.Errorf("failed to adjust account balances: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	var missing []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to scan missing account: %w", err)
+		}
+		missing = append(missing, id)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to adjust account balances: %w", queryError(ctx, err))
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("failed to adjust account balances: %w", &MissingAccountsError{AccountIDs: missing})
+	}
+
+	return nil
+}
+
+const openBalanceQuery = `
+	INSERT INTO balances (account_id, currency)
+	SELECT id, $2 FROM accounts WHERE id = $1
+	ON CONFLICT (account_id, currency) DO NOTHING
+`
+
+// OpenBalance gives the account an empty balance in currency, so funds in that currency can be
+// adjusted. Opening a balance the account already holds changes nothing.
+func (r *accountRepository) OpenBalance(ctx context.Context, accountID uuid.UUID, currency string) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if !models.Currencies.IsValid(currency) {
+		return fmt.Errorf("failed to open balance: %w: %q", models.ErrInvalidCurrency, currency)
 	}
 
+	result, err := r.exec.ExecContext(ctx, r.sql.openBalance, accountID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to open balance: %w", queryError(ctx, err))
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("account not found")
+	if rowsAffected > 0 {
+		return nil
 	}
 
+	// Nothing inserted: either the balance is already open or the account does not exist
+	if err := r.missingBalance(ctx, accountID, currency); errors.Is(err, models.ErrBalanceNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open balance: %w", err)
+	}
 	return nil
 }
+
+const findBalancesQuery = `
+	SELECT account_id, currency, balance_cents, available_balance_cents, created_at, updated_at
+	FROM balances
+	WHERE account_id = $1
+	ORDER BY currency
+`
+
+// FindBalances returns the account's balances in every currency it holds, ordered by currency
+// It returns an empty slice when the account does not exist.
+func (r *accountRepository) FindBalances(ctx context.Context, accountID uuid.UUID) ([]*models.Balance, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.findBalances, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find balances: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	balances := []*models.Balance{}
+	for rows.Next() {
+		var b models.Balance
+		if err := rows.Scan(&b.AccountID, &b.Currency, &b.BalanceCents, &b.AvailableBalanceCents, &b.CreatedAt, &b.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+		balances = append(balances, &b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find balances: %w", queryError(ctx, err))
+	}
+
+	return balances, nil
+}
+
+const findBalanceBreakdownQuery = `
+	SELECT a.balance_cents, a.available_balance_cents, a.currency,
+	       COUNT(h.id), COALESCE(SUM(h.outstanding), 0)
+	FROM accounts_with_balance a
+	LEFT JOIN LATERAL (
+		SELECT t.id,
+		       GREATEST(
+		           COALESCE((t.metadata->'fx'->>'converted_amount')::BIGINT, t.amount_cents)
+		           - COALESCE((
+		               SELECT SUM(COALESCE((c.metadata->'fx'->>'converted_amount')::BIGINT, c.amount_cents))
+		               FROM transactions c
+		               WHERE c.reference_id = t.id AND c.type IN ('CAPTURE', 'PARTIAL_VOID')
+		           ), 0),
+		           0
+		       ) AS outstanding
+		FROM transactions t
+		WHERE t.account_id = a.id AND t.type = 'AUTH_HOLD' AND t.status = 'ACTIVE'
+	) h ON TRUE
+	WHERE a.id = $1
+	GROUP BY a.id, a.balance_cents, a.available_balance_cents, a.currency
+`
+
+// FindBalanceBreakdown returns the account's balances alongside the outstanding value of its active
+// authorization holds. A hold's outstanding value is its amount in the account currency, converted
+// at the rate recorded at authorization, less what its captures have already settled and what
+// partial voids have released.
+func (r *accountRepository) FindBalanceBreakdown(ctx context.Context, accountID uuid.UUID) (*models.BalanceBreakdown, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	breakdown := models.BalanceBreakdown{AccountID: accountID}
+	err := r.exec.QueryRowContext(ctx, r.sql.findBalanceBreakdown, accountID).Scan(
+		&breakdown.BalanceCents,
+		&breakdown.AvailableBalanceCents,
+		&breakdown.Currency,
+		&breakdown.OutstandingHolds,
+		&breakdown.OutstandingHoldsCents,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to find balance breakdown: %w", models.ErrAccountNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find balance breakdown: %w", queryError(ctx, err))
+	}
+
+	breakdown.PendingCents = max(breakdown.BalanceCents-breakdown.AvailableBalanceCents, 0)
+	return &breakdown, nil
+}
+
+// ledgerBalances returns CTEs ending in "derived", which holds each balance of the accounts
+// matching accountFilter, a condition on account_id, next to the balance its ledger adds up to: the opening balance, the
+// transactions that move funds, the adjustments audited as made outside the ledger, and the
+// outstanding value of active authorization holds.
+// Amounts are settlement amounts, so a converted transaction counts in the currency it settled in.
+// An account opened with a FUNDING transaction gets its opening balance from it; older accounts
+// only have their opening balance audit row. Archived transactions count like live ones.
+func ledgerBalances(accountFilter string) string {
+	return `
+	ledger AS (
+		SELECT id, account_id, type, status, reference_id,
+		       COALESCE(metadata->'fx'->>'converted_currency', currency) AS currency,
+		       COALESCE((metadata->'fx'->>'converted_amount')::BIGINT, amount_cents) AS amount_cents
+		FROM transactions
+		WHERE ` + accountFilter + `
+		UNION ALL
+		SELECT id, account_id, type, status, reference_id,
+		       COALESCE(metadata->'fx'->>'converted_currency', currency),
+		       COALESCE((metadata->'fx'->>'converted_amount')::BIGINT, amount_cents)
+		FROM transactions_archive
+		WHERE ` + accountFilter + `
+	),
+	opening AS (
+		SELECT o.account_id, o.currency,
+		       SUM(o.balance_delta_cents) AS balance_cents,
+		       SUM(o.available_balance_delta_cents) AS available_balance_cents
+		FROM balance_audit o
+		WHERE ` + accountFilter + `
+		  AND o.reason = '` + openingBalanceReason + `'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM ledger f
+		      WHERE f.account_id = o.account_id AND f.currency = o.currency AND f.type = 'FUNDING'
+		  )
+		GROUP BY o.account_id, o.currency
+	),
+	adjusted AS (
+		SELECT account_id, currency,
+		       SUM(balance_delta_cents) AS balance_cents,
+		       SUM(available_balance_delta_cents) AS available_balance_cents
+		FROM balance_audit
+		WHERE ` + accountFilter + ` AND outside_ledger
+		GROUP BY account_id, currency
+	),
+	posted AS (
+		SELECT account_id, currency,
+		       SUM(CASE WHEN type IN ('CAPTURE', 'TRANSFER_OUT') THEN -amount_cents ELSE amount_cents END) AS balance_cents
+		FROM ledger
+		WHERE type IN ('FUNDING', 'CAPTURE', 'REFUND', 'CHARGEBACK', 'TRANSFER_IN', 'TRANSFER_OUT')
+		GROUP BY account_id, currency
+	),
+	held AS (
+		SELECT h.account_id, h.currency, SUM(GREATEST(h.amount_cents - COALESCE(s.settled_cents, 0), 0)) AS held_cents
+		FROM ledger h
+		LEFT JOIN (
+			SELECT reference_id, SUM(amount_cents) AS settled_cents
+			FROM ledger
+			WHERE type IN ('CAPTURE', 'PARTIAL_VOID')
+			GROUP BY reference_id
+		) s ON s.reference_id = h.id
+		WHERE h.type = 'AUTH_HOLD' AND h.status = 'ACTIVE'
+		GROUP BY h.account_id, h.currency
+	),
+	derived AS (
+		SELECT b.account_id, b.currency, b.balance_cents, b.available_balance_cents,
+		       COALESCE(o.balance_cents, 0) + COALESCE(a.balance_cents, 0) + COALESCE(p.balance_cents, 0) AS ledger_cents,
+		       COALESCE(o.available_balance_cents, 0) + COALESCE(a.available_balance_cents, 0) + COALESCE(p.balance_cents, 0)
+		           - COALESCE(h.held_cents, 0) AS ledger_available_cents
+		FROM (SELECT * FROM balances WHERE ` + accountFilter + `) b
+		LEFT JOIN opening o ON o.account_id = b.account_id AND o.currency = b.currency
+		LEFT JOIN adjusted a ON a.account_id = b.account_id AND a.currency = b.currency
+		LEFT JOIN posted p ON p.account_id = b.account_id AND p.currency = b.currency
+		LEFT JOIN held h ON h.account_id = b.account_id AND h.currency = b.currency
+	)
+`
+}
+
+var findBalanceDriftQuery = `
+	WITH ` + ledgerBalances("account_id = $1") + `
+	SELECT d.currency, d.balance_cents, d.available_balance_cents, d.ledger_cents, d.ledger_available_cents
+	FROM accounts a
+	JOIN derived d ON d.account_id = a.id AND d.currency = a.currency
+	WHERE a.id = $1
+`
+
+// FindBalanceDrift compares the account's balance in its primary currency with the balance its
+// ledger adds up to. Both are read by one statement, so concurrent transactions cannot make them
+// disagree.
+func (r *accountRepository) FindBalanceDrift(ctx context.Context, accountID uuid.UUID) (*models.BalanceDrift, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	drift := models.BalanceDrift{AccountID: accountID}
+	err := r.exec.QueryRowContext(ctx, r.sql.findBalanceDrift, accountID).Scan(
+		&drift.Currency,
+		&drift.BalanceCents,
+		&drift.AvailableBalanceCents,
+		&drift.LedgerCents,
+		&drift.LedgerAvailableCents,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to find balance drift: %w", models.ErrAccountNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find balance drift: %w", queryError(ctx, err))
+	}
+
+	return &drift, nil
+}
+
+var findBalanceDriftsQuery = `
+	WITH ` + ledgerBalances("TRUE") + `
+	SELECT account_id, currency, balance_cents, available_balance_cents, ledger_cents, ledger_available_cents
+	FROM derived
+	WHERE balance_cents <> ledger_cents OR available_balance_cents <> ledger_available_cents
+	ORDER BY account_id, currency
+`
+
+// FindBalanceDrifts returns every balance, in any currency, whose balance or available balance
+// differs from what its ledger adds up to, ordered by account and currency. An empty result means
+// the ledger reconciles.
+func (r *accountRepository) FindBalanceDrifts(ctx context.Context) ([]*models.BalanceDrift, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.findBalanceDrifts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find balance drifts: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	drifts := []*models.BalanceDrift{}
+	for rows.Next() {
+		var d models.BalanceDrift
+		if err := rows.Scan(
+			&d.AccountID,
+			&d.Currency,
+			&d.BalanceCents,
+			&d.AvailableBalanceCents,
+			&d.LedgerCents,
+			&d.LedgerAvailableCents,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan balance drift: %w", queryError(ctx, err))
+		}
+		drifts = append(drifts, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find balance drifts: %w", queryError(ctx, err))
+	}
+
+	return drifts, nil
+}
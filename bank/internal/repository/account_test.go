@@ -1,9 +1,14 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/benx421/payment-gateway/bank/internal/cvvcrypt"
+	"github.com/benx421/payment-gateway/bank/internal/logging"
+	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,6 +100,111 @@ func TestAccountRepository_FindByID(t *testing.T) {
 	}
 }
 
+func TestAccountRepository_Create_EncryptsCVV(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	cvvCipher, err := cvvcrypt.NewCipher(map[byte][]byte{1: bytes.Repeat([]byte{7}, cvvcrypt.KeySize)}, 1)
+	require.NoError(t, err)
+	repo := NewAccountRepository(database, WithCVVCipher(cvvCipher))
+
+	account := &models.Account{
+		AccountNumber: "4000056655665556",
+		CVV:           "987",
+		ExpiryMonth:   1,
+		ExpiryYear:    2031,
+		Currency:      "USD",
+	}
+	require.NoError(t, repo.Create(context.Background(), account))
+	assert.NotEqual(t, uuid.Nil, account.ID)
+
+	stored, err := repo.FindByAccountNumber(context.Background(), account.AccountNumber)
+	require.NoError(t, err)
+	assert.NotEqual(t, "987", stored.CVV, "cvv must not be stored in plaintext")
+
+	plain, err := cvvCipher.DecryptCVV(stored.CVV)
+	require.NoError(t, err)
+	assert.Equal(t, "987", plain)
+}
+
+func TestAccountRepository_CreateWithFunding(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := context.Background()
+
+	account := &models.Account{
+		AccountNumber:         "4000056655665556",
+		CVV:                   "111",
+		ExpiryMonth:           1,
+		ExpiryYear:            2031,
+		BalanceCents:          2500,
+		AvailableBalanceCents: 2500,
+		Currency:              "EUR",
+	}
+	funding, err := repo.CreateWithFunding(ctx, account)
+	require.NoError(t, err)
+	assert.Equal(t, account.ID, funding.AccountID)
+	assert.Equal(t, account.CreatedAt, funding.CreatedAt)
+
+	stored, err := NewTransactionRepository(database).FindByID(ctx, funding.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransactionTypeFunding, stored.Type)
+	assert.Equal(t, models.TransactionStatusCompleted, stored.Status)
+	assert.Equal(t, int64(2500), stored.AmountCents)
+	assert.Equal(t, "EUR", stored.Currency)
+
+	drift, err := repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), drift.DriftCents())
+
+	// Without an opening balance there is nothing to fund, and nothing is created
+	empty := &models.Account{AccountNumber: "4111111111111111", CVV: "222", ExpiryMonth: 2, ExpiryYear: 2031, Currency: "USD"}
+	_, err = repo.CreateWithFunding(ctx, empty)
+	assert.ErrorIs(t, err, models.ErrInvalidAmount)
+	_, err = repo.FindByAccountNumber(ctx, empty.AccountNumber)
+	assert.ErrorIs(t, err, models.ErrAccountNotFound)
+}
+
+func TestAccountRepository_ListLowBalance(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := context.Background()
+
+	for i, available := range []int64{5000, 300, 900, 100} {
+		require.NoError(t, repo.Create(ctx, &models.Account{
+			AccountNumber:         fmt.Sprintf("400000000000%04d", i),
+			CVV:                   "123",
+			ExpiryMonth:           1,
+			ExpiryYear:            2031,
+			BalanceCents:          available,
+			AvailableBalanceCents: available,
+			Currency:              "USD",
+		}))
+	}
+
+	accounts, err := repo.ListLowBalance(ctx, 1000, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	assert.Equal(t, []int64{100, 300, 900}, []int64{
+		accounts[0].AvailableBalanceCents, accounts[1].AvailableBalanceCents, accounts[2].AvailableBalanceCents,
+	})
+
+	page, err := repo.ListLowBalance(ctx, 1000, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, int64(900), page[0].AvailableBalanceCents)
+
+	_, err = repo.ListLowBalance(ctx, 1000, 0, 0)
+	assert.Error(t, err)
+}
+
 func TestAccountRepository_AdjustBalances(t *testing.T) {
 	database := setupTestDB(t)
 	defer cleanupTestDB(t, database)
@@ -159,16 +269,17 @@ func TestAccountRepository_AdjustBalances(t *testing.T) {
 				currentAvailable = acc.AvailableBalanceCents
 			}
 
-			err := repo.AdjustBalances(
+			balance, available, err := repo.AdjustBalances(
 				context.Background(),
 				tt.accountID,
-				tt.balanceDelta,
-				tt.availableBalanceDelta,
+				models.NewMoney(tt.balanceDelta, "USD"),
+				models.NewMoney(tt.availableBalanceDelta, "USD"),
+				"test",
 			)
 
 			if tt.wantErr {
 				assert.Error(t, err, "expected error")
-				assert.Contains(t, err.Error(), "not found", "expected 'not found' error")
+				assert.ErrorIs(t, err, models.ErrAccountNotFound, "expected account not found error")
 				return
 			}
 
@@ -183,6 +294,8 @@ func TestAccountRepository_AdjustBalances(t *testing.T) {
 
 				assert.Equal(t, expectedBalance, updatedAccount.BalanceCents, "balance_cents mismatch")
 				assert.Equal(t, expectedAvailable, updatedAccount.AvailableBalanceCents, "available_balance_cents mismatch")
+				assert.Equal(t, models.NewMoney(expectedBalance, "USD"), balance, "returned balance mismatch")
+				assert.Equal(t, models.NewMoney(expectedAvailable, "USD"), available, "returned available balance mismatch")
 			}
 		})
 	}
@@ -215,15 +328,22 @@ func TestAccountRepository_AdjustBalances_Concurrent(t *testing.T) {
 	const delta = -1000
 
 	errCh := make(chan error, numGoroutines)
+	balanceCh := make(chan int64, numGoroutines)
 	for i := 0; i < numGoroutines; i++ {
 		go func() {
-			errCh <- repo.AdjustBalances(context.Background(), account.ID, delta, 0)
+			balance, _, err := repo.AdjustBalances(context.Background(), account.ID, models.NewMoney(delta, "USD"), models.NewMoney(0, "USD"), "test")
+			balanceCh <- balance.Cents
+			errCh <- err
 		}()
 	}
 
+	seen := make(map[int64]bool, numGoroutines)
 	for i := 0; i < numGoroutines; i++ {
 		assert.NoError(t, <-errCh, "concurrent adjustment failed")
+		seen[<-balanceCh] = true
 	}
+	// Each update returns the balance it produced, so every intermediate value appears exactly once
+	assert.Len(t, seen, numGoroutines, "returned balances should be distinct")
 
 	finalAccount, err := repo.FindByID(context.Background(), account.ID)
 	require.NoError(t, err, "failed to get final account")
@@ -231,3 +351,550 @@ func TestAccountRepository_AdjustBalances_Concurrent(t *testing.T) {
 	expectedBalance := initialBalance + (numGoroutines * delta)
 	assert.Equal(t, expectedBalance, finalAccount.BalanceCents, "concurrent updates lost update detected!")
 }
+
+func TestAccountRepository_AdjustBalancesIfAvailable(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := context.Background()
+
+	account, setupErr := repo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, setupErr, "failed to get account")
+
+	stale := models.NewMoney(account.AvailableBalanceCents+1, "USD")
+	_, _, updated, err := repo.AdjustBalancesIfAvailable(ctx, account.ID, stale, models.NewMoney(0, "USD"), models.NewMoney(-100, "USD"), "test")
+	require.NoError(t, err)
+	assert.False(t, updated, "a changed available balance must not be updated")
+
+	unchanged, err := repo.FindByID(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.AvailableBalanceCents, unchanged.AvailableBalanceCents)
+
+	expected := models.NewMoney(account.AvailableBalanceCents, "USD")
+	balance, available, updated, err := repo.AdjustBalancesIfAvailable(ctx, account.ID, expected, models.NewMoney(0, "USD"), models.NewMoney(-100, "USD"), "test")
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, account.BalanceCents, balance.Cents)
+	assert.Equal(t, account.AvailableBalanceCents-100, available.Cents)
+
+	_, _, _, err = repo.AdjustBalancesIfAvailable(ctx, uuid.New(), expected, models.NewMoney(0, "USD"), models.NewMoney(-100, "USD"), "test")
+	assert.ErrorIs(t, err, models.ErrAccountNotFound)
+
+	_, _, _, err = repo.AdjustBalancesIfAvailable(ctx, account.ID, models.NewMoney(0, "EUR"), models.NewMoney(0, "EUR"), models.NewMoney(-100, "EUR"), "test")
+	assert.ErrorIs(t, err, models.ErrBalanceNotFound)
+
+	var audited int64
+	require.NoError(t, database.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(available_balance_delta_cents), 0) FROM balance_audit WHERE account_id = $1 AND reason = 'test'`,
+		account.ID).Scan(&audited))
+	assert.Equal(t, int64(-100), audited, "conditional updates are audited")
+}
+
+func TestUpdateBalanceOptimistically_Concurrent(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+
+	account, setupErr := repo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, setupErr, "failed to get account")
+
+	// Every round of conflicting updates has a winner, so this many attempts always suffice
+	const numGoroutines = DefaultBalanceUpdateAttempts
+	const hold = 1000
+
+	errCh := make(chan error, numGoroutines)
+	for range numGoroutines {
+		go func() {
+			_, err := UpdateBalanceOptimistically(context.Background(), repo, account.ID, "USD", DefaultBalanceUpdateAttempts, "test",
+				func(current *models.Balance) (models.Money, models.Money, error) {
+					if current.AvailableBalanceCents < hold {
+						return models.Money{}, models.Money{}, models.ErrInsufficientFunds
+					}
+					return models.NewMoney(0, "USD"), models.NewMoney(-hold, "USD"), nil
+				})
+			errCh <- err
+		}()
+	}
+	for range numGoroutines {
+		assert.NoError(t, <-errCh)
+	}
+
+	final, err := repo.FindByID(context.Background(), account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.AvailableBalanceCents-numGoroutines*hold, final.AvailableBalanceCents, "no update may be lost")
+}
+
+// racingAccountRepository holds one balance in memory and lets another writer change it before
+// each of the first races conditional updates
+type racingAccountRepository struct {
+	AccountRepository
+	balance models.Balance
+	races   int
+	reads   int
+}
+
+func (r *racingAccountRepository) FindBalances(context.Context, uuid.UUID) ([]*models.Balance, error) {
+	r.reads++
+	b := r.balance
+	return []*models.Balance{&b}, nil
+}
+
+func (r *racingAccountRepository) AdjustBalancesIfAvailable(
+	_ context.Context,
+	_ uuid.UUID,
+	expectedAvailable, balanceDelta, availableBalanceDelta models.Money,
+	_ string,
+) (models.Money, models.Money, bool, error) {
+	if r.races > 0 {
+		r.races--
+		r.balance.AvailableBalanceCents -= 10
+	}
+	if r.balance.AvailableBalanceCents != expectedAvailable.Cents {
+		return models.Money{}, models.Money{}, false, nil
+	}
+	r.balance.BalanceCents += balanceDelta.Cents
+	r.balance.AvailableBalanceCents += availableBalanceDelta.Cents
+	return models.NewMoney(r.balance.BalanceCents, "USD"), models.NewMoney(r.balance.AvailableBalanceCents, "USD"), true, nil
+}
+
+func TestUpdateBalanceOptimistically(t *testing.T) {
+	release := func(*models.Balance) (models.Money, models.Money, error) {
+		return models.NewMoney(0, "USD"), models.NewMoney(100, "USD"), nil
+	}
+
+	t.Run("retries with a fresh read after losing a race", func(t *testing.T) {
+		repo := &racingAccountRepository{balance: models.Balance{Currency: "USD", BalanceCents: 1000, AvailableBalanceCents: 500}, races: 2}
+
+		balance, err := UpdateBalanceOptimistically(context.Background(), repo, uuid.New(), "USD", 3, "test", release)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, repo.reads)
+		assert.Equal(t, int64(500-20+100), balance.AvailableBalanceCents)
+		assert.Equal(t, int64(1000), balance.BalanceCents)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		repo := &racingAccountRepository{balance: models.Balance{Currency: "USD", AvailableBalanceCents: 500}, races: 3}
+
+		_, err := UpdateBalanceOptimistically(context.Background(), repo, uuid.New(), "USD", 3, "test", release)
+
+		require.ErrorIs(t, err, models.ErrBalanceConflict)
+		assert.Equal(t, int64(470), repo.balance.AvailableBalanceCents, "only the racing writer changed the balance")
+	})
+
+	t.Run("returns the update's error without writing", func(t *testing.T) {
+		repo := &racingAccountRepository{balance: models.Balance{Currency: "USD", AvailableBalanceCents: 500}}
+
+		_, err := UpdateBalanceOptimistically(context.Background(), repo, uuid.New(), "USD", 3, "test",
+			func(*models.Balance) (models.Money, models.Money, error) {
+				return models.Money{}, models.Money{}, models.ErrInsufficientFunds
+			})
+
+		require.ErrorIs(t, err, models.ErrInsufficientFunds)
+		assert.Equal(t, int64(500), repo.balance.AvailableBalanceCents)
+	})
+
+	t.Run("missing currency", func(t *testing.T) {
+		repo := &racingAccountRepository{balance: models.Balance{Currency: "USD"}}
+
+		_, err := UpdateBalanceOptimistically(context.Background(), repo, uuid.New(), "EUR", 3, "test", release)
+
+		require.ErrorIs(t, err, models.ErrBalanceNotFound)
+	})
+}
+
+func TestAccountRepository_AdjustBalancesBatch(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := context.Background()
+
+	first, err := repo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err)
+	second, err := repo.FindByAccountNumber(ctx, "4242424242424242")
+	require.NoError(t, err)
+
+	t.Run("applies every adjustment", func(t *testing.T) {
+		err := repo.AdjustBalancesBatch(ctx, []BalanceAdjustment{
+			{AccountID: first.ID, BalanceDelta: models.NewMoney(-2000, "USD"), AvailableBalanceDelta: models.NewMoney(0, "USD")},
+			{AccountID: second.ID, BalanceDelta: models.NewMoney(3000, "USD"), AvailableBalanceDelta: models.NewMoney(3000, "USD")},
+			{AccountID: first.ID, BalanceDelta: models.NewMoney(-500, "USD"), AvailableBalanceDelta: models.NewMoney(-500, "USD")},
+		})
+		require.NoError(t, err)
+
+		updatedFirst, err := repo.FindByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.BalanceCents-2500, updatedFirst.BalanceCents)
+		assert.Equal(t, first.AvailableBalanceCents-500, updatedFirst.AvailableBalanceCents)
+
+		updatedSecond, err := repo.FindByID(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, second.BalanceCents+3000, updatedSecond.BalanceCents)
+		assert.Equal(t, second.AvailableBalanceCents+3000, updatedSecond.AvailableBalanceCents)
+
+		first, second = updatedFirst, updatedSecond
+	})
+
+	t.Run("missing account leaves every balance unchanged", func(t *testing.T) {
+		missingID := uuid.New()
+		err := repo.AdjustBalancesBatch(ctx, []BalanceAdjustment{
+			{AccountID: first.ID, BalanceDelta: models.NewMoney(-1000, "USD"), AvailableBalanceDelta: models.NewMoney(-1000, "USD")},
+			{AccountID: missingID, BalanceDelta: models.NewMoney(1000, "USD"), AvailableBalanceDelta: models.NewMoney(1000, "USD")},
+		})
+		require.ErrorIs(t, err, models.ErrAccountNotFound)
+
+		var missingErr *MissingAccountsError
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []uuid.UUID{missingID}, missingErr.AccountIDs)
+
+		unchanged, err := repo.FindByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.BalanceCents, unchanged.BalanceCents)
+		assert.Equal(t, first.AvailableBalanceCents, unchanged.AvailableBalanceCents)
+	})
+
+	t.Run("empty batch is a no-op", func(t *testing.T) {
+		assert.NoError(t, repo.AdjustBalancesBatch(ctx, nil))
+	})
+
+	t.Run("currency mismatch", func(t *testing.T) {
+		err := repo.AdjustBalancesBatch(ctx, []BalanceAdjustment{
+			{AccountID: first.ID, BalanceDelta: models.NewMoney(100, "USD"), AvailableBalanceDelta: models.NewMoney(100, "EUR")},
+		})
+		assert.ErrorIs(t, err, models.ErrCurrencyMismatch)
+	})
+}
+
+func TestAccountRepository_CurrencyBalances(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := context.Background()
+
+	account, err := repo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err)
+
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(500, "EUR"), models.NewMoney(500, "EUR"), "test")
+	require.ErrorIs(t, err, models.ErrBalanceNotFound, "a currency the account does not hold cannot be adjusted")
+
+	require.NoError(t, repo.OpenBalance(ctx, account.ID, "EUR"))
+	require.NoError(t, repo.OpenBalance(ctx, account.ID, "EUR"), "opening a balance twice is a no-op")
+
+	balance, available, err := repo.AdjustBalances(ctx, account.ID, models.NewMoney(500, "EUR"), models.NewMoney(400, "EUR"), "test")
+	require.NoError(t, err)
+	assert.Equal(t, models.NewMoney(500, "EUR"), balance)
+	assert.Equal(t, models.NewMoney(400, "EUR"), available)
+
+	balances, err := repo.FindBalances(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	assert.Equal(t, "EUR", balances[0].Currency)
+	assert.Equal(t, int64(500), balances[0].BalanceCents)
+	assert.Equal(t, "USD", balances[1].Currency)
+	assert.Equal(t, account.BalanceCents, balances[1].BalanceCents)
+
+	unchanged, err := repo.FindByID(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.BalanceCents, unchanged.BalanceCents, "the primary balance is unaffected by other currencies")
+
+	err = repo.OpenBalance(ctx, uuid.New(), "EUR")
+	assert.ErrorIs(t, err, models.ErrAccountNotFound)
+}
+
+func TestAccountRepository_FindBalanceBreakdown(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	txnRepo := NewTransactionRepository(database)
+	ctx := context.Background()
+
+	account, err := repo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err)
+
+	partlyCaptured := &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 5000, Currency: "USD",
+		Status: models.TransactionStatusActive,
+	}
+	converted := &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 1000, Currency: "EUR",
+		Status: models.TransactionStatusActive,
+		Metadata: map[string]any{models.MetadataKeyFX: models.FXConversion{
+			OriginalAmount: 1000, OriginalCurrency: "EUR", ConvertedAmount: 1100, ConvertedCurrency: "USD", Rate: "1.100000",
+		}},
+	}
+	voided := &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 700, Currency: "USD",
+		Status: models.TransactionStatusVoided,
+	}
+	for _, txn := range []*models.Transaction{partlyCaptured, converted, voided} {
+		require.NoError(t, txnRepo.Create(ctx, txn))
+	}
+	require.NoError(t, txnRepo.Create(ctx, &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeCapture, AmountCents: 2000, Currency: "USD",
+		ReferenceID: &partlyCaptured.ID, Status: models.TransactionStatusCompleted,
+	}))
+
+	// Mirror what the services do: both holds reserve funds, the capture settles part of one
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(-2000, "USD"), models.NewMoney(-6100, "USD"), "test")
+	require.NoError(t, err)
+
+	breakdown, err := repo.FindBalanceBreakdown(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.BalanceCents-2000, breakdown.BalanceCents)
+	assert.Equal(t, account.AvailableBalanceCents-6100, breakdown.AvailableBalanceCents)
+	assert.Equal(t, int64(4100), breakdown.PendingCents)
+	assert.Equal(t, 2, breakdown.OutstandingHolds)
+	assert.Equal(t, int64(3000+1100), breakdown.OutstandingHoldsCents)
+	assert.Equal(t, "USD", breakdown.Currency)
+
+	_, err = repo.FindBalanceBreakdown(ctx, uuid.New())
+	assert.ErrorIs(t, err, models.ErrAccountNotFound)
+}
+
+func TestAccountRepository_BalanceAudit(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := logging.ContextWithActor(logging.ContextWithRequestID(context.Background(), "req-1"), "api_key:abc")
+
+	account, err := repo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err)
+
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(-700, "USD"), models.NewMoney(0, "USD"), "capture 1")
+	require.NoError(t, err)
+	require.NoError(t, repo.AdjustBalancesBatch(context.Background(), []BalanceAdjustment{
+		{AccountID: account.ID, BalanceDelta: models.NewMoney(100, "USD"), AvailableBalanceDelta: models.NewMoney(100, "USD"), Reason: "refund 2"},
+		{AccountID: account.ID, BalanceDelta: models.NewMoney(0, "USD"), AvailableBalanceDelta: models.NewMoney(-50, "USD"), Reason: "hold 3"},
+	}))
+	err = repo.AdjustBalancesBatch(ctx, []BalanceAdjustment{
+		{AccountID: account.ID, BalanceDelta: models.NewMoney(5, "USD"), AvailableBalanceDelta: models.NewMoney(5, "USD"), Reason: "rolled back"},
+		{AccountID: uuid.New(), BalanceDelta: models.NewMoney(5, "USD"), AvailableBalanceDelta: models.NewMoney(5, "USD"), Reason: "rolled back"},
+	})
+	require.ErrorIs(t, err, models.ErrAccountNotFound)
+
+	rows, err := database.QueryContext(ctx, `
+		SELECT balance_delta_cents, available_balance_delta_cents, actor, reason, COALESCE(request_id, '')
+		FROM balance_audit WHERE account_id = $1 ORDER BY id`, account.ID)
+	require.NoError(t, err)
+	defer func() { _ = rows.Close() }()
+
+	type entry struct {
+		actor, reason, requestID string
+		balance, available       int64
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		require.NoError(t, rows.Scan(&e.balance, &e.available, &e.actor, &e.reason, &e.requestID))
+		entries = append(entries, e)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []entry{
+		{balance: 1000000, available: 1000000, actor: "system", reason: openingBalanceReason},
+		{balance: -700, available: 0, actor: "api_key:abc", reason: "capture 1", requestID: "req-1"},
+		{balance: 100, available: 100, actor: anonymousActor, reason: "refund 2"},
+		{balance: 0, available: -50, actor: anonymousActor, reason: "hold 3"},
+	}, entries, "a failed batch must not be audited")
+
+	_, err = database.ExecContext(ctx, `UPDATE balance_audit SET reason = 'edited'`)
+	assert.ErrorContains(t, err, "append-only")
+	_, err = database.ExecContext(ctx, `DELETE FROM balance_audit`)
+	assert.ErrorContains(t, err, "append-only")
+}
+
+func TestAccountRepository_BalanceDrift(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	txnRepo := NewTransactionRepository(database)
+	ctx := context.Background()
+
+	account := &models.Account{
+		AccountNumber:         "4000056655665556",
+		CVV:                   "111",
+		ExpiryMonth:           1,
+		ExpiryYear:            2031,
+		BalanceCents:          2000,
+		AvailableBalanceCents: 2000,
+		Currency:              "USD",
+	}
+	require.NoError(t, repo.Create(ctx, account))
+
+	// Mirror the services: a converted hold of 800 USD, part captured and part voided, and a refund
+	hold := &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 700, Currency: "EUR",
+		Status: models.TransactionStatusActive,
+		Metadata: map[string]any{models.MetadataKeyFX: models.FXConversion{
+			OriginalAmount: 700, OriginalCurrency: "EUR", ConvertedAmount: 800, ConvertedCurrency: "USD", Rate: "1.142857",
+		}},
+	}
+	require.NoError(t, txnRepo.Create(ctx, hold))
+	_, _, err := repo.AdjustBalances(ctx, account.ID, models.NewMoney(0, "USD"), models.NewMoney(-800, "USD"), "hold")
+	require.NoError(t, err)
+	capture := &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeCapture, AmountCents: 350, Currency: "EUR",
+		ReferenceID: &hold.ID, Status: models.TransactionStatusCompleted,
+		Metadata: map[string]any{models.MetadataKeyFX: models.FXConversion{
+			OriginalAmount: 350, OriginalCurrency: "EUR", ConvertedAmount: 400, ConvertedCurrency: "USD", Rate: "1.142857",
+		}},
+	}
+	require.NoError(t, txnRepo.Create(ctx, capture))
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(-400, "USD"), models.NewMoney(0, "USD"), "capture")
+	require.NoError(t, err)
+	require.NoError(t, txnRepo.Create(ctx, &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypePartialVoid, AmountCents: 88, Currency: "EUR",
+		ReferenceID: &hold.ID, Status: models.TransactionStatusCompleted,
+		Metadata: map[string]any{models.MetadataKeyFX: models.FXConversion{
+			OriginalAmount: 88, OriginalCurrency: "EUR", ConvertedAmount: 100, ConvertedCurrency: "USD", Rate: "1.142857",
+		}},
+	}))
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(0, "USD"), models.NewMoney(100, "USD"), "partial void")
+	require.NoError(t, err)
+	require.NoError(t, txnRepo.Create(ctx, &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeRefund, AmountCents: 150, Currency: "USD",
+		ReferenceID: &capture.ID, Status: models.TransactionStatusCompleted,
+	}))
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(150, "USD"), models.NewMoney(150, "USD"), "refund")
+	require.NoError(t, err)
+
+	drift, err := repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1750), drift.BalanceCents)
+	assert.Equal(t, int64(1450), drift.AvailableBalanceCents)
+	assert.Zero(t, drift.DriftCents())
+	assert.Zero(t, drift.AvailableDriftCents(), "the hold still reserves 800 less 400 captured and 100 voided")
+
+	drifts, err := repo.FindBalanceDrifts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// A wrong delta is audited like any other, but no transaction accounts for it
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(0, "USD"), models.NewMoney(300, "USD"), "double release")
+	require.NoError(t, err)
+
+	drift, err = repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Zero(t, drift.DriftCents())
+	assert.Equal(t, int64(300), drift.AvailableDriftCents())
+
+	// As is a balance written directly
+	_, err = database.ExecContext(ctx, `UPDATE balances SET balance_cents = balance_cents - 200 WHERE account_id = $1`, account.ID)
+	require.NoError(t, err)
+
+	drifts, err = repo.FindBalanceDrifts(ctx)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, models.BalanceDrift{
+		AccountID: account.ID, Currency: "USD",
+		BalanceCents: 1550, AvailableBalanceCents: 1750,
+		LedgerCents: 1750, LedgerAvailableCents: 1450,
+	}, *drifts[0])
+
+	_, err = repo.FindBalanceDrift(ctx, uuid.New())
+	assert.ErrorIs(t, err, models.ErrAccountNotFound)
+}
+
+func TestAccountRepository_BalanceDrift_AdjustmentsOutsideLedger(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	ctx := context.Background()
+
+	account, err := repo.FindByAccountNumber(ctx, "4242424242424242")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AdjustBalancesBatch(ctx, []BalanceAdjustment{{
+		AccountID: account.ID, BalanceDelta: models.NewMoney(500, "USD"), AvailableBalanceDelta: models.NewMoney(500, "USD"),
+		Reason: "goodwill credit",
+	}}))
+	_, err = UpdateBalanceOptimistically(ctx, repo, account.ID, "USD", DefaultBalanceUpdateAttempts, "manual hold",
+		func(*models.Balance) (models.Money, models.Money, error) {
+			return models.NewMoney(0, "USD"), models.NewMoney(-200, "USD"), nil
+		})
+	require.NoError(t, err)
+
+	drift, err := repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50500), drift.BalanceCents)
+	assert.Equal(t, int64(50300), drift.AvailableBalanceCents)
+	assert.Zero(t, drift.DriftCents())
+	assert.Zero(t, drift.AvailableDriftCents())
+
+	// A plain AdjustBalances is expected to come with a transaction, so on its own it drifts
+	_, _, err = repo.AdjustBalances(ctx, account.ID, models.NewMoney(100, "USD"), models.NewMoney(100, "USD"), "no transaction")
+	require.NoError(t, err)
+
+	drift, err = repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), drift.DriftCents())
+}
+
+func TestAccountRepository_BalanceDrift_CaptureBeforeAuditTrail(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewAccountRepository(database)
+	txnRepo := NewTransactionRepository(database)
+	ctx := context.Background()
+
+	account, err := repo.FindByAccountNumber(ctx, "4242424242424242")
+	require.NoError(t, err)
+
+	// Before the audit trail existed: a hold of 3000, 2000 of it captured, with the balance changed unaudited
+	hold := &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 3000, Currency: "USD",
+		Status: models.TransactionStatusActive,
+	}
+	require.NoError(t, txnRepo.Create(ctx, hold))
+	require.NoError(t, txnRepo.Create(ctx, &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeCapture, AmountCents: 2000, Currency: "USD",
+		ReferenceID: &hold.ID, Status: models.TransactionStatusCompleted,
+	}))
+	_, err = database.ExecContext(ctx, `TRUNCATE TABLE balance_audit`)
+	require.NoError(t, err)
+	_, err = database.ExecContext(ctx,
+		`UPDATE balances SET balance_cents = 48000, available_balance_cents = 47000 WHERE account_id = $1`, account.ID)
+	require.NoError(t, err)
+
+	applyMigrationFile(t, database, "000015_balance_audit_opening.up.sql")
+
+	drift, err := repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), drift.DriftCents(), "the opening row already includes the capture")
+
+	applyMigrationFile(t, database, "000016_balance_audit_ledger.up.sql")
+
+	drift, err = repo.FindBalanceDrift(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Zero(t, drift.DriftCents())
+	assert.Zero(t, drift.AvailableDriftCents(), "the hold still reserves the 1000 not captured")
+
+	drifts, err := repo.FindBalanceDrifts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	var audited, auditedAvailable int64
+	err = database.QueryRowContext(ctx, `
+		SELECT SUM(balance_delta_cents), SUM(available_balance_delta_cents) FROM balance_audit WHERE account_id = $1
+	`, account.ID).Scan(&audited, &auditedAvailable)
+	require.NoError(t, err)
+	assert.Equal(t, int64(48000), audited, "the trail still sums to the balance")
+	assert.Equal(t, int64(47000), auditedAvailable)
+}
@@ -34,17 +34,35 @@ func setupTestDB(t *testing.T) *db.DB {
 func runMigrations(t *testing.T, database *db.DB) {
 	t.Helper()
 
-	migrationPath := filepath.Join("..", "..", "internal", "db", "migrations", "000001_init.up.sql")
-	sqlBytes, err := os.ReadFile(migrationPath) // #nosec G304
-	if err != nil {
-		t.Fatalf("failed to read migration file: %v", err)
+	migrationPaths, err := filepath.Glob(filepath.Join("..", "..", "internal", "db", "migrations", "*.up.sql"))
+	if err != nil || len(migrationPaths) == 0 {
+		t.Fatalf("failed to find migration files: %v", err)
 	}
 
-	_, err = database.ExecContext(context.Background(), string(sqlBytes))
-	if err != nil {
-		if err.Error() != "pq: relation \"accounts\" already exists" {
-			t.Logf("migration execution completed (tables may already exist)")
+	// Glob returns paths in lexical order, which matches the migration sequence
+	for _, migrationPath := range migrationPaths {
+		sqlBytes, err := os.ReadFile(migrationPath) // #nosec G304
+		if err != nil {
+			t.Fatalf("failed to read migration file: %v", err)
 		}
+
+		_, err = database.ExecContext(context.Background(), string(sqlBytes))
+		if err != nil {
+			t.Logf("migration %s skipped (already applied): %v", filepath.Base(migrationPath), err)
+		}
+	}
+}
+
+// applyMigrationFile runs the named up migration again, as it would run on a database holding the current rows
+func applyMigrationFile(t *testing.T, database *db.DB, name string) {
+	t.Helper()
+
+	sqlBytes, err := os.ReadFile(filepath.Join("..", "..", "internal", "db", "migrations", name)) // #nosec G304
+	if err != nil {
+		t.Fatalf("failed to read migration file: %v", err)
+	}
+	if _, err := database.ExecContext(context.Background(), string(sqlBytes)); err != nil {
+		t.Fatalf("failed to apply migration %s: %v", name, err)
 	}
 }
 
@@ -58,7 +76,7 @@ func cleanupTestDB(t *testing.T, database *db.DB) {
 func truncateTables(t *testing.T, database *db.DB) {
 	t.Helper()
 
-	tables := []string{"transactions", "idempotency_keys"}
+	tables := []string{"transactions", "transactions_archive", "idempotency_keys", "balance_audit", "request_nonces"}
 	for _, table := range tables {
 		_, err := database.ExecContext(context.Background(), "TRUNCATE TABLE "+table+" CASCADE")
 		if err != nil {
@@ -68,11 +86,28 @@ func truncateTables(t *testing.T, database *db.DB) {
 
 	_, err := database.ExecContext(context.Background(), `
 		DELETE FROM accounts;
-		INSERT INTO accounts (account_number, cvv, expiry_month, expiry_year, balance_cents, available_balance_cents) VALUES
-			('4111111111111111', '123', 12, 2030, 1000000, 1000000),
-			('4242424242424242', '456', 6, 2030, 50000, 50000),
-			('5555555555554444', '789', 9, 2030, 0, 0),
-			('5105105105105100', '321', 3, 2020, 500000, 500000);
+		WITH seeded (account_number, cvv, expiry_month, expiry_year, balance_cents) AS (
+			VALUES ('4111111111111111', '123', 12, 2030, 1000000::bigint),
+			       ('4242424242424242', '456', 6, 2030, 50000),
+			       ('5555555555554444', '789', 9, 2030, 0),
+			       ('5105105105105100', '321', 3, 2020, 500000)
+		),
+		account AS (
+			INSERT INTO accounts (account_number, cvv, expiry_month, expiry_year)
+			SELECT account_number, cvv, expiry_month, expiry_year FROM seeded
+			RETURNING id, account_number, currency
+		),
+		balance AS (
+			INSERT INTO balances (account_id, currency, balance_cents, available_balance_cents)
+			SELECT a.id, a.currency, s.balance_cents, s.balance_cents
+			FROM account a
+			JOIN seeded s ON s.account_number = a.account_number
+			RETURNING account_id, currency, balance_cents
+		)
+		INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents, actor, reason)
+		SELECT account_id, currency, balance_cents, balance_cents, 'system', 'opening balance'
+		FROM balance
+		WHERE balance_cents <> 0;
 	`)
 	if err != nil {
 		t.Fatalf("failed to reset accounts: %v", err)
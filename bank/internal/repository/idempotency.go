@@ -8,60 +8,167 @@ import (
 
 	"github.com/benx421/payment-gateway/bank/internal/db"
 	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/google/uuid"
 )
 
 // IdempotencyRepository defines the interface for idempotency key data access
 type IdempotencyRepository interface {
 	Get(ctx context.Context, key, requestPath string) (*models.IdempotencyKey, error)
 	Store(ctx context.Context, idemKey *models.IdempotencyKey) error
+	Insert(ctx context.Context, idemKey *models.IdempotencyKey) error
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*models.IdempotencyKey, error)
 	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
 }
 
 type idempotencyRepository struct {
 	exec db.Executor
+	sql  *idempotencyStatements
+}
+
+// idempotencyStatements are the idempotencyRepository queries with a table prefix applied
+type idempotencyStatements struct {
+	get                string
+	getByTransactionID string
+	store              string
+	insert             string
+	deleteOlderThan    string
+}
+
+// newIdempotencyStatements builds the idempotencyRepository queries for tables named with prefix
+func newIdempotencyStatements(prefix string) idempotencyStatements {
+	return idempotencyStatements{
+		get:                db.PrefixTables(getIdempotencyKeyQuery, prefix),
+		getByTransactionID: db.PrefixTables(getIdempotencyKeyByTransactionQuery, prefix),
+		store:              db.PrefixTables(idempotencyInsert+` ON CONFLICT (key, request_path) DO NOTHING`, prefix),
+		insert:             db.PrefixTables(idempotencyInsert, prefix),
+		deleteOlderThan:    db.PrefixTables(deleteIdempotencyKeysQuery, prefix),
+	}
+}
+
+// IdempotencyOption configures optional IdempotencyRepository behaviour
+type IdempotencyOption func(*idempotencyRepository)
+
+// WithIdempotencyTables makes the repository query the tables of tables instead of the unprefixed ones
+func WithIdempotencyTables(tables *Tables) IdempotencyOption {
+	return func(r *idempotencyRepository) {
+		if tables != nil {
+			r.sql = &tables.idempotency
+		}
+	}
 }
 
 // NewIdempotencyRepository creates a new IdempotencyRepository
 // The exec parameter can be either *db.DB or *db.Tx, allowing the repository
 // to work with or without transactions
-func NewIdempotencyRepository(exec db.Executor) IdempotencyRepository {
-	return &idempotencyRepository{exec: exec}
+func NewIdempotencyRepository(exec db.Executor, opts ...IdempotencyOption) IdempotencyRepository {
+	r := &idempotencyRepository{exec: exec, sql: &defaultTables.idempotency}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return &tracedIdempotencyRepository{next: r}
 }
 
+const getIdempotencyKeyQuery = `
+	SELECT ` + idempotencyColumns + `
+	FROM idempotency_keys
+	WHERE key = $1 AND request_path = $2
+`
+
 // Get retrieves a cached idempotency key and its response
 func (r *idempotencyRepository) Get(ctx context.Context, key, requestPath string) (*models.IdempotencyKey, error) {
-	query := `
-		SELECT key, request_path, response_status, response_body, created_at
-		FROM idempotency_keys
-		WHERE key = $1 AND request_path = $2
-	`
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	idemKey, err := scanIdempotencyKey(r.exec.QueryRowContext(ctx, r.sql.get, key, requestPath))
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found is not an error. This means this is a new request
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", queryError(ctx, err))
+	}
+
+	return idemKey, nil
+}
+
+const getIdempotencyKeyByTransactionQuery = `
+	SELECT ` + idempotencyColumns + `
+	FROM idempotency_keys
+	WHERE transaction_id = $1
+	ORDER BY created_at
+	LIMIT 1
+`
+
+// GetByTransactionID returns the idempotency record of the request that created a transaction,
+// or nil if none is stored. When later requests replayed the same transaction, the earliest wins.
+func (r *idempotencyRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*models.IdempotencyKey, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	idemKey, err := scanIdempotencyKey(r.exec.QueryRowContext(ctx, r.sql.getByTransactionID, transactionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key by transaction: %w", queryError(ctx, err))
+	}
 
+	return idemKey, nil
+}
+
+// idempotencyColumns are the columns scanIdempotencyKey reads
+const idempotencyColumns = `key, request_path, response_status, response_body, transaction_id, created_at`
+
+func scanIdempotencyKey(row *sql.Row) (*models.IdempotencyKey, error) {
 	var idemKey models.IdempotencyKey
-	err := r.exec.QueryRowContext(ctx, query, key, requestPath).Scan(
+	var transactionID uuid.NullUUID
+	if err := row.Scan(
 		&idemKey.Key,
 		&idemKey.RequestPath,
 		&idemKey.ResponseStatus,
 		&idemKey.ResponseBody,
+		&transactionID,
 		&idemKey.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil // Not found is not an error. This means this is a new request
+	); err != nil {
+		return nil, err
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	if transactionID.Valid {
+		idemKey.TransactionID = &transactionID.UUID
 	}
-
 	return &idemKey, nil
 }
 
-// Store saves an idempotency key with its response
+// idempotencyInsert inserts an idempotency key with its response
+const idempotencyInsert = `
+	INSERT INTO idempotency_keys (key, request_path, response_status, response_body, transaction_id, created_at)
+	VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()))
+`
+
+// Store saves an idempotency key with its response, keeping the stored one if the key is already used
 func (r *idempotencyRepository) Store(ctx context.Context, idemKey *models.IdempotencyKey) error {
-	query := `
-		INSERT INTO idempotency_keys (key, request_path, response_status, response_body, created_at)
-		VALUES ($1, $2, $3, $4, COALESCE($5, NOW()))
-		ON CONFLICT (key, request_path) DO NOTHING
-	`
+	if err := r.insert(ctx, r.sql.store, idemKey); err != nil {
+		return fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Insert saves an idempotency key with its response like Store, but fails with
+// models.ErrDuplicateIdempotencyKey when the key is already used for the path. Written in the same
+// database transaction as the change it records, the unique violation rolls back the second of
+// two concurrent requests with one key once the first commits.
+func (r *idempotencyRepository) Insert(ctx context.Context, idemKey *models.IdempotencyKey) error {
+	err := r.insert(ctx, r.sql.insert, idemKey)
+	if db.IsUniqueViolation(err) {
+		return fmt.Errorf("failed to insert idempotency key: %w", models.ErrDuplicateIdempotencyKey)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) insert(ctx context.Context, query string, idemKey *models.IdempotencyKey) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
 	_, err := r.exec.ExecContext(
 		ctx, query,
@@ -69,26 +176,29 @@ func (r *idempotencyRepository) Store(ctx context.Context, idemKey *models.Idemp
 		idemKey.RequestPath,
 		idemKey.ResponseStatus,
 		idemKey.ResponseBody,
+		idemKey.TransactionID,
 		idemKey.CreatedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to store idempotency key: %w", err)
+		return queryError(ctx, err)
 	}
-
 	return nil
 }
 
+const deleteIdempotencyKeysQuery = `
+	DELETE FROM idempotency_keys
+	WHERE created_at < $1
+`
+
 // DeleteOlderThan removes idempotency keys created before the specified time
 // This is used for cleanup of keys older than 24 hours
 func (r *idempotencyRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
-	query := `
-		DELETE FROM idempotency_keys
-		WHERE created_at < $1
-	`
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	result, err := r.exec.ExecContext(ctx, query, before)
+	result, err := r.exec.ExecContext(ctx, r.sql.deleteOlderThan, before)
 	if err != nil {
-		return 0, fmt.Errorf("failed to delete old idempotency keys: %w", err)
+		return 0, fmt.Errorf("failed to delete old idempotency keys: %w", queryError(ctx, err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/db"
 	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -218,3 +220,126 @@ func TestIdempotencyRepository_DeleteOlderThan_NoneDeleted(t *testing.T) {
 	require.NoError(t, err, "unexpected error")
 	assert.Equal(t, int64(0), deletedCount, "deleted count should be 0")
 }
+
+func TestIdempotencyRepository_StoreInTransaction(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	account, err := NewAccountRepository(database).FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	// writeBoth records a transaction row and its idempotency key through repositories bound to one tx
+	writeBoth := func(t *testing.T, key string) (*db.Tx, *models.Transaction) {
+		t.Helper()
+
+		tx, err := database.BeginTx(context.Background(), nil)
+		require.NoError(t, err, "failed to begin transaction")
+
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+		}
+		require.NoError(t, NewTransactionRepository(tx).Create(context.Background(), txn))
+		require.NoError(t, NewIdempotencyRepository(tx).Store(context.Background(), &models.IdempotencyKey{
+			Key:            key,
+			RequestPath:    "/api/v1/authorizations",
+			ResponseStatus: 200,
+			ResponseBody:   `{"status":"approved"}`,
+			TransactionID:  &txn.ID,
+		}))
+		return tx, txn
+	}
+
+	t.Run("rollback discards both", func(t *testing.T) {
+		tx, txn := writeBoth(t, "rolled-back-key")
+		require.NoError(t, tx.Rollback())
+
+		idemKey, err := NewIdempotencyRepository(database).Get(context.Background(), "rolled-back-key", "/api/v1/authorizations")
+		require.NoError(t, err)
+		assert.Nil(t, idemKey, "idempotency key should be discarded with the transaction")
+
+		_, err = NewTransactionRepository(database).FindByID(context.Background(), txn.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound, "transaction row should be discarded with the idempotency key")
+	})
+
+	t.Run("commit keeps both", func(t *testing.T) {
+		tx, txn := writeBoth(t, "committed-key")
+		require.NoError(t, tx.Commit())
+
+		idemKey, err := NewIdempotencyRepository(database).Get(context.Background(), "committed-key", "/api/v1/authorizations")
+		require.NoError(t, err)
+		require.NotNil(t, idemKey)
+		assert.Equal(t, &txn.ID, idemKey.TransactionID)
+
+		_, err = NewTransactionRepository(database).FindByID(context.Background(), txn.ID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIdempotencyRepository_Insert_Duplicate(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewIdempotencyRepository(database)
+	ctx := context.Background()
+
+	first := &models.IdempotencyKey{
+		Key:            "insert-key",
+		RequestPath:    "/api/v1/authorizations",
+		ResponseStatus: 200,
+		ResponseBody:   `{"id":"first"}`,
+	}
+	require.NoError(t, repo.Insert(ctx, first))
+
+	second := &models.IdempotencyKey{
+		Key:            "insert-key",
+		RequestPath:    "/api/v1/authorizations",
+		ResponseStatus: 200,
+		ResponseBody:   `{"id":"second"}`,
+	}
+	err := repo.Insert(ctx, second)
+	assert.ErrorIs(t, err, models.ErrDuplicateIdempotencyKey)
+
+	stored, err := repo.Get(ctx, "insert-key", "/api/v1/authorizations")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, `{"id":"first"}`, stored.ResponseBody, "first response should be kept")
+}
+
+func TestIdempotencyRepository_GetByTransactionID(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(database)
+	transactionID := uuid.New()
+
+	// A repeat void replays the same transaction under a second key; the first request created it
+	first := time.Now().Add(-time.Minute)
+	for key, createdAt := range map[string]time.Time{"void-key-1": first, "void-key-2": time.Now()} {
+		require.NoError(t, repo.Store(ctx, &models.IdempotencyKey{
+			Key:            key,
+			RequestPath:    "/api/v1/voids",
+			ResponseStatus: 200,
+			ResponseBody:   `{"status":"voided"}`,
+			TransactionID:  &transactionID,
+			CreatedAt:      createdAt,
+		}))
+	}
+
+	idemKey, err := repo.GetByTransactionID(ctx, transactionID)
+	require.NoError(t, err)
+	require.NotNil(t, idemKey)
+	assert.Equal(t, "void-key-1", idemKey.Key)
+	assert.Equal(t, &transactionID, idemKey.TransactionID)
+
+	missing, err := repo.GetByTransactionID(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
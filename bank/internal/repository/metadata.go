@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// defaultMetadataSchema bounds free-form metadata: at most 32 top-level keys, scalar values or one
+// level of nested objects, and strings of at most 512 characters
+const defaultMetadataSchema = `{
+	"type": "object",
+	"maxProperties": 32,
+	"additionalProperties": {
+		"anyOf": [
+			{"type": "string", "maxLength": 512},
+			{"type": "number"},
+			{"type": "boolean"},
+			{
+				"type": "object",
+				"maxProperties": 16,
+				"additionalProperties": {
+					"anyOf": [
+						{"type": "string", "maxLength": 512},
+						{"type": "number"},
+						{"type": "boolean"}
+					]
+				}
+			}
+		]
+	}
+}`
+
+// MetadataSchemas validates transaction metadata against a JSON Schema chosen by transaction type
+type MetadataSchemas struct {
+	byType   map[models.TransactionType]*openapi3.Schema
+	fallback *openapi3.Schema
+}
+
+// NewMetadataSchemas creates MetadataSchemas from per-type schemas
+// Types without a schema of their own are validated against fallback, or the built-in default when nil
+func NewMetadataSchemas(fallback *openapi3.Schema, byType map[models.TransactionType]*openapi3.Schema) (*MetadataSchemas, error) {
+	if fallback == nil {
+		fallback = &openapi3.Schema{}
+		if err := json.Unmarshal([]byte(defaultMetadataSchema), fallback); err != nil {
+			return nil, fmt.Errorf("failed to parse default metadata schema: %w", err)
+		}
+	}
+
+	for txnType, schema := range byType {
+		if err := schema.Validate(context.Background()); err != nil {
+			return nil, fmt.Errorf("invalid metadata schema for %s: %w", txnType, err)
+		}
+	}
+	if err := fallback.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid default metadata schema: %w", err)
+	}
+
+	return &MetadataSchemas{byType: byType, fallback: fallback}, nil
+}
+
+// Validate checks metadata against the schema for txnType, returning an error wrapping
+// models.ErrInvalidMetadata when it does not conform
+func (s *MetadataSchemas) Validate(txnType models.TransactionType, metadata map[string]any) error {
+	schema, ok := s.byType[txnType]
+	if !ok {
+		schema = s.fallback
+	}
+
+	// Round-trip through JSON so Go values such as structs and ints are checked in the form they are stored
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	if err := schema.VisitJSON(value); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrInvalidMetadata, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataSchemas_DefaultSchema(t *testing.T) {
+	schemas, err := NewMetadataSchemas(nil, nil)
+	require.NoError(t, err)
+
+	tooManyKeys := make(map[string]any, 33)
+	for i := range 33 {
+		tooManyKeys[fmt.Sprintf("key%d", i)] = i
+	}
+
+	tests := []struct {
+		metadata map[string]any
+		name     string
+		wantErr  bool
+	}{
+		{
+			name: "fx metadata",
+			metadata: map[string]any{
+				models.MetadataKeyFX: models.FXConversion{
+					OriginalAmount:    10000,
+					OriginalCurrency:  "EUR",
+					ConvertedAmount:   10842,
+					ConvertedCurrency: "USD",
+					Rate:              "1.084200",
+				},
+			},
+		},
+		{
+			name:     "scalar values",
+			metadata: map[string]any{"order_id": "ord_123", "attempt": 2, "recurring": true},
+		},
+		{
+			name:     "too many keys",
+			metadata: tooManyKeys,
+			wantErr:  true,
+		},
+		{
+			name:     "string too long",
+			metadata: map[string]any{"note": strings.Repeat("a", 513)},
+			wantErr:  true,
+		},
+		{
+			name:     "nested too deep",
+			metadata: map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}},
+			wantErr:  true,
+		},
+		{
+			name:     "array value",
+			metadata: map[string]any{"tags": []string{"a", "b"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schemas.Validate(models.TransactionTypeAuthHold, tt.metadata)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidMetadata)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMetadataSchemas_PerType(t *testing.T) {
+	refundSchema := openapi3.NewObjectSchema()
+	refundSchema.Required = []string{"reason"}
+
+	schemas, err := NewMetadataSchemas(nil, map[models.TransactionType]*openapi3.Schema{
+		models.TransactionTypeRefund: refundSchema,
+	})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, schemas.Validate(models.TransactionTypeRefund, map[string]any{}), models.ErrInvalidMetadata)
+	assert.NoError(t, schemas.Validate(models.TransactionTypeRefund, map[string]any{"reason": "duplicate"}))
+	assert.NoError(t, schemas.Validate(models.TransactionTypeCapture, map[string]any{}), "other types use the default schema")
+}
+
+func TestNewMetadataSchemas_InvalidSchema(t *testing.T) {
+	_, err := NewMetadataSchemas(&openapi3.Schema{Type: &openapi3.Types{"unknown"}}, nil)
+	require.Error(t, err)
+}
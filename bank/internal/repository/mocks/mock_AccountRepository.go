@@ -8,6 +8,8 @@ import (
 	models "github.com/benx421/payment-gateway/bank/internal/models"
 	mock "github.com/stretchr/testify/mock"
 
+	repository "github.com/benx421/payment-gateway/bank/internal/repository"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -24,22 +26,39 @@ func (_m *MockAccountRepository) EXPECT() *MockAccountRepository_Expecter {
 	return &MockAccountRepository_Expecter{mock: &_m.Mock}
 }
 
-// AdjustBalances provides a mock function with given fields: ctx, accountID, balanceDelta, availableBalanceDelta
-func (_m *MockAccountRepository) AdjustBalances(ctx context.Context, accountID uuid.UUID, balanceDelta int64, availableBalanceDelta int64) error {
-	ret := _m.Called(ctx, accountID, balanceDelta, availableBalanceDelta)
+// AdjustBalances provides a mock function with given fields: ctx, accountID, balanceDelta, availableBalanceDelta, reason
+func (_m *MockAccountRepository) AdjustBalances(ctx context.Context, accountID uuid.UUID, balanceDelta models.Money, availableBalanceDelta models.Money, reason string) (models.Money, models.Money, error) {
+	ret := _m.Called(ctx, accountID, balanceDelta, availableBalanceDelta, reason)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AdjustBalances")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, int64) error); ok {
-		r0 = rf(ctx, accountID, balanceDelta, availableBalanceDelta)
+	var r0 models.Money
+	var r1 models.Money
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Money, models.Money, string) (models.Money, models.Money, error)); ok {
+		return rf(ctx, accountID, balanceDelta, availableBalanceDelta, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Money, models.Money, string) models.Money); ok {
+		r0 = rf(ctx, accountID, balanceDelta, availableBalanceDelta, reason)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.Money)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.Money, models.Money, string) models.Money); ok {
+		r1 = rf(ctx, accountID, balanceDelta, availableBalanceDelta, reason)
+	} else {
+		r1 = ret.Get(1).(models.Money)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, models.Money, models.Money, string) error); ok {
+		r2 = rf(ctx, accountID, balanceDelta, availableBalanceDelta, reason)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // MockAccountRepository_AdjustBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustBalances'
@@ -50,25 +69,489 @@ type MockAccountRepository_AdjustBalances_Call struct {
 // AdjustBalances is a helper method to define mock.On call
 //   - ctx context.Context
 //   - accountID uuid.UUID
-//   - balanceDelta int64
-//   - availableBalanceDelta int64
-func (_e *MockAccountRepository_Expecter) AdjustBalances(ctx interface{}, accountID interface{}, balanceDelta interface{}, availableBalanceDelta interface{}) *MockAccountRepository_AdjustBalances_Call {
-	return &MockAccountRepository_AdjustBalances_Call{Call: _e.mock.On("AdjustBalances", ctx, accountID, balanceDelta, availableBalanceDelta)}
+//   - balanceDelta models.Money
+//   - availableBalanceDelta models.Money
+//   - reason string
+func (_e *MockAccountRepository_Expecter) AdjustBalances(ctx interface{}, accountID interface{}, balanceDelta interface{}, availableBalanceDelta interface{}, reason interface{}) *MockAccountRepository_AdjustBalances_Call {
+	return &MockAccountRepository_AdjustBalances_Call{Call: _e.mock.On("AdjustBalances", ctx, accountID, balanceDelta, availableBalanceDelta, reason)}
 }
 
-func (_c *MockAccountRepository_AdjustBalances_Call) Run(run func(ctx context.Context, accountID uuid.UUID, balanceDelta int64, availableBalanceDelta int64)) *MockAccountRepository_AdjustBalances_Call {
+func (_c *MockAccountRepository_AdjustBalances_Call) Run(run func(ctx context.Context, accountID uuid.UUID, balanceDelta models.Money, availableBalanceDelta models.Money, reason string)) *MockAccountRepository_AdjustBalances_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int64), args[3].(int64))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.Money), args[3].(models.Money), args[4].(string))
 	})
 	return _c
 }
 
-func (_c *MockAccountRepository_AdjustBalances_Call) Return(_a0 error) *MockAccountRepository_AdjustBalances_Call {
+func (_c *MockAccountRepository_AdjustBalances_Call) Return(balance models.Money, available models.Money, err error) *MockAccountRepository_AdjustBalances_Call {
+	_c.Call.Return(balance, available, err)
+	return _c
+}
+
+func (_c *MockAccountRepository_AdjustBalances_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.Money, models.Money, string) (models.Money, models.Money, error)) *MockAccountRepository_AdjustBalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdjustBalancesBatch provides a mock function with given fields: ctx, adjustments
+func (_m *MockAccountRepository) AdjustBalancesBatch(ctx context.Context, adjustments []repository.BalanceAdjustment) error {
+	ret := _m.Called(ctx, adjustments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustBalancesBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []repository.BalanceAdjustment) error); ok {
+		r0 = rf(ctx, adjustments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAccountRepository_AdjustBalancesBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustBalancesBatch'
+type MockAccountRepository_AdjustBalancesBatch_Call struct {
+	*mock.Call
+}
+
+// AdjustBalancesBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adjustments []repository.BalanceAdjustment
+func (_e *MockAccountRepository_Expecter) AdjustBalancesBatch(ctx interface{}, adjustments interface{}) *MockAccountRepository_AdjustBalancesBatch_Call {
+	return &MockAccountRepository_AdjustBalancesBatch_Call{Call: _e.mock.On("AdjustBalancesBatch", ctx, adjustments)}
+}
+
+func (_c *MockAccountRepository_AdjustBalancesBatch_Call) Run(run func(ctx context.Context, adjustments []repository.BalanceAdjustment)) *MockAccountRepository_AdjustBalancesBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]repository.BalanceAdjustment))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_AdjustBalancesBatch_Call) Return(_a0 error) *MockAccountRepository_AdjustBalancesBatch_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockAccountRepository_AdjustBalances_Call) RunAndReturn(run func(context.Context, uuid.UUID, int64, int64) error) *MockAccountRepository_AdjustBalances_Call {
+func (_c *MockAccountRepository_AdjustBalancesBatch_Call) RunAndReturn(run func(context.Context, []repository.BalanceAdjustment) error) *MockAccountRepository_AdjustBalancesBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AdjustBalancesIfAvailable provides a mock function with given fields: ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason
+func (_m *MockAccountRepository) AdjustBalancesIfAvailable(ctx context.Context, accountID uuid.UUID, expectedAvailable models.Money, balanceDelta models.Money, availableBalanceDelta models.Money, reason string) (models.Money, models.Money, bool, error) {
+	ret := _m.Called(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustBalancesIfAvailable")
+	}
+
+	var r0 models.Money
+	var r1 models.Money
+	var r2 bool
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Money, models.Money, models.Money, string) (models.Money, models.Money, bool, error)); ok {
+		return rf(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.Money, models.Money, models.Money, string) models.Money); ok {
+		r0 = rf(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+	} else {
+		r0 = ret.Get(0).(models.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.Money, models.Money, models.Money, string) models.Money); ok {
+		r1 = rf(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+	} else {
+		r1 = ret.Get(1).(models.Money)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, models.Money, models.Money, models.Money, string) bool); ok {
+		r2 = rf(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, uuid.UUID, models.Money, models.Money, models.Money, string) error); ok {
+		r3 = rf(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// MockAccountRepository_AdjustBalancesIfAvailable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustBalancesIfAvailable'
+type MockAccountRepository_AdjustBalancesIfAvailable_Call struct {
+	*mock.Call
+}
+
+// AdjustBalancesIfAvailable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - expectedAvailable models.Money
+//   - balanceDelta models.Money
+//   - availableBalanceDelta models.Money
+//   - reason string
+func (_e *MockAccountRepository_Expecter) AdjustBalancesIfAvailable(ctx interface{}, accountID interface{}, expectedAvailable interface{}, balanceDelta interface{}, availableBalanceDelta interface{}, reason interface{}) *MockAccountRepository_AdjustBalancesIfAvailable_Call {
+	return &MockAccountRepository_AdjustBalancesIfAvailable_Call{Call: _e.mock.On("AdjustBalancesIfAvailable", ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)}
+}
+
+func (_c *MockAccountRepository_AdjustBalancesIfAvailable_Call) Run(run func(ctx context.Context, accountID uuid.UUID, expectedAvailable models.Money, balanceDelta models.Money, availableBalanceDelta models.Money, reason string)) *MockAccountRepository_AdjustBalancesIfAvailable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.Money), args[3].(models.Money), args[4].(models.Money), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_AdjustBalancesIfAvailable_Call) Return(balance models.Money, available models.Money, updated bool, err error) *MockAccountRepository_AdjustBalancesIfAvailable_Call {
+	_c.Call.Return(balance, available, updated, err)
+	return _c
+}
+
+func (_c *MockAccountRepository_AdjustBalancesIfAvailable_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.Money, models.Money, models.Money, string) (models.Money, models.Money, bool, error)) *MockAccountRepository_AdjustBalancesIfAvailable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, account
+func (_m *MockAccountRepository) Create(ctx context.Context, account *models.Account) error {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Account) error); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAccountRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockAccountRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - account *models.Account
+func (_e *MockAccountRepository_Expecter) Create(ctx interface{}, account interface{}) *MockAccountRepository_Create_Call {
+	return &MockAccountRepository_Create_Call{Call: _e.mock.On("Create", ctx, account)}
+}
+
+func (_c *MockAccountRepository_Create_Call) Run(run func(ctx context.Context, account *models.Account)) *MockAccountRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Account))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_Create_Call) Return(_a0 error) *MockAccountRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAccountRepository_Create_Call) RunAndReturn(run func(context.Context, *models.Account) error) *MockAccountRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWithFunding provides a mock function with given fields: ctx, account
+func (_m *MockAccountRepository) CreateWithFunding(ctx context.Context, account *models.Account) (*models.Transaction, error) {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWithFunding")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Account) (*models.Transaction, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Account) *models.Transaction); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Account) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountRepository_CreateWithFunding_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWithFunding'
+type MockAccountRepository_CreateWithFunding_Call struct {
+	*mock.Call
+}
+
+// CreateWithFunding is a helper method to define mock.On call
+//   - ctx context.Context
+//   - account *models.Account
+func (_e *MockAccountRepository_Expecter) CreateWithFunding(ctx interface{}, account interface{}) *MockAccountRepository_CreateWithFunding_Call {
+	return &MockAccountRepository_CreateWithFunding_Call{Call: _e.mock.On("CreateWithFunding", ctx, account)}
+}
+
+func (_c *MockAccountRepository_CreateWithFunding_Call) Run(run func(ctx context.Context, account *models.Account)) *MockAccountRepository_CreateWithFunding_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Account))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_CreateWithFunding_Call) Return(_a0 *models.Transaction, _a1 error) *MockAccountRepository_CreateWithFunding_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountRepository_CreateWithFunding_Call) RunAndReturn(run func(context.Context, *models.Account) (*models.Transaction, error)) *MockAccountRepository_CreateWithFunding_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindBalanceBreakdown provides a mock function with given fields: ctx, accountID
+func (_m *MockAccountRepository) FindBalanceBreakdown(ctx context.Context, accountID uuid.UUID) (*models.BalanceBreakdown, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBalanceBreakdown")
+	}
+
+	var r0 *models.BalanceBreakdown
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.BalanceBreakdown, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.BalanceBreakdown); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BalanceBreakdown)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountRepository_FindBalanceBreakdown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindBalanceBreakdown'
+type MockAccountRepository_FindBalanceBreakdown_Call struct {
+	*mock.Call
+}
+
+// FindBalanceBreakdown is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+func (_e *MockAccountRepository_Expecter) FindBalanceBreakdown(ctx interface{}, accountID interface{}) *MockAccountRepository_FindBalanceBreakdown_Call {
+	return &MockAccountRepository_FindBalanceBreakdown_Call{Call: _e.mock.On("FindBalanceBreakdown", ctx, accountID)}
+}
+
+func (_c *MockAccountRepository_FindBalanceBreakdown_Call) Run(run func(ctx context.Context, accountID uuid.UUID)) *MockAccountRepository_FindBalanceBreakdown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalanceBreakdown_Call) Return(_a0 *models.BalanceBreakdown, _a1 error) *MockAccountRepository_FindBalanceBreakdown_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalanceBreakdown_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.BalanceBreakdown, error)) *MockAccountRepository_FindBalanceBreakdown_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindBalanceDrift provides a mock function with given fields: ctx, accountID
+func (_m *MockAccountRepository) FindBalanceDrift(ctx context.Context, accountID uuid.UUID) (*models.BalanceDrift, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBalanceDrift")
+	}
+
+	var r0 *models.BalanceDrift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.BalanceDrift, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.BalanceDrift); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BalanceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountRepository_FindBalanceDrift_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindBalanceDrift'
+type MockAccountRepository_FindBalanceDrift_Call struct {
+	*mock.Call
+}
+
+// FindBalanceDrift is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+func (_e *MockAccountRepository_Expecter) FindBalanceDrift(ctx interface{}, accountID interface{}) *MockAccountRepository_FindBalanceDrift_Call {
+	return &MockAccountRepository_FindBalanceDrift_Call{Call: _e.mock.On("FindBalanceDrift", ctx, accountID)}
+}
+
+func (_c *MockAccountRepository_FindBalanceDrift_Call) Run(run func(ctx context.Context, accountID uuid.UUID)) *MockAccountRepository_FindBalanceDrift_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalanceDrift_Call) Return(_a0 *models.BalanceDrift, _a1 error) *MockAccountRepository_FindBalanceDrift_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalanceDrift_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.BalanceDrift, error)) *MockAccountRepository_FindBalanceDrift_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindBalanceDrifts provides a mock function with given fields: ctx
+func (_m *MockAccountRepository) FindBalanceDrifts(ctx context.Context) ([]*models.BalanceDrift, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBalanceDrifts")
+	}
+
+	var r0 []*models.BalanceDrift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.BalanceDrift, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.BalanceDrift); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.BalanceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountRepository_FindBalanceDrifts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindBalanceDrifts'
+type MockAccountRepository_FindBalanceDrifts_Call struct {
+	*mock.Call
+}
+
+// FindBalanceDrifts is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAccountRepository_Expecter) FindBalanceDrifts(ctx interface{}) *MockAccountRepository_FindBalanceDrifts_Call {
+	return &MockAccountRepository_FindBalanceDrifts_Call{Call: _e.mock.On("FindBalanceDrifts", ctx)}
+}
+
+func (_c *MockAccountRepository_FindBalanceDrifts_Call) Run(run func(ctx context.Context)) *MockAccountRepository_FindBalanceDrifts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalanceDrifts_Call) Return(_a0 []*models.BalanceDrift, _a1 error) *MockAccountRepository_FindBalanceDrifts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalanceDrifts_Call) RunAndReturn(run func(context.Context) ([]*models.BalanceDrift, error)) *MockAccountRepository_FindBalanceDrifts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindBalances provides a mock function with given fields: ctx, accountID
+func (_m *MockAccountRepository) FindBalances(ctx context.Context, accountID uuid.UUID) ([]*models.Balance, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBalances")
+	}
+
+	var r0 []*models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*models.Balance, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*models.Balance); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountRepository_FindBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindBalances'
+type MockAccountRepository_FindBalances_Call struct {
+	*mock.Call
+}
+
+// FindBalances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+func (_e *MockAccountRepository_Expecter) FindBalances(ctx interface{}, accountID interface{}) *MockAccountRepository_FindBalances_Call {
+	return &MockAccountRepository_FindBalances_Call{Call: _e.mock.On("FindBalances", ctx, accountID)}
+}
+
+func (_c *MockAccountRepository_FindBalances_Call) Run(run func(ctx context.Context, accountID uuid.UUID)) *MockAccountRepository_FindBalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalances_Call) Return(_a0 []*models.Balance, _a1 error) *MockAccountRepository_FindBalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountRepository_FindBalances_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]*models.Balance, error)) *MockAccountRepository_FindBalances_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -250,6 +733,115 @@ func (_c *MockAccountRepository_FindByID_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// ListLowBalance provides a mock function with given fields: ctx, thresholdCents, limit, offset
+func (_m *MockAccountRepository) ListLowBalance(ctx context.Context, thresholdCents int64, limit int, offset int) ([]*models.Account, error) {
+	ret := _m.Called(ctx, thresholdCents, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLowBalance")
+	}
+
+	var r0 []*models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*models.Account, error)); ok {
+		return rf(ctx, thresholdCents, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*models.Account); ok {
+		r0 = rf(ctx, thresholdCents, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, thresholdCents, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountRepository_ListLowBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLowBalance'
+type MockAccountRepository_ListLowBalance_Call struct {
+	*mock.Call
+}
+
+// ListLowBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - thresholdCents int64
+//   - limit int
+//   - offset int
+func (_e *MockAccountRepository_Expecter) ListLowBalance(ctx interface{}, thresholdCents interface{}, limit interface{}, offset interface{}) *MockAccountRepository_ListLowBalance_Call {
+	return &MockAccountRepository_ListLowBalance_Call{Call: _e.mock.On("ListLowBalance", ctx, thresholdCents, limit, offset)}
+}
+
+func (_c *MockAccountRepository_ListLowBalance_Call) Run(run func(ctx context.Context, thresholdCents int64, limit int, offset int)) *MockAccountRepository_ListLowBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_ListLowBalance_Call) Return(_a0 []*models.Account, _a1 error) *MockAccountRepository_ListLowBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountRepository_ListLowBalance_Call) RunAndReturn(run func(context.Context, int64, int, int) ([]*models.Account, error)) *MockAccountRepository_ListLowBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OpenBalance provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockAccountRepository) OpenBalance(ctx context.Context, accountID uuid.UUID, currency string) error {
+	ret := _m.Called(ctx, accountID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenBalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, accountID, currency)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAccountRepository_OpenBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OpenBalance'
+type MockAccountRepository_OpenBalance_Call struct {
+	*mock.Call
+}
+
+// OpenBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - currency string
+func (_e *MockAccountRepository_Expecter) OpenBalance(ctx interface{}, accountID interface{}, currency interface{}) *MockAccountRepository_OpenBalance_Call {
+	return &MockAccountRepository_OpenBalance_Call{Call: _e.mock.On("OpenBalance", ctx, accountID, currency)}
+}
+
+func (_c *MockAccountRepository_OpenBalance_Call) Run(run func(ctx context.Context, accountID uuid.UUID, currency string)) *MockAccountRepository_OpenBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAccountRepository_OpenBalance_Call) Return(_a0 error) *MockAccountRepository_OpenBalance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAccountRepository_OpenBalance_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockAccountRepository_OpenBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockAccountRepository creates a new instance of MockAccountRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockAccountRepository(t interface {
@@ -8,6 +8,12 @@ import (
 	models "github.com/benx421/payment-gateway/bank/internal/models"
 	mock "github.com/stretchr/testify/mock"
 
+	repository "github.com/benx421/payment-gateway/bank/internal/repository"
+
+	sql "database/sql"
+
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -24,6 +30,122 @@ func (_m *MockTransactionRepository) EXPECT() *MockTransactionRepository_Expecte
 	return &MockTransactionRepository_Expecter{mock: &_m.Mock}
 }
 
+// Archive provides a mock function with given fields: ctx, before
+func (_m *MockTransactionRepository) Archive(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Archive")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_Archive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Archive'
+type MockTransactionRepository_Archive_Call struct {
+	*mock.Call
+}
+
+// Archive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockTransactionRepository_Expecter) Archive(ctx interface{}, before interface{}) *MockTransactionRepository_Archive_Call {
+	return &MockTransactionRepository_Archive_Call{Call: _e.mock.On("Archive", ctx, before)}
+}
+
+func (_c *MockTransactionRepository_Archive_Call) Run(run func(ctx context.Context, before time.Time)) *MockTransactionRepository_Archive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_Archive_Call) Return(_a0 int64, _a1 error) *MockTransactionRepository_Archive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_Archive_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockTransactionRepository_Archive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountByAccountSince provides a mock function with given fields: ctx, accountID, txnType, since
+func (_m *MockTransactionRepository) CountByAccountSince(ctx context.Context, accountID uuid.UUID, txnType models.TransactionType, since time.Time) (int, error) {
+	ret := _m.Called(ctx, accountID, txnType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByAccountSince")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType, time.Time) (int, error)); ok {
+		return rf(ctx, accountID, txnType, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType, time.Time) int); ok {
+		r0 = rf(ctx, accountID, txnType, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.TransactionType, time.Time) error); ok {
+		r1 = rf(ctx, accountID, txnType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_CountByAccountSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountByAccountSince'
+type MockTransactionRepository_CountByAccountSince_Call struct {
+	*mock.Call
+}
+
+// CountByAccountSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - txnType models.TransactionType
+//   - since time.Time
+func (_e *MockTransactionRepository_Expecter) CountByAccountSince(ctx interface{}, accountID interface{}, txnType interface{}, since interface{}) *MockTransactionRepository_CountByAccountSince_Call {
+	return &MockTransactionRepository_CountByAccountSince_Call{Call: _e.mock.On("CountByAccountSince", ctx, accountID, txnType, since)}
+}
+
+func (_c *MockTransactionRepository_CountByAccountSince_Call) Run(run func(ctx context.Context, accountID uuid.UUID, txnType models.TransactionType, since time.Time)) *MockTransactionRepository_CountByAccountSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.TransactionType), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_CountByAccountSince_Call) Return(_a0 int, _a1 error) *MockTransactionRepository_CountByAccountSince_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_CountByAccountSince_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.TransactionType, time.Time) (int, error)) *MockTransactionRepository_CountByAccountSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, tx
 func (_m *MockTransactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
 	ret := _m.Called(ctx, tx)
@@ -71,29 +193,77 @@ func (_c *MockTransactionRepository_Create_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
-// FindByID provides a mock function with given fields: ctx, id
-func (_m *MockTransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
-	ret := _m.Called(ctx, id)
+// CreateDeclined provides a mock function with given fields: ctx, txn, reason
+func (_m *MockTransactionRepository) CreateDeclined(ctx context.Context, txn *models.Transaction, reason models.DeclineReason) error {
+	ret := _m.Called(ctx, txn, reason)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindByID")
+		panic("no return value specified for CreateDeclined")
 	}
 
-	var r0 *models.Transaction
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Transaction, models.DeclineReason) error); ok {
+		r0 = rf(ctx, txn, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionRepository_CreateDeclined_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeclined'
+type MockTransactionRepository_CreateDeclined_Call struct {
+	*mock.Call
+}
+
+// CreateDeclined is a helper method to define mock.On call
+//   - ctx context.Context
+//   - txn *models.Transaction
+//   - reason models.DeclineReason
+func (_e *MockTransactionRepository_Expecter) CreateDeclined(ctx interface{}, txn interface{}, reason interface{}) *MockTransactionRepository_CreateDeclined_Call {
+	return &MockTransactionRepository_CreateDeclined_Call{Call: _e.mock.On("CreateDeclined", ctx, txn, reason)}
+}
+
+func (_c *MockTransactionRepository_CreateDeclined_Call) Run(run func(ctx context.Context, txn *models.Transaction, reason models.DeclineReason)) *MockTransactionRepository_CreateDeclined_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Transaction), args[2].(models.DeclineReason))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_CreateDeclined_Call) Return(_a0 error) *MockTransactionRepository_CreateDeclined_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionRepository_CreateDeclined_Call) RunAndReturn(run func(context.Context, *models.Transaction, models.DeclineReason) error) *MockTransactionRepository_CreateDeclined_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportByDateRange provides a mock function with given fields: ctx, from, to
+func (_m *MockTransactionRepository) ExportByDateRange(ctx context.Context, from time.Time, to time.Time) (*sql.Rows, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportByDateRange")
+	}
+
+	var r0 *sql.Rows
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Transaction, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (*sql.Rows, error)); ok {
+		return rf(ctx, from, to)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Transaction); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) *sql.Rows); ok {
+		r0 = rf(ctx, from, to)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Transaction)
+			r0 = ret.Get(0).(*sql.Rows)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -101,41 +271,174 @@ func (_m *MockTransactionRepository) FindByID(ctx context.Context, id uuid.UUID)
 	return r0, r1
 }
 
-// MockTransactionRepository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
-type MockTransactionRepository_FindByID_Call struct {
+// MockTransactionRepository_ExportByDateRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportByDateRange'
+type MockTransactionRepository_ExportByDateRange_Call struct {
 	*mock.Call
 }
 
-// FindByID is a helper method to define mock.On call
+// ExportByDateRange is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockTransactionRepository_Expecter) FindByID(ctx interface{}, id interface{}) *MockTransactionRepository_FindByID_Call {
-	return &MockTransactionRepository_FindByID_Call{Call: _e.mock.On("FindByID", ctx, id)}
+//   - from time.Time
+//   - to time.Time
+func (_e *MockTransactionRepository_Expecter) ExportByDateRange(ctx interface{}, from interface{}, to interface{}) *MockTransactionRepository_ExportByDateRange_Call {
+	return &MockTransactionRepository_ExportByDateRange_Call{Call: _e.mock.On("ExportByDateRange", ctx, from, to)}
 }
 
-func (_c *MockTransactionRepository_FindByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTransactionRepository_FindByID_Call {
+func (_c *MockTransactionRepository_ExportByDateRange_Call) Run(run func(ctx context.Context, from time.Time, to time.Time)) *MockTransactionRepository_ExportByDateRange_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
 	})
 	return _c
 }
 
-func (_c *MockTransactionRepository_FindByID_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindByID_Call {
+func (_c *MockTransactionRepository_ExportByDateRange_Call) Return(_a0 *sql.Rows, _a1 error) *MockTransactionRepository_ExportByDateRange_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockTransactionRepository_FindByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Transaction, error)) *MockTransactionRepository_FindByID_Call {
+func (_c *MockTransactionRepository_ExportByDateRange_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) (*sql.Rows, error)) *MockTransactionRepository_ExportByDateRange_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindByIDForUpdate provides a mock function with given fields: ctx, id
-func (_m *MockTransactionRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+// Find provides a mock function with given fields: ctx, filter
+func (_m *MockTransactionRepository) Find(ctx context.Context, filter repository.TransactionFilter) ([]*models.Transaction, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []*models.Transaction
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) ([]*models.Transaction, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) []*models.Transaction); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.TransactionFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.TransactionFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockTransactionRepository_Find_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Find'
+type MockTransactionRepository_Find_Call struct {
+	*mock.Call
+}
+
+// Find is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.TransactionFilter
+func (_e *MockTransactionRepository_Expecter) Find(ctx interface{}, filter interface{}) *MockTransactionRepository_Find_Call {
+	return &MockTransactionRepository_Find_Call{Call: _e.mock.On("Find", ctx, filter)}
+}
+
+func (_c *MockTransactionRepository_Find_Call) Run(run func(ctx context.Context, filter repository.TransactionFilter)) *MockTransactionRepository_Find_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.TransactionFilter))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_Find_Call) Return(_a0 []*models.Transaction, _a1 int, _a2 error) *MockTransactionRepository_Find_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockTransactionRepository_Find_Call) RunAndReturn(run func(context.Context, repository.TransactionFilter) ([]*models.Transaction, int, error)) *MockTransactionRepository_Find_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindArchived provides a mock function with given fields: ctx, filter
+func (_m *MockTransactionRepository) FindArchived(ctx context.Context, filter repository.TransactionFilter) ([]*models.Transaction, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindArchived")
+	}
+
+	var r0 []*models.Transaction
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) ([]*models.Transaction, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter) []*models.Transaction); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.TransactionFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.TransactionFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockTransactionRepository_FindArchived_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindArchived'
+type MockTransactionRepository_FindArchived_Call struct {
+	*mock.Call
+}
+
+// FindArchived is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.TransactionFilter
+func (_e *MockTransactionRepository_Expecter) FindArchived(ctx interface{}, filter interface{}) *MockTransactionRepository_FindArchived_Call {
+	return &MockTransactionRepository_FindArchived_Call{Call: _e.mock.On("FindArchived", ctx, filter)}
+}
+
+func (_c *MockTransactionRepository_FindArchived_Call) Run(run func(ctx context.Context, filter repository.TransactionFilter)) *MockTransactionRepository_FindArchived_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.TransactionFilter))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindArchived_Call) Return(_a0 []*models.Transaction, _a1 int, _a2 error) *MockTransactionRepository_FindArchived_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindArchived_Call) RunAndReturn(run func(context.Context, repository.TransactionFilter) ([]*models.Transaction, int, error)) *MockTransactionRepository_FindArchived_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindArchivedByID provides a mock function with given fields: ctx, id
+func (_m *MockTransactionRepository) FindArchivedByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindByIDForUpdate")
+		panic("no return value specified for FindArchivedByID")
 	}
 
 	var r0 *models.Transaction
@@ -160,91 +463,1000 @@ func (_m *MockTransactionRepository) FindByIDForUpdate(ctx context.Context, id u
 	return r0, r1
 }
 
-// MockTransactionRepository_FindByIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByIDForUpdate'
-type MockTransactionRepository_FindByIDForUpdate_Call struct {
+// MockTransactionRepository_FindArchivedByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindArchivedByID'
+type MockTransactionRepository_FindArchivedByID_Call struct {
 	*mock.Call
 }
 
-// FindByIDForUpdate is a helper method to define mock.On call
+// FindArchivedByID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockTransactionRepository_Expecter) FindByIDForUpdate(ctx interface{}, id interface{}) *MockTransactionRepository_FindByIDForUpdate_Call {
-	return &MockTransactionRepository_FindByIDForUpdate_Call{Call: _e.mock.On("FindByIDForUpdate", ctx, id)}
+func (_e *MockTransactionRepository_Expecter) FindArchivedByID(ctx interface{}, id interface{}) *MockTransactionRepository_FindArchivedByID_Call {
+	return &MockTransactionRepository_FindArchivedByID_Call{Call: _e.mock.On("FindArchivedByID", ctx, id)}
 }
 
-func (_c *MockTransactionRepository_FindByIDForUpdate_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTransactionRepository_FindByIDForUpdate_Call {
+func (_c *MockTransactionRepository_FindArchivedByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTransactionRepository_FindArchivedByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *MockTransactionRepository_FindByIDForUpdate_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindByIDForUpdate_Call {
+func (_c *MockTransactionRepository_FindArchivedByID_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindArchivedByID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockTransactionRepository_FindByIDForUpdate_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Transaction, error)) *MockTransactionRepository_FindByIDForUpdate_Call {
+func (_c *MockTransactionRepository_FindArchivedByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Transaction, error)) *MockTransactionRepository_FindArchivedByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindByReferenceID provides a mock function with given fields: ctx, refID, txnType
-func (_m *MockTransactionRepository) FindByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (*models.Transaction, error) {
-	ret := _m.Called(ctx, refID, txnType)
+// FindAuthorizations provides a mock function with given fields: ctx, accountID, limit, offset
+func (_m *MockTransactionRepository) FindAuthorizations(ctx context.Context, accountID uuid.UUID, limit int, offset int) ([]*models.Transaction, int, error) {
+	ret := _m.Called(ctx, accountID, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindByReferenceID")
+		panic("no return value specified for FindAuthorizations")
 	}
 
-	var r0 *models.Transaction
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType) (*models.Transaction, error)); ok {
-		return rf(ctx, refID, txnType)
+	var r0 []*models.Transaction
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]*models.Transaction, int, error)); ok {
+		return rf(ctx, accountID, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType) *models.Transaction); ok {
-		r0 = rf(ctx, refID, txnType)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []*models.Transaction); ok {
+		r0 = rf(ctx, accountID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Transaction)
+			r0 = ret.Get(0).([]*models.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.TransactionType) error); ok {
-		r1 = rf(ctx, refID, txnType)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) int); ok {
+		r1 = rf(ctx, accountID, limit, offset)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r2 = rf(ctx, accountID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// MockTransactionRepository_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
-type MockTransactionRepository_FindByReferenceID_Call struct {
+// MockTransactionRepository_FindAuthorizations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAuthorizations'
+type MockTransactionRepository_FindAuthorizations_Call struct {
 	*mock.Call
 }
 
-// FindByReferenceID is a helper method to define mock.On call
+// FindAuthorizations is a helper method to define mock.On call
 //   - ctx context.Context
-//   - refID uuid.UUID
-//   - txnType models.TransactionType
-func (_e *MockTransactionRepository_Expecter) FindByReferenceID(ctx interface{}, refID interface{}, txnType interface{}) *MockTransactionRepository_FindByReferenceID_Call {
-	return &MockTransactionRepository_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, refID, txnType)}
+//   - accountID uuid.UUID
+//   - limit int
+//   - offset int
+func (_e *MockTransactionRepository_Expecter) FindAuthorizations(ctx interface{}, accountID interface{}, limit interface{}, offset interface{}) *MockTransactionRepository_FindAuthorizations_Call {
+	return &MockTransactionRepository_FindAuthorizations_Call{Call: _e.mock.On("FindAuthorizations", ctx, accountID, limit, offset)}
 }
 
-func (_c *MockTransactionRepository_FindByReferenceID_Call) Run(run func(ctx context.Context, refID uuid.UUID, txnType models.TransactionType)) *MockTransactionRepository_FindByReferenceID_Call {
+func (_c *MockTransactionRepository_FindAuthorizations_Call) Run(run func(ctx context.Context, accountID uuid.UUID, limit int, offset int)) *MockTransactionRepository_FindAuthorizations_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.TransactionType))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(int))
 	})
 	return _c
 }
 
-func (_c *MockTransactionRepository_FindByReferenceID_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindByReferenceID_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockTransactionRepository_FindAuthorizations_Call) Return(_a0 []*models.Transaction, _a1 int, _a2 error) *MockTransactionRepository_FindAuthorizations_Call {
+	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *MockTransactionRepository_FindByReferenceID_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.TransactionType) (*models.Transaction, error)) *MockTransactionRepository_FindByReferenceID_Call {
+func (_c *MockTransactionRepository_FindAuthorizations_Call) RunAndReturn(run func(context.Context, uuid.UUID, int, int) ([]*models.Transaction, int, error)) *MockTransactionRepository_FindAuthorizations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *MockTransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
+type MockTransactionRepository_FindByID_Call struct {
+	*mock.Call
+}
+
+// FindByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockTransactionRepository_Expecter) FindByID(ctx interface{}, id interface{}) *MockTransactionRepository_FindByID_Call {
+	return &MockTransactionRepository_FindByID_Call{Call: _e.mock.On("FindByID", ctx, id)}
+}
+
+func (_c *MockTransactionRepository_FindByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTransactionRepository_FindByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByID_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Transaction, error)) *MockTransactionRepository_FindByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *MockTransactionRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIDForUpdate")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_FindByIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByIDForUpdate'
+type MockTransactionRepository_FindByIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// FindByIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockTransactionRepository_Expecter) FindByIDForUpdate(ctx interface{}, id interface{}) *MockTransactionRepository_FindByIDForUpdate_Call {
+	return &MockTransactionRepository_FindByIDForUpdate_Call{Call: _e.mock.On("FindByIDForUpdate", ctx, id)}
+}
+
+func (_c *MockTransactionRepository_FindByIDForUpdate_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTransactionRepository_FindByIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByIDForUpdate_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindByIDForUpdate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByIDForUpdate_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Transaction, error)) *MockTransactionRepository_FindByIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByIDs provides a mock function with given fields: ctx, ids
+func (_m *MockTransactionRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Transaction, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIDs")
+	}
+
+	var r0 map[uuid.UUID]*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (map[uuid.UUID]*models.Transaction, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) map[uuid.UUID]*models.Transaction); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_FindByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByIDs'
+type MockTransactionRepository_FindByIDs_Call struct {
+	*mock.Call
+}
+
+// FindByIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+func (_e *MockTransactionRepository_Expecter) FindByIDs(ctx interface{}, ids interface{}) *MockTransactionRepository_FindByIDs_Call {
+	return &MockTransactionRepository_FindByIDs_Call{Call: _e.mock.On("FindByIDs", ctx, ids)}
+}
+
+func (_c *MockTransactionRepository_FindByIDs_Call) Run(run func(ctx context.Context, ids []uuid.UUID)) *MockTransactionRepository_FindByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByIDs_Call) Return(_a0 map[uuid.UUID]*models.Transaction, _a1 error) *MockTransactionRepository_FindByIDs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByIDs_Call) RunAndReturn(run func(context.Context, []uuid.UUID) (map[uuid.UUID]*models.Transaction, error)) *MockTransactionRepository_FindByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByMetadata provides a mock function with given fields: ctx, key, value, limit
+func (_m *MockTransactionRepository) FindByMetadata(ctx context.Context, key string, value string, limit int) ([]*models.Transaction, error) {
+	ret := _m.Called(ctx, key, value, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByMetadata")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]*models.Transaction, error)); ok {
+		return rf(ctx, key, value, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []*models.Transaction); ok {
+		r0 = rf(ctx, key, value, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, key, value, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_FindByMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByMetadata'
+type MockTransactionRepository_FindByMetadata_Call struct {
+	*mock.Call
+}
+
+// FindByMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - value string
+//   - limit int
+func (_e *MockTransactionRepository_Expecter) FindByMetadata(ctx interface{}, key interface{}, value interface{}, limit interface{}) *MockTransactionRepository_FindByMetadata_Call {
+	return &MockTransactionRepository_FindByMetadata_Call{Call: _e.mock.On("FindByMetadata", ctx, key, value, limit)}
+}
+
+func (_c *MockTransactionRepository_FindByMetadata_Call) Run(run func(ctx context.Context, key string, value string, limit int)) *MockTransactionRepository_FindByMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByMetadata_Call) Return(_a0 []*models.Transaction, _a1 error) *MockTransactionRepository_FindByMetadata_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByMetadata_Call) RunAndReturn(run func(context.Context, string, string, int) ([]*models.Transaction, error)) *MockTransactionRepository_FindByMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByReferenceID provides a mock function with given fields: ctx, refID, txnType
+func (_m *MockTransactionRepository) FindByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (*models.Transaction, error) {
+	ret := _m.Called(ctx, refID, txnType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByReferenceID")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType) (*models.Transaction, error)); ok {
+		return rf(ctx, refID, txnType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType) *models.Transaction); ok {
+		r0 = rf(ctx, refID, txnType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.TransactionType) error); ok {
+		r1 = rf(ctx, refID, txnType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
+type MockTransactionRepository_FindByReferenceID_Call struct {
+	*mock.Call
+}
+
+// FindByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refID uuid.UUID
+//   - txnType models.TransactionType
+func (_e *MockTransactionRepository_Expecter) FindByReferenceID(ctx interface{}, refID interface{}, txnType interface{}) *MockTransactionRepository_FindByReferenceID_Call {
+	return &MockTransactionRepository_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, refID, txnType)}
+}
+
+func (_c *MockTransactionRepository_FindByReferenceID_Call) Run(run func(ctx context.Context, refID uuid.UUID, txnType models.TransactionType)) *MockTransactionRepository_FindByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.TransactionType))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByReferenceID_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionRepository_FindByReferenceID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindByReferenceID_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.TransactionType) (*models.Transaction, error)) *MockTransactionRepository_FindByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindCaptures provides a mock function with given fields: ctx, accountID, limit, offset
+func (_m *MockTransactionRepository) FindCaptures(ctx context.Context, accountID uuid.UUID, limit int, offset int) ([]*models.Transaction, int, error) {
+	ret := _m.Called(ctx, accountID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCaptures")
+	}
+
+	var r0 []*models.Transaction
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]*models.Transaction, int, error)); ok {
+		return rf(ctx, accountID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []*models.Transaction); ok {
+		r0 = rf(ctx, accountID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) int); ok {
+		r1 = rf(ctx, accountID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r2 = rf(ctx, accountID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockTransactionRepository_FindCaptures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCaptures'
+type MockTransactionRepository_FindCaptures_Call struct {
+	*mock.Call
+}
+
+// FindCaptures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - limit int
+//   - offset int
+func (_e *MockTransactionRepository_Expecter) FindCaptures(ctx interface{}, accountID interface{}, limit interface{}, offset interface{}) *MockTransactionRepository_FindCaptures_Call {
+	return &MockTransactionRepository_FindCaptures_Call{Call: _e.mock.On("FindCaptures", ctx, accountID, limit, offset)}
+}
+
+func (_c *MockTransactionRepository_FindCaptures_Call) Run(run func(ctx context.Context, accountID uuid.UUID, limit int, offset int)) *MockTransactionRepository_FindCaptures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindCaptures_Call) Return(_a0 []*models.Transaction, _a1 int, _a2 error) *MockTransactionRepository_FindCaptures_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindCaptures_Call) RunAndReturn(run func(context.Context, uuid.UUID, int, int) ([]*models.Transaction, int, error)) *MockTransactionRepository_FindCaptures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindRefunds provides a mock function with given fields: ctx, accountID, limit, offset
+func (_m *MockTransactionRepository) FindRefunds(ctx context.Context, accountID uuid.UUID, limit int, offset int) ([]*models.Transaction, int, error) {
+	ret := _m.Called(ctx, accountID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRefunds")
+	}
+
+	var r0 []*models.Transaction
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]*models.Transaction, int, error)); ok {
+		return rf(ctx, accountID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []*models.Transaction); ok {
+		r0 = rf(ctx, accountID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) int); ok {
+		r1 = rf(ctx, accountID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r2 = rf(ctx, accountID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockTransactionRepository_FindRefunds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRefunds'
+type MockTransactionRepository_FindRefunds_Call struct {
+	*mock.Call
+}
+
+// FindRefunds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - limit int
+//   - offset int
+func (_e *MockTransactionRepository_Expecter) FindRefunds(ctx interface{}, accountID interface{}, limit interface{}, offset interface{}) *MockTransactionRepository_FindRefunds_Call {
+	return &MockTransactionRepository_FindRefunds_Call{Call: _e.mock.On("FindRefunds", ctx, accountID, limit, offset)}
+}
+
+func (_c *MockTransactionRepository_FindRefunds_Call) Run(run func(ctx context.Context, accountID uuid.UUID, limit int, offset int)) *MockTransactionRepository_FindRefunds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindRefunds_Call) Return(_a0 []*models.Transaction, _a1 int, _a2 error) *MockTransactionRepository_FindRefunds_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockTransactionRepository_FindRefunds_Call) RunAndReturn(run func(context.Context, uuid.UUID, int, int) ([]*models.Transaction, int, error)) *MockTransactionRepository_FindRefunds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Iterate provides a mock function with given fields: ctx, filter, fn
+func (_m *MockTransactionRepository) Iterate(ctx context.Context, filter repository.TransactionFilter, fn func(*models.Transaction) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Iterate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TransactionFilter, func(*models.Transaction) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionRepository_Iterate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Iterate'
+type MockTransactionRepository_Iterate_Call struct {
+	*mock.Call
+}
+
+// Iterate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.TransactionFilter
+//   - fn func(*models.Transaction) error
+func (_e *MockTransactionRepository_Expecter) Iterate(ctx interface{}, filter interface{}, fn interface{}) *MockTransactionRepository_Iterate_Call {
+	return &MockTransactionRepository_Iterate_Call{Call: _e.mock.On("Iterate", ctx, filter, fn)}
+}
+
+func (_c *MockTransactionRepository_Iterate_Call) Run(run func(ctx context.Context, filter repository.TransactionFilter, fn func(*models.Transaction) error)) *MockTransactionRepository_Iterate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.TransactionFilter), args[2].(func(*models.Transaction) error))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_Iterate_Call) Return(_a0 error) *MockTransactionRepository_Iterate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionRepository_Iterate_Call) RunAndReturn(run func(context.Context, repository.TransactionFilter, func(*models.Transaction) error) error) *MockTransactionRepository_Iterate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAfter provides a mock function with given fields: ctx, accountID, afterCreatedAt, afterID, limit
+func (_m *MockTransactionRepository) ListAfter(ctx context.Context, accountID uuid.UUID, afterCreatedAt time.Time, afterID uuid.UUID, limit int) ([]*models.Transaction, *repository.TransactionCursor, error) {
+	ret := _m.Called(ctx, accountID, afterCreatedAt, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAfter")
+	}
+
+	var r0 []*models.Transaction
+	var r1 *repository.TransactionCursor
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, uuid.UUID, int) ([]*models.Transaction, *repository.TransactionCursor, error)); ok {
+		return rf(ctx, accountID, afterCreatedAt, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, uuid.UUID, int) []*models.Transaction); ok {
+		r0 = rf(ctx, accountID, afterCreatedAt, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, uuid.UUID, int) *repository.TransactionCursor); ok {
+		r1 = rf(ctx, accountID, afterCreatedAt, afterID, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*repository.TransactionCursor)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, time.Time, uuid.UUID, int) error); ok {
+		r2 = rf(ctx, accountID, afterCreatedAt, afterID, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockTransactionRepository_ListAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAfter'
+type MockTransactionRepository_ListAfter_Call struct {
+	*mock.Call
+}
+
+// ListAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - afterCreatedAt time.Time
+//   - afterID uuid.UUID
+//   - limit int
+func (_e *MockTransactionRepository_Expecter) ListAfter(ctx interface{}, accountID interface{}, afterCreatedAt interface{}, afterID interface{}, limit interface{}) *MockTransactionRepository_ListAfter_Call {
+	return &MockTransactionRepository_ListAfter_Call{Call: _e.mock.On("ListAfter", ctx, accountID, afterCreatedAt, afterID, limit)}
+}
+
+func (_c *MockTransactionRepository_ListAfter_Call) Run(run func(ctx context.Context, accountID uuid.UUID, afterCreatedAt time.Time, afterID uuid.UUID, limit int)) *MockTransactionRepository_ListAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(uuid.UUID), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_ListAfter_Call) Return(_a0 []*models.Transaction, _a1 *repository.TransactionCursor, _a2 error) *MockTransactionRepository_ListAfter_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockTransactionRepository_ListAfter_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, uuid.UUID, int) ([]*models.Transaction, *repository.TransactionCursor, error)) *MockTransactionRepository_ListAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByDateRange provides a mock function with given fields: ctx, accountID, from, to, limit, offset
+func (_m *MockTransactionRepository) ListByDateRange(ctx context.Context, accountID uuid.UUID, from time.Time, to time.Time, limit int, offset int) ([]*models.Transaction, error) {
+	ret := _m.Called(ctx, accountID, from, to, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByDateRange")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time, int, int) ([]*models.Transaction, error)); ok {
+		return rf(ctx, accountID, from, to, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time, int, int) []*models.Transaction); ok {
+		r0 = rf(ctx, accountID, from, to, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Time, int, int) error); ok {
+		r1 = rf(ctx, accountID, from, to, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_ListByDateRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByDateRange'
+type MockTransactionRepository_ListByDateRange_Call struct {
+	*mock.Call
+}
+
+// ListByDateRange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - from time.Time
+//   - to time.Time
+//   - limit int
+//   - offset int
+func (_e *MockTransactionRepository_Expecter) ListByDateRange(ctx interface{}, accountID interface{}, from interface{}, to interface{}, limit interface{}, offset interface{}) *MockTransactionRepository_ListByDateRange_Call {
+	return &MockTransactionRepository_ListByDateRange_Call{Call: _e.mock.On("ListByDateRange", ctx, accountID, from, to, limit, offset)}
+}
+
+func (_c *MockTransactionRepository_ListByDateRange_Call) Run(run func(ctx context.Context, accountID uuid.UUID, from time.Time, to time.Time, limit int, offset int)) *MockTransactionRepository_ListByDateRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(time.Time), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_ListByDateRange_Call) Return(_a0 []*models.Transaction, _a1 error) *MockTransactionRepository_ListByDateRange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_ListByDateRange_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, time.Time, int, int) ([]*models.Transaction, error)) *MockTransactionRepository_ListByDateRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergeMetadata provides a mock function with given fields: ctx, id, fields
+func (_m *MockTransactionRepository) MergeMetadata(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	ret := _m.Called(ctx, id, fields)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeMetadata")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, map[string]any) error); ok {
+		r0 = rf(ctx, id, fields)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionRepository_MergeMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeMetadata'
+type MockTransactionRepository_MergeMetadata_Call struct {
+	*mock.Call
+}
+
+// MergeMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - fields map[string]any
+func (_e *MockTransactionRepository_Expecter) MergeMetadata(ctx interface{}, id interface{}, fields interface{}) *MockTransactionRepository_MergeMetadata_Call {
+	return &MockTransactionRepository_MergeMetadata_Call{Call: _e.mock.On("MergeMetadata", ctx, id, fields)}
+}
+
+func (_c *MockTransactionRepository_MergeMetadata_Call) Run(run func(ctx context.Context, id uuid.UUID, fields map[string]any)) *MockTransactionRepository_MergeMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(map[string]any))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_MergeMetadata_Call) Return(_a0 error) *MockTransactionRepository_MergeMetadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionRepository_MergeMetadata_Call) RunAndReturn(run func(context.Context, uuid.UUID, map[string]any) error) *MockTransactionRepository_MergeMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: ctx
+func (_m *MockTransactionRepository) Stats(ctx context.Context) (*models.LedgerStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *models.LedgerStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.LedgerStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.LedgerStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LedgerStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockTransactionRepository_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockTransactionRepository_Expecter) Stats(ctx interface{}) *MockTransactionRepository_Stats_Call {
+	return &MockTransactionRepository_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+}
+
+func (_c *MockTransactionRepository_Stats_Call) Run(run func(ctx context.Context)) *MockTransactionRepository_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_Stats_Call) Return(_a0 *models.LedgerStats, _a1 error) *MockTransactionRepository_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_Stats_Call) RunAndReturn(run func(context.Context) (*models.LedgerStats, error)) *MockTransactionRepository_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumByAccountSince provides a mock function with given fields: ctx, accountID, txnType, currency, since
+func (_m *MockTransactionRepository) SumByAccountSince(ctx context.Context, accountID uuid.UUID, txnType models.TransactionType, currency string, since time.Time) (int64, error) {
+	ret := _m.Called(ctx, accountID, txnType, currency, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumByAccountSince")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType, string, time.Time) (int64, error)); ok {
+		return rf(ctx, accountID, txnType, currency, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType, string, time.Time) int64); ok {
+		r0 = rf(ctx, accountID, txnType, currency, since)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.TransactionType, string, time.Time) error); ok {
+		r1 = rf(ctx, accountID, txnType, currency, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_SumByAccountSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumByAccountSince'
+type MockTransactionRepository_SumByAccountSince_Call struct {
+	*mock.Call
+}
+
+// SumByAccountSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - txnType models.TransactionType
+//   - currency string
+//   - since time.Time
+func (_e *MockTransactionRepository_Expecter) SumByAccountSince(ctx interface{}, accountID interface{}, txnType interface{}, currency interface{}, since interface{}) *MockTransactionRepository_SumByAccountSince_Call {
+	return &MockTransactionRepository_SumByAccountSince_Call{Call: _e.mock.On("SumByAccountSince", ctx, accountID, txnType, currency, since)}
+}
+
+func (_c *MockTransactionRepository_SumByAccountSince_Call) Run(run func(ctx context.Context, accountID uuid.UUID, txnType models.TransactionType, currency string, since time.Time)) *MockTransactionRepository_SumByAccountSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.TransactionType), args[3].(string), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_SumByAccountSince_Call) Return(_a0 int64, _a1 error) *MockTransactionRepository_SumByAccountSince_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_SumByAccountSince_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.TransactionType, string, time.Time) (int64, error)) *MockTransactionRepository_SumByAccountSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumByReferenceID provides a mock function with given fields: ctx, refID, txnType
+func (_m *MockTransactionRepository) SumByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (int64, error) {
+	ret := _m.Called(ctx, refID, txnType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumByReferenceID")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType) (int64, error)); ok {
+		return rf(ctx, refID, txnType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.TransactionType) int64); ok {
+		r0 = rf(ctx, refID, txnType)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.TransactionType) error); ok {
+		r1 = rf(ctx, refID, txnType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_SumByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumByReferenceID'
+type MockTransactionRepository_SumByReferenceID_Call struct {
+	*mock.Call
+}
+
+// SumByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refID uuid.UUID
+//   - txnType models.TransactionType
+func (_e *MockTransactionRepository_Expecter) SumByReferenceID(ctx interface{}, refID interface{}, txnType interface{}) *MockTransactionRepository_SumByReferenceID_Call {
+	return &MockTransactionRepository_SumByReferenceID_Call{Call: _e.mock.On("SumByReferenceID", ctx, refID, txnType)}
+}
+
+func (_c *MockTransactionRepository_SumByReferenceID_Call) Run(run func(ctx context.Context, refID uuid.UUID, txnType models.TransactionType)) *MockTransactionRepository_SumByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(models.TransactionType))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_SumByReferenceID_Call) Return(_a0 int64, _a1 error) *MockTransactionRepository_SumByReferenceID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_SumByReferenceID_Call) RunAndReturn(run func(context.Context, uuid.UUID, models.TransactionType) (int64, error)) *MockTransactionRepository_SumByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SummarizeByAccount provides a mock function with given fields: ctx, accountID, from, to
+func (_m *MockTransactionRepository) SummarizeByAccount(ctx context.Context, accountID uuid.UUID, from time.Time, to time.Time) (*models.TransactionSummary, error) {
+	ret := _m.Called(ctx, accountID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SummarizeByAccount")
+	}
+
+	var r0 *models.TransactionSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) (*models.TransactionSummary, error)); ok {
+		return rf(ctx, accountID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) *models.TransactionSummary); ok {
+		r0 = rf(ctx, accountID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransactionSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, accountID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionRepository_SummarizeByAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SummarizeByAccount'
+type MockTransactionRepository_SummarizeByAccount_Call struct {
+	*mock.Call
+}
+
+// SummarizeByAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+//   - from time.Time
+//   - to time.Time
+func (_e *MockTransactionRepository_Expecter) SummarizeByAccount(ctx interface{}, accountID interface{}, from interface{}, to interface{}) *MockTransactionRepository_SummarizeByAccount_Call {
+	return &MockTransactionRepository_SummarizeByAccount_Call{Call: _e.mock.On("SummarizeByAccount", ctx, accountID, from, to)}
+}
+
+func (_c *MockTransactionRepository_SummarizeByAccount_Call) Run(run func(ctx context.Context, accountID uuid.UUID, from time.Time, to time.Time)) *MockTransactionRepository_SummarizeByAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockTransactionRepository_SummarizeByAccount_Call) Return(_a0 *models.TransactionSummary, _a1 error) *MockTransactionRepository_SummarizeByAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionRepository_SummarizeByAccount_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, time.Time) (*models.TransactionSummary, error)) *MockTransactionRepository_SummarizeByAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
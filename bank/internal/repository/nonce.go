@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+)
+
+// NonceRepository defines the interface for signed request nonce storage
+type NonceRepository interface {
+	Use(ctx context.Context, nonce string) (bool, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+type nonceRepository struct {
+	exec db.Executor
+	sql  *nonceStatements
+}
+
+// nonceStatements are the nonceRepository queries with a table prefix applied
+type nonceStatements struct {
+	use             string
+	deleteOlderThan string
+}
+
+// newNonceStatements builds the nonceRepository queries for tables named with prefix
+func newNonceStatements(prefix string) nonceStatements {
+	return nonceStatements{
+		use:             db.PrefixTables(useNonceQuery, prefix),
+		deleteOlderThan: db.PrefixTables(deleteNoncesQuery, prefix),
+	}
+}
+
+// NonceOption configures optional NonceRepository behaviour
+type NonceOption func(*nonceRepository)
+
+// WithNonceTables makes the repository query the tables of tables instead of the unprefixed ones
+func WithNonceTables(tables *Tables) NonceOption {
+	return func(r *nonceRepository) {
+		if tables != nil {
+			r.sql = &tables.nonce
+		}
+	}
+}
+
+// NewNonceRepository creates a new NonceRepository
+func NewNonceRepository(exec db.Executor, opts ...NonceOption) NonceRepository {
+	r := &nonceRepository{exec: exec, sql: &defaultTables.nonce}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return &tracedNonceRepository{next: r}
+}
+
+const useNonceQuery = `
+	INSERT INTO request_nonces (nonce)
+	VALUES ($1)
+	ON CONFLICT (nonce) DO NOTHING
+`
+
+// Use records nonce and reports whether it was new; false means it has been used before
+func (r *nonceRepository) Use(ctx context.Context, nonce string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.exec.ExecContext(ctx, r.sql.use, nonce)
+	if err != nil {
+		return false, fmt.Errorf("failed to store request nonce: %w", queryError(ctx, err))
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to store request nonce: %w", err)
+	}
+
+	return inserted == 1, nil
+}
+
+const deleteNoncesQuery = `DELETE FROM request_nonces WHERE created_at < $1`
+
+// DeleteOlderThan removes nonces recorded before the specified time
+func (r *nonceRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.exec.ExecContext(ctx, r.sql.deleteOlderThan, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old request nonces: %w", queryError(ctx, err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old request nonces: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceRepository_Use(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewNonceRepository(database)
+
+	fresh, err := repo.Use(context.Background(), "nonce-1")
+	require.NoError(t, err)
+	assert.True(t, fresh, "first use should be accepted")
+
+	fresh, err = repo.Use(context.Background(), "nonce-1")
+	require.NoError(t, err)
+	assert.False(t, fresh, "second use should be rejected")
+
+	fresh, err = repo.Use(context.Background(), "nonce-2")
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	_, err = database.ExecContext(context.Background(),
+		"UPDATE request_nonces SET created_at = NOW() - INTERVAL '1 hour' WHERE nonce = 'nonce-1'")
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteOlderThan(context.Background(), time.Now().Add(-30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	fresh, err = repo.Use(context.Background(), "nonce-2")
+	require.NoError(t, err)
+	assert.False(t, fresh, "nonces newer than the cutoff are kept")
+}
@@ -0,0 +1,25 @@
+package repository
+
+// Tables holds the statements of every repository with a table prefix applied. Build it once with
+// NewTables and pass it to the repository constructors through their With...Tables options, so
+// repositories created per request or transaction run their statements without rewriting them.
+type Tables struct {
+	account     accountStatements
+	transaction transactionStatements
+	idempotency idempotencyStatements
+	nonce       nonceStatements
+}
+
+// NewTables builds the repository statements for tables named with prefix, which must match the
+// prefix the schema was migrated with; empty means no prefix
+func NewTables(prefix string) *Tables {
+	return &Tables{
+		account:     newAccountStatements(prefix),
+		transaction: newTransactionStatements(prefix),
+		idempotency: newIdempotencyStatements(prefix),
+		nonce:       newNonceStatements(prefix),
+	}
+}
+
+// defaultTables are used by repositories constructed without tables
+var defaultTables = NewTables("")
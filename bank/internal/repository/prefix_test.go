@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingExecutor records the statements passed to ExecContext
+type recordingExecutor struct {
+	db.Executor
+	queries []string
+}
+
+func (e *recordingExecutor) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	return driver.RowsAffected(0), nil
+}
+
+func TestNewTables(t *testing.T) {
+	tables := NewTables("staging_")
+	ctx := context.Background()
+
+	exec := &recordingExecutor{}
+	_, err := NewNonceRepository(exec).DeleteOlderThan(ctx, time.Now())
+	require.NoError(t, err)
+	_, err = NewNonceRepository(exec, WithNonceTables(tables)).DeleteOlderThan(ctx, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, NewIdempotencyRepository(exec, WithIdempotencyTables(tables)).Store(ctx, &models.IdempotencyKey{}))
+
+	require.Len(t, exec.queries, 3)
+	assert.Contains(t, exec.queries[0], "FROM request_nonces ")
+	assert.Contains(t, exec.queries[1], "FROM staging_request_nonces ")
+	assert.Contains(t, exec.queries[2], "INSERT INTO staging_idempotency_keys ")
+}
+
+func TestNewTables_NilKeepsDefault(t *testing.T) {
+	exec := &recordingExecutor{}
+	_, err := NewNonceRepository(exec, WithNonceTables(nil)).DeleteOlderThan(context.Background(), time.Now())
+	require.NoError(t, err)
+
+	require.Len(t, exec.queries, 1)
+	assert.Contains(t, exec.queries[0], "FROM request_nonces ")
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+)
+
+// DefaultQueryTimeout bounds each repository operation whose context carries no deadline
+const DefaultQueryTimeout = 5 * time.Second
+
+var queryTimeout atomic.Int64
+
+func init() {
+	queryTimeout.Store(int64(DefaultQueryTimeout))
+}
+
+// SetQueryTimeout changes the timeout applied to repository operations whose context carries no
+// deadline; non-positive disables it. Callers needing a different bound for a single operation
+// pass a context with its own deadline, which always takes precedence.
+func SetQueryTimeout(d time.Duration) {
+	queryTimeout.Store(int64(d))
+}
+
+// withQueryTimeout bounds ctx by the query timeout unless it already has a deadline
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := time.Duration(queryTimeout.Load())
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// queryError marks err as models.ErrQueryTimeout when it was caused by ctx's deadline expiring,
+// so callers can tell a slow or blocked query apart from other failures
+func queryError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, models.ErrQueryTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", models.ErrQueryTimeout, err)
+}
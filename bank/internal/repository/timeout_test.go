@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Cleanup(func() { SetQueryTimeout(DefaultQueryTimeout) })
+
+	t.Run("applies default without deadline", func(t *testing.T) {
+		ctx, cancel := withQueryTimeout(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(DefaultQueryTimeout), deadline, time.Second)
+	})
+
+	t.Run("keeps caller deadline", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer parentCancel()
+
+		ctx, cancel := withQueryTimeout(parent)
+		defer cancel()
+
+		assert.Equal(t, parent, ctx)
+	})
+
+	t.Run("configured timeout", func(t *testing.T) {
+		SetQueryTimeout(50 * time.Millisecond)
+		ctx, cancel := withQueryTimeout(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(50*time.Millisecond), deadline, 20*time.Millisecond)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		SetQueryTimeout(0)
+		ctx, cancel := withQueryTimeout(context.Background())
+		defer cancel()
+
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+}
+
+func TestQueryError(t *testing.T) {
+	dbErr := errors.New("pq: canceling statement due to user request")
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+
+	err := queryError(expired, dbErr)
+	assert.ErrorIs(t, err, models.ErrQueryTimeout)
+	assert.ErrorIs(t, err, dbErr)
+
+	assert.Same(t, dbErr, queryError(context.Background(), dbErr), "errors without an expired deadline are unchanged")
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.NotErrorIs(t, queryError(canceled, dbErr), models.ErrQueryTimeout, "cancellation is not a timeout")
+}
@@ -0,0 +1,513 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/benx421/payment-gateway/bank/internal/repository")
+
+// transactionIDKey is the span attribute carrying the transaction an operation touched
+const transactionIDKey = attribute.Key("bank.transaction_id")
+
+// operationSpan is the span of one repository operation, timed for metrics.DBOperationDuration
+type operationSpan struct {
+	trace.Span
+	start     time.Time
+	table     string
+	operation string
+}
+
+// startSpan starts a client span for one repository operation.
+// Spans and metrics are named after the operation and table, never the SQL, so bound values are not
+// exported and metric cardinality stays fixed.
+func startSpan(ctx context.Context, table, operation string, attrs ...attribute.KeyValue) (context.Context, *operationSpan) {
+	attrs = append(attrs,
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBCollectionName(table),
+		semconv.DBOperationName(operation),
+	)
+	ctx, span := tracer.Start(ctx, operation+" "+table, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, &operationSpan{Span: span, start: time.Now(), table: table, operation: operation}
+}
+
+// endSpan ends span and records its duration, treating a lookup that found nothing as a successful query
+func endSpan(span *operationSpan, err error) {
+	if errors.Is(err, models.ErrNotFound) {
+		err = nil
+	}
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	metrics.DBOperationDuration.WithLabelValues(span.table, span.operation, outcome).Observe(time.Since(span.start).Seconds())
+	tracing.End(span.Span, err)
+}
+
+func transactionID(id uuid.UUID) attribute.KeyValue {
+	return transactionIDKey.String(id.String())
+}
+
+// tracedAccountRepository records a span around each AccountRepository operation
+type tracedAccountRepository struct {
+	next AccountRepository
+}
+
+func (r *tracedAccountRepository) Create(ctx context.Context, account *models.Account) (err error) {
+	ctx, span := startSpan(ctx, "accounts", "Create")
+	defer func() { endSpan(span, err) }()
+
+	err = r.next.Create(ctx, account)
+	if err == nil {
+		span.SetAttributes(tracing.AccountID(account.ID))
+	}
+	return err
+}
+
+func (r *tracedAccountRepository) CreateWithFunding(ctx context.Context, account *models.Account) (_ *models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "accounts", "CreateWithFunding")
+	defer func() { endSpan(span, err) }()
+
+	funding, err := r.next.CreateWithFunding(ctx, account)
+	if err == nil {
+		span.SetAttributes(tracing.AccountID(account.ID), transactionID(funding.ID))
+	}
+	return funding, err
+}
+
+func (r *tracedAccountRepository) ListLowBalance(ctx context.Context, thresholdCents int64, limit, offset int) (_ []*models.Account, err error) {
+	ctx, span := startSpan(ctx, "accounts", "ListLowBalance")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.ListLowBalance(ctx, thresholdCents, limit, offset)
+}
+
+func (r *tracedAccountRepository) FindByID(ctx context.Context, id uuid.UUID) (_ *models.Account, err error) {
+	ctx, span := startSpan(ctx, "accounts", "FindByID", tracing.AccountID(id))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *tracedAccountRepository) FindByAccountNumber(ctx context.Context, accountNumber string) (_ *models.Account, err error) {
+	ctx, span := startSpan(ctx, "accounts", "FindByAccountNumber")
+	defer func() { endSpan(span, err) }()
+
+	account, err := r.next.FindByAccountNumber(ctx, accountNumber)
+	if err == nil {
+		span.SetAttributes(tracing.AccountID(account.ID))
+	}
+	return account, err
+}
+
+func (r *tracedAccountRepository) FindByAccountNumberForUpdate(ctx context.Context, accountNumber string) (_ *models.Account, err error) {
+	ctx, span := startSpan(ctx, "accounts", "FindByAccountNumberForUpdate")
+	defer func() { endSpan(span, err) }()
+
+	account, err := r.next.FindByAccountNumberForUpdate(ctx, accountNumber)
+	if err == nil {
+		span.SetAttributes(tracing.AccountID(account.ID))
+	}
+	return account, err
+}
+
+func (r *tracedAccountRepository) AdjustBalances(
+	ctx context.Context,
+	accountID uuid.UUID,
+	balanceDelta, availableBalanceDelta models.Money,
+	reason string,
+) (balance, available models.Money, err error) {
+	ctx, span := startSpan(ctx, "balances", "AdjustBalances", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.AdjustBalances(ctx, accountID, balanceDelta, availableBalanceDelta, reason)
+}
+
+func (r *tracedAccountRepository) AdjustBalancesIfAvailable(
+	ctx context.Context,
+	accountID uuid.UUID,
+	expectedAvailable, balanceDelta, availableBalanceDelta models.Money,
+	reason string,
+) (balance, available models.Money, updated bool, err error) {
+	ctx, span := startSpan(ctx, "balances", "AdjustBalancesIfAvailable", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.AdjustBalancesIfAvailable(ctx, accountID, expectedAvailable, balanceDelta, availableBalanceDelta, reason)
+}
+
+func (r *tracedAccountRepository) AdjustBalancesBatch(ctx context.Context, adjustments []BalanceAdjustment) (err error) {
+	ctx, span := startSpan(ctx, "balances", "AdjustBalancesBatch", attribute.Int("bank.account_count", len(adjustments)))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.AdjustBalancesBatch(ctx, adjustments)
+}
+
+func (r *tracedAccountRepository) OpenBalance(ctx context.Context, accountID uuid.UUID, currency string) (err error) {
+	ctx, span := startSpan(ctx, "balances", "OpenBalance", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.OpenBalance(ctx, accountID, currency)
+}
+
+func (r *tracedAccountRepository) FindBalances(ctx context.Context, accountID uuid.UUID) (_ []*models.Balance, err error) {
+	ctx, span := startSpan(ctx, "balances", "FindBalances", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindBalances(ctx, accountID)
+}
+
+func (r *tracedAccountRepository) FindBalanceBreakdown(ctx context.Context, accountID uuid.UUID) (_ *models.BalanceBreakdown, err error) {
+	ctx, span := startSpan(ctx, "accounts", "FindBalanceBreakdown", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindBalanceBreakdown(ctx, accountID)
+}
+
+func (r *tracedAccountRepository) FindBalanceDrift(ctx context.Context, accountID uuid.UUID) (_ *models.BalanceDrift, err error) {
+	ctx, span := startSpan(ctx, "balances", "FindBalanceDrift", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindBalanceDrift(ctx, accountID)
+}
+
+func (r *tracedAccountRepository) FindBalanceDrifts(ctx context.Context) (_ []*models.BalanceDrift, err error) {
+	ctx, span := startSpan(ctx, "balances", "FindBalanceDrifts")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindBalanceDrifts(ctx)
+}
+
+// tracedTransactionRepository records a span around each TransactionRepository operation
+type tracedTransactionRepository struct {
+	next TransactionRepository
+}
+
+func (r *tracedTransactionRepository) Create(ctx context.Context, tx *models.Transaction) (err error) {
+	ctx, span := startSpan(ctx, "transactions", "Create", tracing.AccountID(tx.AccountID))
+	defer func() { endSpan(span, err) }()
+
+	err = r.next.Create(ctx, tx)
+	if err == nil {
+		span.SetAttributes(transactionID(tx.ID))
+	}
+	return err
+}
+
+func (r *tracedTransactionRepository) CreateDeclined(
+	ctx context.Context,
+	txn *models.Transaction,
+	reason models.DeclineReason,
+) (err error) {
+	ctx, span := startSpan(ctx, "transactions", "CreateDeclined",
+		tracing.AccountID(txn.AccountID), attribute.String("bank.decline_reason", string(reason)))
+	defer func() { endSpan(span, err) }()
+
+	err = r.next.CreateDeclined(ctx, txn, reason)
+	if err == nil {
+		span.SetAttributes(transactionID(txn.ID))
+	}
+	return err
+}
+
+func (r *tracedTransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (_ *models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindByID", transactionID(id))
+	defer func() { endSpan(span, err) }()
+
+	txn, err := r.next.FindByID(ctx, id)
+	if err == nil {
+		span.SetAttributes(tracing.AccountID(txn.AccountID))
+	}
+	return txn, err
+}
+
+func (r *tracedTransactionRepository) FindByIDs(
+	ctx context.Context,
+	ids []uuid.UUID,
+) (_ map[uuid.UUID]*models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindByIDs", attribute.Int("bank.transaction_count", len(ids)))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindByIDs(ctx, ids)
+}
+
+func (r *tracedTransactionRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (_ *models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindByIDForUpdate", transactionID(id))
+	defer func() { endSpan(span, err) }()
+
+	txn, err := r.next.FindByIDForUpdate(ctx, id)
+	if err == nil {
+		span.SetAttributes(tracing.AccountID(txn.AccountID))
+	}
+	return txn, err
+}
+
+func (r *tracedTransactionRepository) FindByReferenceID(
+	ctx context.Context,
+	refID uuid.UUID,
+	txnType models.TransactionType,
+) (_ *models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindByReferenceID", transactionID(refID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindByReferenceID(ctx, refID, txnType)
+}
+
+func (r *tracedTransactionRepository) SumByReferenceID(
+	ctx context.Context,
+	refID uuid.UUID,
+	txnType models.TransactionType,
+) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "transactions", "SumByReferenceID", transactionID(refID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.SumByReferenceID(ctx, refID, txnType)
+}
+
+func (r *tracedTransactionRepository) CountByAccountSince(
+	ctx context.Context,
+	accountID uuid.UUID,
+	txnType models.TransactionType,
+	since time.Time,
+) (_ int, err error) {
+	ctx, span := startSpan(ctx, "transactions", "CountByAccountSince", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.CountByAccountSince(ctx, accountID, txnType, since)
+}
+
+func (r *tracedTransactionRepository) SumByAccountSince(
+	ctx context.Context,
+	accountID uuid.UUID,
+	txnType models.TransactionType,
+	currency string,
+	since time.Time,
+) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "transactions", "SumByAccountSince", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.SumByAccountSince(ctx, accountID, txnType, currency, since)
+}
+
+func (r *tracedTransactionRepository) ListByDateRange(
+	ctx context.Context,
+	accountID uuid.UUID,
+	from, to time.Time,
+	limit, offset int,
+) (_ []*models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions", "ListByDateRange", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.ListByDateRange(ctx, accountID, from, to, limit, offset)
+}
+
+func (r *tracedTransactionRepository) SummarizeByAccount(
+	ctx context.Context,
+	accountID uuid.UUID,
+	from, to time.Time,
+) (_ *models.TransactionSummary, err error) {
+	ctx, span := startSpan(ctx, "transactions", "SummarizeByAccount", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.SummarizeByAccount(ctx, accountID, from, to)
+}
+
+func (r *tracedTransactionRepository) Stats(ctx context.Context) (_ *models.LedgerStats, err error) {
+	ctx, span := startSpan(ctx, "transactions", "Stats")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Stats(ctx)
+}
+
+func (r *tracedTransactionRepository) Find(ctx context.Context, filter TransactionFilter) (_ []*models.Transaction, _ int, err error) {
+	ctx, span := startSpan(ctx, "transactions", "Find", filterAttributes(filter)...)
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Find(ctx, filter)
+}
+
+func (r *tracedTransactionRepository) FindAuthorizations(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit, offset int,
+) (_ []*models.Transaction, _ int, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindAuthorizations", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindAuthorizations(ctx, accountID, limit, offset)
+}
+
+func (r *tracedTransactionRepository) FindCaptures(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit, offset int,
+) (_ []*models.Transaction, _ int, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindCaptures", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindCaptures(ctx, accountID, limit, offset)
+}
+
+func (r *tracedTransactionRepository) FindRefunds(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit, offset int,
+) (_ []*models.Transaction, _ int, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindRefunds", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindRefunds(ctx, accountID, limit, offset)
+}
+
+func (r *tracedTransactionRepository) FindByMetadata(
+	ctx context.Context,
+	key, value string,
+	limit int,
+) (_ []*models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions", "FindByMetadata", attribute.String("bank.metadata_key", key))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindByMetadata(ctx, key, value, limit)
+}
+
+func (r *tracedTransactionRepository) ExportByDateRange(ctx context.Context, from, to time.Time) (_ *sql.Rows, err error) {
+	ctx, span := startSpan(ctx, "transactions", "ExportByDateRange")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.ExportByDateRange(ctx, from, to)
+}
+
+func (r *tracedTransactionRepository) ListAfter(
+	ctx context.Context,
+	accountID uuid.UUID,
+	afterCreatedAt time.Time,
+	afterID uuid.UUID,
+	limit int,
+) (_ []*models.Transaction, _ *TransactionCursor, err error) {
+	ctx, span := startSpan(ctx, "transactions", "ListAfter", tracing.AccountID(accountID))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.ListAfter(ctx, accountID, afterCreatedAt, afterID, limit)
+}
+
+func (r *tracedTransactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.TransactionStatus) (err error) {
+	ctx, span := startSpan(ctx, "transactions", "UpdateStatus", transactionID(id))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.UpdateStatus(ctx, id, status)
+}
+
+func (r *tracedTransactionRepository) MergeMetadata(ctx context.Context, id uuid.UUID, fields map[string]any) (err error) {
+	ctx, span := startSpan(ctx, "transactions", "MergeMetadata", transactionID(id))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.MergeMetadata(ctx, id, fields)
+}
+
+func (r *tracedTransactionRepository) Archive(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "transactions", "Archive")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Archive(ctx, before)
+}
+
+func (r *tracedTransactionRepository) FindArchivedByID(ctx context.Context, id uuid.UUID) (_ *models.Transaction, err error) {
+	ctx, span := startSpan(ctx, "transactions_archive", "FindArchivedByID", transactionID(id))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindArchivedByID(ctx, id)
+}
+
+func (r *tracedTransactionRepository) Iterate(
+	ctx context.Context,
+	filter TransactionFilter,
+	fn func(*models.Transaction) error,
+) (err error) {
+	ctx, span := startSpan(ctx, "transactions", "Iterate", filterAttributes(filter)...)
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Iterate(ctx, filter, fn)
+}
+
+func (r *tracedTransactionRepository) FindArchived(ctx context.Context, filter TransactionFilter) (_ []*models.Transaction, _ int, err error) {
+	ctx, span := startSpan(ctx, "transactions_archive", "FindArchived", filterAttributes(filter)...)
+	defer func() { endSpan(span, err) }()
+
+	return r.next.FindArchived(ctx, filter)
+}
+
+func filterAttributes(filter TransactionFilter) []attribute.KeyValue {
+	if filter.AccountID == nil {
+		return nil
+	}
+	return []attribute.KeyValue{tracing.AccountID(*filter.AccountID)}
+}
+
+// tracedIdempotencyRepository records a span around each IdempotencyRepository operation
+type tracedIdempotencyRepository struct {
+	next IdempotencyRepository
+}
+
+func (r *tracedIdempotencyRepository) Get(ctx context.Context, key, requestPath string) (_ *models.IdempotencyKey, err error) {
+	ctx, span := startSpan(ctx, "idempotency_keys", "Get")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Get(ctx, key, requestPath)
+}
+
+func (r *tracedIdempotencyRepository) Store(ctx context.Context, idemKey *models.IdempotencyKey) (err error) {
+	ctx, span := startSpan(ctx, "idempotency_keys", "Store")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Store(ctx, idemKey)
+}
+
+func (r *tracedIdempotencyRepository) Insert(ctx context.Context, idemKey *models.IdempotencyKey) (err error) {
+	ctx, span := startSpan(ctx, "idempotency_keys", "Insert")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Insert(ctx, idemKey)
+}
+
+func (r *tracedIdempotencyRepository) GetByTransactionID(ctx context.Context, id uuid.UUID) (_ *models.IdempotencyKey, err error) {
+	ctx, span := startSpan(ctx, "idempotency_keys", "GetByTransactionID", transactionID(id))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.GetByTransactionID(ctx, id)
+}
+
+func (r *tracedIdempotencyRepository) DeleteOlderThan(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "idempotency_keys", "DeleteOlderThan")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.DeleteOlderThan(ctx, before)
+}
+
+// tracedNonceRepository records a span around each NonceRepository operation
+type tracedNonceRepository struct {
+	next NonceRepository
+}
+
+func (r *tracedNonceRepository) Use(ctx context.Context, nonce string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "request_nonces", "Use")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Use(ctx, nonce)
+}
+
+func (r *tracedNonceRepository) DeleteOlderThan(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "request_nonces", "DeleteOlderThan")
+	defer func() { endSpan(span, err) }()
+
+	return r.next.DeleteOlderThan(ctx, before)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/tracing"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// stubAccountRepository answers FindByID with a fixed result
+type stubAccountRepository struct {
+	AccountRepository
+	account *models.Account
+	err     error
+}
+
+func (s *stubAccountRepository) FindByID(context.Context, uuid.UUID) (*models.Account, error) {
+	return s.account, s.err
+}
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	original := tracer
+	tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	t.Cleanup(func() { tracer = original })
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracedAccountRepository_FindByID(t *testing.T) {
+	accountID := uuid.New()
+
+	tests := []struct {
+		err        error
+		name       string
+		wantStatus codes.Code
+	}{
+		{name: "found", wantStatus: codes.Unset},
+		{name: "not found is not a failure", err: models.ErrAccountNotFound, wantStatus: codes.Unset},
+		{name: "query failure", err: errors.New("connection reset"), wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			repo := &tracedAccountRepository{next: &stubAccountRepository{account: &models.Account{ID: accountID}, err: tt.err}}
+
+			_, err := repo.FindByID(context.Background(), accountID)
+			assert.ErrorIs(t, err, tt.err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "FindByID accounts", span.Name())
+			assert.Equal(t, tt.wantStatus, span.Status().Code)
+			assert.Equal(t, accountID.String(), spanAttribute(span, tracing.AccountIDKey))
+			assert.Equal(t, "postgresql", spanAttribute(span, "db.system.name"))
+			assert.Equal(t, "FindByID", spanAttribute(span, "db.operation.name"))
+			assert.Empty(t, spanAttribute(span, "db.query.text"), "raw SQL must not be exported")
+		})
+	}
+}
+
+func operationSampleCount(t *testing.T, table, operation, outcome string) uint64 {
+	t.Helper()
+
+	observer, err := metrics.DBOperationDuration.GetMetricWithLabelValues(table, operation, outcome)
+	require.NoError(t, err)
+
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestTracedAccountRepository_RecordsOperationDuration(t *testing.T) {
+	okBefore := operationSampleCount(t, "accounts", "FindByID", "ok")
+	errorBefore := operationSampleCount(t, "accounts", "FindByID", "error")
+
+	for _, err := range []error{nil, models.ErrAccountNotFound, errors.New("connection reset")} {
+		repo := &tracedAccountRepository{next: &stubAccountRepository{account: &models.Account{}, err: err}}
+		_, _ = repo.FindByID(context.Background(), uuid.New())
+	}
+
+	assert.Equal(t, okBefore+2, operationSampleCount(t, "accounts", "FindByID", "ok"), "not found counts as ok")
+	assert.Equal(t, errorBefore+1, operationSampleCount(t, "accounts", "FindByID", "error"))
+}
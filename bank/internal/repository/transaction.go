@@ -5,56 +5,222 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/benx421/payment-gateway/bank/internal/db"
 	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // TransactionRepository defines the interface for transaction data access
 type TransactionRepository interface {
 	Create(ctx context.Context, tx *models.Transaction) error
+	CreateDeclined(ctx context.Context, txn *models.Transaction, reason models.DeclineReason) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Transaction, error)
 	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
 	FindByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (*models.Transaction, error)
+	SumByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (int64, error)
+	CountByAccountSince(ctx context.Context, accountID uuid.UUID, txnType models.TransactionType, since time.Time) (int, error)
+	SumByAccountSince(ctx context.Context, accountID uuid.UUID, txnType models.TransactionType, currency string, since time.Time) (int64, error)
+	ListByDateRange(ctx context.Context, accountID uuid.UUID, from, to time.Time, limit, offset int) ([]*models.Transaction, error)
+	SummarizeByAccount(ctx context.Context, accountID uuid.UUID, from, to time.Time) (*models.TransactionSummary, error)
+	Stats(ctx context.Context) (*models.LedgerStats, error)
+	Find(ctx context.Context, filter TransactionFilter) ([]*models.Transaction, int, error)
+	FindAuthorizations(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*models.Transaction, int, error)
+	FindCaptures(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*models.Transaction, int, error)
+	FindRefunds(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*models.Transaction, int, error)
+	Iterate(ctx context.Context, filter TransactionFilter, fn func(*models.Transaction) error) error
+	FindByMetadata(ctx context.Context, key, value string, limit int) ([]*models.Transaction, error)
+	ExportByDateRange(ctx context.Context, from, to time.Time) (*sql.Rows, error)
+	ListAfter(ctx context.Context, accountID uuid.UUID, afterCreatedAt time.Time, afterID uuid.UUID, limit int) ([]*models.Transaction, *TransactionCursor, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status models.TransactionStatus) error
+	MergeMetadata(ctx context.Context, id uuid.UUID, fields map[string]any) error
+	Archive(ctx context.Context, before time.Time) (int64, error)
+	FindArchivedByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
+	FindArchived(ctx context.Context, filter TransactionFilter) ([]*models.Transaction, int, error)
 }
 
+// TransactionFilter selects transactions for Find and Iterate
+// Nil fields are not filtered on; Limit must be positive for Find
+type TransactionFilter struct {
+	AccountID *uuid.UUID
+	Type      *models.TransactionType
+	Status    *models.TransactionStatus
+	Currency  *string // ISO-4217 code
+	Limit     int
+	Offset    int
+}
+
+// TransactionCursor marks the last transaction of a ListAfter page
+// Pass its fields back to ListAfter to fetch the following page
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// transactionColumns are the columns shared by transactions and transactions_archive
+const transactionColumns = `id, account_id, type, amount_cents, currency,
+		       reference_id, status, expires_at, metadata, created_at, updated_at, decline_reason`
+
+// DefaultMaxMetadataBytes is the serialized metadata size limit applied unless overridden
+const DefaultMaxMetadataBytes = 16 << 10
+
 type transactionRepository struct {
-	exec db.Executor
+	exec             db.Executor
+	sql              *transactionStatements
+	metadataSchemas  *MetadataSchemas
+	maxMetadataBytes int
+}
+
+// transactionStatements are the transactionRepository queries with a table prefix applied
+type transactionStatements struct {
+	create              string
+	findByID            string
+	findByIDs           string
+	findByIDForUpdate   string
+	findByReferenceID   string
+	sumByReferenceID    string
+	countByAccountSince string
+	sumByAccountSince   string
+	listByDateRange     string
+	summarizeByAccount  string
+	stats               string
+	find                pageStatements
+	findArchived        pageStatements
+	iterate             string
+	findByMetadata      string
+	exportByDateRange   string
+	listAfter           string
+	updateStatus        string
+	mergeMetadata       string
+	archive             string
+	findArchivedByID    string
+}
+
+// pageStatements count and select the rows of one table, for a WHERE clause and paging to follow
+type pageStatements struct {
+	count      string
+	selectFrom string
+}
+
+// newTransactionStatements builds the transactionRepository queries for tables named with prefix
+func newTransactionStatements(prefix string) transactionStatements {
+	return transactionStatements{
+		create:              db.PrefixTables(createTransactionQuery, prefix),
+		findByID:            db.PrefixTables(findTransactionByIDQuery, prefix),
+		findByIDs:           db.PrefixTables(findTransactionsByIDsQuery, prefix),
+		findByIDForUpdate:   db.PrefixTables(findTransactionByIDForUpdateQuery, prefix),
+		findByReferenceID:   db.PrefixTables(findTransactionByReferenceIDQuery, prefix),
+		sumByReferenceID:    db.PrefixTables(sumByReferenceIDQuery, prefix),
+		countByAccountSince: db.PrefixTables(countByAccountSinceQuery, prefix),
+		sumByAccountSince:   db.PrefixTables(sumByAccountSinceQuery, prefix),
+		listByDateRange:     db.PrefixTables(listByDateRangeQuery, prefix),
+		summarizeByAccount:  db.PrefixTables(summarizeByAccountQuery, prefix),
+		stats:               db.PrefixTables(ledgerStatsQuery, prefix),
+		find: pageStatements{
+			count:      db.PrefixTables("SELECT COUNT(*) FROM transactions", prefix),
+			selectFrom: db.PrefixTables("SELECT "+transactionColumns+" FROM transactions", prefix),
+		},
+		findArchived: pageStatements{
+			count:      db.PrefixTables("SELECT COUNT(*) FROM transactions_archive", prefix),
+			selectFrom: db.PrefixTables("SELECT "+transactionColumns+", archived_at FROM transactions_archive", prefix),
+		},
+		iterate:           db.PrefixTables("SELECT "+transactionColumns+" FROM transactions", prefix),
+		findByMetadata:    db.PrefixTables(findByMetadataQuery, prefix),
+		exportByDateRange: db.PrefixTables(exportByDateRangeQuery, prefix),
+		listAfter:         db.PrefixTables(listAfterQuery, prefix),
+		updateStatus:      db.PrefixTables(updateStatusQuery, prefix),
+		mergeMetadata:     db.PrefixTables(mergeMetadataQuery, prefix),
+		archive:           db.PrefixTables(archiveTransactionsQuery, prefix),
+		findArchivedByID:  db.PrefixTables(findArchivedByIDQuery, prefix),
+	}
+}
+
+// TransactionOption configures optional TransactionRepository behaviour
+type TransactionOption func(*transactionRepository)
+
+// WithMetadataSchemas makes Create reject metadata that does not conform to schemas
+func WithMetadataSchemas(schemas *MetadataSchemas) TransactionOption {
+	return func(r *transactionRepository) {
+		r.metadataSchemas = schemas
+	}
+}
+
+// WithMaxMetadataBytes sets the largest serialized metadata Create accepts; non-positive disables the limit
+func WithMaxMetadataBytes(n int) TransactionOption {
+	return func(r *transactionRepository) {
+		r.maxMetadataBytes = n
+	}
+}
+
+// WithTransactionTables makes the repository query the tables of tables instead of the unprefixed ones
+func WithTransactionTables(tables *Tables) TransactionOption {
+	return func(r *transactionRepository) {
+		if tables != nil {
+			r.sql = &tables.transaction
+		}
+	}
 }
 
 // NewTransactionRepository creates a new TransactionRepository
 // The exec parameter can be either *db.DB or *db.Tx, allowing the repository
 // to work with or without transactions
-func NewTransactionRepository(exec db.Executor) TransactionRepository {
-	return &transactionRepository{exec: exec}
+func NewTransactionRepository(exec db.Executor, opts ...TransactionOption) TransactionRepository {
+	r := &transactionRepository{exec: exec, sql: &defaultTables.transaction, maxMetadataBytes: DefaultMaxMetadataBytes}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return &tracedTransactionRepository{next: r}
 }
 
+const createTransactionQuery = `
+	INSERT INTO transactions (
+		id, account_id, type, amount_cents, currency,
+		reference_id, status, expires_at, metadata, created_at, updated_at, decline_reason
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW()), COALESCE($10, NOW()), NULLIF($11, ''))
+`
+
 // Create inserts a new transaction into the database
+// Transactions failing models.Transaction.Validate are rejected before reaching the ledger.
+// When metadata schemas are configured, non-conforming metadata is rejected with models.ErrInvalidMetadata;
+// metadata larger than the size limit is rejected with models.ErrMetadataTooLarge
 func (r *transactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if err := tx.Validate(); err != nil {
+		return err
+	}
+
 	if tx.ID == uuid.Nil {
 		tx.ID = uuid.New()
 	}
 
-	var metadataJSON *[]byte
+	if r.metadataSchemas != nil && tx.Metadata != nil {
+		if err := r.metadataSchemas.Validate(tx.Type, tx.Metadata); err != nil {
+			return err
+		}
+	}
+
+	// Passed as text: a []byte would be sent in binary format when binary_parameters is on, which jsonb rejects
+	var metadataJSON *string
 	if tx.Metadata != nil {
 		jsonBytes, err := json.Marshal(tx.Metadata)
 		if err != nil {
 			return fmt.Errorf("failed to marshal metadata: %w", err)
 		}
-		metadataJSON = &jsonBytes
+		if r.maxMetadataBytes > 0 && len(jsonBytes) > r.maxMetadataBytes {
+			return fmt.Errorf("%w: %d bytes exceeds limit of %d", models.ErrMetadataTooLarge, len(jsonBytes), r.maxMetadataBytes)
+		}
+		jsonText := string(jsonBytes)
+		metadataJSON = &jsonText
 	}
 
-	query := `
-		INSERT INTO transactions (
-			id, account_id, type, amount_cents, currency,
-			reference_id, status, expires_at, metadata, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW()))
-	`
-
 	_, err := r.exec.ExecContext(
-		ctx, query,
+		ctx, r.sql.create,
 		tx.ID,
 		tx.AccountID,
 		tx.Type,
@@ -65,116 +231,829 @@ func (r *transactionRepository) Create(ctx context.Context, tx *models.Transacti
 		tx.ExpiresAt,
 		metadataJSON,
 		tx.CreatedAt,
+		tx.DeclineReason,
 	)
 	if err != nil {
 		if db.IsUniqueViolation(err) {
 			return models.ErrDuplicateTransaction
 		}
-		return fmt.Errorf("failed to create transaction: %w", err)
+		return fmt.Errorf("failed to create transaction: %w", queryError(ctx, err))
 	}
 
+	tx.UpdatedAt = tx.CreatedAt
+
 	return nil
 }
 
+// CreateDeclined records txn as a DECLINED transaction refused for reason, so the attempt shows in
+// the account's history. It sets txn's status and decline reason and otherwise behaves as Create.
+func (r *transactionRepository) CreateDeclined(ctx context.Context, txn *models.Transaction, reason models.DeclineReason) error {
+	if reason == "" {
+		return fmt.Errorf("%w: declined without a reason", models.ErrInvalidTransactionStatus)
+	}
+	txn.Status = models.TransactionStatusDeclined
+	txn.DeclineReason = reason
+	return r.Create(ctx, txn)
+}
+
+const findTransactionByIDQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE id = $1
+`
+
 // FindByID retrieves a transaction by its ID
 func (r *transactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
-	query := `
-		SELECT id, account_id, type, amount_cents, currency,
-		       reference_id, status, expires_at, metadata, created_at
-		FROM transactions
-		WHERE id = $1
-	`
-
-	var tx models.Transaction
-	var metadataJSON []byte
+	tx, err := r.findOne(ctx, r.sql.findByID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", models.ErrTransactionNotFound)
+	}
+	return tx, nil
+}
 
-	err := r.exec.QueryRowContext(ctx, query, id).Scan(
-		&tx.ID,
-		&tx.AccountID,
-		&tx.Type,
-		&tx.AmountCents,
-		&tx.Currency,
-		&tx.ReferenceID,
-		&tx.Status,
-		&tx.ExpiresAt,
-		&metadataJSON,
-		&tx.CreatedAt,
-	)
+const findTransactionsByIDsQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE id = ANY($1)
+`
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("transaction not found: %w", err)
+// FindByIDs retrieves the live transactions with the given IDs in one query, keyed by ID.
+// IDs with no transaction are absent from the map; an empty ids returns an empty map without querying.
+func (r *transactionRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Transaction, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*models.Transaction{}, nil
 	}
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.findByIDs, pq.Array(ids))
 	if err != nil {
-		return nil, fmt.Errorf("failed to find transaction: %w", err)
+		return nil, fmt.Errorf("failed to find transactions: %w", queryError(ctx, err))
 	}
+	defer rows.Close()
 
-	if metadataJSON != nil {
-		if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
+	txns, err := scanTransactions(rows)
+	if err != nil {
+		return nil, queryError(ctx, err)
 	}
 
-	return &tx, nil
+	byID := make(map[uuid.UUID]*models.Transaction, len(txns))
+	for _, txn := range txns {
+		byID[txn.ID] = txn
+	}
+	return byID, nil
 }
 
+const findTransactionByIDForUpdateQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE id = $1
+	FOR UPDATE
+`
+
 // FindByIDForUpdate retrieves a transaction by ID with a row lock (SELECT FOR UPDATE)
 // This must be called within a transaction to prevent race conditions
 func (r *transactionRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
-	query := `
-		SELECT id, account_id, type, amount_cents, currency,
-		       reference_id, status, expires_at, metadata, created_at
+	tx, err := r.findOne(ctx, r.sql.findByIDForUpdate, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", models.ErrTransactionNotFound)
+	}
+	return tx, nil
+}
+
+const findTransactionByReferenceIDQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE reference_id = $1 AND type = $2
+	LIMIT 1
+`
+
+// FindByReferenceID finds a transaction by its reference_id and type
+// This is used to check if a capture/void/refund/chargeback already exists for an authorization/capture
+func (r *transactionRepository) FindByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (*models.Transaction, error) {
+	// Not found is not an error for this use case
+	tx, err := r.findOne(ctx, r.sql.findByReferenceID, refID, txnType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction by reference: %w", err)
+	}
+	return tx, nil
+}
+
+// findOne runs a query selecting transactionColumns from transactions and returns its first row,
+// or nil when there is none
+func (r *transactionRepository) findOne(ctx context.Context, query string, args ...any) (*models.Transaction, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	txns, err := scanTransactions(rows)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	if len(txns) == 0 {
+		return nil, nil
+	}
+	return txns[0], nil
+}
+
+const sumByReferenceIDQuery = `
+	SELECT COALESCE(SUM(amount_cents), 0)
+	FROM transactions
+	WHERE reference_id = $1 AND type = $2
+`
+
+// SumByReferenceID totals the amounts of all transactions of a type that reference refID
+// This is used to cap the sum of partial refunds at the captured amount
+func (r *transactionRepository) SumByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var total int64
+	if err := r.exec.QueryRowContext(ctx, r.sql.sumByReferenceID, refID, txnType).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum transactions by reference: %w", queryError(ctx, err))
+	}
+
+	return total, nil
+}
+
+const countByAccountSinceQuery = `
+	SELECT COUNT(*)
+	FROM transactions
+	WHERE account_id = $1 AND type = $2 AND created_at >= $3 AND status <> $4
+`
+
+// CountByAccountSince counts an account's transactions of txnType created at or after since, whatever
+// their status except DECLINED
+func (r *transactionRepository) CountByAccountSince(
+	ctx context.Context,
+	accountID uuid.UUID,
+	txnType models.TransactionType,
+	since time.Time,
+) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var count int
+	if err := r.exec.QueryRowContext(ctx, r.sql.countByAccountSince, accountID, txnType, since, models.TransactionStatusDeclined).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count transactions by account: %w", queryError(ctx, err))
+	}
+
+	return count, nil
+}
+
+const sumByAccountSinceQuery = `
+	SELECT COALESCE(SUM(amount_cents), 0)
+	FROM transactions
+	WHERE account_id = $1 AND type = $2 AND currency = $3 AND created_at >= $4 AND status <> $5
+`
+
+// SumByAccountSince totals the amounts of an account's transactions of txnType in currency created at
+// or after since, except DECLINED ones
+func (r *transactionRepository) SumByAccountSince(
+	ctx context.Context,
+	accountID uuid.UUID,
+	txnType models.TransactionType,
+	currency string,
+	since time.Time,
+) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var total int64
+	err := r.exec.QueryRowContext(ctx, r.sql.sumByAccountSince, accountID, txnType, currency, since, models.TransactionStatusDeclined).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum transactions by account: %w", queryError(ctx, err))
+	}
+
+	return total, nil
+}
+
+const listByDateRangeQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE account_id = $1
+	  AND created_at BETWEEN COALESCE($2::timestamp, '-infinity') AND COALESCE($3::timestamp, 'infinity')
+	ORDER BY created_at ASC, id ASC
+	LIMIT $4 OFFSET $5
+`
+
+// ListByDateRange returns an account's transactions created within [from, to], oldest first
+// A zero from or to leaves that end of the range open
+func (r *transactionRepository) ListByDateRange(
+	ctx context.Context,
+	accountID uuid.UUID,
+	from, to time.Time,
+	limit, offset int,
+) ([]*models.Transaction, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if limit <= 0 || offset < 0 {
+		return nil, fmt.Errorf("invalid pagination: limit must be positive and offset non-negative")
+	}
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.listByDateRange, accountID, nullableTime(from), nullableTime(to), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions by date range: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	txns, err := scanTransactions(rows)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return txns, nil
+}
+
+const summarizeByAccountQuery = `
+	SELECT type, status, COUNT(*),
+	       COALESCE(SUM(COALESCE((metadata->'fx'->>'converted_amount')::BIGINT, amount_cents)), 0)
+	FROM (
+		SELECT type, status, amount_cents, metadata, created_at FROM transactions WHERE account_id = $1
+		UNION ALL
+		SELECT type, status, amount_cents, metadata, created_at FROM transactions_archive WHERE account_id = $1
+	) t
+	WHERE created_at BETWEEN COALESCE($2::timestamp, '-infinity') AND COALESCE($3::timestamp, 'infinity')
+	GROUP BY type, status
+	ORDER BY type, status
+`
+
+// SummarizeByAccount counts and sums an account's transactions by type and status in one grouped query
+// Archived transactions are included. Amounts are in the account currency, using the converted
+// amount of cross-currency transactions. Zero from or to leaves that end of the period open.
+func (r *transactionRepository) SummarizeByAccount(
+	ctx context.Context,
+	accountID uuid.UUID,
+	from, to time.Time,
+) (*models.TransactionSummary, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.summarizeByAccount, accountID, nullableTime(from), nullableTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	summary := &models.TransactionSummary{AccountID: accountID, From: from, To: to}
+	for rows.Next() {
+		var totals models.TransactionTotals
+		if err := rows.Scan(&totals.Type, &totals.Status, &totals.Count, &totals.AmountCents); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction summary: %w", queryError(ctx, err))
+		}
+		summary.Totals = append(summary.Totals, totals)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", queryError(ctx, err))
+	}
+
+	return summary, nil
+}
+
+// The outer join keeps the account count when there are no transactions
+const ledgerStatsQuery = `
+	SELECT a.total, t.type, t.status, COALESCE(t.n, 0)
+	FROM (SELECT COUNT(*) AS total FROM accounts) a
+	LEFT JOIN (
+		SELECT type, status, COUNT(*) AS n
 		FROM transactions
-		WHERE id = $1
-		FOR UPDATE
-	`
+		GROUP BY type, status
+	) t ON true
+	ORDER BY t.type, t.status
+`
 
-	var tx models.Transaction
-	var metadataJSON []byte
+// Stats counts live transactions by type and status, and accounts, in a single query.
+// ComputedAt is left for the caller to set.
+func (r *transactionRepository) Stats(ctx context.Context) (*models.LedgerStats, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
 
-	err := r.exec.QueryRowContext(ctx, query, id).Scan(
-		&tx.ID,
-		&tx.AccountID,
-		&tx.Type,
-		&tx.AmountCents,
-		&tx.Currency,
-		&tx.ReferenceID,
-		&tx.Status,
-		&tx.ExpiresAt,
-		&metadataJSON,
-		&tx.CreatedAt,
-	)
+	rows, err := r.exec.QueryContext(ctx, r.sql.stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count transactions: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("transaction not found: %w", err)
+	stats := &models.LedgerStats{}
+	for rows.Next() {
+		var txnType, status sql.NullString
+		var count int
+		if err := rows.Scan(&stats.Accounts, &txnType, &status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction counts: %w", err)
+		}
+		if !txnType.Valid {
+			continue
+		}
+		stats.Transactions = append(stats.Transactions, models.TransactionCount{
+			Type:   models.TransactionType(txnType.String),
+			Status: models.TransactionStatus(status.String),
+			Count:  count,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count transactions: %w", queryError(ctx, err))
 	}
+
+	return stats, nil
+}
+
+// Find returns a page of transactions matching filter, newest first, together with the
+// total number of matching transactions across all pages
+func (r *transactionRepository) Find(ctx context.Context, filter TransactionFilter) ([]*models.Transaction, int, error) {
+	return r.find(ctx, false, filter)
+}
+
+// FindAuthorizations returns a page of an account's authorization holds, newest first, with their total
+func (r *transactionRepository) FindAuthorizations(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit, offset int,
+) ([]*models.Transaction, int, error) {
+	return r.findByType(ctx, accountID, models.TransactionTypeAuthHold, limit, offset)
+}
+
+// FindCaptures returns a page of an account's captures, newest first, with their total
+func (r *transactionRepository) FindCaptures(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit, offset int,
+) ([]*models.Transaction, int, error) {
+	return r.findByType(ctx, accountID, models.TransactionTypeCapture, limit, offset)
+}
+
+// FindRefunds returns a page of an account's refunds, newest first, with their total
+func (r *transactionRepository) FindRefunds(
+	ctx context.Context,
+	accountID uuid.UUID,
+	limit, offset int,
+) ([]*models.Transaction, int, error) {
+	return r.findByType(ctx, accountID, models.TransactionTypeRefund, limit, offset)
+}
+
+// findByType is Find filtered to one account and transaction type
+func (r *transactionRepository) findByType(
+	ctx context.Context,
+	accountID uuid.UUID,
+	txnType models.TransactionType,
+	limit, offset int,
+) ([]*models.Transaction, int, error) {
+	return r.find(ctx, false, TransactionFilter{
+		AccountID: &accountID,
+		Type:      &txnType,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+// find runs a filtered, paginated query against transactions, or transactions_archive when archived is set
+func (r *transactionRepository) find(ctx context.Context, archived bool, filter TransactionFilter) ([]*models.Transaction, int, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if filter.Limit <= 0 || filter.Offset < 0 {
+		return nil, 0, fmt.Errorf("invalid pagination: limit must be positive and offset non-negative")
+	}
+	where, args, err := filterConditions(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find transaction: %w", err)
+		return nil, 0, err
 	}
 
-	if metadataJSON != nil {
-		if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	page := r.sql.find
+	if archived {
+		page = r.sql.findArchived
+	}
+
+	var total int
+	countQuery := page.count + " " + where
+	if err := r.exec.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", queryError(ctx, err))
+	}
+
+	query := fmt.Sprintf(`
+		%s
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, page.selectFrom, where, len(args)+1, len(args)+2)
+
+	rows, err := r.exec.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find transactions: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	txns, err := scanTransactionRows(rows, archived)
+	if err != nil {
+		return nil, 0, queryError(ctx, err)
+	}
+
+	return txns, total, nil
+}
+
+// Iterate calls fn with each live transaction matching filter, oldest first, reading one row at a
+// time so that any number of transactions can be processed without loading them all. A zero Limit
+// visits every match. Iteration stops at the first error from fn, which is returned unchanged.
+// No query timeout applies since the rows stay open while fn runs; bound it with ctx.
+func (r *transactionRepository) Iterate(
+	ctx context.Context,
+	filter TransactionFilter,
+	fn func(*models.Transaction) error,
+) error {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return fmt.Errorf("invalid pagination: limit and offset must be non-negative")
+	}
+	where, args, err := filterConditions(filter)
+	if err != nil {
+		return err
+	}
+
+	query := r.sql.iterate + " " + where + " ORDER BY created_at, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := r.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to iterate transactions: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		txn, err := scanTransactionRow(rows, false)
+		if err != nil {
+			return queryError(ctx, err)
 		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate transactions: %w", queryError(ctx, err))
 	}
 
-	return &tx, nil
+	return nil
 }
 
-// FindByReferenceID finds a transaction by its reference_id and type
-// This is used to check if a capture/void/refund already exists for an authorization/capture
-func (r *transactionRepository) FindByReferenceID(ctx context.Context, refID uuid.UUID, txnType models.TransactionType) (*models.Transaction, error) {
-	query := `
-		SELECT id, account_id, type, amount_cents, currency,
-		       reference_id, status, expires_at, metadata, created_at
+// filterConditions builds the WHERE clause and its arguments for filter, numbering parameters from $1
+func filterConditions(filter TransactionFilter) (string, []any, error) {
+	if filter.Currency != nil && !models.Currencies.IsValid(*filter.Currency) {
+		return "", nil, fmt.Errorf("%w: %q", models.ErrInvalidCurrency, *filter.Currency)
+	}
+
+	var conditions []string
+	var args []any
+	addCondition := func(column string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if filter.AccountID != nil {
+		addCondition("account_id", *filter.AccountID)
+	}
+	if filter.Type != nil {
+		addCondition("type", *filter.Type)
+	}
+	if filter.Status != nil {
+		addCondition("status", *filter.Status)
+	}
+	if filter.Currency != nil {
+		addCondition("currency", *filter.Currency)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+const findByMetadataQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE metadata @> $1::jsonb
+	ORDER BY created_at DESC, id DESC
+	LIMIT $2
+`
+
+// FindByMetadata returns up to limit transactions, newest first, whose metadata has key set to
+// the string value. The containment match is served by the GIN index on metadata; archived
+// transactions are not searched.
+func (r *transactionRepository) FindByMetadata(ctx context.Context, key, value string, limit int) ([]*models.Transaction, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if key == "" {
+		return nil, fmt.Errorf("metadata key cannot be empty")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("invalid pagination: limit must be positive")
+	}
+
+	contains, err := json.Marshal(map[string]string{key: value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata filter: %w", err)
+	}
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.findByMetadata, string(contains), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transactions by metadata: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	txns, err := scanTransactions(rows)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	return txns, nil
+}
+
+const exportByDateRangeQuery = `
+	SELECT t.id, t.account_id, a.account_number, t.type, t.status, t.amount_cents, t.currency,
+	       t.reference_id, t.created_at, t.updated_at
+	FROM (
+		SELECT id, account_id, type, status, amount_cents, currency, reference_id, created_at, updated_at
 		FROM transactions
-		WHERE reference_id = $1 AND type = $2
-		LIMIT 1
-	`
+		WHERE created_at >= $1 AND created_at < $2
+		UNION ALL
+		SELECT id, account_id, type, status, amount_cents, currency, reference_id, created_at, updated_at
+		FROM transactions_archive
+		WHERE created_at >= $1 AND created_at < $2
+	) t
+	JOIN accounts a ON a.id = t.account_id
+	ORDER BY t.created_at, t.id
+`
+
+// ExportByDateRange opens a cursor over every transaction, live or archived, created within [from, to),
+// oldest first. Read it with ScanExportedTransaction and close it when done.
+// No query timeout applies since the cursor stays open for as long as the export is read; bound it with ctx.
+func (r *transactionRepository) ExportByDateRange(ctx context.Context, from, to time.Time) (*sql.Rows, error) {
+	rows, err := r.exec.QueryContext(ctx, r.sql.exportByDateRange, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export transactions: %w", queryError(ctx, err))
+	}
+	return rows, nil
+}
 
+// ScanExportedTransaction reads the current row of an ExportByDateRange cursor.
+// The account number is masked here so the full card number never leaves the repository.
+func ScanExportedTransaction(rows *sql.Rows) (*models.ExportedTransaction, error) {
+	var txn models.ExportedTransaction
+	var accountNumber string
+	err := rows.Scan(
+		&txn.ID,
+		&txn.AccountID,
+		&accountNumber,
+		&txn.Type,
+		&txn.Status,
+		&txn.AmountCents,
+		&txn.Currency,
+		&txn.ReferenceID,
+		&txn.CreatedAt,
+		&txn.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan exported transaction: %w", err)
+	}
+
+	txn.MaskedAccountNumber = models.MaskAccountNumber(accountNumber)
+	return &txn, nil
+}
+
+const listAfterQuery = `
+	SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE account_id = $1
+	  AND ($2::timestamp IS NULL OR (created_at, id) < ($2, $3))
+	ORDER BY created_at DESC, id DESC
+	LIMIT $4
+`
+
+// ListAfter returns up to limit of an account's transactions that sort strictly after the
+// (afterCreatedAt, afterID) position, newest first. A zero afterCreatedAt starts from the newest
+// transaction. The returned cursor is nil once there are no further pages.
+func (r *transactionRepository) ListAfter(
+	ctx context.Context,
+	accountID uuid.UUID,
+	afterCreatedAt time.Time,
+	afterID uuid.UUID,
+	limit int,
+) ([]*models.Transaction, *TransactionCursor, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("invalid pagination: limit must be positive")
+	}
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.listAfter, accountID, nullableTime(afterCreatedAt), afterID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list transactions: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	txns, err := scanTransactions(rows)
+	if err != nil {
+		return nil, nil, queryError(ctx, err)
+	}
+
+	if len(txns) < limit {
+		return txns, nil, nil
+	}
+	last := txns[len(txns)-1]
+	return txns, &TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+const updateStatusQuery = `
+	UPDATE transactions
+	SET status = $2,
+	    updated_at = NOW()
+	WHERE id = $1
+`
+
+// UpdateStatus updates the status of a transaction and stamps updated_at
+func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.TransactionStatus) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.exec.ExecContext(ctx, r.sql.updateStatus, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", queryError(ctx, err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update transaction status: %w", models.ErrTransactionNotFound)
+	}
+
+	return nil
+}
+
+const mergeMetadataQuery = `
+	UPDATE transactions
+	SET metadata = COALESCE(metadata, '{}'::jsonb) || $2::jsonb,
+	    updated_at = NOW()
+	WHERE id = $1
+`
+
+// MergeMetadata sets fields in the transaction's metadata, keeping the keys it does not name
+func (r *transactionRepository) MergeMetadata(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	jsonBytes, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	// Passed as text for the same reason as in Create
+	result, err := r.exec.ExecContext(ctx, r.sql.mergeMetadata, id, string(jsonBytes))
+	if err != nil {
+		return fmt.Errorf("failed to update transaction metadata: %w", queryError(ctx, err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update transaction metadata: %w", models.ErrTransactionNotFound)
+	}
+
+	return nil
+}
+
+const archiveTransactionsQuery = `
+	WITH RECURSIVE chain AS (
+		SELECT id, id AS root_id
+		FROM transactions
+		WHERE reference_id IS NULL AND created_at < $1
+		UNION ALL
+		SELECT t.id, c.root_id
+		FROM transactions t
+		JOIN chain c ON t.reference_id = c.id
+	),
+	settled AS (
+		SELECT c.root_id
+		FROM chain c
+		JOIN transactions t ON t.id = c.id
+		GROUP BY c.root_id
+		HAVING MAX(t.created_at) < $1 AND BOOL_AND(t.status <> $2)
+	),
+	moved AS (
+		DELETE FROM transactions t
+		USING chain c, settled s
+		WHERE t.id = c.id AND c.root_id = s.root_id
+		RETURNING t.id, t.account_id, t.type, t.amount_cents, t.currency,
+		          t.reference_id, t.status, t.expires_at, t.metadata, t.created_at, t.updated_at,
+		          t.decline_reason
+	)
+	INSERT INTO transactions_archive (` + transactionColumns + `)
+	SELECT ` + transactionColumns + `
+	FROM moved
+`
+
+// Archive moves settled transaction chains whose newest entry was created before the cutoff into
+// transactions_archive, returning the number of transactions moved. A chain is an authorization
+// with its captures, voids, and the refunds and chargebacks of those captures; it is moved whole,
+// and only once none of its entries is still ACTIVE, so totals such as SumByReferenceID never
+// see half a chain. Run it in a SERIALIZABLE transaction so a payment racing the move aborts.
+func (r *transactionRepository) Archive(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := r.exec.ExecContext(ctx, r.sql.archive, before, models.TransactionStatusActive)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive transactions: %w", queryError(ctx, err))
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return archived, nil
+}
+
+const findArchivedByIDQuery = `
+	SELECT ` + transactionColumns + `, archived_at
+	FROM transactions_archive
+	WHERE id = $1
+`
+
+// FindArchivedByID retrieves a transaction that Archive moved out of the transactions table
+func (r *transactionRepository) FindArchivedByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.exec.QueryContext(ctx, r.sql.findArchivedByID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find archived transaction: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	txns, err := scanTransactionRows(rows, true)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	if len(txns) == 0 {
+		return nil, fmt.Errorf("failed to find archived transaction: %w", models.ErrTransactionNotFound)
+	}
+
+	return txns[0], nil
+}
+
+// FindArchived is Find over the archive
+func (r *transactionRepository) FindArchived(ctx context.Context, filter TransactionFilter) ([]*models.Transaction, int, error) {
+	return r.find(ctx, true, filter)
+}
+
+// scanTransactions reads every row of a transactions query and decodes its metadata
+func scanTransactions(rows *sql.Rows) ([]*models.Transaction, error) {
+	return scanTransactionRows(rows, false)
+}
+
+// scanTransactionRows is scanTransactions for rows that, when archived is set, end with archived_at
+func scanTransactionRows(rows *sql.Rows, archived bool) ([]*models.Transaction, error) {
+	var txns []*models.Transaction
+	for rows.Next() {
+		tx, err := scanTransactionRow(rows, archived)
+		if err != nil {
+			return nil, err
+		}
+		txns = append(txns, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+
+	return txns, nil
+}
+
+// scanTransactionRow reads the current row of a transactions query and decodes its metadata
+func scanTransactionRow(rows *sql.Rows, archived bool) (*models.Transaction, error) {
 	var tx models.Transaction
 	var metadataJSON []byte
+	var declineReason sql.NullString
 
-	err := r.exec.QueryRowContext(ctx, query, refID, txnType).Scan(
+	dest := []any{
 		&tx.ID,
 		&tx.AccountID,
 		&tx.Type,
@@ -185,14 +1064,16 @@ func (r *transactionRepository) FindByReferenceID(ctx context.Context, refID uui
 		&tx.ExpiresAt,
 		&metadataJSON,
 		&tx.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil // Not found is not an error for this use case
+		&tx.UpdatedAt,
+		&declineReason,
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to find transaction by reference: %w", err)
+	if archived {
+		dest = append(dest, &tx.ArchivedAt)
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf("failed to scan transaction: %w", err)
 	}
+	tx.DeclineReason = models.DeclineReason(declineReason.String)
 
 	if metadataJSON != nil {
 		if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
@@ -203,26 +1084,10 @@ func (r *transactionRepository) FindByReferenceID(ctx context.Context, refID uui
 	return &tx, nil
 }
 
-// UpdateStatus updates the status of a transaction
-func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.TransactionStatus) error {
-	query := `
-		UPDATE transactions
-		SET status = $2
-		WHERE id = $1
-	`
-
-	result, err := r.exec.ExecContext(ctx, query, id, status)
-	if err != nil {
-		return fmt.Errorf("failed to update transaction status: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("transaction not found")
+// nullableTime maps the zero time to NULL so SQL can treat it as unbounded
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
-
-	return nil
+	return &t
 }
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,9 +26,9 @@ func TestTransactionRepository_Create(t *testing.T) {
 	require.NoError(t, err, "failed to get account")
 
 	tests := []struct {
+		wantErr error
 		tx      *models.Transaction
 		name    string
-		wantErr bool
 	}{
 		{
 			name: "create AUTH_HOLD transaction",
@@ -37,7 +40,6 @@ func TestTransactionRepository_Create(t *testing.T) {
 				Status:      models.TransactionStatusActive,
 				ExpiresAt:   timePtr(time.Now().Add(7 * 24 * time.Hour)),
 			},
-			wantErr: false,
 		},
 		{
 			name: "create transaction with metadata",
@@ -52,7 +54,6 @@ func TestTransactionRepository_Create(t *testing.T) {
 					"order_id":    "12345",
 				},
 			},
-			wantErr: false,
 		},
 		{
 			name: "create transaction with pre-set ID",
@@ -60,11 +61,32 @@ func TestTransactionRepository_Create(t *testing.T) {
 				ID:          uuid.New(),
 				AccountID:   account.ID,
 				Type:        models.TransactionTypeVoid,
+				AmountCents: 10000,
+				Currency:    "USD",
+				Status:      models.TransactionStatusCompleted,
+			},
+		},
+		{
+			name: "reject zero amount",
+			tx: &models.Transaction{
+				AccountID:   account.ID,
+				Type:        models.TransactionTypeCapture,
 				AmountCents: 0,
 				Currency:    "USD",
 				Status:      models.TransactionStatusCompleted,
 			},
-			wantErr: false,
+			wantErr: models.ErrInvalidAmount,
+		},
+		{
+			name: "reject unknown currency",
+			tx: &models.Transaction{
+				AccountID:   account.ID,
+				Type:        models.TransactionTypeCapture,
+				AmountCents: 5000,
+				Currency:    "XXY",
+				Status:      models.TransactionStatusCompleted,
+			},
+			wantErr: models.ErrInvalidCurrency,
 		},
 	}
 
@@ -74,8 +96,8 @@ func TestTransactionRepository_Create(t *testing.T) {
 
 			err := repo.Create(context.Background(), tt.tx)
 
-			if tt.wantErr {
-				assert.Error(t, err, "expected error")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 				return
 			}
 
@@ -100,6 +122,38 @@ func TestTransactionRepository_Create(t *testing.T) {
 	}
 }
 
+func TestTransactionRepository_Create_MetadataTooLarge(t *testing.T) {
+	oversized := make(map[string]any, 100)
+	for i := range 100 {
+		oversized[fmt.Sprintf("key%03d", i)] = strings.Repeat("x", 200)
+	}
+
+	newTxn := func() *models.Transaction {
+		return &models.Transaction{
+			AccountID:   uuid.New(),
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    oversized,
+		}
+	}
+
+	// The size check runs before any query, so no database is needed
+	t.Run("default limit", func(t *testing.T) {
+		err := NewTransactionRepository(nil).Create(context.Background(), newTxn())
+		assert.ErrorIs(t, err, models.ErrMetadataTooLarge)
+	})
+
+	t.Run("configured limit", func(t *testing.T) {
+		txn := newTxn()
+		txn.Metadata = map[string]any{"note": strings.Repeat("x", 100)}
+
+		err := NewTransactionRepository(nil, WithMaxMetadataBytes(64)).Create(context.Background(), txn)
+		assert.ErrorIs(t, err, models.ErrMetadataTooLarge)
+	})
+}
+
 func TestTransactionRepository_FindByID(t *testing.T) {
 	database := setupTestDB(t)
 	defer cleanupTestDB(t, database)
@@ -228,6 +282,691 @@ func TestTransactionRepository_FindByReferenceID(t *testing.T) {
 	}
 }
 
+func TestTransactionRepository_SumByReferenceID(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	captureTx := &models.Transaction{
+		AccountID:   account.ID,
+		Type:        models.TransactionTypeCapture,
+		AmountCents: 10000,
+		Currency:    "USD",
+		Status:      models.TransactionStatusCompleted,
+	}
+	require.NoError(t, repo.Create(context.Background(), captureTx), "failed to create capture")
+
+	total, err := repo.SumByReferenceID(context.Background(), captureTx.ID, models.TransactionTypeRefund)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total, "no refunds yet")
+
+	for _, amount := range []int64{2500, 4000} {
+		refundTx := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeRefund,
+			AmountCents: amount,
+			Currency:    "USD",
+			Status:      models.TransactionStatusCompleted,
+			ReferenceID: &captureTx.ID,
+		}
+		require.NoError(t, repo.Create(context.Background(), refundTx), "failed to create refund")
+	}
+
+	total, err = repo.SumByReferenceID(context.Background(), captureTx.ID, models.TransactionTypeRefund)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6500), total, "refund total mismatch")
+}
+
+func TestTransactionRepository_CountByAccountSince(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+	other, err := accountRepo.FindByAccountNumber(context.Background(), "4242424242424242")
+	require.NoError(t, err, "failed to get account")
+
+	now := time.Now().UTC()
+	fixtures := []struct {
+		createdAt time.Time
+		txnType   models.TransactionType
+		status    models.TransactionStatus
+		accountID uuid.UUID
+	}{
+		{now.Add(-time.Minute), models.TransactionTypeAuthHold, models.TransactionStatusActive, account.ID},
+		{now.Add(-2 * time.Minute), models.TransactionTypeAuthHold, models.TransactionStatusVoided, account.ID},
+		{now.Add(-time.Hour), models.TransactionTypeAuthHold, models.TransactionStatusActive, account.ID},
+		{now.Add(-time.Minute), models.TransactionTypeCapture, models.TransactionStatusCompleted, account.ID},
+		{now.Add(-time.Minute), models.TransactionTypeAuthHold, models.TransactionStatusActive, other.ID},
+	}
+	for _, f := range fixtures {
+		txn := &models.Transaction{
+			AccountID:   f.accountID,
+			Type:        f.txnType,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      f.status,
+			CreatedAt:   f.createdAt,
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+	}
+
+	count, err := repo.CountByAccountSince(context.Background(), account.ID, models.TransactionTypeAuthHold, now.Add(-10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "only the account's holds inside the window count, whatever their status")
+
+	count, err = repo.CountByAccountSince(context.Background(), account.ID, models.TransactionTypeAuthHold, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	declined := &models.Transaction{AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 1000, Currency: "USD"}
+	require.NoError(t, repo.CreateDeclined(context.Background(), declined, models.DeclineReasonInsufficientFunds))
+	count, err = repo.CountByAccountSince(context.Background(), account.ID, models.TransactionTypeAuthHold, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count, "declined authorizations do not count")
+}
+
+func TestTransactionRepository_SumByAccountSince(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	account, err := NewAccountRepository(database).FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	now := time.Now().UTC()
+	fixtures := []struct {
+		createdAt time.Time
+		txnType   models.TransactionType
+		currency  string
+		amount    int64
+	}{
+		{now.Add(-time.Minute), models.TransactionTypeCapture, "USD", 1500},
+		{now.Add(-time.Hour), models.TransactionTypeCapture, "USD", 2500},
+		{now.Add(-time.Minute), models.TransactionTypeCapture, "EUR", 9900},
+		{now.Add(-time.Minute), models.TransactionTypeRefund, "USD", 700},
+		{now.Add(-48 * time.Hour), models.TransactionTypeCapture, "USD", 10000},
+	}
+	for _, f := range fixtures {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        f.txnType,
+			AmountCents: f.amount,
+			Currency:    f.currency,
+			Status:      models.TransactionStatusCompleted,
+			CreatedAt:   f.createdAt,
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+	}
+
+	total, err := repo.SumByAccountSince(context.Background(), account.ID, models.TransactionTypeCapture, "USD", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4000), total, "only the account's USD captures since the cutoff count")
+
+	total, err = repo.SumByAccountSince(context.Background(), uuid.New(), models.TransactionTypeCapture, "USD", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestTransactionRepository_CreateDeclined(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	account, err := NewAccountRepository(database).FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err)
+
+	declined := &models.Transaction{
+		AccountID:   account.ID,
+		Type:        models.TransactionTypeAuthHold,
+		AmountCents: 2500,
+		Currency:    "USD",
+	}
+	require.NoError(t, repo.CreateDeclined(context.Background(), declined, models.DeclineReasonCVVMismatch))
+
+	found, err := repo.FindByID(context.Background(), declined.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransactionStatusDeclined, found.Status)
+	assert.Equal(t, models.DeclineReasonCVVMismatch, found.DeclineReason)
+
+	active := &models.Transaction{
+		AccountID:   account.ID,
+		Type:        models.TransactionTypeAuthHold,
+		AmountCents: 2500,
+		Currency:    "USD",
+		Status:      models.TransactionStatusActive,
+	}
+	require.NoError(t, repo.Create(context.Background(), active))
+	found, err = repo.FindByID(context.Background(), active.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found.DeclineReason, "NULL reads back as no reason")
+
+	err = repo.CreateDeclined(context.Background(), &models.Transaction{
+		AccountID: account.ID, Type: models.TransactionTypeAuthHold, AmountCents: 1, Currency: "USD",
+	}, "")
+	assert.ErrorIs(t, err, models.ErrInvalidTransactionStatus)
+}
+
+func TestTransactionRepository_ListByDateRange(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var created []*models.Transaction
+	for day := 0; day < 4; day++ {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: int64(1000 * (day + 1)),
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    map[string]any{"day": day},
+			CreatedAt:   base.AddDate(0, 0, day),
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		created = append(created, txn)
+	}
+
+	tests := []struct {
+		from    time.Time
+		to      time.Time
+		name    string
+		wantIDs []uuid.UUID
+		limit   int
+		offset  int
+	}{
+		{
+			name:    "closed range is inclusive",
+			from:    base.AddDate(0, 0, 1),
+			to:      base.AddDate(0, 0, 2),
+			limit:   10,
+			wantIDs: []uuid.UUID{created[1].ID, created[2].ID},
+		},
+		{
+			name:    "open start",
+			to:      base.AddDate(0, 0, 1),
+			limit:   10,
+			wantIDs: []uuid.UUID{created[0].ID, created[1].ID},
+		},
+		{
+			name:    "open end",
+			from:    base.AddDate(0, 0, 3),
+			limit:   10,
+			wantIDs: []uuid.UUID{created[3].ID},
+		},
+		{
+			name:    "paged",
+			limit:   2,
+			offset:  1,
+			wantIDs: []uuid.UUID{created[1].ID, created[2].ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txns, err := repo.ListByDateRange(context.Background(), account.ID, tt.from, tt.to, tt.limit, tt.offset)
+			require.NoError(t, err, "unexpected error")
+
+			ids := make([]uuid.UUID, 0, len(txns))
+			for _, txn := range txns {
+				ids = append(ids, txn.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids, "unexpected transactions or order")
+		})
+	}
+
+	txns, err := repo.ListByDateRange(context.Background(), account.ID, time.Time{}, time.Time{}, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	assert.Equal(t, float64(0), txns[0].Metadata["day"], "metadata should be decoded")
+
+	_, err = repo.ListByDateRange(context.Background(), account.ID, time.Time{}, time.Time{}, 0, 0)
+	assert.Error(t, err, "zero limit should be rejected")
+}
+
+func TestTransactionRepository_SummarizeByAccount(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	ctx := context.Background()
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	create := func(txnType models.TransactionType, status models.TransactionStatus, amount int64, ref *uuid.UUID, createdAt time.Time) *models.Transaction {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        txnType,
+			AmountCents: amount,
+			Currency:    "USD",
+			ReferenceID: ref,
+			Status:      status,
+			CreatedAt:   createdAt,
+		}
+		require.NoError(t, repo.Create(ctx, txn), "failed to create transaction")
+		return txn
+	}
+
+	// A settled chain old enough to be archived
+	oldAuth := create(models.TransactionTypeAuthHold, models.TransactionStatusCompleted, 9000, nil, base.AddDate(0, 0, -10))
+	create(models.TransactionTypeCapture, models.TransactionStatusCompleted, 9000, &oldAuth.ID, base.AddDate(0, 0, -10).Add(time.Hour))
+	archived, err := repo.Archive(ctx, base.AddDate(0, 0, -5))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), archived)
+
+	auth := create(models.TransactionTypeAuthHold, models.TransactionStatusCompleted, 5000, nil, base)
+	create(models.TransactionTypeCapture, models.TransactionStatusCompleted, 3000, &auth.ID, base.Add(time.Hour))
+	fxCapture := &models.Transaction{
+		AccountID:   account.ID,
+		Type:        models.TransactionTypeCapture,
+		AmountCents: 1000,
+		Currency:    "EUR",
+		ReferenceID: &auth.ID,
+		Status:      models.TransactionStatusCompleted,
+		Metadata: map[string]any{models.MetadataKeyFX: models.FXConversion{
+			OriginalAmount: 1000, OriginalCurrency: "EUR", ConvertedAmount: 1100, ConvertedCurrency: "USD", Rate: "1.100000",
+		}},
+		CreatedAt: base.Add(2 * time.Hour),
+	}
+	require.NoError(t, repo.Create(ctx, fxCapture))
+	create(models.TransactionTypeRefund, models.TransactionStatusCompleted, 500, &fxCapture.ID, base.AddDate(0, 0, 1))
+	active := create(models.TransactionTypeAuthHold, models.TransactionStatusActive, 2000, nil, base.AddDate(0, 0, 1))
+	create(models.TransactionTypeVoid, models.TransactionStatusCompleted, 700, &active.ID, base.AddDate(0, 0, 2))
+
+	t.Run("whole period includes archived transactions", func(t *testing.T) {
+		summary, err := repo.SummarizeByAccount(ctx, account.ID, time.Time{}, time.Time{})
+		require.NoError(t, err)
+
+		assert.Equal(t, account.ID, summary.AccountID)
+		assert.Equal(t, models.TransactionTotals{Type: models.TransactionTypeCapture, Count: 3, AmountCents: 9000 + 3000 + 1100},
+			summary.ByType(models.TransactionTypeCapture), "cross-currency amounts are summed in the account currency")
+		assert.Equal(t, models.TransactionTotals{Type: models.TransactionTypeAuthHold, Count: 3, AmountCents: 9000 + 5000 + 2000},
+			summary.ByType(models.TransactionTypeAuthHold))
+		assert.Contains(t, summary.Totals, models.TransactionTotals{
+			Type: models.TransactionTypeAuthHold, Status: models.TransactionStatusActive, Count: 1, AmountCents: 2000,
+		})
+		assert.Len(t, summary.Totals, 5, "one row per type and status")
+	})
+
+	t.Run("bounded period", func(t *testing.T) {
+		summary, err := repo.SummarizeByAccount(ctx, account.ID, base.Add(time.Hour), base.AddDate(0, 0, 1))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), summary.ByType(models.TransactionTypeCapture).Count)
+		assert.Equal(t, int64(500), summary.ByType(models.TransactionTypeRefund).AmountCents)
+		assert.Equal(t, int64(1), summary.ByType(models.TransactionTypeAuthHold).Count)
+		assert.Zero(t, summary.ByType(models.TransactionTypeVoid).Count)
+	})
+
+	t.Run("account without transactions", func(t *testing.T) {
+		summary, err := repo.SummarizeByAccount(ctx, uuid.New(), time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.Empty(t, summary.Totals)
+	})
+}
+
+func TestTransactionRepository_Find(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	primary, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+	secondary, err := accountRepo.FindByAccountNumber(context.Background(), "4242424242424242")
+	require.NoError(t, err, "failed to get account")
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	fixtures := []struct {
+		txnType   models.TransactionType
+		status    models.TransactionStatus
+		currency  string
+		accountID uuid.UUID
+	}{
+		{models.TransactionTypeAuthHold, models.TransactionStatusActive, "USD", primary.ID},
+		{models.TransactionTypeAuthHold, models.TransactionStatusActive, "USD", primary.ID},
+		{models.TransactionTypeAuthHold, models.TransactionStatusVoided, "USD", primary.ID},
+		{models.TransactionTypeCapture, models.TransactionStatusCompleted, "USD", primary.ID},
+		{models.TransactionTypeAuthHold, models.TransactionStatusActive, "EUR", secondary.ID},
+	}
+	created := make([]*models.Transaction, len(fixtures))
+	for i, f := range fixtures {
+		txn := &models.Transaction{
+			AccountID:   f.accountID,
+			Type:        f.txnType,
+			AmountCents: 1000,
+			Currency:    f.currency,
+			Status:      f.status,
+			CreatedAt:   base.Add(time.Duration(i) * time.Hour),
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		created[i] = txn
+	}
+
+	authHold := models.TransactionTypeAuthHold
+	active := models.TransactionStatusActive
+	usd, eur := "USD", "EUR"
+
+	tests := []struct {
+		name      string
+		filter    TransactionFilter
+		wantIDs   []uuid.UUID
+		wantTotal int
+	}{
+		{
+			name:      "no filters",
+			filter:    TransactionFilter{Limit: 10},
+			wantIDs:   []uuid.UUID{created[4].ID, created[3].ID, created[2].ID, created[1].ID, created[0].ID},
+			wantTotal: 5,
+		},
+		{
+			name:      "account, type and status",
+			filter:    TransactionFilter{AccountID: &primary.ID, Type: &authHold, Status: &active, Limit: 10},
+			wantIDs:   []uuid.UUID{created[1].ID, created[0].ID},
+			wantTotal: 2,
+		},
+		{
+			name:      "type only",
+			filter:    TransactionFilter{Type: &authHold, Limit: 10},
+			wantIDs:   []uuid.UUID{created[4].ID, created[2].ID, created[1].ID, created[0].ID},
+			wantTotal: 4,
+		},
+		{
+			name:      "currency only",
+			filter:    TransactionFilter{Currency: &usd, Limit: 10},
+			wantIDs:   []uuid.UUID{created[3].ID, created[2].ID, created[1].ID, created[0].ID},
+			wantTotal: 4,
+		},
+		{
+			name:      "type and currency",
+			filter:    TransactionFilter{Type: &authHold, Currency: &eur, Limit: 10},
+			wantIDs:   []uuid.UUID{created[4].ID},
+			wantTotal: 1,
+		},
+		{
+			name:      "page reports full total",
+			filter:    TransactionFilter{AccountID: &primary.ID, Limit: 2, Offset: 1},
+			wantIDs:   []uuid.UUID{created[2].ID, created[1].ID},
+			wantTotal: 4,
+		},
+		{
+			name:      "offset past the end",
+			filter:    TransactionFilter{AccountID: &primary.ID, Limit: 2, Offset: 10},
+			wantIDs:   []uuid.UUID{},
+			wantTotal: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txns, total, err := repo.Find(context.Background(), tt.filter)
+			require.NoError(t, err, "unexpected error")
+
+			ids := make([]uuid.UUID, 0, len(txns))
+			for _, txn := range txns {
+				ids = append(ids, txn.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids, "unexpected transactions or order")
+			assert.Equal(t, tt.wantTotal, total, "unexpected total")
+		})
+	}
+
+	_, _, err = repo.Find(context.Background(), TransactionFilter{})
+	assert.Error(t, err, "zero limit should be rejected")
+
+	unknown := "XYZ"
+	_, _, err = repo.Find(context.Background(), TransactionFilter{Currency: &unknown, Limit: 10})
+	assert.ErrorIs(t, err, models.ErrInvalidCurrency)
+}
+
+func TestTransactionRepository_FindByType(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	accountRepo := NewAccountRepository(database)
+	primary, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to find primary account")
+	secondary, err := accountRepo.FindByAccountNumber(context.Background(), "4242424242424242")
+	require.NoError(t, err, "failed to find secondary account")
+
+	repo := NewTransactionRepository(database)
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	create := func(accountID uuid.UUID, txnType models.TransactionType, offset time.Duration) *models.Transaction {
+		txn := &models.Transaction{
+			AccountID:   accountID,
+			Type:        txnType,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusCompleted,
+			CreatedAt:   base.Add(offset),
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		return txn
+	}
+	olderAuth := create(primary.ID, models.TransactionTypeAuthHold, 0)
+	newerAuth := create(primary.ID, models.TransactionTypeAuthHold, time.Minute)
+	capture := create(primary.ID, models.TransactionTypeCapture, 2*time.Minute)
+	refund := create(primary.ID, models.TransactionTypeRefund, 3*time.Minute)
+	create(secondary.ID, models.TransactionTypeAuthHold, 4*time.Minute)
+
+	auths, total, err := repo.FindAuthorizations(context.Background(), primary.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, auths, 2)
+	assert.Equal(t, newerAuth.ID, auths[0].ID, "newest first")
+	assert.Equal(t, olderAuth.ID, auths[1].ID)
+
+	auths, total, err = repo.FindAuthorizations(context.Background(), primary.ID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total, "total covers every page")
+	require.Len(t, auths, 1)
+	assert.Equal(t, olderAuth.ID, auths[0].ID)
+
+	captures, total, err := repo.FindCaptures(context.Background(), primary.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, captures, 1)
+	assert.Equal(t, capture.ID, captures[0].ID)
+
+	refunds, total, err := repo.FindRefunds(context.Background(), primary.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, refunds, 1)
+	assert.Equal(t, refund.ID, refunds[0].ID)
+
+	_, _, err = repo.FindCaptures(context.Background(), primary.ID, 0, 0)
+	assert.Error(t, err, "zero limit should be rejected")
+}
+
+func TestTransactionRepository_FindByMetadata(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	metadata := []map[string]any{
+		{"order_id": "ord_1", "channel": "web"},
+		{"order_id": "ord_2"},
+		{"order_id": "ord_1"},
+		{"order_ref": "ord_1"},
+		nil,
+	}
+	created := make([]*models.Transaction, len(metadata))
+	for i, m := range metadata {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    m,
+			CreatedAt:   base.Add(time.Duration(i) * time.Hour),
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		created[i] = txn
+	}
+
+	txns, err := repo.FindByMetadata(context.Background(), "order_id", "ord_1", 10)
+	require.NoError(t, err, "unexpected error")
+	require.Len(t, txns, 2, "only transactions with the exact key and value should match")
+	assert.Equal(t, created[2].ID, txns[0].ID, "newest first")
+	assert.Equal(t, created[0].ID, txns[1].ID)
+	assert.Equal(t, "web", txns[1].Metadata["channel"])
+
+	txns, err = repo.FindByMetadata(context.Background(), "order_id", "ord_1", 1)
+	require.NoError(t, err, "unexpected error")
+	require.Len(t, txns, 1, "limit should be applied")
+	assert.Equal(t, created[2].ID, txns[0].ID)
+
+	txns, err = repo.FindByMetadata(context.Background(), "order_id", "ord_missing", 10)
+	require.NoError(t, err, "unexpected error")
+	assert.Empty(t, txns)
+
+	_, err = repo.FindByMetadata(context.Background(), "", "ord_1", 10)
+	assert.Error(t, err, "empty key should be rejected")
+	_, err = repo.FindByMetadata(context.Background(), "order_id", "ord_1", 0)
+	assert.Error(t, err, "zero limit should be rejected")
+}
+
+func TestTransactionRepository_FindByIDs(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	created := make([]*models.Transaction, 3)
+	for i := range created {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: int64(1000 * (i + 1)),
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    map[string]any{"order_id": fmt.Sprintf("ord_%d", i)},
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		created[i] = txn
+	}
+
+	missing := uuid.New()
+	txns, err := repo.FindByIDs(context.Background(), []uuid.UUID{created[2].ID, missing, created[0].ID})
+	require.NoError(t, err, "unexpected error")
+	require.Len(t, txns, 2, "unknown IDs should be absent")
+	assert.Equal(t, int64(3000), txns[created[2].ID].AmountCents)
+	assert.Equal(t, "ord_0", txns[created[0].ID].Metadata["order_id"])
+	assert.NotContains(t, txns, missing)
+
+	txns, err = repo.FindByIDs(context.Background(), nil)
+	require.NoError(t, err, "unexpected error")
+	assert.Empty(t, txns)
+}
+
+func TestTransactionRepository_FindByIDsEmptySkipsQuery(t *testing.T) {
+	// A nil executor would panic if the empty slice reached the database
+	repo := NewTransactionRepository(nil)
+
+	txns, err := repo.FindByIDs(context.Background(), []uuid.UUID{})
+	require.NoError(t, err)
+	assert.NotNil(t, txns, "callers index the result without a nil check")
+	assert.Empty(t, txns)
+}
+
+func TestTransactionRepository_ListAfter(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	// Two transactions share a timestamp so the id tiebreaker is exercised
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{base, base.Add(time.Hour), base.Add(time.Hour), base.Add(2 * time.Hour), base.Add(3 * time.Hour)}
+	want := make(map[uuid.UUID]bool)
+	for _, at := range createdAt {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			CreatedAt:   at,
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		want[txn.ID] = true
+	}
+
+	var walked []*models.Transaction
+	var cursor TransactionCursor
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 10, "pagination did not terminate")
+
+		page, next, err := repo.ListAfter(context.Background(), account.ID, cursor.CreatedAt, cursor.ID, 2)
+		require.NoError(t, err, "unexpected error")
+		walked = append(walked, page...)
+		if next == nil {
+			break
+		}
+		cursor = *next
+	}
+
+	require.Len(t, walked, len(createdAt), "every transaction should be visited once")
+	for i, txn := range walked {
+		assert.True(t, want[txn.ID], "unexpected transaction")
+		delete(want, txn.ID)
+		if i > 0 {
+			prev := walked[i-1]
+			assert.True(t, txn.CreatedAt.Before(prev.CreatedAt) ||
+				(txn.CreatedAt.Equal(prev.CreatedAt) && txn.ID.String() < prev.ID.String()),
+				"transactions should be ordered by (created_at, id) descending")
+		}
+	}
+
+	_, _, err = repo.ListAfter(context.Background(), account.ID, time.Time{}, uuid.Nil, 0)
+	assert.Error(t, err, "zero limit should be rejected")
+}
+
 func TestTransactionRepository_UpdateStatus(t *testing.T) {
 	database := setupTestDB(t)
 	defer cleanupTestDB(t, database)
@@ -284,10 +1023,266 @@ func TestTransactionRepository_UpdateStatus(t *testing.T) {
 			require.NoError(t, err, "failed to retrieve updated transaction")
 
 			assert.Equal(t, tt.newStatus, updated.Status, "status mismatch")
+			assert.True(t, updated.UpdatedAt.After(updated.CreatedAt), "updated_at should advance on status change")
 		})
 	}
 }
 
+func TestTransactionRepository_MergeMetadata(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	ctx := context.Background()
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	tx := &models.Transaction{
+		AccountID:   account.ID,
+		Type:        models.TransactionTypeAuthHold,
+		AmountCents: 10000,
+		Currency:    "USD",
+		Status:      models.TransactionStatusActive,
+		Metadata:    map[string]any{"order_id": "ord-1"},
+	}
+	require.NoError(t, repo.Create(ctx, tx))
+
+	require.NoError(t, repo.MergeMetadata(ctx, tx.ID, map[string]any{models.MetadataKeyAuthorizedAmount: int64(7000)}))
+
+	updated, err := repo.FindByID(ctx, tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ord-1", updated.Metadata["order_id"], "existing keys should be kept")
+	assert.Equal(t, int64(7000), updated.AuthorizedAmount())
+
+	err = repo.MergeMetadata(ctx, uuid.New(), map[string]any{"order_id": "ord-2"})
+	assert.ErrorIs(t, err, models.ErrTransactionNotFound)
+}
+
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestTransactionRepository_Archive(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	ctx := context.Background()
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(ctx, "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	create := func(txnType models.TransactionType, status models.TransactionStatus, ref *uuid.UUID, createdAt time.Time) *models.Transaction {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        txnType,
+			AmountCents: 1000,
+			Currency:    "USD",
+			ReferenceID: ref,
+			Status:      status,
+			CreatedAt:   createdAt,
+		}
+		require.NoError(t, repo.Create(ctx, txn), "failed to create transaction")
+		return txn
+	}
+	old := cutoff.Add(-30 * 24 * time.Hour)
+
+	// Settled before the cutoff: authorization, capture and refund move together
+	settledAuth := create(models.TransactionTypeAuthHold, models.TransactionStatusCompleted, nil, old)
+	settledCapture := create(models.TransactionTypeCapture, models.TransactionStatusCompleted, &settledAuth.ID, old.Add(time.Hour))
+	settledRefund := create(models.TransactionTypeRefund, models.TransactionStatusCompleted, &settledCapture.ID, old.Add(2*time.Hour))
+
+	// Old capture refunded after the cutoff: the whole chain stays
+	recentAuth := create(models.TransactionTypeAuthHold, models.TransactionStatusCompleted, nil, old)
+	recentCapture := create(models.TransactionTypeCapture, models.TransactionStatusCompleted, &recentAuth.ID, old.Add(time.Hour))
+	create(models.TransactionTypeRefund, models.TransactionStatusCompleted, &recentCapture.ID, cutoff.Add(time.Hour))
+
+	// Old authorization that is still partially capturable stays with its capture
+	activeAuth := create(models.TransactionTypeAuthHold, models.TransactionStatusActive, nil, old)
+	create(models.TransactionTypeCapture, models.TransactionStatusCompleted, &activeAuth.ID, old.Add(time.Hour))
+
+	archived, err := repo.Archive(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), archived)
+
+	for _, id := range []uuid.UUID{settledAuth.ID, settledCapture.ID, settledRefund.ID} {
+		_, err := repo.FindByID(ctx, id)
+		assert.ErrorIs(t, err, models.ErrTransactionNotFound, "archived transaction should leave the hot table")
+
+		txn, err := repo.FindArchivedByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, txn.ID)
+		assert.NotNil(t, txn.ArchivedAt)
+	}
+
+	_, total, err := repo.Find(ctx, TransactionFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 5, total, "unsettled chains should stay in the hot table")
+
+	capture := models.TransactionTypeCapture
+	archivedCaptures, archivedTotal, err := repo.FindArchived(ctx, TransactionFilter{Type: &capture, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, archivedTotal)
+	require.Len(t, archivedCaptures, 1)
+	assert.Equal(t, settledCapture.ID, archivedCaptures[0].ID)
+
+	_, err = repo.FindArchivedByID(ctx, recentAuth.ID)
+	assert.ErrorIs(t, err, models.ErrTransactionNotFound)
+
+	again, err := repo.Archive(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), again, "archiving is idempotent")
+}
+
+func TestTransactionRepository_ExportByDateRange(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	created := make([]*models.Transaction, 0, 4)
+	for _, at := range []time.Time{to.Add(-time.Minute), from, from.Add(-time.Minute), to} {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			CreatedAt:   at,
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		created = append(created, txn)
+	}
+
+	rows, err := repo.ExportByDateRange(context.Background(), from, to)
+	require.NoError(t, err, "unexpected error")
+	defer rows.Close()
+
+	var exported []*models.ExportedTransaction
+	for rows.Next() {
+		txn, err := ScanExportedTransaction(rows)
+		require.NoError(t, err, "unexpected scan error")
+		exported = append(exported, txn)
+	}
+	require.NoError(t, rows.Err())
+
+	require.Len(t, exported, 2, "only transactions within [from, to) should be exported")
+	assert.Equal(t, created[1].ID, exported[0].ID, "oldest first")
+	assert.Equal(t, created[0].ID, exported[1].ID)
+	assert.Equal(t, "************1111", exported[0].MaskedAccountNumber, "card number should be masked")
+}
+
+func TestTransactionRepository_Iterate(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	statuses := []models.TransactionStatus{
+		models.TransactionStatusActive,
+		models.TransactionStatusExpired,
+		models.TransactionStatusActive,
+		models.TransactionStatusActive,
+	}
+	created := make([]*models.Transaction, len(statuses))
+	for i, status := range statuses {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      status,
+			CreatedAt:   base.Add(time.Duration(i) * time.Hour),
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+		created[i] = txn
+	}
+
+	active := models.TransactionStatusActive
+	var visited []uuid.UUID
+	err = repo.Iterate(context.Background(), TransactionFilter{Status: &active}, func(txn *models.Transaction) error {
+		visited = append(visited, txn.ID)
+		return nil
+	})
+	require.NoError(t, err, "unexpected error")
+	assert.Equal(t, []uuid.UUID{created[0].ID, created[2].ID, created[3].ID}, visited, "matches oldest first")
+
+	visited = nil
+	err = repo.Iterate(context.Background(), TransactionFilter{Status: &active, Limit: 1, Offset: 1}, func(txn *models.Transaction) error {
+		visited = append(visited, txn.ID)
+		return nil
+	})
+	require.NoError(t, err, "unexpected error")
+	assert.Equal(t, []uuid.UUID{created[2].ID}, visited, "limit and offset should be applied")
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = repo.Iterate(context.Background(), TransactionFilter{}, func(*models.Transaction) error {
+		calls++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop, "fn's error should be returned")
+	assert.Equal(t, 1, calls, "iteration should stop at the first error")
+
+	err = repo.Iterate(context.Background(), TransactionFilter{Limit: -1}, func(*models.Transaction) error { return nil })
+	assert.Error(t, err, "negative limit should be rejected")
+}
+
+func TestTransactionRepository_Stats(t *testing.T) {
+	database := setupTestDB(t)
+	defer cleanupTestDB(t, database)
+	truncateTables(t, database)
+
+	repo := NewTransactionRepository(database)
+	accountRepo := NewAccountRepository(database)
+
+	stats, err := repo.Stats(context.Background())
+	require.NoError(t, err, "unexpected error")
+	assert.Positive(t, stats.Accounts, "accounts should be counted without transactions")
+	assert.Empty(t, stats.Transactions)
+	accounts := stats.Accounts
+
+	account, err := accountRepo.FindByAccountNumber(context.Background(), "4111111111111111")
+	require.NoError(t, err, "failed to get account")
+	for _, status := range []models.TransactionStatus{
+		models.TransactionStatusActive,
+		models.TransactionStatusActive,
+		models.TransactionStatusExpired,
+	} {
+		txn := &models.Transaction{
+			AccountID:   account.ID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 1000,
+			Currency:    "USD",
+			Status:      status,
+		}
+		require.NoError(t, repo.Create(context.Background(), txn), "failed to create transaction")
+	}
+
+	stats, err = repo.Stats(context.Background())
+	require.NoError(t, err, "unexpected error")
+	assert.Equal(t, accounts, stats.Accounts)
+	assert.Equal(t, []models.TransactionCount{
+		{Type: models.TransactionTypeAuthHold, Status: models.TransactionStatusActive, Count: 2},
+		{Type: models.TransactionTypeAuthHold, Status: models.TransactionStatusExpired, Count: 1},
+	}, stats.Transactions)
+}
@@ -0,0 +1,38 @@
+package respond
+
+// PaginatedResponse is the envelope for one page of a list endpoint
+//
+// Total counts every item matching the query, not just this page. NextOffset is the offset of the
+// following page and is omitted on the last one.
+type PaginatedResponse[T any] struct {
+	NextOffset *int `json:"next_offset,omitempty"`
+	Items      []T  `json:"items"`
+	Total      int  `json:"total"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+}
+
+// Paginate builds the envelope for a repository page fetched with limit and offset, converting
+// each row with convert. total is the count the repository reported for the whole query.
+func Paginate[M, T any](page []M, total, limit, offset int, convert func(M) T) PaginatedResponse[T] {
+	items := make([]T, 0, len(page))
+	for _, row := range page {
+		items = append(items, convert(row))
+	}
+
+	resp := PaginatedResponse[T]{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if next := offset + len(page); len(page) > 0 && next < total {
+		resp.NextOffset = &next
+	}
+	return resp
+}
+
+// HasMore reports whether items remain after this page
+func (p PaginatedResponse[T]) HasMore() bool {
+	return p.NextOffset != nil
+}
@@ -0,0 +1,52 @@
+package respond
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		nextOffset *int
+		name       string
+		page       []int
+		total      int
+		offset     int
+	}{
+		{name: "first of several pages", page: []int{1, 2}, total: 5, offset: 0, nextOffset: intPtr(2)},
+		{name: "last full page", page: []int{4, 5}, total: 5, offset: 3},
+		{name: "offset past the end", page: nil, total: 5, offset: 10},
+		{name: "empty result", page: nil, total: 0, offset: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := Paginate(tt.page, tt.total, 2, tt.offset, strconv.Itoa)
+
+			assert.Len(t, resp.Items, len(tt.page))
+			assert.Equal(t, tt.total, resp.Total)
+			assert.Equal(t, 2, resp.Limit)
+			assert.Equal(t, tt.offset, resp.Offset)
+			assert.Equal(t, tt.nextOffset, resp.NextOffset)
+			assert.Equal(t, tt.nextOffset != nil, resp.HasMore())
+		})
+	}
+}
+
+func TestPaginate_JSON(t *testing.T) {
+	body, err := json.Marshal(Paginate([]int(nil), 0, 20, 0, strconv.Itoa))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[],"total":0,"limit":20,"offset":0}`, string(body))
+
+	body, err = json.Marshal(Paginate([]int{7}, 3, 1, 0, strconv.Itoa))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":["7"],"total":3,"limit":1,"offset":0,"next_offset":1}`, string(body))
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,33 @@
+// Package respond writes JSON responses in the API's shared envelope.
+package respond
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/benx421/payment-gateway/bank/internal/api"
+)
+
+// JSON writes v as the JSON body of a response with the given status
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	//nolint:errcheck // Best effort response writing
+	json.NewEncoder(w).Encode(v)
+}
+
+// Error writes an error in the {"error":{"code":...,"message":...}} envelope
+func Error(w http.ResponseWriter, status int, code api.ErrorCode, message string) {
+	JSON(w, status, NewError(code, message))
+}
+
+// NewError builds the error envelope for code and message
+func NewError(code api.ErrorCode, message string) api.ErrorResponse {
+	return api.ErrorResponse{
+		Error: api.ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
@@ -0,0 +1,181 @@
+// Package seed loads development fixtures of accounts and transactions into the database.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is the contents of a seed file
+type Fixture struct {
+	Accounts     []Account     `yaml:"accounts"`
+	Transactions []Transaction `yaml:"transactions"`
+}
+
+// Account is an account to create unless one with the same number already exists
+type Account struct {
+	AvailableBalanceCents *int64 `yaml:"available_balance_cents"` // Defaults to BalanceCents
+	AccountNumber         string `yaml:"account_number"`
+	CVV                   string `yaml:"cvv"`
+	Currency              string `yaml:"currency"`
+	BalanceCents          int64  `yaml:"balance_cents"`
+	ExpiryMonth           int    `yaml:"expiry_month"`
+	ExpiryYear            int    `yaml:"expiry_year"`
+}
+
+// Transaction is a ledger entry to create unless one with the same ID already exists
+// Balances are not adjusted; fixture accounts should already reflect their transactions.
+type Transaction struct {
+	CreatedAt     *time.Time               `yaml:"created_at"`
+	ExpiresAt     *time.Time               `yaml:"expires_at"`
+	ReferenceID   *uuid.UUID               `yaml:"reference_id"`
+	Metadata      map[string]any           `yaml:"metadata"`
+	AccountNumber string                   `yaml:"account_number"`
+	Currency      string                   `yaml:"currency"`
+	Type          models.TransactionType   `yaml:"type"`
+	Status        models.TransactionStatus `yaml:"status"`
+	AmountCents   int64                    `yaml:"amount_cents"`
+	ID            uuid.UUID                `yaml:"id"`
+}
+
+// Result counts the rows a Load created and skipped
+type Result struct {
+	AccountsCreated     int
+	AccountsSkipped     int
+	TransactionsCreated int
+	TransactionsSkipped int
+}
+
+// ReadFile parses a YAML or JSON fixture, rejecting unknown fields
+func ReadFile(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator-controlled configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	// JSON is a subset of YAML, so one decoder handles both
+	var fixture Fixture
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse seed file %s: %w", path, err)
+	}
+
+	for i, txn := range fixture.Transactions {
+		if txn.ID == uuid.Nil {
+			return nil, fmt.Errorf("seed transaction %d: id is required so reruns can skip it", i)
+		}
+	}
+	return &fixture, nil
+}
+
+// Load inserts the fixture's accounts and then its transactions in a single database transaction,
+// skipping rows that already exist so it can run on every startup.
+func Load(
+	ctx context.Context,
+	database *db.DB,
+	fixture *Fixture,
+	accountOpts []repository.AccountOption,
+	txnOpts []repository.TransactionOption,
+) (Result, error) {
+	var result Result
+
+	tx, err := database.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
+	}()
+
+	accountRepo := repository.NewAccountRepository(tx, accountOpts...)
+	transactionRepo := repository.NewTransactionRepository(tx, txnOpts...)
+
+	for _, a := range fixture.Accounts {
+		_, err := accountRepo.FindByAccountNumber(ctx, a.AccountNumber)
+		if err == nil {
+			result.AccountsSkipped++
+			continue
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return result, err
+		}
+
+		available := a.BalanceCents
+		if a.AvailableBalanceCents != nil {
+			available = *a.AvailableBalanceCents
+		}
+		currency := a.Currency
+		if currency == "" {
+			currency = "USD"
+		}
+
+		if err := accountRepo.Create(ctx, &models.Account{
+			AccountNumber:         a.AccountNumber,
+			CVV:                   a.CVV,
+			ExpiryMonth:           a.ExpiryMonth,
+			ExpiryYear:            a.ExpiryYear,
+			BalanceCents:          a.BalanceCents,
+			AvailableBalanceCents: available,
+			Currency:              currency,
+		}); err != nil {
+			return result, fmt.Errorf("seed account %s: %w", models.MaskAccountNumber(a.AccountNumber), err)
+		}
+		result.AccountsCreated++
+	}
+
+	for _, t := range fixture.Transactions {
+		_, err := transactionRepo.FindByID(ctx, t.ID)
+		if err == nil {
+			result.TransactionsSkipped++
+			continue
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return result, err
+		}
+
+		account, err := accountRepo.FindByAccountNumber(ctx, t.AccountNumber)
+		if err != nil {
+			return result, fmt.Errorf("seed transaction %s: %w", t.ID, err)
+		}
+
+		txn := &models.Transaction{
+			ID:          t.ID,
+			AccountID:   account.ID,
+			Type:        t.Type,
+			Status:      t.Status,
+			AmountCents: t.AmountCents,
+			Currency:    t.Currency,
+			ReferenceID: t.ReferenceID,
+			ExpiresAt:   t.ExpiresAt,
+			Metadata:    t.Metadata,
+			CreatedAt:   time.Now(),
+		}
+		if txn.Currency == "" {
+			txn.Currency = account.Currency
+		}
+		if t.CreatedAt != nil {
+			txn.CreatedAt = *t.CreatedAt
+		}
+		if err := transactionRepo.Create(ctx, txn); err != nil {
+			return result, fmt.Errorf("seed transaction %s: %w", t.ID, err)
+		}
+		result.TransactionsCreated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
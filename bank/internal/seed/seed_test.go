@@ -0,0 +1,92 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFixture(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadFile_YAML(t *testing.T) {
+	path := writeFixture(t, "seed.yaml", `
+accounts:
+  - account_number: "4000056655665556"
+    cvv: "111"
+    expiry_month: 8
+    expiry_year: 2031
+    balance_cents: 250000
+transactions:
+  - id: 7b0e6c1a-2f4e-4d7b-9a51-3c8e2d1f0a01
+    account_number: "4000056655665556"
+    type: AUTH_HOLD
+    status: ACTIVE
+    amount_cents: 10000
+    expires_at: 2031-01-01T00:00:00Z
+`)
+
+	fixture, err := ReadFile(path)
+
+	require.NoError(t, err)
+	require.Len(t, fixture.Accounts, 1)
+	assert.Equal(t, "4000056655665556", fixture.Accounts[0].AccountNumber)
+	assert.Nil(t, fixture.Accounts[0].AvailableBalanceCents)
+	require.Len(t, fixture.Transactions, 1)
+	assert.Equal(t, "7b0e6c1a-2f4e-4d7b-9a51-3c8e2d1f0a01", fixture.Transactions[0].ID.String())
+	assert.Equal(t, models.TransactionTypeAuthHold, fixture.Transactions[0].Type)
+	require.NotNil(t, fixture.Transactions[0].ExpiresAt)
+	assert.Equal(t, 2031, fixture.Transactions[0].ExpiresAt.Year())
+}
+
+func TestReadFile_JSON(t *testing.T) {
+	path := writeFixture(t, "seed.json", `{"accounts": [{"account_number": "4000002760003184", "cvv": "222", "currency": "EUR", "balance_cents": 75000}]}`)
+
+	fixture, err := ReadFile(path)
+
+	require.NoError(t, err)
+	require.Len(t, fixture.Accounts, 1)
+	assert.Equal(t, "EUR", fixture.Accounts[0].Currency)
+	assert.Equal(t, int64(75000), fixture.Accounts[0].BalanceCents)
+}
+
+func TestReadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{
+			name:        "unknown field",
+			content:     "accounts:\n  - acount_number: \"4111111111111111\"\n",
+			errContains: "failed to parse seed file",
+		},
+		{
+			name:        "transaction without id",
+			content:     "transactions:\n  - account_number: \"4111111111111111\"\n    type: AUTH_HOLD\n",
+			errContains: "id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadFile(writeFixture(t, "seed.yaml", tt.content))
+			assert.ErrorContains(t, err, tt.errContains)
+		})
+	}
+}
+
+func TestReadFile_DevFixture(t *testing.T) {
+	fixture, err := ReadFile(filepath.Join("..", "..", "seeds", "dev.yaml"))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, fixture.Accounts)
+}
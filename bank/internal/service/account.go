@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AccountService handles read-only account lookups and balance reconciliation
+type AccountService struct {
+	db          *db.DB
+	accountOpts []repository.AccountOption
+}
+
+// NewAccountService creates a new AccountService
+func NewAccountService(database *db.DB) *AccountService {
+	accountOpts, _ := repositoryOptions(database, nil)
+	return &AccountService{db: database, accountOpts: accountOpts}
+}
+
+// GetAccount retrieves an account by its card number
+func (s *AccountService) GetAccount(ctx context.Context, accountNumber string) (*models.Account, error) {
+	repo := repository.NewAccountRepository(s.db.Reader(ctx), s.accountOpts...)
+	return accountResult(repo.FindByAccountNumber(ctx, accountNumber))
+}
+
+// GetAccountByID retrieves an account by its ID
+func (s *AccountService) GetAccountByID(ctx context.Context, id uuid.UUID) (*models.Account, error) {
+	repo := repository.NewAccountRepository(s.db.Reader(ctx), s.accountOpts...)
+	return accountResult(repo.FindByID(ctx, id))
+}
+
+// ListLowBalance returns a page of accounts whose available balance is below thresholdCents, lowest first
+func (s *AccountService) ListLowBalance(ctx context.Context, thresholdCents int64, limit, offset int) ([]*models.Account, error) {
+	accounts, err := repository.NewAccountRepository(s.db.Reader(ctx), s.accountOpts...).ListLowBalance(ctx, thresholdCents, limit, offset)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to list low balance accounts",
+			Err:     err,
+		}
+	}
+	return accounts, nil
+}
+
+// accountResult maps a repository account lookup to the service's errors
+func accountResult(account *models.Account, err error) (*models.Account, error) {
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, &ServiceError{
+			Code:    ErrCodeAccountNotFound,
+			Message: "account not found",
+			Err:     models.ErrAccountNotFound,
+		}
+	}
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to find account: %v", err),
+			Err:     err,
+		}
+	}
+
+	return account, nil
+}
+
+// ReconcileAccount compares the account's stored balance in its primary currency with the balance
+// its ledger adds up to and returns the difference, stored minus ledger. Any non-zero drift means
+// a balance was changed by something other than the transactions recorded against it.
+func (s *AccountService) ReconcileAccount(ctx context.Context, accountID uuid.UUID) (int64, error) {
+	repo := repository.NewAccountRepository(s.db, s.accountOpts...)
+	drift, err := repo.FindBalanceDrift(ctx, accountID)
+	if errors.Is(err, models.ErrAccountNotFound) {
+		return 0, &ServiceError{
+			Code:    ErrCodeAccountNotFound,
+			Message: "account not found",
+			Err:     err,
+		}
+	}
+	if err != nil {
+		return 0, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to reconcile account: %v", err),
+			Err:     err,
+		}
+	}
+
+	return drift.DriftCents(), nil
+}
+
+// ReconcileBalances returns every balance whose balance or available balance differs from its
+// ledger and publishes their number as the bank_balance_drifts gauge
+func (s *AccountService) ReconcileBalances(ctx context.Context) ([]*models.BalanceDrift, error) {
+	repo := repository.NewAccountRepository(s.db, s.accountOpts...)
+	drifts, err := repo.FindBalanceDrifts(ctx)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to reconcile balances: %v", err),
+			Err:     err,
+		}
+	}
+
+	metrics.BalanceDrifts.Set(float64(len(drifts)))
+	return drifts, nil
+}
+
+// balanceReason describes a balance change for the audit trail by the transaction that caused it
+func balanceReason(action string, txnID uuid.UUID) string {
+	return action + " " + txnID.String()
+}
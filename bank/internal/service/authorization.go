@@ -2,88 +2,193 @@ package service
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/cvvcrypt"
 	"github.com/benx421/payment-gateway/bank/internal/db"
 	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/benx421/payment-gateway/bank/internal/repository"
 	"github.com/google/uuid"
 )
 
+// HoldExpiry bounds how long authorization holds last
+type HoldExpiry struct {
+	Default time.Duration // Applied when the caller requests no expiry
+	Max     time.Duration // Furthest ahead a requested expiry may be
+}
+
+// AuthorizationLimits are the risk controls checked before an authorization touches the ledger
+type AuthorizationLimits struct {
+	MaxAmountByCurrency map[string]int64 // Overrides MaxAmount for the currencies it lists
+	MaxAmount           int64            // Largest single authorization in minor units of its currency. Unlimited when 0
+	VelocityWindow      time.Duration    // Period over which an account's authorizations are counted
+	VelocityMax         int              // Most authorizations an account may make within VelocityWindow. Unlimited when 0
+}
+
+// maxAmount returns the cap on an authorization in currency, or 0 when there is none
+func (l AuthorizationLimits) maxAmount(currency string) int64 {
+	if limit, ok := l.MaxAmountByCurrency[currency]; ok {
+		return limit
+	}
+	return l.MaxAmount
+}
+
+// declineReasons maps the errors that refuse an authorization for a known card to the reason recorded
+// with the declined transaction. Other failures, such as an unknown card, are not recorded.
+var declineReasons = map[error]models.DeclineReason{
+	ErrInsufficientFunds: models.DeclineReasonInsufficientFunds,
+	ErrCVVMismatch:       models.DeclineReasonCVVMismatch,
+	ErrCardExpired:       models.DeclineReasonExpiredCard,
+	ErrVelocityExceeded:  models.DeclineReasonVelocityExceeded,
+}
+
 // AuthorizationService handles payment authorization operations
 type AuthorizationService struct {
-	db              *db.DB
-	authExpiryHours int
+	db          *db.DB
+	notifier    Notifier
+	fx          FXProvider
+	cvvCipher   *cvvcrypt.Cipher
+	accountOpts []repository.AccountOption
+	txnOpts     []repository.TransactionOption
+	limits      AuthorizationLimits
+	expiry      HoldExpiry
 }
 
 // NewAuthorizationService creates a new AuthorizationService
+// The notifier is optional and receives the authorization once it is committed.
+// cvvCipher is optional and decrypts stored CVVs for verification.
+// txnOpts configure the transaction repositories the service creates
 func NewAuthorizationService(
 	database *db.DB,
-	authExpiryHours int,
+	notifier Notifier,
+	expiry HoldExpiry,
+	limits AuthorizationLimits,
+	fx FXProvider,
+	cvvCipher *cvvcrypt.Cipher,
+	txnOpts ...repository.TransactionOption,
 ) *AuthorizationService {
+	accountOpts, txnOpts := repositoryOptions(database, txnOpts)
 	return &AuthorizationService{
-		db:              database,
-		authExpiryHours: authExpiryHours,
+		db:          database,
+		notifier:    notifier,
+		fx:          fx,
+		cvvCipher:   cvvCipher,
+		txnOpts:     txnOpts,
+		accountOpts: accountOpts,
+		limits:      limits,
+		expiry:      expiry,
 	}
 }
 
 // Authorize creates an authorization hold on a customer's account
-func (s *AuthorizationService) Authorize(ctx context.Context, cardNumber, cvv string, amount int64) (*models.Transaction, error) {
-	if err := s.validateAuthorizationRequest(cardNumber, cvv, amount); err != nil {
+// The amount is in the given currency and is converted into the account currency when they differ.
+// The account is locked, checked, and its available balance reduced in a single serializable transaction,
+// so a failure at any step leaves the balances untouched. A card refused for one of the declineReasons
+// is recorded as a DECLINED authorization; no other failure reaches the transaction log.
+// expiresAt is optional; the hold otherwise lasts the configured default.
+func (s *AuthorizationService) Authorize(
+	ctx context.Context,
+	cardNumber, cvv string,
+	amount int64,
+	currency string,
+	expiresAt *time.Time,
+) (*models.Transaction, error) {
+	return s.authorize(ctx, cardNumber, cvv, amount, currency, expiresAt, false)
+}
+
+// SimulateAuthorization runs every check Authorize does and returns the authorization it would create,
+// without recording it or holding any funds
+func (s *AuthorizationService) SimulateAuthorization(
+	ctx context.Context,
+	cardNumber, cvv string,
+	amount int64,
+	currency string,
+	expiresAt *time.Time,
+) (*models.Transaction, error) {
+	return s.authorize(ctx, cardNumber, cvv, amount, currency, expiresAt, true)
+}
+
+func (s *AuthorizationService) authorize(
+	ctx context.Context,
+	cardNumber, cvv string,
+	amount int64,
+	currency string,
+	expiresAt *time.Time,
+	dryRun bool,
+) (*models.Transaction, error) {
+	if err := s.validateAuthorizationRequest(cardNumber, cvv, amount, currency, expiresAt); err != nil {
 		return nil, err
 	}
 
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	var authTx *models.Transaction
+	err := s.db.WithTransaction(ctx, db.SerializableTx(), func(tx *db.Tx) error {
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+
+		var err error
+		authTx, err = s.performAuthorization(ctx, txAccountRepo, txTransactionRepo, cardNumber, cvv, models.NewMoney(amount, currency), expiresAt, dryRun)
+		if err != nil || dryRun {
+			return err
+		}
+		return beforeCommit(ctx, tx, authTx)
+	})
 	if err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to start transaction: %v", err),
+		if !dryRun {
+			s.recordDecline(ctx, cardNumber, models.NewMoney(amount, currency), err)
 		}
+		return nil, transactionError(err)
 	}
-	defer func() {
-		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
-	}()
-
-	txAccountRepo := repository.NewAccountRepository(tx)
-	txTransactionRepo := repository.NewTransactionRepository(tx)
 
-	authTx, err := s.performAuthorization(ctx, txAccountRepo, txTransactionRepo, cardNumber, cvv, amount)
-	if err != nil {
-		return nil, err
+	if dryRun {
+		return authTx, nil
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to commit transaction: %v", err),
-		}
+	recordCommitted(authTx)
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, authTx)
 	}
 
 	return authTx, nil
 }
 
 // performAuthorization contains the core authorization business logic
+// A nil expiresAt applies the default hold duration.
+// With dryRun set it stops short of writing the authorization and adjusting the balance.
 func (s *AuthorizationService) performAuthorization(
 	ctx context.Context,
 	accountRepo repository.AccountRepository,
 	transactionRepo repository.TransactionRepository,
 	cardNumber, cvv string,
-	amount int64,
+	amount models.Money,
+	expiresAt *time.Time,
+	dryRun bool,
 ) (*models.Transaction, error) {
 	account, err := accountRepo.FindByAccountNumberForUpdate(ctx, cardNumber)
+	if db.IsRetryable(err) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, &ServiceError{
 			Code:    ErrCodeInvalidCard,
 			Message: "card not found or invalid",
+			Err:     ErrCardNotFound,
 		}
 	}
 
-	if account.CVV != cvv {
+	if err := VerifyCVV(s.cvvCipher, account.CVV, cvv); err != nil {
+		if errors.Is(err, ErrCVVMismatch) {
+			return nil, &ServiceError{
+				Code:    ErrCodeInvalidCVV,
+				Message: "CVV does not match",
+				Err:     ErrCVVMismatch,
+			}
+		}
 		return nil, &ServiceError{
-			Code:    ErrCodeInvalidCVV,
-			Message: "CVV does not match",
+			Code:    ErrCodeInternalError,
+			Message: "failed to verify cvv",
+			Err:     err,
 		}
 	}
 
@@ -91,63 +196,169 @@ func (s *AuthorizationService) performAuthorization(
 		return nil, &ServiceError{
 			Code:    ErrCodeCardExpired,
 			Message: err.Error(),
+			Err:     ErrCardExpired,
 		}
 	}
 
-	if account.AvailableBalanceCents < amount {
+	if err := s.checkVelocity(ctx, transactionRepo, account.ID); err != nil {
+		return nil, err
+	}
+
+	hold := amount
+	var metadata map[string]any
+	if account.Currency != amount.Currency {
+		rate, err := s.fx.Rate(ctx, amount.Currency, account.Currency)
+		if err != nil {
+			return nil, &ServiceError{
+				Code:    ErrCodeFXRateUnavailable,
+				Message: fmt.Sprintf("no exchange rate available from %s to %s", amount.Currency, account.Currency),
+				Err:     err,
+			}
+		}
+
+		hold, err = convertMoney(amount, account.Currency, rate)
+		if err != nil {
+			return nil, &ServiceError{
+				Code:    ErrCodeInvalidAmount,
+				Message: err.Error(),
+			}
+		}
+		metadata = map[string]any{models.MetadataKeyFX: newFXConversion(amount, hold, rate)}
+	}
+
+	if account.AvailableBalanceCents < hold.Cents {
 		return nil, &ServiceError{
 			Code:    ErrCodeInsufficientFunds,
 			Message: "insufficient funds",
+			Err:     ErrInsufficientFunds,
 		}
 	}
 
 	authID := uuid.New()
-	expiresAt := time.Now().Add(time.Duration(s.authExpiryHours) * time.Hour)
 	createdAt := time.Now()
+	if expiresAt == nil {
+		defaultExpiry := createdAt.Add(s.expiry.Default)
+		expiresAt = &defaultExpiry
+	}
 
 	authTx := &models.Transaction{
 		ID:          authID,
 		AccountID:   account.ID,
 		Type:        models.TransactionTypeAuthHold,
-		AmountCents: amount,
-		Currency:    "USD",
+		AmountCents: amount.Cents,
+		Currency:    amount.Currency,
 		Status:      models.TransactionStatusActive,
-		ExpiresAt:   &expiresAt,
+		ExpiresAt:   expiresAt,
+		Metadata:    metadata,
 		CreatedAt:   createdAt,
 	}
+	if dryRun {
+		return authTx, nil
+	}
 
 	if err := transactionRepo.Create(ctx, authTx); err != nil {
 		return nil, &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to create authorization: %v", err),
+			Message: "failed to create authorization",
+			Err:     err,
 		}
 	}
 
-	if err := accountRepo.AdjustBalances(ctx, account.ID, 0, -amount); err != nil {
+	if _, _, err := accountRepo.AdjustBalances(ctx, account.ID, models.NewMoney(0, hold.Currency), hold.Neg(),
+		balanceReason("authorization hold", authID)); err != nil {
 		return nil, &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to adjust balance: %v", err),
+			Message: "failed to adjust balance",
+			Err:     err,
 		}
 	}
 
 	return authTx, nil
 }
 
+// recordDecline stores a DECLINED authorization for the card when err refused it for one of the
+// declineReasons. It runs once the failed transaction has rolled back and, like notifications, is
+// best effort: the caller is told of the decline whether or not it could be recorded.
+func (s *AuthorizationService) recordDecline(ctx context.Context, cardNumber string, amount models.Money, err error) {
+	var svcErr *ServiceError
+	if !errors.As(err, &svcErr) {
+		return
+	}
+	reason, ok := declineReasons[svcErr.Err]
+	if !ok {
+		return
+	}
+
+	account, err := repository.NewAccountRepository(s.db, s.accountOpts...).FindByAccountNumber(ctx, cardNumber)
+	if err != nil {
+		return
+	}
+	declined := &models.Transaction{
+		AccountID:   account.ID,
+		Type:        models.TransactionTypeAuthHold,
+		AmountCents: amount.Cents,
+		Currency:    amount.Currency,
+		CreatedAt:   time.Now(),
+	}
+	if repository.NewTransactionRepository(s.db, s.txnOpts...).CreateDeclined(ctx, declined, reason) == nil {
+		recordCommitted(declined)
+	}
+}
+
+// checkVelocity rejects an authorization that would take the account past the configured number of
+// authorizations within the velocity window. The account row is already locked, so concurrent
+// authorizations on it are counted one after another.
+func (s *AuthorizationService) checkVelocity(ctx context.Context, transactionRepo repository.TransactionRepository, accountID uuid.UUID) error {
+	if s.limits.VelocityMax <= 0 {
+		return nil
+	}
+
+	since := time.Now().Add(-s.limits.VelocityWindow)
+	recent, err := transactionRepo.CountByAccountSince(ctx, accountID, models.TransactionTypeAuthHold, since)
+	if db.IsRetryable(err) {
+		return err
+	}
+	if err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to count recent authorizations",
+			Err:     err,
+		}
+	}
+
+	if recent >= s.limits.VelocityMax {
+		return &ServiceError{
+			Code:    ErrCodeVelocityExceeded,
+			Message: fmt.Sprintf("too many authorizations: at most %d allowed per %s", s.limits.VelocityMax, s.limits.VelocityWindow),
+			Err:     ErrVelocityExceeded,
+		}
+	}
+	return nil
+}
+
 // GetAuthorization retrieves an authorization by ID
 func (s *AuthorizationService) GetAuthorization(ctx context.Context, authID uuid.UUID) (*models.Transaction, error) {
-	repo := repository.NewTransactionRepository(s.db)
+	repo := repository.NewTransactionRepository(s.db, s.txnOpts...)
 	txn, err := repo.FindByID(ctx, authID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to find transaction",
+			Err:     err,
+		}
+	}
 	if err != nil || txn.Type != models.TransactionTypeAuthHold {
 		return nil, &ServiceError{
 			Code:    ErrCodeAuthNotFound,
 			Message: "authorization not found",
+			Err:     models.ErrTransactionNotFound,
 		}
 	}
 
 	return txn, nil
 }
 
-func (s *AuthorizationService) validateAuthorizationRequest(cardNumber, cvv string, amount int64) error {
+func (s *AuthorizationService) validateAuthorizationRequest(cardNumber, cvv string, amount int64, currency string, expiresAt *time.Time) error {
 	if err := ValidateLuhn(cardNumber); err != nil {
 		return &ServiceError{
 			Code:    ErrCodeInvalidCard,
@@ -169,5 +380,29 @@ func (s *AuthorizationService) validateAuthorizationRequest(cardNumber, cvv stri
 		}
 	}
 
+	if err := ValidateCurrency(currency); err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInvalidCurrency,
+			Message: err.Error(),
+		}
+	}
+
+	if limit := s.limits.maxAmount(currency); limit > 0 && amount > limit {
+		return &ServiceError{
+			Code:    ErrCodeAmountExceedsLimit,
+			Message: fmt.Sprintf("amount exceeds the maximum of %d for a single %s authorization", limit, currency),
+			Err:     ErrAmountExceedsLimit,
+		}
+	}
+
+	if expiresAt != nil {
+		if err := ValidateHoldExpiry(*expiresAt, time.Now(), s.expiry.Max); err != nil {
+			return &ServiceError{
+				Code:    ErrCodeInvalidExpiry,
+				Message: err.Error(),
+			}
+		}
+	}
+
 	return nil
 }
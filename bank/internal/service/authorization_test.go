@@ -3,20 +3,25 @@ package service
 import (
 	"context"
 	"database/sql"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/benx421/payment-gateway/bank/internal/repository/mocks"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+var testHoldExpiry = HoldExpiry{Default: 168 * time.Hour, Max: 720 * time.Hour}
+
 func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	t.Run("successful authorization", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		accountID := uuid.New()
@@ -32,13 +37,14 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 			ExpiryYear:            2030,
 			BalanceCents:          50000,
 			AvailableBalanceCents: 50000,
+			Currency:              "USD",
 		}
 
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).Return(account, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(0), int64(-10000)).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(-10000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -47,16 +53,47 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 		assert.Equal(t, amount, result.AmountCents)
 		assert.Equal(t, "USD", result.Currency)
 		assert.Equal(t, models.TransactionStatusActive, result.Status)
-		assert.NotNil(t, result.ExpiresAt)
+		require.NotNil(t, result.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(testHoldExpiry.Default), *result.ExpiresAt, time.Minute)
 
 		mockAccountRepo.AssertExpectations(t)
 		mockTxRepo.AssertExpectations(t)
 	})
 
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
+		ctx := context.Background()
+
+		account := &models.Account{
+			ID:                    uuid.New(),
+			AccountNumber:         "4111111111111111",
+			CVV:                   "123",
+			ExpiryMonth:           12,
+			ExpiryYear:            2030,
+			BalanceCents:          50000,
+			AvailableBalanceCents: 50000,
+			Currency:              "USD",
+		}
+
+		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, account.AccountNumber).Return(account, nil)
+
+		requested := time.Now().Add(48 * time.Hour)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, account.AccountNumber, "123", models.NewMoney(10000, "USD"), &requested, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, account.ID, result.AccountID)
+		assert.Equal(t, int64(10000), result.AmountCents)
+		assert.Equal(t, requested, *result.ExpiresAt)
+		mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockAccountRepo.AssertNotCalled(t, "AdjustBalances", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("account not found", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		cardNumber := "4111111111111111"
@@ -66,7 +103,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).
 			Return(nil, sql.ErrNoRows)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -75,6 +112,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 		if assert.ErrorAs(t, err, &svcErr) {
 			assert.Equal(t, ErrCodeInvalidCard, svcErr.Code)
 		}
+		assert.ErrorIs(t, err, ErrCardNotFound)
 
 		mockAccountRepo.AssertExpectations(t)
 	})
@@ -82,7 +120,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	t.Run("CVV mismatch", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		accountID := uuid.New()
@@ -98,11 +136,12 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 			ExpiryYear:            2030,
 			BalanceCents:          50000,
 			AvailableBalanceCents: 50000,
+			Currency:              "USD",
 		}
 
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).Return(account, nil)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -111,6 +150,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 		if assert.ErrorAs(t, err, &svcErr) {
 			assert.Equal(t, ErrCodeInvalidCVV, svcErr.Code)
 		}
+		assert.ErrorIs(t, err, ErrCVVMismatch)
 
 		mockAccountRepo.AssertExpectations(t)
 	})
@@ -118,7 +158,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	t.Run("card expired", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		accountID := uuid.New()
@@ -134,11 +174,12 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 			ExpiryYear:            2020, // Expired
 			BalanceCents:          50000,
 			AvailableBalanceCents: 50000,
+			Currency:              "USD",
 		}
 
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).Return(account, nil)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -147,6 +188,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 		if assert.ErrorAs(t, err, &svcErr) {
 			assert.Equal(t, ErrCodeCardExpired, svcErr.Code)
 		}
+		assert.ErrorIs(t, err, ErrCardExpired)
 
 		mockAccountRepo.AssertExpectations(t)
 	})
@@ -154,7 +196,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	t.Run("insufficient funds", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		accountID := uuid.New()
@@ -170,11 +212,12 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 			ExpiryYear:            2030,
 			BalanceCents:          5000,
 			AvailableBalanceCents: 5000, // Less than requested amount
+			Currency:              "USD",
 		}
 
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).Return(account, nil)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -183,6 +226,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 		if assert.ErrorAs(t, err, &svcErr) {
 			assert.Equal(t, ErrCodeInsufficientFunds, svcErr.Code)
 		}
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
 
 		mockAccountRepo.AssertExpectations(t)
 	})
@@ -190,7 +234,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	t.Run("transaction creation fails", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		accountID := uuid.New()
@@ -206,13 +250,14 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 			ExpiryYear:            2030,
 			BalanceCents:          50000,
 			AvailableBalanceCents: 50000,
+			Currency:              "USD",
 		}
 
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).Return(account, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
 			Return(models.ErrDuplicateTransaction)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -229,7 +274,7 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	t.Run("balance adjustment fails", func(t *testing.T) {
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
-		service := NewAuthorizationService(nil, 168)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 		ctx := context.Background()
 
 		accountID := uuid.New()
@@ -245,14 +290,15 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 			ExpiryYear:            2030,
 			BalanceCents:          50000,
 			AvailableBalanceCents: 50000,
+			Currency:              "USD",
 		}
 
 		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, cardNumber).Return(account, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(0), int64(-10000)).
-			Return(assert.AnError)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(-10000, "USD"), mock.Anything).
+			Return(models.Money{}, models.Money{}, assert.AnError)
 
-		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, amount)
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, cardNumber, cvv, models.NewMoney(amount, "USD"), nil, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -267,13 +313,89 @@ func TestAuthorizationService_PerformAuthorization(t *testing.T) {
 	})
 }
 
+func TestAuthorizationService_PerformAuthorization_CrossCurrency(t *testing.T) {
+	newAccount := func(id uuid.UUID) *models.Account {
+		return &models.Account{
+			ID:                    id,
+			AccountNumber:         "4111111111111111",
+			CVV:                   "123",
+			ExpiryMonth:           12,
+			ExpiryYear:            2030,
+			BalanceCents:          50000,
+			AvailableBalanceCents: 50000,
+			Currency:              "USD",
+		}
+	}
+
+	t.Run("converts hold into account currency", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		fx := NewStaticFXProvider()
+		fx.SetRate("EUR", "USD", big.NewRat(108, 100))
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, fx, nil)
+		ctx := context.Background()
+
+		accountID := uuid.New()
+		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, "4111111111111111").Return(newAccount(accountID), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(-10800, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, "4111111111111111", "123", models.NewMoney(10000, "EUR"), nil, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.NewMoney(10000, "EUR"), result.Amount())
+		assert.Equal(t, models.NewMoney(10800, "USD"), result.SettlementAmount())
+
+		conv, ok := result.FXConversion()
+		require.True(t, ok)
+		assert.Equal(t, "1.080000", conv.Rate)
+	})
+
+	t.Run("rejects when no rate is available", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
+		ctx := context.Background()
+
+		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, "4111111111111111").Return(newAccount(uuid.New()), nil)
+
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, "4111111111111111", "123", models.NewMoney(10000, "JPY"), nil, false)
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeFXRateUnavailable, svcErr.Code)
+		}
+		assert.ErrorIs(t, err, ErrFXRateUnavailable)
+	})
+
+	t.Run("converted hold counts against available balance", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		fx := NewStaticFXProvider()
+		fx.SetRate("GBP", "USD", big.NewRat(127, 100))
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, fx, nil)
+		ctx := context.Background()
+
+		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, "4111111111111111").Return(newAccount(uuid.New()), nil)
+
+		// 450.00 GBP is 571.50 USD, above the 500.00 USD available
+		_, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, "4111111111111111", "123", models.NewMoney(45000, "GBP"), nil, false)
+
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeInsufficientFunds, svcErr.Code)
+		}
+	})
+}
+
 func TestAuthorizationService_ValidateAuthorizationRequest(t *testing.T) {
-	service := NewAuthorizationService(nil, 168)
+	service := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
 
 	// Individual validators are already tested in validators_test.go
 	// This test verifies that validation errors are wrapped in ServiceError with correct codes
 	t.Run("wraps validation errors in ServiceError", func(t *testing.T) {
-		err := service.validateAuthorizationRequest("1234567890123456", "123", 10000)
+		err := service.validateAuthorizationRequest("1234567890123456", "123", 10000, "USD", nil)
 		assert.Error(t, err)
 
 		var svcErr *ServiceError
@@ -281,4 +403,106 @@ func TestAuthorizationService_ValidateAuthorizationRequest(t *testing.T) {
 			assert.Equal(t, ErrCodeInvalidCard, svcErr.Code)
 		}
 	})
+
+	t.Run("rejects expiry beyond the cap", func(t *testing.T) {
+		tooLate := time.Now().Add(testHoldExpiry.Max + time.Hour)
+		err := service.validateAuthorizationRequest("4111111111111111", "123", 10000, "USD", &tooLate)
+
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeInvalidExpiry, svcErr.Code)
+		}
+	})
+}
+
+func TestAuthorizationService_MaxAmount(t *testing.T) {
+	limits := AuthorizationLimits{
+		MaxAmount:           100000,
+		MaxAmountByCurrency: map[string]int64{"JPY": 5000000},
+	}
+	service := NewAuthorizationService(nil, nil, testHoldExpiry, limits, NewStaticFXProvider(), nil)
+
+	tests := []struct {
+		name     string
+		currency string
+		amount   int64
+		rejected bool
+	}{
+		{"at the default cap", "USD", 100000, false},
+		{"above the default cap", "USD", 100001, true},
+		{"currency override allows more", "JPY", 4000000, false},
+		{"above the currency override", "JPY", 5000001, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validateAuthorizationRequest("4111111111111111", "123", tt.amount, tt.currency, nil)
+			if !tt.rejected {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrAmountExceedsLimit)
+			var svcErr *ServiceError
+			if assert.ErrorAs(t, err, &svcErr) {
+				assert.Equal(t, ErrCodeAmountExceedsLimit, svcErr.Code)
+			}
+		})
+	}
+
+	t.Run("unlimited when no cap is configured", func(t *testing.T) {
+		unlimited := NewAuthorizationService(nil, nil, testHoldExpiry, AuthorizationLimits{}, NewStaticFXProvider(), nil)
+		assert.NoError(t, unlimited.validateAuthorizationRequest("4111111111111111", "123", 1<<40, "USD", nil))
+	})
+}
+
+func TestAuthorizationService_Velocity(t *testing.T) {
+	limits := AuthorizationLimits{VelocityWindow: 10 * time.Minute, VelocityMax: 3}
+	account := &models.Account{
+		ID:                    uuid.New(),
+		AccountNumber:         "4111111111111111",
+		CVV:                   "123",
+		ExpiryMonth:           12,
+		ExpiryYear:            2030,
+		BalanceCents:          50000,
+		AvailableBalanceCents: 50000,
+		Currency:              "USD",
+	}
+	inWindow := mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= limits.VelocityWindow && time.Since(since) < limits.VelocityWindow+time.Minute
+	})
+
+	t.Run("allows authorizations below the threshold", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, limits, NewStaticFXProvider(), nil)
+		ctx := context.Background()
+
+		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, account.AccountNumber).Return(account, nil)
+		mockTxRepo.On("CountByAccountSince", ctx, account.ID, models.TransactionTypeAuthHold, inWindow).Return(2, nil)
+
+		_, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, account.AccountNumber, "123", models.NewMoney(1000, "USD"), nil, true)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects once the threshold is reached", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		service := NewAuthorizationService(nil, nil, testHoldExpiry, limits, NewStaticFXProvider(), nil)
+		ctx := context.Background()
+
+		mockAccountRepo.On("FindByAccountNumberForUpdate", ctx, account.AccountNumber).Return(account, nil)
+		mockTxRepo.On("CountByAccountSince", ctx, account.ID, models.TransactionTypeAuthHold, inWindow).Return(3, nil)
+
+		result, err := service.performAuthorization(ctx, mockAccountRepo, mockTxRepo, account.AccountNumber, "123", models.NewMoney(1000, "USD"), nil, false)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrVelocityExceeded)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeVelocityExceeded, svcErr.Code)
+		}
+		mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 }
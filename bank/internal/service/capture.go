@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -13,88 +12,181 @@ import (
 	"github.com/google/uuid"
 )
 
+// CaptureLimits are the risk controls checked before a capture moves funds
+type CaptureLimits struct {
+	DailyMaxByCurrency map[string]int64 // Overrides DailyMax for the currencies it lists
+	DayLocation        *time.Location   // Time zone whose midnight starts a new day. UTC when nil
+	DailyMax           int64            // Most an account may capture per day, in minor units of the capture currency. Unlimited when 0
+}
+
+// dailyMax returns the daily capture limit in currency, or 0 when there is none
+func (l CaptureLimits) dailyMax(currency string) int64 {
+	if limit, ok := l.DailyMaxByCurrency[currency]; ok {
+		return limit
+	}
+	return l.DailyMax
+}
+
+// startOfDay returns the most recent midnight at or before t in the limits' time zone
+func (l CaptureLimits) startOfDay(t time.Time) time.Time {
+	loc := l.DayLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
 // CaptureService handles payment capture operations
 type CaptureService struct {
-	db *db.DB
+	db          *db.DB
+	notifier    Notifier
+	now         func() time.Time
+	limits      CaptureLimits
+	accountOpts []repository.AccountOption
+	txnOpts     []repository.TransactionOption
 }
 
 // NewCaptureService creates a new CaptureService
-func NewCaptureService(database *db.DB) *CaptureService {
+// The notifier is optional and receives the capture once it is committed.
+// txnOpts configure the transaction repositories the service creates.
+func NewCaptureService(
+	database *db.DB,
+	notifier Notifier,
+	limits CaptureLimits,
+	txnOpts ...repository.TransactionOption,
+) *CaptureService {
+	accountOpts, txnOpts := repositoryOptions(database, txnOpts)
 	return &CaptureService{
-		db: database,
+		db:          database,
+		notifier:    notifier,
+		now:         time.Now,
+		txnOpts:     txnOpts,
+		accountOpts: accountOpts,
+		limits:      limits,
 	}
 }
 
-// Capture captures an authorized payment
+// Capture captures all or part of an authorized payment
+// Several partial captures may be made against one authorization as long as their total stays
+// within the authorized amount; the authorization completes once it is fully captured.
+// It runs at SERIALIZABLE and is retried when it conflicts with a concurrent capture or void.
 func (s *CaptureService) Capture(ctx context.Context, authorizationID uuid.UUID, amount int64) (*models.Transaction, error) {
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
-	if err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to start transaction: %v", err),
-		}
-	}
-	defer func() {
-		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
-	}()
-
-	txTransactionRepo := repository.NewTransactionRepository(tx)
-	txAccountRepo := repository.NewAccountRepository(tx)
+	var captureTxn *models.Transaction
+	var authStatus models.TransactionStatus
+	var expiredErr error
+	err := s.db.WithTransaction(ctx, db.SerializableTx(), func(tx *db.Tx) error {
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
 
-	captureTxn, err := s.performCapture(ctx, txTransactionRepo, txAccountRepo, authorizationID, amount)
+		var err error
+		captureTxn, authStatus, err = s.performCapture(ctx, txTransactionRepo, txAccountRepo, authorizationID, amount)
+		if errors.Is(err, ErrAuthorizationExpired) {
+			// Persist the expiry and released hold even though the capture itself fails
+			expiredErr = err
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return beforeCommit(ctx, tx, captureTxn)
+	})
 	if err != nil {
-		return nil, err
+		return nil, transactionError(err)
+	}
+	if expiredErr != nil {
+		countTransaction(models.TransactionTypeAuthHold, models.TransactionStatusExpired)
+		return nil, expiredErr
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to commit transaction: %v", err),
-		}
+	recordCommitted(captureTxn)
+	if authStatus != "" {
+		countTransaction(models.TransactionTypeAuthHold, authStatus)
+	}
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, captureTxn)
 	}
 
 	return captureTxn, nil
 }
 
 // performCapture contains the core capture business logic
+// It returns the authorization's new status, or "" when it stays active.
 func (s *CaptureService) performCapture(
 	ctx context.Context,
 	transactionRepo repository.TransactionRepository,
 	accountRepo repository.AccountRepository,
 	authorizationID uuid.UUID,
 	amount int64,
-) (*models.Transaction, error) {
+) (*models.Transaction, models.TransactionStatus, error) {
 	authTxn, err := transactionRepo.FindByIDForUpdate(ctx, authorizationID)
+	if db.IsRetryable(err) {
+		return nil, "", err
+	}
 	if err != nil || authTxn.Type != models.TransactionTypeAuthHold {
-		return nil, &ServiceError{
+		return nil, "", &ServiceError{
 			Code:    ErrCodeAuthNotFound,
 			Message: "authorization not found",
 		}
 	}
 
 	if authTxn.Status != models.TransactionStatusActive {
-		return nil, &ServiceError{
+		return nil, "", &ServiceError{
 			Code:    ErrCodeAuthAlreadyUsed,
 			Message: "authorization has already been completed or cancelled",
 		}
 	}
 
-	if authTxn.ExpiresAt != nil && time.Now().After(*authTxn.ExpiresAt) {
-		return nil, &ServiceError{
-			Code:    ErrCodeAuthExpired,
-			Message: "authorization has expired",
+	if err := ValidateAmount(amount); err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInvalidAmount,
+			Message: err.Error(),
 		}
 	}
 
-	if amount != authTxn.AmountCents {
-		return nil, &ServiceError{
-			Code:    ErrCodeAmountMismatch,
-			Message: "capture amount does not match authorized amount",
+	// The authorization row lock above serializes concurrent captures, so the sum is stable
+	alreadyCaptured, err := transactionRepo.SumByReferenceID(ctx, authorizationID, models.TransactionTypeCapture)
+	if err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to sum existing captures",
+			Err:     err,
 		}
 	}
 
+	// Partial voids lower the authorized amount; what they released is consumed like a capture
+	authorized := authTxn.AuthorizedAmount()
+	consumed := alreadyCaptured + authTxn.AmountCents - authorized
+
+	if authTxn.ExpiresAt != nil && s.now().After(*authTxn.ExpiresAt) {
+		return nil, "", s.expireAuthorization(ctx, transactionRepo, accountRepo, authTxn, consumed)
+	}
+
+	remaining := authorized - alreadyCaptured
+	if amount > remaining {
+		return nil, "", &ServiceError{
+			Code: ErrCodeCaptureExceedsAuth,
+			Message: fmt.Sprintf("capture amount (%d) exceeds remaining authorized amount (%d)",
+				amount, remaining),
+			Err: ErrCaptureExceedsAuth,
+		}
+	}
+
+	capturedAt := s.now()
+	if err := s.checkDailyLimit(ctx, transactionRepo, authTxn, amount, capturedAt); err != nil {
+		return nil, "", err
+	}
+
 	captureID := uuid.New()
-	capturedAt := time.Now()
+
+	metadata, err := fxMetadata(authTxn, consumed, amount)
+	if err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to convert amount",
+			Err:     err,
+		}
+	}
 
 	captureTxn := &models.Transaction{
 		ID:          captureID,
@@ -104,44 +196,150 @@ func (s *CaptureService) performCapture(
 		Currency:    authTxn.Currency,
 		ReferenceID: &authorizationID,
 		Status:      models.TransactionStatusCompleted,
+		Metadata:    metadata,
 		CreatedAt:   capturedAt,
 	}
 
 	if err := transactionRepo.Create(ctx, captureTxn); err != nil {
 		if errors.Is(err, models.ErrDuplicateTransaction) {
-			return nil, &ServiceError{
+			return nil, "", &ServiceError{
 				Code:    ErrCodeAlreadyCaptured,
 				Message: "authorization has already been captured",
 			}
 		}
-		return nil, fmt.Errorf("failed to create capture: %w", err)
+		return nil, "", fmt.Errorf("failed to create capture: %w", err)
 	}
 
-	if err := transactionRepo.UpdateStatus(ctx, authorizationID, models.TransactionStatusCompleted); err != nil {
-		return nil, &ServiceError{
+	var authStatus models.TransactionStatus
+	if amount == remaining {
+		authStatus = models.TransactionStatusCompleted
+		if err := transactionRepo.UpdateStatus(ctx, authorizationID, authStatus); err != nil {
+			return nil, "", &ServiceError{
+				Code:    ErrCodeInternalError,
+				Message: "failed to update authorization",
+				Err:     err,
+			}
+		}
+	}
+
+	captured := captureTxn.SettlementAmount()
+	if _, _, err := accountRepo.AdjustBalances(ctx, authTxn.AccountID, captured.Neg(), models.NewMoney(0, captured.Currency),
+		balanceReason("capture", captureTxn.ID)); err != nil {
+		return nil, "", &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to update authorization: %v", err),
+			Message: "failed to adjust balance",
+			Err:     err,
 		}
 	}
 
-	if err := accountRepo.AdjustBalances(ctx, authTxn.AccountID, -amount, 0); err != nil {
-		return nil, &ServiceError{
+	return captureTxn, authStatus, nil
+}
+
+// checkDailyLimit rejects a capture that would take the account's captures in its currency since the
+// start of the day past the daily limit. Captures against other authorizations of the account are
+// not locked out, so a concurrent one fails the serializable transaction and is retried.
+func (s *CaptureService) checkDailyLimit(
+	ctx context.Context,
+	transactionRepo repository.TransactionRepository,
+	authTxn *models.Transaction,
+	amount int64,
+	now time.Time,
+) error {
+	limit := s.limits.dailyMax(authTxn.Currency)
+	if limit <= 0 {
+		return nil
+	}
+
+	captured, err := transactionRepo.SumByAccountSince(ctx, authTxn.AccountID, models.TransactionTypeCapture,
+		authTxn.Currency, s.limits.startOfDay(now))
+	if db.IsRetryable(err) {
+		return err
+	}
+	if err != nil {
+		return &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to adjust balance: %v", err),
+			Message: "failed to sum today's captures",
+			Err:     err,
 		}
 	}
 
-	return captureTxn, nil
+	if captured+amount > limit {
+		return &ServiceError{
+			Code: ErrCodeDailyLimitExceeded,
+			Message: fmt.Sprintf("capture of %d would exceed the daily limit of %d %s (%d already captured today)",
+				amount, limit, authTxn.Currency, captured),
+			Err: ErrDailyLimitExceeded,
+		}
+	}
+	return nil
+}
+
+// expireAuthorization marks a lapsed authorization EXPIRED and releases the part of its hold
+// that was not captured or partially voided before it lapsed.
+// It always returns an error: the expiry error on success, or the failure that prevented it.
+func (s *CaptureService) expireAuthorization(
+	ctx context.Context,
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.AccountRepository,
+	authTxn *models.Transaction,
+	consumed int64,
+) error {
+	if err := transactionRepo.UpdateStatus(ctx, authTxn.ID, models.TransactionStatusExpired); err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to expire authorization",
+			Err:     err,
+		}
+	}
+
+	settled, err := proratedSettlement(authTxn, consumed)
+	if err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to convert amount",
+			Err:     err,
+		}
+	}
+	released, err := authTxn.SettlementAmount().Sub(settled)
+	if err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to compute released hold",
+			Err:     err,
+		}
+	}
+	if _, _, err := accountRepo.AdjustBalances(ctx, authTxn.AccountID, models.NewMoney(0, released.Currency), released,
+		balanceReason("authorization expired", authTxn.ID)); err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to release expired hold",
+			Err:     err,
+		}
+	}
+
+	return &ServiceError{
+		Code:    ErrCodeAuthExpired,
+		Message: "authorization has expired",
+		Err:     ErrAuthorizationExpired,
+	}
 }
 
 // GetCapture retrieves a capture by ID
 func (s *CaptureService) GetCapture(ctx context.Context, captureID uuid.UUID) (*models.Transaction, error) {
-	repo := repository.NewTransactionRepository(s.db)
+	repo := repository.NewTransactionRepository(s.db, s.txnOpts...)
 	txn, err := repo.FindByID(ctx, captureID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to find transaction",
+			Err:     err,
+		}
+	}
 	if err != nil || txn.Type != models.TransactionTypeCapture {
 		return nil, &ServiceError{
 			Code:    ErrCodeCaptureNotFound,
 			Message: "capture not found",
+			Err:     models.ErrTransactionNotFound,
 		}
 	}
 
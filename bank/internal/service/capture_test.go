@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,13 +12,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("successful capture", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -36,13 +38,17 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
 		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(-10000), int64(0)).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(-10000, "USD"), models.NewMoney(0, "USD"),
+			mock.MatchedBy(func(reason string) bool { return strings.HasPrefix(reason, "capture ") })).
+			Return(models.Money{}, models.Money{}, nil)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, authStatus, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.NoError(t, err)
+		assert.Equal(t, models.TransactionStatusCompleted, authStatus)
 		assert.NotNil(t, result)
 		assert.Equal(t, models.TransactionTypeCapture, result.Type)
 		assert.Equal(t, amount, result.AmountCents)
@@ -56,7 +62,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("authorization not found", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -64,7 +70,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(nil, sql.ErrNoRows)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -80,7 +86,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("wrong transaction type", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -98,7 +104,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(captureTx, nil)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -114,7 +120,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("authorization already used", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -131,7 +137,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -147,7 +153,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("authorization expired", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -160,16 +166,21 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 			AccountID:   accountID,
 			Type:        models.TransactionTypeAuthHold,
 			AmountCents: amount,
+			Currency:    "USD",
 			Status:      models.TransactionStatusActive,
 			ExpiresAt:   &expiresAt,
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusExpired).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(10000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrAuthorizationExpired)
 
 		var svcErr *ServiceError
 		if assert.ErrorAs(t, err, &svcErr) {
@@ -177,46 +188,172 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 		}
 
 		mockTxRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
 	})
 
-	t.Run("amount mismatch", func(t *testing.T) {
+	t.Run("capture exceeds remaining authorization", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
 		accountID := uuid.New()
-		var authAmount int64 = 10000
-		var captureAmount int64 = 5000 // Different amount
 
 		authTx := &models.Transaction{
 			ID:          authID,
 			AccountID:   accountID,
 			Type:        models.TransactionTypeAuthHold,
-			AmountCents: authAmount,
+			AmountCents: 10000,
+			Currency:    "USD",
 			Status:      models.TransactionStatusActive,
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(6000), nil)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, captureAmount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 5000)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrCaptureExceedsAuth)
 
 		var svcErr *ServiceError
 		if assert.ErrorAs(t, err, &svcErr) {
-			assert.Equal(t, ErrCodeAmountMismatch, svcErr.Code)
+			assert.Equal(t, ErrCodeCaptureExceedsAuth, svcErr.Code)
+		}
+
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("capture limited by partial void", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
+		ctx := context.Background()
+
+		authID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    map[string]any{models.MetadataKeyAuthorizedAmount: float64(7000)},
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
+
+		_, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 7001)
+
+		assert.ErrorIs(t, err, ErrCaptureExceedsAuth)
+	})
+
+	t.Run("partial capture keeps authorization active", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(-4000, "USD"), models.NewMoney(0, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 4000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4000), result.AmountCents)
+
+		mockTxRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		mockTxRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("final partial capture completes authorization", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
 		}
 
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(6000), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(-4000, "USD"), models.NewMoney(0, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 4000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4000), result.AmountCents)
+
 		mockTxRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("expiry releases only the uncaptured hold", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+		expiresAt := time.Now().Add(-1 * time.Hour)
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			ExpiresAt:   &expiresAt,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(4000), nil)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusExpired).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(6000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		_, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 6000)
+
+		assert.ErrorIs(t, err, ErrAuthorizationExpired)
+
+		mockTxRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
 	})
 
 	t.Run("already captured - duplicate error", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -232,10 +369,11 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
 			Return(models.ErrDuplicateTransaction)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -251,7 +389,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("status update fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -267,11 +405,12 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
 		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).
 			Return(assert.AnError)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -287,7 +426,7 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 	t.Run("balance adjustment fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewCaptureService(nil)
+		service := NewCaptureService(nil, nil, CaptureLimits{})
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -299,16 +438,18 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 			AccountID:   accountID,
 			Type:        models.TransactionTypeAuthHold,
 			AmountCents: amount,
+			Currency:    "USD",
 			Status:      models.TransactionStatusActive,
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
 		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(-10000), int64(0)).
-			Return(assert.AnError)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(-10000, "USD"), models.NewMoney(0, "USD"), mock.Anything).
+			Return(models.Money{}, models.Money{}, assert.AnError)
 
-		result, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
+		result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, amount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -322,3 +463,153 @@ func TestCaptureService_PerformCapture(t *testing.T) {
 		mockAccountRepo.AssertExpectations(t)
 	})
 }
+
+func TestCaptureService_PerformCapture_ExpiryBoundary(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		now         time.Time
+		name        string
+		wantExpired bool
+	}{
+		{name: "just before expiry", now: expiresAt.Add(-time.Millisecond), wantExpired: false},
+		{name: "exactly at expiry", now: expiresAt, wantExpired: false},
+		{name: "just after expiry", now: expiresAt.Add(time.Millisecond), wantExpired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTxRepo := mocks.NewMockTransactionRepository(t)
+			mockAccountRepo := mocks.NewMockAccountRepository(t)
+			service := NewCaptureService(nil, nil, CaptureLimits{})
+			service.now = func() time.Time { return tt.now }
+			ctx := context.Background()
+
+			authID := uuid.New()
+			accountID := uuid.New()
+			authExpiresAt := expiresAt
+
+			authTx := &models.Transaction{
+				ID:          authID,
+				AccountID:   accountID,
+				Type:        models.TransactionTypeAuthHold,
+				AmountCents: 10000,
+				Currency:    "USD",
+				Status:      models.TransactionStatusActive,
+				ExpiresAt:   &authExpiresAt,
+			}
+
+			mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+			mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
+			if tt.wantExpired {
+				mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusExpired).Return(nil)
+				mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(10000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+			} else {
+				mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+				mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
+				mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(-10000, "USD"), models.NewMoney(0, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+			}
+
+			result, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 10000)
+
+			if tt.wantExpired {
+				assert.ErrorIs(t, err, ErrAuthorizationExpired)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+
+			mockTxRepo.AssertExpectations(t)
+			mockAccountRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCaptureService_DailyLimit(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 02:00 UTC is still the previous evening in New York
+	now := time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expectedDay   time.Time
+		name          string
+		limits        CaptureLimits
+		capturedSoFar int64
+		wantErr       bool
+	}{
+		{
+			name:          "within the limit",
+			limits:        CaptureLimits{DailyMax: 50000},
+			capturedSoFar: 40000,
+			expectedDay:   time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "exceeds the limit",
+			limits:        CaptureLimits{DailyMax: 50000},
+			capturedSoFar: 40001,
+			expectedDay:   time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			wantErr:       true,
+		},
+		{
+			name:          "per-currency limit overrides the default",
+			limits:        CaptureLimits{DailyMax: 1_000_000, DailyMaxByCurrency: map[string]int64{"USD": 20000}},
+			capturedSoFar: 15000,
+			expectedDay:   time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			wantErr:       true,
+		},
+		{
+			name:          "day starts at midnight in the configured time zone",
+			limits:        CaptureLimits{DailyMax: 50000, DayLocation: newYork},
+			capturedSoFar: 0,
+			expectedDay:   time.Date(2026, 3, 9, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTxRepo := mocks.NewMockTransactionRepository(t)
+			mockAccountRepo := mocks.NewMockAccountRepository(t)
+			service := NewCaptureService(nil, nil, tt.limits)
+			service.now = func() time.Time { return now }
+			ctx := context.Background()
+
+			authID := uuid.New()
+			accountID := uuid.New()
+			expiresAt := now.Add(24 * time.Hour)
+			authTx := &models.Transaction{
+				ID:          authID,
+				AccountID:   accountID,
+				Type:        models.TransactionTypeAuthHold,
+				AmountCents: 10000,
+				Currency:    "USD",
+				Status:      models.TransactionStatusActive,
+				ExpiresAt:   &expiresAt,
+			}
+
+			mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+			mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
+			mockTxRepo.On("SumByAccountSince", ctx, accountID, models.TransactionTypeCapture, "USD",
+				mock.MatchedBy(func(since time.Time) bool { return since.Equal(tt.expectedDay) })).
+				Return(tt.capturedSoFar, nil)
+			if !tt.wantErr {
+				mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+				mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
+				mockAccountRepo.On("AdjustBalances", ctx, accountID, mock.Anything, mock.Anything, mock.Anything).
+					Return(models.Money{}, models.Money{}, nil)
+			}
+
+			_, _, err := service.performCapture(ctx, mockTxRepo, mockAccountRepo, authID, 10000)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrDailyLimitExceeded)
+				var svcErr *ServiceError
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, ErrCodeDailyLimitExceeded, svcErr.Code)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
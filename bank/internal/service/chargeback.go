@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ChargebackService handles disputes raised by the cardholder's bank against a capture
+type ChargebackService struct {
+	db          *db.DB
+	notifier    Notifier
+	accountOpts []repository.AccountOption
+	txnOpts     []repository.TransactionOption
+}
+
+// NewChargebackService creates a new ChargebackService
+// The notifier is optional and receives the chargeback once it is committed.
+// txnOpts configure the transaction repositories the service creates.
+func NewChargebackService(database *db.DB, notifier Notifier, txnOpts ...repository.TransactionOption) *ChargebackService {
+	accountOpts, txnOpts := repositoryOptions(database, txnOpts)
+	return &ChargebackService{
+		db:          database,
+		notifier:    notifier,
+		txnOpts:     txnOpts,
+		accountOpts: accountOpts,
+	}
+}
+
+// Chargeback reverses all or part of a captured payment on the cardholder's behalf
+// Unlike a refund, it is forced on the merchant: the disputed amount is credited back to the
+// cardholder's account, which the capture debited, and the merchant is debited for it together
+// with the fee recorded in the metadata when the capture is settled. This bank holds no merchant
+// accounts, so no merchant balance is posted here. A capture can be charged back once, for at
+// most the amount that has not already been refunded.
+func (s *ChargebackService) Chargeback(ctx context.Context, captureID uuid.UUID, amount, fee int64, reason string) (*models.Transaction, error) {
+	var chargebackTxn *models.Transaction
+	err := s.db.WithTransaction(ctx, db.ReadCommittedTx(), func(tx *db.Tx) error {
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
+
+		var err error
+		chargebackTxn, err = s.performChargeback(ctx, txTransactionRepo, txAccountRepo, captureID, amount, fee, reason)
+		if err != nil {
+			return err
+		}
+		return beforeCommit(ctx, tx, chargebackTxn)
+	})
+	if err != nil {
+		return nil, transactionError(err)
+	}
+
+	recordCommitted(chargebackTxn)
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, chargebackTxn)
+	}
+
+	return chargebackTxn, nil
+}
+
+// performChargeback contains the core chargeback business logic
+func (s *ChargebackService) performChargeback(
+	ctx context.Context,
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.AccountRepository,
+	captureID uuid.UUID,
+	amount, fee int64,
+	reason string,
+) (*models.Transaction, error) {
+	captureTxn, err := transactionRepo.FindByIDForUpdate(ctx, captureID)
+	if db.IsRetryable(err) {
+		return nil, err
+	}
+	if err != nil || captureTxn.Type != models.TransactionTypeCapture {
+		return nil, &ServiceError{
+			Code:    ErrCodeCaptureNotFound,
+			Message: "capture not found",
+		}
+	}
+
+	if captureTxn.Status != models.TransactionStatusCompleted {
+		return nil, &ServiceError{
+			Code:    ErrCodeCaptureNotFound,
+			Message: "capture is not in completed status",
+		}
+	}
+
+	if err := ValidateAmount(amount); err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidAmount,
+			Message: err.Error(),
+		}
+	}
+	if fee < 0 {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidAmount,
+			Message: "invalid chargeback fee: must not be negative",
+		}
+	}
+
+	// The capture row lock above serializes chargebacks and refunds, so the totals are stable
+	existing, err := transactionRepo.FindByReferenceID(ctx, captureID, models.TransactionTypeChargeback)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to check existing chargebacks",
+			Err:     err,
+		}
+	}
+	if existing != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeAlreadyChargedBack,
+			Message: "capture has already been charged back",
+		}
+	}
+
+	alreadyRefunded, err := transactionRepo.SumByReferenceID(ctx, captureID, models.TransactionTypeRefund)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to sum existing refunds",
+			Err:     err,
+		}
+	}
+
+	remaining := captureTxn.AmountCents - alreadyRefunded
+	if amount > remaining {
+		return nil, &ServiceError{
+			Code: ErrCodeChargebackExceedsCapture,
+			Message: fmt.Sprintf("chargeback amount (%d) exceeds the unrefunded captured amount (%d)",
+				amount, remaining),
+			Err: ErrChargebackExceedsCapture,
+		}
+	}
+
+	metadata, err := fxMetadata(captureTxn, alreadyRefunded, amount)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to convert amount",
+			Err:     err,
+		}
+	}
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata[models.MetadataKeyChargeback] = models.ChargebackDetails{
+		Reason:   reason,
+		FeeCents: fee,
+	}
+
+	chargebackTxn := &models.Transaction{
+		ID:          uuid.New(),
+		AccountID:   captureTxn.AccountID,
+		Type:        models.TransactionTypeChargeback,
+		AmountCents: amount,
+		Currency:    captureTxn.Currency,
+		ReferenceID: &captureID,
+		Status:      models.TransactionStatusCompleted,
+		Metadata:    metadata,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := transactionRepo.Create(ctx, chargebackTxn); err != nil {
+		if errors.Is(err, models.ErrDuplicateTransaction) {
+			return nil, &ServiceError{
+				Code:    ErrCodeAlreadyChargedBack,
+				Message: "capture has already been charged back",
+			}
+		}
+		return nil, fmt.Errorf("failed to create chargeback: %w", err)
+	}
+
+	reversed := chargebackTxn.SettlementAmount()
+	if _, _, err := accountRepo.AdjustBalances(ctx, captureTxn.AccountID, reversed, reversed,
+		balanceReason("chargeback", chargebackTxn.ID)); err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to adjust balance",
+			Err:     err,
+		}
+	}
+
+	return chargebackTxn, nil
+}
+
+// GetChargeback retrieves the chargeback raised against a capture
+func (s *ChargebackService) GetChargeback(ctx context.Context, captureID uuid.UUID) (*models.Transaction, error) {
+	repo := repository.NewTransactionRepository(s.db, s.txnOpts...)
+	txn, err := repo.FindByReferenceID(ctx, captureID, models.TransactionTypeChargeback)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to find chargeback",
+			Err:     err,
+		}
+	}
+	if txn == nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeChargebackNotFound,
+			Message: "chargeback not found",
+			Err:     models.ErrTransactionNotFound,
+		}
+	}
+
+	return txn, nil
+}
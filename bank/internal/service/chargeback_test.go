@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChargebackService_PerformChargeback(t *testing.T) {
+	newCapture := func(id, accountID uuid.UUID) *models.Transaction {
+		return &models.Transaction{
+			ID:          id,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeCapture,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusCompleted,
+		}
+	}
+
+	t.Run("successful chargeback", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		accountID := uuid.New()
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(newCapture(captureID, accountID), nil)
+		mockTxRepo.On("FindByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(nil, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(2500), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(7500, "USD"), models.NewMoney(7500, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, captureID, 7500, 1500, "fraudulent")
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, result) {
+			assert.Equal(t, models.TransactionTypeChargeback, result.Type)
+			assert.Equal(t, int64(7500), result.AmountCents)
+			assert.Equal(t, captureID, *result.ReferenceID)
+			assert.Equal(t, models.TransactionStatusCompleted, result.Status)
+
+			details, ok := result.ChargebackDetails()
+			if assert.True(t, ok) {
+				assert.Equal(t, int64(1500), details.FeeCents)
+				assert.Equal(t, "fraudulent", details.Reason)
+			}
+		}
+
+		mockTxRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("only captures can be charged back", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+		auth := newCapture(authID, uuid.New())
+		auth.Type = models.TransactionTypeAuthHold
+		auth.Status = models.TransactionStatusActive
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(auth, nil)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, authID, 100, 0, "")
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeCaptureNotFound, svcErr.Code)
+		}
+	})
+
+	t.Run("capture not found", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(nil, sql.ErrNoRows)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, captureID, 100, 0, "")
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeCaptureNotFound, svcErr.Code)
+		}
+	})
+
+	t.Run("negative fee", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(newCapture(captureID, uuid.New()), nil)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, captureID, 100, -1, "")
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeInvalidAmount, svcErr.Code)
+		}
+	})
+
+	t.Run("already charged back", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(newCapture(captureID, uuid.New()), nil)
+		mockTxRepo.On("FindByReferenceID", ctx, captureID, models.TransactionTypeChargeback).
+			Return(&models.Transaction{Type: models.TransactionTypeChargeback}, nil)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, captureID, 100, 0, "")
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeAlreadyChargedBack, svcErr.Code)
+		}
+	})
+
+	t.Run("exceeds unrefunded amount", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(newCapture(captureID, uuid.New()), nil)
+		mockTxRepo.On("FindByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(nil, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(6000), nil)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, captureID, 5000, 0, "")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrChargebackExceedsCapture)
+	})
+
+	t.Run("duplicate chargeback on insert", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewChargebackService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(newCapture(captureID, uuid.New()), nil)
+		mockTxRepo.On("FindByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(nil, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(0), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(models.ErrDuplicateTransaction)
+
+		result, err := service.performChargeback(ctx, mockTxRepo, mockAccountRepo, captureID, 100, 0, "")
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeAlreadyChargedBack, svcErr.Code)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+)
+
+// BeforeCommitFunc runs inside a mutating service's database transaction, after the business change
+// has been written and before it commits. Work done through tx commits or rolls back with the change,
+// and returning an error rolls the whole change back.
+type BeforeCommitFunc func(ctx context.Context, tx *db.Tx, txn *models.Transaction) error
+
+type beforeCommitKey struct{}
+
+// WithBeforeCommit returns a context under which Authorize, Capture, Void, Refund and Chargeback
+// call fn before committing
+// Handlers use it to write the idempotency record in the same transaction as the payment.
+func WithBeforeCommit(ctx context.Context, fn BeforeCommitFunc) context.Context {
+	return context.WithValue(ctx, beforeCommitKey{}, fn)
+}
+
+// beforeCommit runs the hook registered on ctx, if any
+func beforeCommit(ctx context.Context, tx *db.Tx, txn *models.Transaction) error {
+	fn, ok := ctx.Value(beforeCommitKey{}).(BeforeCommitFunc)
+	if !ok {
+		return nil
+	}
+	if err := fn(ctx, tx, txn); err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to complete transaction",
+			Err:     err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeforeCommit(t *testing.T) {
+	txn := &models.Transaction{}
+
+	assert.NoError(t, beforeCommit(context.Background(), nil, txn), "no hook registered")
+
+	var got *models.Transaction
+	ctx := WithBeforeCommit(context.Background(), func(_ context.Context, _ *db.Tx, txn *models.Transaction) error {
+		got = txn
+		return nil
+	})
+	assert.NoError(t, beforeCommit(ctx, nil, txn))
+	assert.Same(t, txn, got)
+
+	ctx = WithBeforeCommit(context.Background(), func(context.Context, *db.Tx, *models.Transaction) error {
+		return assert.AnError
+	})
+	err := beforeCommit(ctx, nil, txn)
+	assert.ErrorIs(t, err, assert.AnError)
+	var svcErr *ServiceError
+	if assert.ErrorAs(t, err, &svcErr) {
+		assert.Equal(t, ErrCodeInternalError, svcErr.Code)
+	}
+}
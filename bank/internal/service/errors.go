@@ -1,6 +1,53 @@
 package service
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+)
+
+// Sentinel errors carried in ServiceError.Err for callers that match with errors.Is
+var (
+	// ErrCardNotFound indicates no account exists for the card number
+	ErrCardNotFound = errors.New("card not found")
+
+	// ErrCVVMismatch indicates the supplied CVV does not match the account
+	ErrCVVMismatch = errors.New("cvv mismatch")
+
+	// ErrCardExpired indicates the card's expiry date has passed
+	ErrCardExpired = errors.New("card expired")
+
+	// ErrInsufficientFunds indicates the available balance cannot cover the hold
+	ErrInsufficientFunds = models.ErrInsufficientFunds
+
+	// ErrRefundExceedsCapture indicates a refund would take the refunded total above the captured amount
+	ErrRefundExceedsCapture = errors.New("refund exceeds captured amount")
+
+	// ErrChargebackExceedsCapture indicates a chargeback is larger than the captured amount left unrefunded
+	ErrChargebackExceedsCapture = errors.New("chargeback exceeds unrefunded captured amount")
+
+	// ErrCaptureExceedsAuth indicates a capture would take the captured total above the authorized amount
+	ErrCaptureExceedsAuth = errors.New("capture exceeds authorized amount")
+
+	// ErrVoidExceedsAuth indicates a partial void is larger than the authorized amount left uncaptured
+	ErrVoidExceedsAuth = errors.New("void exceeds remaining authorized amount")
+
+	// ErrAlreadyCaptured indicates an operation that requires an uncaptured authorization found a capture
+	ErrAlreadyCaptured = errors.New("authorization already captured")
+
+	// ErrAuthorizationExpired indicates a capture was attempted after the authorization's expires_at
+	ErrAuthorizationExpired = errors.New("authorization expired")
+
+	// ErrAmountExceedsLimit indicates an authorization is larger than the configured per-authorization cap
+	ErrAmountExceedsLimit = errors.New("amount exceeds authorization limit")
+
+	// ErrVelocityExceeded indicates an account has made more authorizations within the velocity window than allowed
+	ErrVelocityExceeded = errors.New("authorization velocity exceeded")
+
+	// ErrDailyLimitExceeded indicates a capture would take an account's captures for the day past the configured limit
+	ErrDailyLimitExceeded = errors.New("daily capture limit exceeded")
+)
 
 // ServiceError represents a business logic error with a code
 type ServiceError struct {
@@ -21,21 +68,50 @@ func (e *ServiceError) Unwrap() error {
 	return e.Err
 }
 
+// transactionError reports a failed WithTransaction call: errors raised by the business logic pass
+// through unchanged, while failures to begin or commit the transaction become internal errors
+func transactionError(err error) error {
+	var svcErr *ServiceError
+	if errors.As(err, &svcErr) {
+		return err
+	}
+	return &ServiceError{
+		Code:    ErrCodeInternalError,
+		Message: "transaction failed",
+		Err:     err,
+	}
+}
+
 // Common error codes
 const (
-	ErrCodeInvalidCard       = "invalid_card"
-	ErrCodeInvalidCVV        = "invalid_cvv"
-	ErrCodeInvalidAmount     = "invalid_amount"
-	ErrCodeCardExpired       = "card_expired"
-	ErrCodeInsufficientFunds = "insufficient_funds"
-	ErrCodeAccountNotFound   = "account_not_found"
-	ErrCodeAuthNotFound      = "authorization_not_found"
-	ErrCodeAuthExpired       = "authorization_expired"
-	ErrCodeAuthAlreadyUsed   = "authorization_already_used"
-	ErrCodeAlreadyCaptured   = "already_captured"
-	ErrCodeAlreadyVoided     = "already_voided"
-	ErrCodeAlreadyRefunded   = "already_refunded"
-	ErrCodeAmountMismatch    = "amount_mismatch"
-	ErrCodeCaptureNotFound   = "capture_not_found"
-	ErrCodeInternalError     = "internal_error"
+	ErrCodeInvalidCard              = "invalid_card"
+	ErrCodeInvalidCVV               = "invalid_cvv"
+	ErrCodeInvalidAmount            = "invalid_amount"
+	ErrCodeAmountExceedsLimit       = "amount_exceeds_limit"
+	ErrCodeVelocityExceeded         = "velocity_exceeded"
+	ErrCodeDailyLimitExceeded       = "daily_limit_exceeded"
+	ErrCodeInvalidCurrency          = "invalid_currency"
+	ErrCodeFXRateUnavailable        = "fx_rate_unavailable"
+	ErrCodeCardExpired              = "card_expired"
+	ErrCodeInvalidExpiry            = "invalid_expiry"
+	ErrCodeInsufficientFunds        = "insufficient_funds"
+	ErrCodeAccountNotFound          = "account_not_found"
+	ErrCodeInvalidTransfer          = "invalid_transfer"
+	ErrCodeAuthNotFound             = "authorization_not_found"
+	ErrCodeAuthExpired              = "authorization_expired"
+	ErrCodeAuthAlreadyUsed          = "authorization_already_used"
+	ErrCodeAlreadyCaptured          = "already_captured"
+	ErrCodeAlreadyVoided            = "already_voided"
+	ErrCodeAlreadyRefunded          = "already_refunded"
+	ErrCodeAmountMismatch           = "amount_mismatch"
+	ErrCodeRefundExceedsCapture     = "refund_exceeds_capture"
+	ErrCodeCaptureExceedsAuth       = "capture_exceeds_authorization"
+	ErrCodeVoidExceedsAuth          = "void_exceeds_authorization"
+	ErrCodeCaptureNotFound          = "capture_not_found"
+	ErrCodeRefundNotFound           = "refund_not_found"
+	ErrCodeTransactionNotFound      = "transaction_not_found"
+	ErrCodeAlreadyChargedBack       = "already_charged_back"
+	ErrCodeChargebackExceedsCapture = "chargeback_exceeds_capture"
+	ErrCodeChargebackNotFound       = "chargeback_not_found"
+	ErrCodeInternalError            = "internal_error"
 )
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+)
+
+// ErrFXRateUnavailable indicates no exchange rate is known for a currency pair
+var ErrFXRateUnavailable = errors.New("exchange rate unavailable")
+
+// StaticFXProvider serves exchange rates from an in-memory table
+type StaticFXProvider struct {
+	rates map[string]*big.Rat
+	mu    sync.RWMutex
+}
+
+// NewStaticFXProvider creates a StaticFXProvider with no rates
+func NewStaticFXProvider() *StaticFXProvider {
+	return &StaticFXProvider{rates: make(map[string]*big.Rat)}
+}
+
+// SetRate registers the rate for converting one unit of from into to
+func (p *StaticFXProvider) SetRate(from, to string, rate *big.Rat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates[fxPairKey(from, to)] = new(big.Rat).Set(rate)
+}
+
+// Rate returns the rate for converting from into to, falling back to the inverse of the reverse pair
+func (p *StaticFXProvider) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	if strings.EqualFold(from, to) {
+		return big.NewRat(1, 1), nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if rate, ok := p.rates[fxPairKey(from, to)]; ok {
+		return new(big.Rat).Set(rate), nil
+	}
+	if rate, ok := p.rates[fxPairKey(to, from)]; ok && rate.Sign() != 0 {
+		return new(big.Rat).Inv(rate), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s/%s", ErrFXRateUnavailable, from, to)
+}
+
+func fxPairKey(from, to string) string {
+	return strings.ToUpper(from) + "/" + strings.ToUpper(to)
+}
+
+// convertMoney converts m into the target currency at a rate quoted in major units, rounding
+// half away from zero to the target's minor unit
+func convertMoney(m models.Money, to string, rate *big.Rat) (models.Money, error) {
+	if rate == nil || rate.Sign() <= 0 {
+		return models.Money{}, fmt.Errorf("invalid exchange rate for %s/%s", m.Currency, to)
+	}
+
+	return scaleMoney(m, to, minorUnitRate(rate, m.Currency, to))
+}
+
+// minorUnitRate turns a major-unit rate into one between minor units, so 150 JPY per USD becomes
+// 1.5 yen per cent
+func minorUnitRate(rate *big.Rat, from, to string) *big.Rat {
+	shift := models.Currencies.MinorUnits(to) - models.Currencies.MinorUnits(from)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt(shift))), nil))
+	if shift < 0 {
+		scale.Inv(scale)
+	}
+	return new(big.Rat).Mul(rate, scale)
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// scaleMoney multiplies m by a ratio between minor units, rounding half away from zero
+func scaleMoney(m models.Money, to string, ratio *big.Rat) (models.Money, error) {
+	num := new(big.Int).Mul(big.NewInt(m.Cents), ratio.Num())
+	den := ratio.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+
+	if !quo.IsInt64() {
+		return models.Money{}, fmt.Errorf("converted amount overflows for %s", m)
+	}
+
+	return models.NewMoney(quo.Int64(), to), nil
+}
+
+// newFXConversion describes the conversion of original into converted at the given rate
+func newFXConversion(original, converted models.Money, rate *big.Rat) models.FXConversion {
+	return models.FXConversion{
+		OriginalAmount:    original.Cents,
+		OriginalCurrency:  original.Currency,
+		ConvertedAmount:   converted.Cents,
+		ConvertedCurrency: converted.Currency,
+		Rate:              rate.FloatString(6),
+	}
+}
+
+// fxMetadata carries the conversion of a parent transaction over to a follow-up transaction
+// for amount, so it settles at the rate locked in at authorization. prior is the part of the
+// parent already consumed by earlier follow-ups; prorating cumulatively means a sequence of
+// partial amounts always settles to exactly the parent's converted amount.
+func fxMetadata(parent *models.Transaction, prior, amount int64) (map[string]any, error) {
+	conv, ok := parent.FXConversion()
+	if !ok {
+		return nil, nil
+	}
+	if prior == 0 && amount == conv.OriginalAmount {
+		return map[string]any{models.MetadataKeyFX: *conv}, nil
+	}
+
+	before, err := proratedSettlement(parent, prior)
+	if err != nil {
+		return nil, err
+	}
+	after, err := proratedSettlement(parent, prior+amount)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := after.Sub(before)
+	if err != nil {
+		return nil, err
+	}
+
+	prorated := *conv
+	prorated.OriginalAmount = amount
+	prorated.ConvertedAmount = converted.Cents
+	return map[string]any{models.MetadataKeyFX: prorated}, nil
+}
+
+// proratedSettlement returns the share of the parent's settlement amount that corresponds to
+// amount of its original amount
+func proratedSettlement(parent *models.Transaction, amount int64) (models.Money, error) {
+	conv, ok := parent.FXConversion()
+	if !ok {
+		return models.NewMoney(amount, parent.Currency), nil
+	}
+	if amount == conv.OriginalAmount {
+		return models.NewMoney(conv.ConvertedAmount, conv.ConvertedCurrency), nil
+	}
+	if conv.OriginalAmount == 0 {
+		return models.Money{}, fmt.Errorf("cannot prorate conversion of a zero amount")
+	}
+
+	original := models.NewMoney(amount, conv.OriginalCurrency)
+	return scaleMoney(original, conv.ConvertedCurrency, big.NewRat(conv.ConvertedAmount, conv.OriginalAmount))
+}
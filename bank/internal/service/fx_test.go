@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticFXProvider_Rate(t *testing.T) {
+	fx := NewStaticFXProvider()
+	fx.SetRate("EUR", "USD", big.NewRat(5, 4))
+	ctx := context.Background()
+
+	rate, err := fx.Rate(ctx, "EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "1.25", rate.FloatString(2))
+
+	inverse, err := fx.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.80", inverse.FloatString(2))
+
+	same, err := fx.Rate(ctx, "JPY", "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), same.Num().Int64())
+
+	_, err = fx.Rate(ctx, "EUR", "JPY")
+	assert.ErrorIs(t, err, ErrFXRateUnavailable)
+}
+
+func TestConvertMoney(t *testing.T) {
+	tests := []struct {
+		rate *big.Rat
+		name string
+		in   models.Money
+		want models.Money
+	}{
+		{name: "exact", in: models.NewMoney(10000, "EUR"), rate: big.NewRat(108, 100), want: models.NewMoney(10800, "USD")},
+		{name: "rounds half up", in: models.NewMoney(1, "EUR"), rate: big.NewRat(3, 2), want: models.NewMoney(2, "USD")},
+		{name: "rounds down", in: models.NewMoney(1, "EUR"), rate: big.NewRat(4, 3), want: models.NewMoney(1, "USD")},
+		{name: "negative rounds away from zero", in: models.NewMoney(-1, "EUR"), rate: big.NewRat(3, 2), want: models.NewMoney(-2, "USD")},
+		{name: "USD to JPY", in: models.NewMoney(10000, "USD"), rate: big.NewRat(15025, 100), want: models.NewMoney(15025, "JPY")},
+		{name: "JPY to USD", in: models.NewMoney(15025, "JPY"), rate: big.NewRat(100, 15025), want: models.NewMoney(10000, "USD")},
+		{name: "JPY to USD rounds to the cent", in: models.NewMoney(1, "JPY"), rate: big.NewRat(1, 150), want: models.NewMoney(1, "USD")},
+		{name: "USD to BHD", in: models.NewMoney(10000, "USD"), rate: big.NewRat(376, 1000), want: models.NewMoney(37600, "BHD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertMoney(tt.in, tt.want.Currency, tt.rate)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := convertMoney(models.NewMoney(100, "EUR"), "USD", big.NewRat(0, 1))
+	assert.Error(t, err)
+}
+
+func TestFXMetadata_Prorates(t *testing.T) {
+	capture := &models.Transaction{
+		AmountCents: 10000,
+		Currency:    "EUR",
+		Metadata: map[string]any{models.MetadataKeyFX: models.FXConversion{
+			OriginalAmount:    10000,
+			OriginalCurrency:  "EUR",
+			ConvertedAmount:   10843,
+			ConvertedCurrency: "USD",
+			Rate:              "1.084300",
+		}},
+	}
+
+	full, err := fxMetadata(capture, 0, 10000)
+	require.NoError(t, err)
+	assert.Equal(t, capture.Metadata, full)
+
+	partial, err := fxMetadata(capture, 0, 2500)
+	require.NoError(t, err)
+	refund := &models.Transaction{AmountCents: 2500, Currency: "EUR", Metadata: partial}
+	assert.Equal(t, models.NewMoney(2711, "USD"), refund.SettlementAmount())
+
+	// Three partial thirds must settle to the full converted amount despite rounding
+	var settled int64
+	for _, prior := range []int64{0, 3333, 6666} {
+		amount := int64(3333)
+		if prior == 6666 {
+			amount = 3334
+		}
+		md, err := fxMetadata(capture, prior, amount)
+		require.NoError(t, err)
+		part := &models.Transaction{AmountCents: amount, Currency: "EUR", Metadata: md}
+		settled += part.SettlementAmount().Cents
+	}
+	assert.Equal(t, int64(10843), settled)
+
+	none, err := fxMetadata(&models.Transaction{AmountCents: 100, Currency: "USD"}, 0, 100)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"math/big"
+	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/db"
 	"github.com/benx421/payment-gateway/bank/internal/models"
 	"github.com/google/uuid"
 )
@@ -10,11 +14,26 @@ import (
 // HealthChecker validates system health.
 type HealthChecker interface {
 	PingContext(ctx context.Context) error
+	Stats() sql.DBStats
+	ReplicaStatus() (db.ReplicaStatus, bool)
+}
+
+// FXProvider looks up exchange rates between currencies.
+type FXProvider interface {
+	// Rate returns the amount of to received for one unit of from
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// Notifier is informed of transactions once their status change has been committed.
+// Implementations must not block the caller.
+type Notifier interface {
+	Notify(ctx context.Context, txn *models.Transaction)
 }
 
 // Authorizer handles payment authorization operations
 type Authorizer interface {
-	Authorize(ctx context.Context, cardNumber, cvv string, amount int64) (*models.Transaction, error)
+	Authorize(ctx context.Context, cardNumber, cvv string, amount int64, currency string, expiresAt *time.Time) (*models.Transaction, error)
+	SimulateAuthorization(ctx context.Context, cardNumber, cvv string, amount int64, currency string, expiresAt *time.Time) (*models.Transaction, error)
 	GetAuthorization(ctx context.Context, authID uuid.UUID) (*models.Transaction, error)
 }
 
@@ -27,6 +46,7 @@ type Capturer interface {
 // Voider handles authorization void operations
 type Voider interface {
 	Void(ctx context.Context, authorizationID uuid.UUID) (*models.Transaction, error)
+	PartialVoid(ctx context.Context, authorizationID uuid.UUID, amount int64) (*models.Transaction, error)
 }
 
 // Refunder handles refund operations
@@ -35,10 +55,32 @@ type Refunder interface {
 	GetRefund(ctx context.Context, refundID uuid.UUID) (*models.Transaction, error)
 }
 
+// AccountReader handles read-only account lookups and balance reconciliation
+type AccountReader interface {
+	GetAccount(ctx context.Context, accountNumber string) (*models.Account, error)
+	GetAccountByID(ctx context.Context, id uuid.UUID) (*models.Account, error)
+	ReconcileAccount(ctx context.Context, accountID uuid.UUID) (int64, error)
+	ReconcileBalances(ctx context.Context) ([]*models.BalanceDrift, error)
+	ListLowBalance(ctx context.Context, thresholdCents int64, limit, offset int) ([]*models.Account, error)
+}
+
+// TransactionReader handles read-only transaction lookups
+type TransactionReader interface {
+	GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
+	GetTransactions(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Transaction, error)
+	FindByMetadata(ctx context.Context, key, value string, limit int) ([]*models.Transaction, error)
+	ExportTransactions(ctx context.Context, from, to time.Time, fn func(*models.ExportedTransaction) error) error
+	Stats(ctx context.Context) (*models.LedgerStats, error)
+}
+
 // Ensure concrete types implement interfaces
 var (
-	_ Authorizer = (*AuthorizationService)(nil)
-	_ Capturer   = (*CaptureService)(nil)
-	_ Voider     = (*VoidService)(nil)
-	_ Refunder   = (*RefundService)(nil)
+	_ Authorizer        = (*AuthorizationService)(nil)
+	_ Capturer          = (*CaptureService)(nil)
+	_ Voider            = (*VoidService)(nil)
+	_ Refunder          = (*RefundService)(nil)
+	_ AccountReader     = (*AccountService)(nil)
+	_ TransactionReader = (*TransactionService)(nil)
+
+	_ FXProvider = (*StaticFXProvider)(nil)
 )
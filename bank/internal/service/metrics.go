@@ -0,0 +1,24 @@
+package service
+
+import (
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+)
+
+// recordCommitted records txn, written by a database transaction that has committed, in the
+// transaction count and amount metrics
+func recordCommitted(txn *models.Transaction) {
+	countTransaction(txn.Type, txn.Status)
+	observeAmount(txn.Amount(), txn.Type)
+}
+
+// countTransaction counts a committed transaction of txnType that was written in, or moved to,
+// status in metrics.TransactionsTotal
+func countTransaction(txnType models.TransactionType, status models.TransactionStatus) {
+	metrics.TransactionsTotal.WithLabelValues(string(txnType), string(status)).Inc()
+}
+
+// observeAmount records the amount of a committed transaction of txnType in metrics.TransactionAmount
+func observeAmount(amount models.Money, txnType models.TransactionType) {
+	metrics.TransactionAmount.WithLabelValues(amount.Currency, string(txnType)).Observe(float64(amount.Cents))
+}
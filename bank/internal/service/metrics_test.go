@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/metrics"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func amountHistogram(t *testing.T, currency string, txnType models.TransactionType) *dto.Histogram {
+	t.Helper()
+
+	observer, err := metrics.TransactionAmount.GetMetricWithLabelValues(currency, string(txnType))
+	require.NoError(t, err)
+
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram()
+}
+
+func transactionCount(t *testing.T, txnType models.TransactionType, status models.TransactionStatus) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	require.NoError(t, metrics.TransactionsTotal.WithLabelValues(string(txnType), string(status)).Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func TestRecordCommitted(t *testing.T) {
+	before := transactionCount(t, models.TransactionTypeChargeback, models.TransactionStatusCompleted)
+	amounts := amountHistogram(t, "CHF", models.TransactionTypeChargeback).GetSampleCount()
+
+	recordCommitted(&models.Transaction{
+		Type:        models.TransactionTypeChargeback,
+		Status:      models.TransactionStatusCompleted,
+		AmountCents: 1200,
+		Currency:    "CHF",
+	})
+
+	assert.InDelta(t, before+1, transactionCount(t, models.TransactionTypeChargeback, models.TransactionStatusCompleted), 0)
+	assert.Equal(t, amounts+1, amountHistogram(t, "CHF", models.TransactionTypeChargeback).GetSampleCount())
+}
+
+func TestObserveAmount(t *testing.T) {
+	before := amountHistogram(t, "JPY", models.TransactionTypeRefund)
+	otherBefore := amountHistogram(t, "JPY", models.TransactionTypeCapture).GetSampleCount()
+
+	observeAmount(models.NewMoney(2500, "JPY"), models.TransactionTypeRefund)
+
+	after := amountHistogram(t, "JPY", models.TransactionTypeRefund)
+	assert.Equal(t, before.GetSampleCount()+1, after.GetSampleCount())
+	assert.InDelta(t, before.GetSampleSum()+2500, after.GetSampleSum(), 0)
+	assert.Equal(t, otherBefore, amountHistogram(t, "JPY", models.TransactionTypeCapture).GetSampleCount(),
+		"other types are counted separately")
+}
@@ -0,0 +1,333 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/benx421/payment-gateway/bank/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockAccountReader is an autogenerated mock type for the AccountReader type
+type MockAccountReader struct {
+	mock.Mock
+}
+
+type MockAccountReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAccountReader) EXPECT() *MockAccountReader_Expecter {
+	return &MockAccountReader_Expecter{mock: &_m.Mock}
+}
+
+// GetAccount provides a mock function with given fields: ctx, accountNumber
+func (_m *MockAccountReader) GetAccount(ctx context.Context, accountNumber string) (*models.Account, error) {
+	ret := _m.Called(ctx, accountNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccount")
+	}
+
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Account, error)); ok {
+		return rf(ctx, accountNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Account); ok {
+		r0 = rf(ctx, accountNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountReader_GetAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccount'
+type MockAccountReader_GetAccount_Call struct {
+	*mock.Call
+}
+
+// GetAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountNumber string
+func (_e *MockAccountReader_Expecter) GetAccount(ctx interface{}, accountNumber interface{}) *MockAccountReader_GetAccount_Call {
+	return &MockAccountReader_GetAccount_Call{Call: _e.mock.On("GetAccount", ctx, accountNumber)}
+}
+
+func (_c *MockAccountReader_GetAccount_Call) Run(run func(ctx context.Context, accountNumber string)) *MockAccountReader_GetAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAccountReader_GetAccount_Call) Return(_a0 *models.Account, _a1 error) *MockAccountReader_GetAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountReader_GetAccount_Call) RunAndReturn(run func(context.Context, string) (*models.Account, error)) *MockAccountReader_GetAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccountByID provides a mock function with given fields: ctx, id
+func (_m *MockAccountReader) GetAccountByID(ctx context.Context, id uuid.UUID) (*models.Account, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountByID")
+	}
+
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Account, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Account); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountReader_GetAccountByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccountByID'
+type MockAccountReader_GetAccountByID_Call struct {
+	*mock.Call
+}
+
+// GetAccountByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockAccountReader_Expecter) GetAccountByID(ctx interface{}, id interface{}) *MockAccountReader_GetAccountByID_Call {
+	return &MockAccountReader_GetAccountByID_Call{Call: _e.mock.On("GetAccountByID", ctx, id)}
+}
+
+func (_c *MockAccountReader_GetAccountByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockAccountReader_GetAccountByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAccountReader_GetAccountByID_Call) Return(_a0 *models.Account, _a1 error) *MockAccountReader_GetAccountByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountReader_GetAccountByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Account, error)) *MockAccountReader_GetAccountByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLowBalance provides a mock function with given fields: ctx, thresholdCents, limit, offset
+func (_m *MockAccountReader) ListLowBalance(ctx context.Context, thresholdCents int64, limit int, offset int) ([]*models.Account, error) {
+	ret := _m.Called(ctx, thresholdCents, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLowBalance")
+	}
+
+	var r0 []*models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*models.Account, error)); ok {
+		return rf(ctx, thresholdCents, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*models.Account); ok {
+		r0 = rf(ctx, thresholdCents, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, thresholdCents, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountReader_ListLowBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLowBalance'
+type MockAccountReader_ListLowBalance_Call struct {
+	*mock.Call
+}
+
+// ListLowBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - thresholdCents int64
+//   - limit int
+//   - offset int
+func (_e *MockAccountReader_Expecter) ListLowBalance(ctx interface{}, thresholdCents interface{}, limit interface{}, offset interface{}) *MockAccountReader_ListLowBalance_Call {
+	return &MockAccountReader_ListLowBalance_Call{Call: _e.mock.On("ListLowBalance", ctx, thresholdCents, limit, offset)}
+}
+
+func (_c *MockAccountReader_ListLowBalance_Call) Run(run func(ctx context.Context, thresholdCents int64, limit int, offset int)) *MockAccountReader_ListLowBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAccountReader_ListLowBalance_Call) Return(_a0 []*models.Account, _a1 error) *MockAccountReader_ListLowBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountReader_ListLowBalance_Call) RunAndReturn(run func(context.Context, int64, int, int) ([]*models.Account, error)) *MockAccountReader_ListLowBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReconcileAccount provides a mock function with given fields: ctx, accountID
+func (_m *MockAccountReader) ReconcileAccount(ctx context.Context, accountID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileAccount")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountReader_ReconcileAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconcileAccount'
+type MockAccountReader_ReconcileAccount_Call struct {
+	*mock.Call
+}
+
+// ReconcileAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID uuid.UUID
+func (_e *MockAccountReader_Expecter) ReconcileAccount(ctx interface{}, accountID interface{}) *MockAccountReader_ReconcileAccount_Call {
+	return &MockAccountReader_ReconcileAccount_Call{Call: _e.mock.On("ReconcileAccount", ctx, accountID)}
+}
+
+func (_c *MockAccountReader_ReconcileAccount_Call) Run(run func(ctx context.Context, accountID uuid.UUID)) *MockAccountReader_ReconcileAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAccountReader_ReconcileAccount_Call) Return(_a0 int64, _a1 error) *MockAccountReader_ReconcileAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountReader_ReconcileAccount_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int64, error)) *MockAccountReader_ReconcileAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReconcileBalances provides a mock function with given fields: ctx
+func (_m *MockAccountReader) ReconcileBalances(ctx context.Context) ([]*models.BalanceDrift, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileBalances")
+	}
+
+	var r0 []*models.BalanceDrift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.BalanceDrift, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.BalanceDrift); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.BalanceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountReader_ReconcileBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconcileBalances'
+type MockAccountReader_ReconcileBalances_Call struct {
+	*mock.Call
+}
+
+// ReconcileBalances is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAccountReader_Expecter) ReconcileBalances(ctx interface{}) *MockAccountReader_ReconcileBalances_Call {
+	return &MockAccountReader_ReconcileBalances_Call{Call: _e.mock.On("ReconcileBalances", ctx)}
+}
+
+func (_c *MockAccountReader_ReconcileBalances_Call) Run(run func(ctx context.Context)) *MockAccountReader_ReconcileBalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockAccountReader_ReconcileBalances_Call) Return(_a0 []*models.BalanceDrift, _a1 error) *MockAccountReader_ReconcileBalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountReader_ReconcileBalances_Call) RunAndReturn(run func(context.Context) ([]*models.BalanceDrift, error)) *MockAccountReader_ReconcileBalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAccountReader creates a new instance of MockAccountReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAccountReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAccountReader {
+	mock := &MockAccountReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
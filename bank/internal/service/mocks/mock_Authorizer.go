@@ -8,6 +8,8 @@ import (
 	models "github.com/benx421/payment-gateway/bank/internal/models"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -24,9 +26,9 @@ func (_m *MockAuthorizer) EXPECT() *MockAuthorizer_Expecter {
 	return &MockAuthorizer_Expecter{mock: &_m.Mock}
 }
 
-// Authorize provides a mock function with given fields: ctx, cardNumber, cvv, amount
-func (_m *MockAuthorizer) Authorize(ctx context.Context, cardNumber string, cvv string, amount int64) (*models.Transaction, error) {
-	ret := _m.Called(ctx, cardNumber, cvv, amount)
+// Authorize provides a mock function with given fields: ctx, cardNumber, cvv, amount, currency, expiresAt
+func (_m *MockAuthorizer) Authorize(ctx context.Context, cardNumber string, cvv string, amount int64, currency string, expiresAt *time.Time) (*models.Transaction, error) {
+	ret := _m.Called(ctx, cardNumber, cvv, amount, currency, expiresAt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Authorize")
@@ -34,19 +36,19 @@ func (_m *MockAuthorizer) Authorize(ctx context.Context, cardNumber string, cvv
 
 	var r0 *models.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (*models.Transaction, error)); ok {
-		return rf(ctx, cardNumber, cvv, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, string, *time.Time) (*models.Transaction, error)); ok {
+		return rf(ctx, cardNumber, cvv, amount, currency, expiresAt)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) *models.Transaction); ok {
-		r0 = rf(ctx, cardNumber, cvv, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, string, *time.Time) *models.Transaction); ok {
+		r0 = rf(ctx, cardNumber, cvv, amount, currency, expiresAt)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
-		r1 = rf(ctx, cardNumber, cvv, amount)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, string, *time.Time) error); ok {
+		r1 = rf(ctx, cardNumber, cvv, amount, currency, expiresAt)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -64,13 +66,15 @@ type MockAuthorizer_Authorize_Call struct {
 //   - cardNumber string
 //   - cvv string
 //   - amount int64
-func (_e *MockAuthorizer_Expecter) Authorize(ctx interface{}, cardNumber interface{}, cvv interface{}, amount interface{}) *MockAuthorizer_Authorize_Call {
-	return &MockAuthorizer_Authorize_Call{Call: _e.mock.On("Authorize", ctx, cardNumber, cvv, amount)}
+//   - currency string
+//   - expiresAt *time.Time
+func (_e *MockAuthorizer_Expecter) Authorize(ctx interface{}, cardNumber interface{}, cvv interface{}, amount interface{}, currency interface{}, expiresAt interface{}) *MockAuthorizer_Authorize_Call {
+	return &MockAuthorizer_Authorize_Call{Call: _e.mock.On("Authorize", ctx, cardNumber, cvv, amount, currency, expiresAt)}
 }
 
-func (_c *MockAuthorizer_Authorize_Call) Run(run func(ctx context.Context, cardNumber string, cvv string, amount int64)) *MockAuthorizer_Authorize_Call {
+func (_c *MockAuthorizer_Authorize_Call) Run(run func(ctx context.Context, cardNumber string, cvv string, amount int64, currency string, expiresAt *time.Time)) *MockAuthorizer_Authorize_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(string), args[5].(*time.Time))
 	})
 	return _c
 }
@@ -80,7 +84,7 @@ func (_c *MockAuthorizer_Authorize_Call) Return(_a0 *models.Transaction, _a1 err
 	return _c
 }
 
-func (_c *MockAuthorizer_Authorize_Call) RunAndReturn(run func(context.Context, string, string, int64) (*models.Transaction, error)) *MockAuthorizer_Authorize_Call {
+func (_c *MockAuthorizer_Authorize_Call) RunAndReturn(run func(context.Context, string, string, int64, string, *time.Time) (*models.Transaction, error)) *MockAuthorizer_Authorize_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -144,6 +148,69 @@ func (_c *MockAuthorizer_GetAuthorization_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// SimulateAuthorization provides a mock function with given fields: ctx, cardNumber, cvv, amount, currency, expiresAt
+func (_m *MockAuthorizer) SimulateAuthorization(ctx context.Context, cardNumber string, cvv string, amount int64, currency string, expiresAt *time.Time) (*models.Transaction, error) {
+	ret := _m.Called(ctx, cardNumber, cvv, amount, currency, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateAuthorization")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, string, *time.Time) (*models.Transaction, error)); ok {
+		return rf(ctx, cardNumber, cvv, amount, currency, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, string, *time.Time) *models.Transaction); ok {
+		r0 = rf(ctx, cardNumber, cvv, amount, currency, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, string, *time.Time) error); ok {
+		r1 = rf(ctx, cardNumber, cvv, amount, currency, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuthorizer_SimulateAuthorization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SimulateAuthorization'
+type MockAuthorizer_SimulateAuthorization_Call struct {
+	*mock.Call
+}
+
+// SimulateAuthorization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cardNumber string
+//   - cvv string
+//   - amount int64
+//   - currency string
+//   - expiresAt *time.Time
+func (_e *MockAuthorizer_Expecter) SimulateAuthorization(ctx interface{}, cardNumber interface{}, cvv interface{}, amount interface{}, currency interface{}, expiresAt interface{}) *MockAuthorizer_SimulateAuthorization_Call {
+	return &MockAuthorizer_SimulateAuthorization_Call{Call: _e.mock.On("SimulateAuthorization", ctx, cardNumber, cvv, amount, currency, expiresAt)}
+}
+
+func (_c *MockAuthorizer_SimulateAuthorization_Call) Run(run func(ctx context.Context, cardNumber string, cvv string, amount int64, currency string, expiresAt *time.Time)) *MockAuthorizer_SimulateAuthorization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(string), args[5].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockAuthorizer_SimulateAuthorization_Call) Return(_a0 *models.Transaction, _a1 error) *MockAuthorizer_SimulateAuthorization_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuthorizer_SimulateAuthorization_Call) RunAndReturn(run func(context.Context, string, string, int64, string, *time.Time) (*models.Transaction, error)) *MockAuthorizer_SimulateAuthorization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockAuthorizer creates a new instance of MockAuthorizer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockAuthorizer(t interface {
@@ -0,0 +1,93 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockNonceStore is an autogenerated mock type for the NonceStore type
+type MockNonceStore struct {
+	mock.Mock
+}
+
+type MockNonceStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockNonceStore) EXPECT() *MockNonceStore_Expecter {
+	return &MockNonceStore_Expecter{mock: &_m.Mock}
+}
+
+// Use provides a mock function with given fields: ctx, nonce
+func (_m *MockNonceStore) Use(ctx context.Context, nonce string) (bool, error) {
+	ret := _m.Called(ctx, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Use")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, nonce)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNonceStore_Use_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Use'
+type MockNonceStore_Use_Call struct {
+	*mock.Call
+}
+
+// Use is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nonce string
+func (_e *MockNonceStore_Expecter) Use(ctx interface{}, nonce interface{}) *MockNonceStore_Use_Call {
+	return &MockNonceStore_Use_Call{Call: _e.mock.On("Use", ctx, nonce)}
+}
+
+func (_c *MockNonceStore_Use_Call) Run(run func(ctx context.Context, nonce string)) *MockNonceStore_Use_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockNonceStore_Use_Call) Return(_a0 bool, _a1 error) *MockNonceStore_Use_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNonceStore_Use_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockNonceStore_Use_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockNonceStore creates a new instance of MockNonceStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockNonceStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockNonceStore {
+	mock := &MockNonceStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTenantRouter is an autogenerated mock type for the TenantRouter type
+type MockTenantRouter struct {
+	mock.Mock
+}
+
+type MockTenantRouter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTenantRouter) EXPECT() *MockTenantRouter_Expecter {
+	return &MockTenantRouter_Expecter{mock: &_m.Mock}
+}
+
+// WithTenant provides a mock function with given fields: ctx, name
+func (_m *MockTenantRouter) WithTenant(ctx context.Context, name string) (context.Context, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTenant")
+	}
+
+	var r0 context.Context
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (context.Context, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) context.Context); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTenantRouter_WithTenant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTenant'
+type MockTenantRouter_WithTenant_Call struct {
+	*mock.Call
+}
+
+// WithTenant is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockTenantRouter_Expecter) WithTenant(ctx interface{}, name interface{}) *MockTenantRouter_WithTenant_Call {
+	return &MockTenantRouter_WithTenant_Call{Call: _e.mock.On("WithTenant", ctx, name)}
+}
+
+func (_c *MockTenantRouter_WithTenant_Call) Run(run func(ctx context.Context, name string)) *MockTenantRouter_WithTenant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTenantRouter_WithTenant_Call) Return(_a0 context.Context, _a1 error) *MockTenantRouter_WithTenant_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTenantRouter_WithTenant_Call) RunAndReturn(run func(context.Context, string) (context.Context, error)) *MockTenantRouter_WithTenant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTenantRouter creates a new instance of MockTenantRouter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTenantRouter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTenantRouter {
+	mock := &MockTenantRouter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,327 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/benx421/payment-gateway/bank/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockTransactionReader is an autogenerated mock type for the TransactionReader type
+type MockTransactionReader struct {
+	mock.Mock
+}
+
+type MockTransactionReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTransactionReader) EXPECT() *MockTransactionReader_Expecter {
+	return &MockTransactionReader_Expecter{mock: &_m.Mock}
+}
+
+// ExportTransactions provides a mock function with given fields: ctx, from, to, fn
+func (_m *MockTransactionReader) ExportTransactions(ctx context.Context, from time.Time, to time.Time, fn func(*models.ExportedTransaction) error) error {
+	ret := _m.Called(ctx, from, to, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportTransactions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, func(*models.ExportedTransaction) error) error); ok {
+		r0 = rf(ctx, from, to, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionReader_ExportTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportTransactions'
+type MockTransactionReader_ExportTransactions_Call struct {
+	*mock.Call
+}
+
+// ExportTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from time.Time
+//   - to time.Time
+//   - fn func(*models.ExportedTransaction) error
+func (_e *MockTransactionReader_Expecter) ExportTransactions(ctx interface{}, from interface{}, to interface{}, fn interface{}) *MockTransactionReader_ExportTransactions_Call {
+	return &MockTransactionReader_ExportTransactions_Call{Call: _e.mock.On("ExportTransactions", ctx, from, to, fn)}
+}
+
+func (_c *MockTransactionReader_ExportTransactions_Call) Run(run func(ctx context.Context, from time.Time, to time.Time, fn func(*models.ExportedTransaction) error)) *MockTransactionReader_ExportTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(func(*models.ExportedTransaction) error))
+	})
+	return _c
+}
+
+func (_c *MockTransactionReader_ExportTransactions_Call) Return(_a0 error) *MockTransactionReader_ExportTransactions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionReader_ExportTransactions_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, func(*models.ExportedTransaction) error) error) *MockTransactionReader_ExportTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByMetadata provides a mock function with given fields: ctx, key, value, limit
+func (_m *MockTransactionReader) FindByMetadata(ctx context.Context, key string, value string, limit int) ([]*models.Transaction, error) {
+	ret := _m.Called(ctx, key, value, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByMetadata")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]*models.Transaction, error)); ok {
+		return rf(ctx, key, value, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []*models.Transaction); ok {
+		r0 = rf(ctx, key, value, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, key, value, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionReader_FindByMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByMetadata'
+type MockTransactionReader_FindByMetadata_Call struct {
+	*mock.Call
+}
+
+// FindByMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - value string
+//   - limit int
+func (_e *MockTransactionReader_Expecter) FindByMetadata(ctx interface{}, key interface{}, value interface{}, limit interface{}) *MockTransactionReader_FindByMetadata_Call {
+	return &MockTransactionReader_FindByMetadata_Call{Call: _e.mock.On("FindByMetadata", ctx, key, value, limit)}
+}
+
+func (_c *MockTransactionReader_FindByMetadata_Call) Run(run func(ctx context.Context, key string, value string, limit int)) *MockTransactionReader_FindByMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockTransactionReader_FindByMetadata_Call) Return(_a0 []*models.Transaction, _a1 error) *MockTransactionReader_FindByMetadata_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionReader_FindByMetadata_Call) RunAndReturn(run func(context.Context, string, string, int) ([]*models.Transaction, error)) *MockTransactionReader_FindByMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransaction provides a mock function with given fields: ctx, id
+func (_m *MockTransactionReader) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransaction")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionReader_GetTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransaction'
+type MockTransactionReader_GetTransaction_Call struct {
+	*mock.Call
+}
+
+// GetTransaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockTransactionReader_Expecter) GetTransaction(ctx interface{}, id interface{}) *MockTransactionReader_GetTransaction_Call {
+	return &MockTransactionReader_GetTransaction_Call{Call: _e.mock.On("GetTransaction", ctx, id)}
+}
+
+func (_c *MockTransactionReader_GetTransaction_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockTransactionReader_GetTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockTransactionReader_GetTransaction_Call) Return(_a0 *models.Transaction, _a1 error) *MockTransactionReader_GetTransaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionReader_GetTransaction_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Transaction, error)) *MockTransactionReader_GetTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactions provides a mock function with given fields: ctx, ids
+func (_m *MockTransactionReader) GetTransactions(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Transaction, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactions")
+	}
+
+	var r0 map[uuid.UUID]*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (map[uuid.UUID]*models.Transaction, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) map[uuid.UUID]*models.Transaction); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionReader_GetTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactions'
+type MockTransactionReader_GetTransactions_Call struct {
+	*mock.Call
+}
+
+// GetTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+func (_e *MockTransactionReader_Expecter) GetTransactions(ctx interface{}, ids interface{}) *MockTransactionReader_GetTransactions_Call {
+	return &MockTransactionReader_GetTransactions_Call{Call: _e.mock.On("GetTransactions", ctx, ids)}
+}
+
+func (_c *MockTransactionReader_GetTransactions_Call) Run(run func(ctx context.Context, ids []uuid.UUID)) *MockTransactionReader_GetTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockTransactionReader_GetTransactions_Call) Return(_a0 map[uuid.UUID]*models.Transaction, _a1 error) *MockTransactionReader_GetTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionReader_GetTransactions_Call) RunAndReturn(run func(context.Context, []uuid.UUID) (map[uuid.UUID]*models.Transaction, error)) *MockTransactionReader_GetTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: ctx
+func (_m *MockTransactionReader) Stats(ctx context.Context) (*models.LedgerStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *models.LedgerStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.LedgerStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.LedgerStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LedgerStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionReader_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockTransactionReader_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockTransactionReader_Expecter) Stats(ctx interface{}) *MockTransactionReader_Stats_Call {
+	return &MockTransactionReader_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+}
+
+func (_c *MockTransactionReader_Stats_Call) Run(run func(ctx context.Context)) *MockTransactionReader_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockTransactionReader_Stats_Call) Return(_a0 *models.LedgerStats, _a1 error) *MockTransactionReader_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionReader_Stats_Call) RunAndReturn(run func(context.Context) (*models.LedgerStats, error)) *MockTransactionReader_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTransactionReader creates a new instance of MockTransactionReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTransactionReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTransactionReader {
+	mock := &MockTransactionReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
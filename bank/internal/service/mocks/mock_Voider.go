@@ -24,6 +24,66 @@ func (_m *MockVoider) EXPECT() *MockVoider_Expecter {
 	return &MockVoider_Expecter{mock: &_m.Mock}
 }
 
+// PartialVoid provides a mock function with given fields: ctx, authorizationID, amount
+func (_m *MockVoider) PartialVoid(ctx context.Context, authorizationID uuid.UUID, amount int64) (*models.Transaction, error) {
+	ret := _m.Called(ctx, authorizationID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PartialVoid")
+	}
+
+	var r0 *models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) (*models.Transaction, error)); ok {
+		return rf(ctx, authorizationID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) *models.Transaction); ok {
+		r0 = rf(ctx, authorizationID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64) error); ok {
+		r1 = rf(ctx, authorizationID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockVoider_PartialVoid_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PartialVoid'
+type MockVoider_PartialVoid_Call struct {
+	*mock.Call
+}
+
+// PartialVoid is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorizationID uuid.UUID
+//   - amount int64
+func (_e *MockVoider_Expecter) PartialVoid(ctx interface{}, authorizationID interface{}, amount interface{}) *MockVoider_PartialVoid_Call {
+	return &MockVoider_PartialVoid_Call{Call: _e.mock.On("PartialVoid", ctx, authorizationID, amount)}
+}
+
+func (_c *MockVoider_PartialVoid_Call) Run(run func(ctx context.Context, authorizationID uuid.UUID, amount int64)) *MockVoider_PartialVoid_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockVoider_PartialVoid_Call) Return(_a0 *models.Transaction, _a1 error) *MockVoider_PartialVoid_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockVoider_PartialVoid_Call) RunAndReturn(run func(context.Context, uuid.UUID, int64) (*models.Transaction, error)) *MockVoider_PartialVoid_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Void provides a mock function with given fields: ctx, authorizationID
 func (_m *MockVoider) Void(ctx context.Context, authorizationID uuid.UUID) (*models.Transaction, error) {
 	ret := _m.Called(ctx, authorizationID)
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -15,42 +14,47 @@ import (
 
 // RefundService handles refund operations
 type RefundService struct {
-	db *db.DB
+	db          *db.DB
+	notifier    Notifier
+	accountOpts []repository.AccountOption
+	txnOpts     []repository.TransactionOption
 }
 
 // NewRefundService creates a new RefundService
-func NewRefundService(database *db.DB) *RefundService {
+// The notifier is optional and receives the refund once it is committed.
+// txnOpts configure the transaction repositories the service creates.
+func NewRefundService(database *db.DB, notifier Notifier, txnOpts ...repository.TransactionOption) *RefundService {
+	accountOpts, txnOpts := repositoryOptions(database, txnOpts)
 	return &RefundService{
-		db: database,
+		db:          database,
+		notifier:    notifier,
+		txnOpts:     txnOpts,
+		accountOpts: accountOpts,
 	}
 }
 
-// Refund refunds a captured payment
+// Refund refunds all or part of a captured payment
+// The total of all refunds against a capture may not exceed the captured amount
 func (s *RefundService) Refund(ctx context.Context, captureID uuid.UUID, amount int64) (*models.Transaction, error) {
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
-	if err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to start transaction: %v", err),
+	var refundTxn *models.Transaction
+	err := s.db.WithTransaction(ctx, db.ReadCommittedTx(), func(tx *db.Tx) error {
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
+
+		var err error
+		refundTxn, err = s.performRefund(ctx, txTransactionRepo, txAccountRepo, captureID, amount)
+		if err != nil {
+			return err
 		}
-	}
-	defer func() {
-		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
-	}()
-
-	txTransactionRepo := repository.NewTransactionRepository(tx)
-	txAccountRepo := repository.NewAccountRepository(tx)
-
-	refundTxn, err := s.performRefund(ctx, txTransactionRepo, txAccountRepo, captureID, amount)
+		return beforeCommit(ctx, tx, refundTxn)
+	})
 	if err != nil {
-		return nil, err
+		return nil, transactionError(err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to commit transaction: %v", err),
-		}
+	recordCommitted(refundTxn)
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, refundTxn)
 	}
 
 	return refundTxn, nil
@@ -65,6 +69,9 @@ func (s *RefundService) performRefund(
 	amount int64,
 ) (*models.Transaction, error) {
 	captureTxn, err := transactionRepo.FindByIDForUpdate(ctx, captureID)
+	if db.IsRetryable(err) {
+		return nil, err
+	}
 	if err != nil || captureTxn.Type != models.TransactionTypeCapture {
 		return nil, &ServiceError{
 			Code:    ErrCodeCaptureNotFound,
@@ -79,17 +86,62 @@ func (s *RefundService) performRefund(
 		}
 	}
 
-	if amount != captureTxn.AmountCents {
+	if err := ValidateAmount(amount); err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidAmount,
+			Message: err.Error(),
+		}
+	}
+
+	// The capture row lock above serializes concurrent refunds, so the sum is stable
+	alreadyRefunded, err := transactionRepo.SumByReferenceID(ctx, captureID, models.TransactionTypeRefund)
+	if err != nil {
 		return nil, &ServiceError{
-			Code: ErrCodeAmountMismatch,
-			Message: fmt.Sprintf("refund amount (%d) must equal capture amount (%d)",
-				amount, captureTxn.AmountCents),
+			Code:    ErrCodeInternalError,
+			Message: "failed to sum existing refunds",
+			Err:     err,
+		}
+	}
+
+	chargedBack, err := transactionRepo.SumByReferenceID(ctx, captureID, models.TransactionTypeChargeback)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to sum existing chargebacks",
+			Err:     err,
+		}
+	}
+
+	// Funds returned through a chargeback can no longer be refunded
+	reversed := alreadyRefunded + chargedBack
+	remaining := captureTxn.AmountCents - reversed
+	if remaining <= 0 {
+		return nil, &ServiceError{
+			Code:    ErrCodeAlreadyRefunded,
+			Message: "capture has already been fully refunded",
+		}
+	}
+	if amount > remaining {
+		return nil, &ServiceError{
+			Code: ErrCodeRefundExceedsCapture,
+			Message: fmt.Sprintf("refund amount (%d) exceeds remaining refundable amount (%d)",
+				amount, remaining),
+			Err: ErrRefundExceedsCapture,
 		}
 	}
 
 	refundID := uuid.New()
 	refundedAt := time.Now()
 
+	metadata, err := fxMetadata(captureTxn, reversed, amount)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to convert amount",
+			Err:     err,
+		}
+	}
+
 	refundTxn := &models.Transaction{
 		ID:          refundID,
 		AccountID:   captureTxn.AccountID,
@@ -98,6 +150,7 @@ func (s *RefundService) performRefund(
 		Currency:    captureTxn.Currency,
 		ReferenceID: &captureID,
 		Status:      models.TransactionStatusCompleted,
+		Metadata:    metadata,
 		CreatedAt:   refundedAt,
 	}
 
@@ -111,10 +164,13 @@ func (s *RefundService) performRefund(
 		return nil, fmt.Errorf("failed to create refund: %w", err)
 	}
 
-	if err := accountRepo.AdjustBalances(ctx, captureTxn.AccountID, amount, amount); err != nil {
+	refunded := refundTxn.SettlementAmount()
+	if _, _, err := accountRepo.AdjustBalances(ctx, captureTxn.AccountID, refunded, refunded,
+		balanceReason("refund", refundTxn.ID)); err != nil {
 		return nil, &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to adjust balance: %v", err),
+			Message: "failed to adjust balance",
+			Err:     err,
 		}
 	}
 
@@ -123,12 +179,20 @@ func (s *RefundService) performRefund(
 
 // GetRefund retrieves a refund by ID
 func (s *RefundService) GetRefund(ctx context.Context, refundID uuid.UUID) (*models.Transaction, error) {
-	repo := repository.NewTransactionRepository(s.db)
+	repo := repository.NewTransactionRepository(s.db, s.txnOpts...)
 	txn, err := repo.FindByID(ctx, refundID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to find transaction",
+			Err:     err,
+		}
+	}
 	if err != nil || txn.Type != models.TransactionTypeRefund {
 		return nil, &ServiceError{
-			Code:    "refund_not_found",
+			Code:    ErrCodeRefundNotFound,
 			Message: "refund not found",
+			Err:     models.ErrTransactionNotFound,
 		}
 	}
 
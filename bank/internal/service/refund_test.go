@@ -16,7 +16,7 @@ func TestRefundService_PerformRefund(t *testing.T) {
 	t.Run("successful refund", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -33,8 +33,10 @@ func TestRefundService_PerformRefund(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(0), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(10000), int64(10000)).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(10000, "USD"), models.NewMoney(10000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
 
 		result, err := service.performRefund(ctx, mockTxRepo, mockAccountRepo, captureID, amount)
 
@@ -52,7 +54,7 @@ func TestRefundService_PerformRefund(t *testing.T) {
 	t.Run("capture not found", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -76,7 +78,7 @@ func TestRefundService_PerformRefund(t *testing.T) {
 	t.Run("wrong transaction type", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -110,7 +112,7 @@ func TestRefundService_PerformRefund(t *testing.T) {
 	t.Run("capture not completed", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -140,16 +142,16 @@ func TestRefundService_PerformRefund(t *testing.T) {
 		mockTxRepo.AssertExpectations(t)
 	})
 
-	t.Run("amount mismatch", func(t *testing.T) {
+	t.Run("refund exceeds remaining captured amount", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
 		accountID := uuid.New()
 		var captureAmount int64 = 10000
-		var refundAmount int64 = 5000 // Different amount
+		var refundAmount int64 = 5000
 
 		captureTx := &models.Transaction{
 			ID:          captureID,
@@ -160,24 +162,119 @@ func TestRefundService_PerformRefund(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(6000), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
 
 		result, err := service.performRefund(ctx, mockTxRepo, mockAccountRepo, captureID, refundAmount)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrRefundExceedsCapture)
 
 		var svcErr *ServiceError
 		if assert.ErrorAs(t, err, &svcErr) {
-			assert.Equal(t, ErrCodeAmountMismatch, svcErr.Code)
+			assert.Equal(t, ErrCodeRefundExceedsCapture, svcErr.Code)
 		}
 
 		mockTxRepo.AssertExpectations(t)
 	})
 
+	t.Run("partial refund within remaining amount", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewRefundService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+		accountID := uuid.New()
+
+		captureTx := &models.Transaction{
+			ID:          captureID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeCapture,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusCompleted,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(6000), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(4000, "USD"), models.NewMoney(4000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		result, err := service.performRefund(ctx, mockTxRepo, mockAccountRepo, captureID, 4000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4000), result.AmountCents)
+
+		mockTxRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("capture fully refunded", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewRefundService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+
+		captureTx := &models.Transaction{
+			ID:          captureID,
+			AccountID:   uuid.New(),
+			Type:        models.TransactionTypeCapture,
+			AmountCents: 10000,
+			Status:      models.TransactionStatusCompleted,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(10000), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
+
+		result, err := service.performRefund(ctx, mockTxRepo, mockAccountRepo, captureID, 100)
+
+		assert.Nil(t, result)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeAlreadyRefunded, svcErr.Code)
+		}
+
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("charged back funds are not refundable", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewRefundService(nil, nil)
+		ctx := context.Background()
+
+		captureID := uuid.New()
+
+		captureTx := &models.Transaction{
+			ID:          captureID,
+			AccountID:   uuid.New(),
+			Type:        models.TransactionTypeCapture,
+			AmountCents: 10000,
+			Status:      models.TransactionStatusCompleted,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(0), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(7000), nil)
+
+		result, err := service.performRefund(ctx, mockTxRepo, mockAccountRepo, captureID, 5000)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrRefundExceedsCapture)
+
+		mockTxRepo.AssertExpectations(t)
+	})
+
 	t.Run("already refunded - duplicate error", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -193,6 +290,8 @@ func TestRefundService_PerformRefund(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(0), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
 			Return(models.ErrDuplicateTransaction)
 
@@ -212,7 +311,7 @@ func TestRefundService_PerformRefund(t *testing.T) {
 	t.Run("transaction creation fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -228,6 +327,8 @@ func TestRefundService_PerformRefund(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(0), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
 			Return(assert.AnError)
 
@@ -243,7 +344,7 @@ func TestRefundService_PerformRefund(t *testing.T) {
 	t.Run("balance adjustment fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewRefundService(nil)
+		service := NewRefundService(nil, nil)
 		ctx := context.Background()
 
 		captureID := uuid.New()
@@ -255,13 +356,16 @@ func TestRefundService_PerformRefund(t *testing.T) {
 			AccountID:   accountID,
 			Type:        models.TransactionTypeCapture,
 			AmountCents: amount,
+			Currency:    "USD",
 			Status:      models.TransactionStatusCompleted,
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, captureID).Return(captureTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeRefund).Return(int64(0), nil)
+		mockTxRepo.On("SumByReferenceID", ctx, captureID, models.TransactionTypeChargeback).Return(int64(0), nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(10000), int64(10000)).
-			Return(assert.AnError)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(10000, "USD"), models.NewMoney(10000, "USD"), mock.Anything).
+			Return(models.Money{}, models.Money{}, assert.AnError)
 
 		result, err := service.performRefund(ctx, mockTxRepo, mockAccountRepo, captureID, amount)
 
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+)
+
+// repositoryOptions returns the account and transaction repository options for the tables of
+// database's prefix, with txnOpts appended to the transaction options
+// A nil database, as in unit tests, uses the unprefixed tables.
+func repositoryOptions(database *db.DB, txnOpts []repository.TransactionOption) ([]repository.AccountOption, []repository.TransactionOption) {
+	var prefix string
+	if database != nil {
+		prefix = database.TablePrefix()
+	}
+	tables := repository.NewTables(prefix)
+	return []repository.AccountOption{repository.WithAccountTables(tables)},
+		append([]repository.TransactionOption{repository.WithTransactionTables(tables)}, txnOpts...)
+}
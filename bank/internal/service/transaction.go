@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/google/uuid"
+)
+
+// statsCacheTTL is how long ledger statistics are reused before they are counted again
+const statsCacheTTL = 5 * time.Second
+
+// TransactionService handles read-only lookups of ledger transactions of any type
+type TransactionService struct {
+	db      *db.DB
+	stats   *models.LedgerStats
+	txnOpts []repository.TransactionOption
+	statsMu sync.Mutex
+}
+
+// NewTransactionService creates a new TransactionService
+// txnOpts configure the transaction repositories the service creates
+func NewTransactionService(database *db.DB, txnOpts ...repository.TransactionOption) *TransactionService {
+	_, txnOpts = repositoryOptions(database, txnOpts)
+	return &TransactionService{
+		db:      database,
+		txnOpts: txnOpts,
+	}
+}
+
+// GetTransaction retrieves a transaction by ID
+func (s *TransactionService) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	repo := repository.NewTransactionRepository(s.db.Reader(ctx), s.txnOpts...)
+	txn, err := repo.FindByID(ctx, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, &ServiceError{
+			Code:    ErrCodeTransactionNotFound,
+			Message: "transaction not found",
+			Err:     models.ErrTransactionNotFound,
+		}
+	}
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to find transaction: %v", err),
+			Err:     err,
+		}
+	}
+
+	return txn, nil
+}
+
+// GetTransactions retrieves the transactions with the given IDs, keyed by ID.
+// IDs with no transaction are absent from the map rather than failing the lookup.
+func (s *TransactionService) GetTransactions(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Transaction, error) {
+	repo := repository.NewTransactionRepository(s.db.Reader(ctx), s.txnOpts...)
+	txns, err := repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to find transactions: %v", err),
+			Err:     err,
+		}
+	}
+
+	return txns, nil
+}
+
+// FindByMetadata returns up to limit of the newest transactions whose metadata sets key to value
+func (s *TransactionService) FindByMetadata(ctx context.Context, key, value string, limit int) ([]*models.Transaction, error) {
+	repo := repository.NewTransactionRepository(s.db.Reader(ctx), s.txnOpts...)
+	txns, err := repo.FindByMetadata(ctx, key, value, limit)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to search transactions: %v", err),
+			Err:     err,
+		}
+	}
+
+	return txns, nil
+}
+
+// ExportTransactions calls fn with every transaction, live or archived, created within [from, to), oldest first.
+// Transactions are streamed from the database one at a time, so any number can be exported. An error
+// returned by fn stops the export and is returned unchanged.
+func (s *TransactionService) ExportTransactions(
+	ctx context.Context,
+	from, to time.Time,
+	fn func(*models.ExportedTransaction) error,
+) error {
+	repo := repository.NewTransactionRepository(s.db.Reader(ctx), s.txnOpts...)
+	rows, err := repo.ExportByDateRange(ctx, from, to)
+	if err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to export transactions: %v", err),
+			Err:     err,
+		}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		txn, err := repository.ScanExportedTransaction(rows)
+		if err != nil {
+			return &ServiceError{
+				Code:    ErrCodeInternalError,
+				Message: "failed to read exported transaction",
+				Err:     err,
+			}
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to export transactions",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Stats returns counts of live transactions by type and status, and of accounts.
+// Results are reused for statsCacheTTL; concurrent callers wait for a single query rather than
+// each counting the ledger.
+func (s *TransactionService) Stats(ctx context.Context) (*models.LedgerStats, error) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	if s.stats != nil && time.Since(s.stats.ComputedAt) < statsCacheTTL {
+		return s.stats, nil
+	}
+
+	repo := repository.NewTransactionRepository(s.db.Reader(ctx), s.txnOpts...)
+	stats, err := repo.Stats(ctx)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: fmt.Sprintf("failed to count transactions: %v", err),
+			Err:     err,
+		}
+	}
+
+	stats.ComputedAt = time.Now().UTC()
+	s.stats = stats
+	return stats, nil
+}
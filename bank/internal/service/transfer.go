@@ -0,0 +1,232 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/db"
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository"
+	"github.com/google/uuid"
+)
+
+// TransferService moves funds between accounts
+type TransferService struct {
+	db          *db.DB
+	now         func() time.Time
+	accountOpts []repository.AccountOption
+	txnOpts     []repository.TransactionOption
+}
+
+// NewTransferService creates a new TransferService
+// txnOpts configure the transaction repositories the service creates.
+func NewTransferService(database *db.DB, txnOpts ...repository.TransactionOption) *TransferService {
+	accountOpts, txnOpts := repositoryOptions(database, txnOpts)
+	return &TransferService{
+		db:          database,
+		now:         time.Now,
+		txnOpts:     txnOpts,
+		accountOpts: accountOpts,
+	}
+}
+
+// TransferResult identifies the two ledger transactions recorded for a transfer
+type TransferResult struct {
+	DebitID  uuid.UUID // TRANSFER_OUT on the source account
+	CreditID uuid.UUID // TRANSFER_IN on the destination account, referencing the debit
+}
+
+// Transfer moves amount from one account to another, recording a TRANSFER_OUT on the source and a
+// TRANSFER_IN on the destination that references it. Both accounts must hold currency, and
+// the source must have amount available. Everything commits in one database transaction.
+// Both accounts are locked in ascending ID order whichever way the money flows, so concurrent
+// transfers between the same accounts queue behind each other instead of deadlocking.
+func (s *TransferService) Transfer(
+	ctx context.Context,
+	fromAccountNumber, toAccountNumber string,
+	amount int64,
+	currency string,
+) (*TransferResult, error) {
+	var result *TransferResult
+	err := s.db.WithTransaction(ctx, db.ReadCommittedTx(), func(tx *db.Tx) error {
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
+
+		var err error
+		result, err = s.performTransfer(ctx, txTransactionRepo, txAccountRepo, fromAccountNumber, toAccountNumber, amount, currency)
+		return err
+	})
+	if err != nil {
+		return nil, transactionError(err)
+	}
+
+	transferred := models.NewMoney(amount, currency)
+	for _, txnType := range []models.TransactionType{models.TransactionTypeTransferOut, models.TransactionTypeTransferIn} {
+		countTransaction(txnType, models.TransactionStatusCompleted)
+		observeAmount(transferred, txnType)
+	}
+	return result, nil
+}
+
+// performTransfer contains the core transfer business logic
+func (s *TransferService) performTransfer(
+	ctx context.Context,
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.AccountRepository,
+	fromAccountNumber, toAccountNumber string,
+	amount int64,
+	currency string,
+) (*TransferResult, error) {
+	if err := ValidateAmount(amount); err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidAmount,
+			Message: err.Error(),
+		}
+	}
+	if !models.Currencies.IsValid(currency) {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidCurrency,
+			Message: fmt.Sprintf("unsupported currency %q", currency),
+			Err:     models.ErrInvalidCurrency,
+		}
+	}
+	if fromAccountNumber == toAccountNumber {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidTransfer,
+			Message: "cannot transfer to the same account",
+		}
+	}
+
+	locked, err := lockAccounts(ctx, accountRepo, fromAccountNumber, toAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	from, to := locked[0], locked[1]
+
+	if from.Currency != currency || to.Currency != currency {
+		return nil, &ServiceError{
+			Code:    ErrCodeInvalidCurrency,
+			Message: fmt.Sprintf("both accounts must hold %s to transfer it", currency),
+			Err:     models.ErrCurrencyMismatch,
+		}
+	}
+	// The source row is locked, so its available balance cannot change before the debit
+	if from.AvailableBalanceCents < amount {
+		return nil, &ServiceError{
+			Code:    ErrCodeInsufficientFunds,
+			Message: "insufficient funds",
+			Err:     ErrInsufficientFunds,
+		}
+	}
+
+	debitID, creditID := uuid.New(), uuid.New()
+	transferredAt := s.now()
+	entries := []*models.Transaction{
+		{
+			ID:          debitID,
+			AccountID:   from.ID,
+			Type:        models.TransactionTypeTransferOut,
+			AmountCents: amount,
+			Currency:    currency,
+			Status:      models.TransactionStatusCompleted,
+			CreatedAt:   transferredAt,
+		},
+		{
+			ID:          creditID,
+			AccountID:   to.ID,
+			Type:        models.TransactionTypeTransferIn,
+			AmountCents: amount,
+			Currency:    currency,
+			ReferenceID: &debitID,
+			Status:      models.TransactionStatusCompleted,
+			CreatedAt:   transferredAt,
+		},
+	}
+	for _, entry := range entries {
+		if err := transactionRepo.Create(ctx, entry); err != nil {
+			return nil, &ServiceError{
+				Code:    ErrCodeInternalError,
+				Message: "failed to record transfer",
+				Err:     err,
+			}
+		}
+	}
+
+	if _, _, err := accountRepo.AdjustBalances(ctx, from.ID,
+		models.NewMoney(-amount, currency), models.NewMoney(-amount, currency), balanceReason("transfer out", debitID)); err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to debit account",
+			Err:     err,
+		}
+	}
+	if _, _, err := accountRepo.AdjustBalances(ctx, to.ID,
+		models.NewMoney(amount, currency), models.NewMoney(amount, currency), balanceReason("transfer in", creditID)); err != nil {
+		return nil, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to credit account",
+			Err:     err,
+		}
+	}
+
+	return &TransferResult{DebitID: debitID, CreditID: creditID}, nil
+}
+
+// lockAccounts row-locks the accounts with the given numbers and returns them in the order given.
+// Locks are taken in ascending account ID order, not argument order, so that two transactions
+// locking the same accounts always acquire them in the same sequence and cannot deadlock.
+func lockAccounts(
+	ctx context.Context,
+	accountRepo repository.AccountRepository,
+	accountNumbers ...string,
+) ([]*models.Account, error) {
+	type pending struct {
+		account *models.Account
+		index   int
+	}
+
+	order := make([]pending, len(accountNumbers))
+	for i, number := range accountNumbers {
+		account, err := accountRepo.FindByAccountNumber(ctx, number)
+		if err != nil {
+			return nil, accountLookupError(err)
+		}
+		order[i] = pending{account: account, index: i}
+	}
+	slices.SortFunc(order, func(a, b pending) int {
+		return bytes.Compare(a.account.ID[:], b.account.ID[:])
+	})
+
+	locked := make([]*models.Account, len(accountNumbers))
+	for _, p := range order {
+		account, err := accountRepo.FindByAccountNumberForUpdate(ctx, p.account.AccountNumber)
+		if err != nil {
+			return nil, accountLookupError(err)
+		}
+		locked[p.index] = account
+	}
+	return locked, nil
+}
+
+// accountLookupError maps a failed account lookup, passing retryable failures through for WithTransaction
+func accountLookupError(err error) error {
+	if db.IsRetryable(err) {
+		return err
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		return &ServiceError{
+			Code:    ErrCodeAccountNotFound,
+			Message: "account not found",
+			Err:     models.ErrAccountNotFound,
+		}
+	}
+	return &ServiceError{
+		Code:    ErrCodeInternalError,
+		Message: "failed to look up account",
+		Err:     err,
+	}
+}
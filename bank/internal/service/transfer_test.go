@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/benx421/payment-gateway/bank/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferService_PerformTransfer(t *testing.T) {
+	low := &models.Account{
+		ID:                    uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		AccountNumber:         "4111111111111111",
+		Currency:              "USD",
+		AvailableBalanceCents: 1000,
+	}
+	high := &models.Account{
+		ID:                    uuid.MustParse("ffffffff-0000-0000-0000-000000000001"),
+		AccountNumber:         "4242424242424242",
+		Currency:              "USD",
+		AvailableBalanceCents: 1000,
+	}
+
+	// expectLocks sets up lookups of both accounts, requiring the lower ID to be locked first
+	expectLocks := func(repo *mocks.MockAccountRepository, ctx context.Context, first, second *models.Account) {
+		repo.On("FindByAccountNumber", ctx, first.AccountNumber).Return(first, nil)
+		repo.On("FindByAccountNumber", ctx, second.AccountNumber).Return(second, nil)
+		mock.InOrder(
+			repo.On("FindByAccountNumberForUpdate", ctx, first.AccountNumber).Return(first, nil),
+			repo.On("FindByAccountNumberForUpdate", ctx, second.AccountNumber).Return(second, nil),
+		)
+	}
+
+	for _, tt := range []struct {
+		from, to *models.Account
+		name     string
+	}{
+		{low, high, "from the lower ID"},
+		{high, low, "from the higher ID"},
+	} {
+		t.Run(tt.name+" locks the lower ID first and records linked entries", func(t *testing.T) {
+			mockTxRepo := mocks.NewMockTransactionRepository(t)
+			mockAccountRepo := mocks.NewMockAccountRepository(t)
+			service := NewTransferService(nil)
+			ctx := context.Background()
+
+			expectLocks(mockAccountRepo, ctx, low, high)
+			var entries []*models.Transaction
+			mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
+				Run(func(args mock.Arguments) { entries = append(entries, args.Get(1).(*models.Transaction)) }).
+				Return(nil)
+			mockAccountRepo.On("AdjustBalances", ctx, tt.from.ID, models.NewMoney(-500, "USD"), models.NewMoney(-500, "USD"), mock.Anything).
+				Return(models.Money{}, models.Money{}, nil)
+			mockAccountRepo.On("AdjustBalances", ctx, tt.to.ID, models.NewMoney(500, "USD"), models.NewMoney(500, "USD"), mock.Anything).
+				Return(models.Money{}, models.Money{}, nil)
+
+			result, err := service.performTransfer(ctx, mockTxRepo, mockAccountRepo, tt.from.AccountNumber, tt.to.AccountNumber, 500, "USD")
+
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			debit, credit := entries[0], entries[1]
+			assert.Equal(t, result.DebitID, debit.ID)
+			assert.Equal(t, result.CreditID, credit.ID)
+			assert.Equal(t, models.TransactionTypeTransferOut, debit.Type)
+			assert.Equal(t, tt.from.ID, debit.AccountID)
+			assert.Equal(t, models.TransactionTypeTransferIn, credit.Type)
+			assert.Equal(t, tt.to.ID, credit.AccountID)
+			assert.Nil(t, debit.ReferenceID, "the debit should start the chain so the transfer can be archived")
+			assert.Equal(t, debit.ID, *credit.ReferenceID, "the credit should reference the debit")
+			assert.Equal(t, int64(500), debit.AmountCents)
+			assert.Equal(t, models.TransactionStatusCompleted, credit.Status)
+			mockAccountRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewTransferService(nil)
+		ctx := context.Background()
+
+		expectLocks(mockAccountRepo, ctx, low, high)
+
+		_, err := service.performTransfer(ctx, mocks.NewMockTransactionRepository(t), mockAccountRepo,
+			low.AccountNumber, high.AccountNumber, 1001, "USD")
+
+		var svcErr *ServiceError
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, ErrCodeInsufficientFunds, svcErr.Code)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("currency differs from an account", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewTransferService(nil)
+		ctx := context.Background()
+
+		expectLocks(mockAccountRepo, ctx, low, high)
+
+		_, err := service.performTransfer(ctx, mocks.NewMockTransactionRepository(t), mockAccountRepo,
+			low.AccountNumber, high.AccountNumber, 500, "EUR")
+
+		var svcErr *ServiceError
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, ErrCodeInvalidCurrency, svcErr.Code)
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		service := NewTransferService(nil)
+
+		_, err := service.performTransfer(context.Background(), mocks.NewMockTransactionRepository(t), mocks.NewMockAccountRepository(t),
+			low.AccountNumber, high.AccountNumber, 500, "XXX")
+
+		var svcErr *ServiceError
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, ErrCodeInvalidCurrency, svcErr.Code)
+	})
+
+	t.Run("same account", func(t *testing.T) {
+		service := NewTransferService(nil)
+
+		_, err := service.performTransfer(context.Background(), mocks.NewMockTransactionRepository(t), mocks.NewMockAccountRepository(t),
+			low.AccountNumber, low.AccountNumber, 500, "USD")
+
+		var svcErr *ServiceError
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, ErrCodeInvalidTransfer, svcErr.Code)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		service := NewTransferService(nil)
+
+		_, err := service.performTransfer(context.Background(), mocks.NewMockTransactionRepository(t), mocks.NewMockAccountRepository(t),
+			low.AccountNumber, high.AccountNumber, 0, "USD")
+
+		var svcErr *ServiceError
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, ErrCodeInvalidAmount, svcErr.Code)
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewTransferService(nil)
+		ctx := context.Background()
+
+		mockAccountRepo.On("FindByAccountNumber", ctx, low.AccountNumber).Return(low, nil)
+		mockAccountRepo.On("FindByAccountNumber", ctx, "4000000000000002").Return(nil, models.ErrAccountNotFound)
+
+		_, err := service.performTransfer(ctx, mocks.NewMockTransactionRepository(t), mockAccountRepo,
+			low.AccountNumber, "4000000000000002", 500, "USD")
+
+		var svcErr *ServiceError
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, ErrCodeAccountNotFound, svcErr.Code)
+	})
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/cvvcrypt"
+	"github.com/benx421/payment-gateway/bank/internal/models"
 )
 
 // ValidateLuhn validates a card number using the Luhn algorithm
@@ -86,3 +90,54 @@ func ValidateAmount(amount int64) error {
 
 	return nil
 }
+
+// ValidateHoldExpiry checks that a requested authorization expiry lies after now and no more than maxHold ahead
+func ValidateHoldExpiry(expiresAt, now time.Time, maxHold time.Duration) error {
+	if !expiresAt.After(now) {
+		return fmt.Errorf("invalid expiry: must be in the future")
+	}
+
+	if expiresAt.After(now.Add(maxHold)) {
+		return fmt.Errorf("invalid expiry: must be within %s", maxHold)
+	}
+
+	return nil
+}
+
+// ValidateCurrency checks that currency is a three-letter uppercase code in the ISO-4217 registry
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return fmt.Errorf("invalid currency: must be a 3-letter code")
+	}
+
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return fmt.Errorf("invalid currency: must contain only uppercase letters")
+		}
+	}
+
+	if !models.Currencies.IsValid(currency) {
+		return fmt.Errorf("invalid currency: %s is not a supported ISO-4217 code", currency)
+	}
+
+	return nil
+}
+
+// VerifyCVV checks a supplied CVV against the stored value in constant time.
+// Stored values are decrypted with cvvCipher when it is set; values written before encryption
+// was enabled are still compared as plaintext.
+func VerifyCVV(cvvCipher *cvvcrypt.Cipher, stored, supplied string) error {
+	expected := stored
+	if _, encrypted := cvvcrypt.KeyVersion(stored); cvvCipher != nil && encrypted {
+		plain, err := cvvCipher.DecryptCVV(stored)
+		if err != nil {
+			return err
+		}
+		expected = plain
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(supplied)) != 1 {
+		return ErrCVVMismatch
+	}
+	return nil
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
+	"github.com/benx421/payment-gateway/bank/internal/cvvcrypt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateLuhn(t *testing.T) {
@@ -185,3 +189,64 @@ func TestValidateAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHoldExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	maxHold := 30 * 24 * time.Hour
+
+	tests := []struct {
+		expiresAt time.Time
+		name      string
+		wantErr   bool
+	}{
+		{name: "within the cap", expiresAt: now.Add(7 * 24 * time.Hour)},
+		{name: "exactly at the cap", expiresAt: now.Add(maxHold)},
+		{name: "beyond the cap", expiresAt: now.Add(maxHold + time.Second), wantErr: true},
+		{name: "now", expiresAt: now, wantErr: true},
+		{name: "in the past", expiresAt: now.Add(-time.Hour), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHoldExpiry(tt.expiresAt, now, maxHold)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyCVV(t *testing.T) {
+	cvvCipher, err := cvvcrypt.NewCipher(map[byte][]byte{1: bytes.Repeat([]byte{1}, cvvcrypt.KeySize)}, 1)
+	require.NoError(t, err)
+	encrypted, err := cvvCipher.EncryptCVV("123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		wantErr  error
+		cipher   *cvvcrypt.Cipher
+		name     string
+		stored   string
+		supplied string
+	}{
+		{name: "plaintext match", stored: "123", supplied: "123"},
+		{name: "plaintext mismatch", stored: "123", supplied: "124", wantErr: ErrCVVMismatch},
+		{name: "encrypted match", cipher: cvvCipher, stored: encrypted, supplied: "123"},
+		{name: "encrypted mismatch", cipher: cvvCipher, stored: encrypted, supplied: "321", wantErr: ErrCVVMismatch},
+		{name: "legacy plaintext with cipher", cipher: cvvCipher, stored: "123", supplied: "123"},
+		{name: "ciphertext without cipher", stored: encrypted, supplied: "123", wantErr: ErrCVVMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyCVV(tt.cipher, tt.stored, tt.supplied)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -15,41 +14,50 @@ import (
 
 // VoidService handles authorization void operations
 type VoidService struct {
-	db *db.DB
+	db          *db.DB
+	notifier    Notifier
+	accountOpts []repository.AccountOption
+	txnOpts     []repository.TransactionOption
 }
 
 // NewVoidService creates a new VoidService
-func NewVoidService(database *db.DB) *VoidService {
+// The notifier is optional and receives the void once it is committed.
+// txnOpts configure the transaction repositories the service creates.
+func NewVoidService(database *db.DB, notifier Notifier, txnOpts ...repository.TransactionOption) *VoidService {
+	accountOpts, txnOpts := repositoryOptions(database, txnOpts)
 	return &VoidService{
-		db: database,
+		db:          database,
+		notifier:    notifier,
+		txnOpts:     txnOpts,
+		accountOpts: accountOpts,
 	}
 }
 
 // Void cancels an authorization before it's captured
+// Voiding an already voided authorization returns the existing void
 func (s *VoidService) Void(ctx context.Context, authorizationID uuid.UUID) (*models.Transaction, error) {
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
-	if err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to start transaction: %v", err),
-		}
-	}
-	defer func() {
-		_ = tx.Rollback() //nolint:errcheck // rollback error is not critical in defer
-	}()
+	var voidTxn *models.Transaction
+	var created bool
+	err := s.db.WithTransaction(ctx, db.ReadCommittedTx(), func(tx *db.Tx) error {
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
 
-	txTransactionRepo := repository.NewTransactionRepository(tx)
-	txAccountRepo := repository.NewAccountRepository(tx)
-
-	voidTxn, err := s.performVoid(ctx, txTransactionRepo, txAccountRepo, authorizationID)
+		var err error
+		voidTxn, created, err = s.performVoid(ctx, txTransactionRepo, txAccountRepo, authorizationID)
+		if err != nil {
+			return err
+		}
+		return beforeCommit(ctx, tx, voidTxn)
+	})
 	if err != nil {
-		return nil, err
+		return nil, transactionError(err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, &ServiceError{
-			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to commit transaction: %v", err),
+	if created {
+		recordCommitted(voidTxn)
+		countTransaction(models.TransactionTypeAuthHold, models.TransactionStatusVoided)
+		if s.notifier != nil {
+			s.notifier.Notify(ctx, voidTxn)
 		}
 	}
 
@@ -57,78 +65,276 @@ func (s *VoidService) Void(ctx context.Context, authorizationID uuid.UUID) (*mod
 }
 
 // performVoid contains the core void business logic
+// It reports whether a new void was created, or an existing one returned
 func (s *VoidService) performVoid(
 	ctx context.Context,
 	transactionRepo repository.TransactionRepository,
 	accountRepo repository.AccountRepository,
 	authorizationID uuid.UUID,
-) (*models.Transaction, error) {
+) (*models.Transaction, bool, error) {
 	authTxn, err := transactionRepo.FindByIDForUpdate(ctx, authorizationID)
+	if db.IsRetryable(err) {
+		return nil, false, err
+	}
 	if err != nil || authTxn.Type != models.TransactionTypeAuthHold {
-		return nil, &ServiceError{
+		return nil, false, &ServiceError{
 			Code:    ErrCodeAuthNotFound,
 			Message: "authorization not found",
 		}
 	}
 
-	if authTxn.Status != models.TransactionStatusActive {
-		return nil, &ServiceError{
-			Code:    ErrCodeAuthAlreadyUsed,
-			Message: "authorization has already been completed or cancelled",
+	// The authorization row lock above serializes concurrent voids, so a repeat sees the first void
+	existingVoid, err := transactionRepo.FindByReferenceID(ctx, authorizationID, models.TransactionTypeVoid)
+	if err != nil {
+		return nil, false, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to check existing void",
+			Err:     err,
 		}
 	}
+	if existingVoid != nil {
+		return existingVoid, false, nil
+	}
 
 	existingCapture, err := transactionRepo.FindByReferenceID(ctx, authorizationID, models.TransactionTypeCapture)
 	if err != nil {
-		return nil, &ServiceError{
+		return nil, false, &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to check existing capture: %v", err),
+			Message: "failed to check existing capture",
+			Err:     err,
 		}
 	}
 	if existingCapture != nil {
-		return nil, &ServiceError{
+		return nil, false, &ServiceError{
 			Code:    ErrCodeAlreadyCaptured,
 			Message: "cannot void an authorization that has been captured",
+			Err:     ErrAlreadyCaptured,
+		}
+	}
+
+	if authTxn.Status != models.TransactionStatusActive {
+		return nil, false, &ServiceError{
+			Code:    ErrCodeAuthAlreadyUsed,
+			Message: "authorization has already been completed or cancelled",
 		}
 	}
 
 	voidID := uuid.New()
 	voidedAt := time.Now()
 
+	// Partial voids have already released part of the hold; the void cancels what is left
+	authorized := authTxn.AuthorizedAmount()
+	reduced := authTxn.AmountCents - authorized
+	metadata, err := fxMetadata(authTxn, reduced, authorized)
+	if err != nil {
+		return nil, false, &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to convert amount",
+			Err:     err,
+		}
+	}
+
 	voidTxn := &models.Transaction{
 		ID:          voidID,
 		AccountID:   authTxn.AccountID,
 		Type:        models.TransactionTypeVoid,
-		AmountCents: authTxn.AmountCents,
+		AmountCents: authorized,
 		Currency:    authTxn.Currency,
 		ReferenceID: &authorizationID,
 		Status:      models.TransactionStatusCompleted,
+		Metadata:    metadata,
 		CreatedAt:   voidedAt,
 	}
 
 	if err := transactionRepo.Create(ctx, voidTxn); err != nil {
 		if errors.Is(err, models.ErrDuplicateTransaction) {
-			return nil, &ServiceError{
+			return nil, false, &ServiceError{
 				Code:    ErrCodeAlreadyVoided,
 				Message: "authorization has already been voided",
 			}
 		}
-		return nil, fmt.Errorf("failed to create void: %w", err)
+		return nil, false, fmt.Errorf("failed to create void: %w", err)
 	}
 
-	if err := transactionRepo.UpdateStatus(ctx, authorizationID, models.TransactionStatusCompleted); err != nil {
-		return nil, &ServiceError{
+	if err := transactionRepo.UpdateStatus(ctx, authorizationID, models.TransactionStatusVoided); err != nil {
+		return nil, false, &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to update authorization: %v", err),
+			Message: "failed to update authorization",
+			Err:     err,
 		}
 	}
 
-	if err := accountRepo.AdjustBalances(ctx, authTxn.AccountID, 0, authTxn.AmountCents); err != nil {
-		return nil, &ServiceError{
+	released := voidTxn.SettlementAmount()
+	if _, _, err := accountRepo.AdjustBalances(ctx, authTxn.AccountID, models.NewMoney(0, released.Currency), released,
+		balanceReason("void", voidTxn.ID)); err != nil {
+		return nil, false, &ServiceError{
 			Code:    ErrCodeInternalError,
-			Message: fmt.Sprintf("failed to adjust balance: %v", err),
+			Message: "failed to adjust balance",
+			Err:     err,
 		}
 	}
 
+	return voidTxn, true, nil
+}
+
+// PartialVoid lowers an active authorization by amount, releasing that much of the hold while
+// the authorization stays open for capture. The reduction is recorded as a PARTIAL_VOID and the
+// authorization's remaining amount is kept in its metadata. Reducing an authorization to what
+// has already been captured closes it. Releasing all of an uncaptured authorization voids it,
+// recorded as a VOID so a later Void returns it instead of voiding the authorization again.
+func (s *VoidService) PartialVoid(ctx context.Context, authorizationID uuid.UUID, amount int64) (*models.Transaction, error) {
+	var voidTxn *models.Transaction
+	var authStatus models.TransactionStatus
+	err := s.db.WithTransaction(ctx, db.ReadCommittedTx(), func(tx *db.Tx) error {
+		txTransactionRepo := repository.NewTransactionRepository(tx, s.txnOpts...)
+		txAccountRepo := repository.NewAccountRepository(tx, s.accountOpts...)
+
+		var err error
+		voidTxn, authStatus, err = s.performPartialVoid(ctx, txTransactionRepo, txAccountRepo, authorizationID, amount)
+		if err != nil {
+			return err
+		}
+		return beforeCommit(ctx, tx, voidTxn)
+	})
+	if err != nil {
+		return nil, transactionError(err)
+	}
+
+	recordCommitted(voidTxn)
+	if authStatus != "" {
+		countTransaction(models.TransactionTypeAuthHold, authStatus)
+	}
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, voidTxn)
+	}
+
 	return voidTxn, nil
 }
+
+// performPartialVoid contains the core partial void business logic
+// It returns the authorization's new status, or "" when it stays active.
+func (s *VoidService) performPartialVoid(
+	ctx context.Context,
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.AccountRepository,
+	authorizationID uuid.UUID,
+	amount int64,
+) (*models.Transaction, models.TransactionStatus, error) {
+	authTxn, err := transactionRepo.FindByIDForUpdate(ctx, authorizationID)
+	if db.IsRetryable(err) {
+		return nil, "", err
+	}
+	if err != nil || authTxn.Type != models.TransactionTypeAuthHold {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeAuthNotFound,
+			Message: "authorization not found",
+		}
+	}
+
+	if authTxn.Status != models.TransactionStatusActive {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeAuthAlreadyUsed,
+			Message: "authorization has already been completed or cancelled",
+		}
+	}
+
+	if err := ValidateAmount(amount); err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInvalidAmount,
+			Message: err.Error(),
+		}
+	}
+
+	// The authorization row lock above serializes captures and voids, so the sum is stable
+	captured, err := transactionRepo.SumByReferenceID(ctx, authorizationID, models.TransactionTypeCapture)
+	if err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to sum existing captures",
+			Err:     err,
+		}
+	}
+
+	authorized := authTxn.AuthorizedAmount()
+	remaining := authorized - captured
+	if amount > remaining {
+		return nil, "", &ServiceError{
+			Code: ErrCodeVoidExceedsAuth,
+			Message: fmt.Sprintf("void amount (%d) exceeds remaining authorized amount (%d)",
+				amount, remaining),
+			Err: ErrVoidExceedsAuth,
+		}
+	}
+
+	reduced := authTxn.AmountCents - authorized
+	metadata, err := fxMetadata(authTxn, reduced+captured, amount)
+	if err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to convert amount",
+			Err:     err,
+		}
+	}
+	if metadata == nil {
+		metadata = make(map[string]any, 1)
+	}
+	authorized -= amount
+	metadata[models.MetadataKeyAuthorizedAmount] = authorized
+
+	txnType, reason := models.TransactionTypePartialVoid, "partial void"
+	if amount == remaining && captured == 0 {
+		txnType, reason = models.TransactionTypeVoid, "void"
+	}
+
+	voidTxn := &models.Transaction{
+		ID:          uuid.New(),
+		AccountID:   authTxn.AccountID,
+		Type:        txnType,
+		AmountCents: amount,
+		Currency:    authTxn.Currency,
+		ReferenceID: &authorizationID,
+		Status:      models.TransactionStatusCompleted,
+		Metadata:    metadata,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := transactionRepo.Create(ctx, voidTxn); err != nil {
+		return nil, "", fmt.Errorf("failed to create partial void: %w", err)
+	}
+
+	if err := transactionRepo.MergeMetadata(ctx, authorizationID,
+		map[string]any{models.MetadataKeyAuthorizedAmount: authorized}); err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to update authorization",
+			Err:     err,
+		}
+	}
+
+	var authStatus models.TransactionStatus
+	if amount == remaining {
+		authStatus = models.TransactionStatusVoided
+		if captured > 0 {
+			authStatus = models.TransactionStatusCompleted
+		}
+		if err := transactionRepo.UpdateStatus(ctx, authorizationID, authStatus); err != nil {
+			return nil, "", &ServiceError{
+				Code:    ErrCodeInternalError,
+				Message: "failed to update authorization",
+				Err:     err,
+			}
+		}
+	}
+
+	released := voidTxn.SettlementAmount()
+	if _, _, err := accountRepo.AdjustBalances(ctx, authTxn.AccountID, models.NewMoney(0, released.Currency), released,
+		balanceReason(reason, voidTxn.ID)); err != nil {
+		return nil, "", &ServiceError{
+			Code:    ErrCodeInternalError,
+			Message: "failed to adjust balance",
+			Err:     err,
+		}
+	}
+
+	return voidTxn, authStatus, nil
+}
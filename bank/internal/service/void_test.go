@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	"github.com/benx421/payment-gateway/bank/internal/models"
@@ -16,7 +17,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("successful void", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -33,14 +34,16 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
 		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(nil, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(0), int64(10000)).Return(nil)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusVoided).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(10000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, created, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.NoError(t, err)
+		assert.True(t, created)
 		assert.NotNil(t, result)
 		assert.Equal(t, models.TransactionTypeVoid, result.Type)
 		assert.Equal(t, amount, result.AmountCents)
@@ -54,14 +57,14 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("authorization not found", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(nil, sql.ErrNoRows)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -77,7 +80,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("wrong transaction type", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -95,7 +98,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(captureTx, nil)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -111,7 +114,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("authorization already used", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -123,12 +126,14 @@ func TestVoidService_PerformVoid(t *testing.T) {
 			AccountID:   accountID,
 			Type:        models.TransactionTypeAuthHold,
 			AmountCents: amount,
-			Status:      models.TransactionStatusCompleted, // Already used
+			Status:      models.TransactionStatusExpired, // Already used
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(nil, nil)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -144,7 +149,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("authorization already captured", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -170,9 +175,10 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
 		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(existingCapture, nil)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -181,14 +187,56 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		if assert.ErrorAs(t, err, &svcErr) {
 			assert.Equal(t, ErrCodeAlreadyCaptured, svcErr.Code)
 		}
+		assert.ErrorIs(t, err, ErrAlreadyCaptured)
 
 		mockTxRepo.AssertExpectations(t)
 	})
 
+	t.Run("already voided returns existing void", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewVoidService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Status:      models.TransactionStatusVoided,
+		}
+
+		existingVoid := &models.Transaction{
+			ID:          uuid.New(),
+			AccountID:   accountID,
+			Type:        models.TransactionTypeVoid,
+			AmountCents: 10000,
+			ReferenceID: &authID,
+			Status:      models.TransactionStatusCompleted,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(existingVoid, nil)
+
+		result, created, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existingVoid, result)
+
+		// No new row, status change or balance release on a repeat void
+		mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockAccountRepo.AssertNotCalled(t, "AdjustBalances", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockTxRepo.AssertExpectations(t)
+	})
+
 	t.Run("check existing capture fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -204,9 +252,10 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
 		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(nil, assert.AnError)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -222,7 +271,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("already voided - duplicate error", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -238,11 +287,12 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
 		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(nil, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
 			Return(models.ErrDuplicateTransaction)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -258,7 +308,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("status update fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -274,12 +324,13 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
 		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(nil, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusVoided).
 			Return(assert.AnError)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -295,7 +346,7 @@ func TestVoidService_PerformVoid(t *testing.T) {
 	t.Run("balance adjustment fails", func(t *testing.T) {
 		mockTxRepo := mocks.NewMockTransactionRepository(t)
 		mockAccountRepo := mocks.NewMockAccountRepository(t)
-		service := NewVoidService(nil)
+		service := NewVoidService(nil, nil)
 		ctx := context.Background()
 
 		authID := uuid.New()
@@ -307,17 +358,19 @@ func TestVoidService_PerformVoid(t *testing.T) {
 			AccountID:   accountID,
 			Type:        models.TransactionTypeAuthHold,
 			AmountCents: amount,
+			Currency:    "USD",
 			Status:      models.TransactionStatusActive,
 		}
 
 		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeVoid).Return(nil, nil)
 		mockTxRepo.On("FindByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(nil, nil)
 		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
-		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
-		mockAccountRepo.On("AdjustBalances", ctx, accountID, int64(0), int64(10000)).
-			Return(assert.AnError)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusVoided).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(10000, "USD"), mock.Anything).
+			Return(models.Money{}, models.Money{}, assert.AnError)
 
-		result, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
+		result, _, err := service.performVoid(ctx, mockTxRepo, mockAccountRepo, authID)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -331,3 +384,160 @@ func TestVoidService_PerformVoid(t *testing.T) {
 		mockAccountRepo.AssertExpectations(t)
 	})
 }
+
+func TestVoidService_PerformPartialVoid(t *testing.T) {
+	t.Run("releases part of the hold and keeps the authorization active", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewVoidService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    map[string]any{models.MetadataKeyAuthorizedAmount: float64(8000)},
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(1000), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockTxRepo.On("MergeMetadata", ctx, authID, map[string]any{models.MetadataKeyAuthorizedAmount: int64(5000)}).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(3000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		result, authStatus, err := service.performPartialVoid(ctx, mockTxRepo, mockAccountRepo, authID, 3000)
+
+		assert.NoError(t, err)
+		assert.Empty(t, authStatus, "the authorization stays active")
+		assert.Equal(t, models.TransactionTypePartialVoid, result.Type)
+		assert.Equal(t, int64(3000), result.AmountCents)
+		assert.Equal(t, authID, *result.ReferenceID)
+		assert.Equal(t, int64(5000), result.AuthorizedAmount())
+		mockTxRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("releasing the uncaptured rest completes the authorization", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewVoidService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(6000), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockTxRepo.On("MergeMetadata", ctx, authID, map[string]any{models.MetadataKeyAuthorizedAmount: int64(6000)}).Return(nil)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusCompleted).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(4000, "USD"), mock.Anything).Return(models.Money{}, models.Money{}, nil)
+
+		_, authStatus, err := service.performPartialVoid(ctx, mockTxRepo, mockAccountRepo, authID, 4000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.TransactionStatusCompleted, authStatus)
+	})
+
+	t.Run("releasing all of an uncaptured authorization records a void", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewVoidService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+		accountID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			AccountID:   accountID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+			Metadata:    map[string]any{models.MetadataKeyAuthorizedAmount: float64(7000)},
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(0), nil)
+		mockTxRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+		mockTxRepo.On("MergeMetadata", ctx, authID, map[string]any{models.MetadataKeyAuthorizedAmount: int64(0)}).Return(nil)
+		mockTxRepo.On("UpdateStatus", ctx, authID, models.TransactionStatusVoided).Return(nil)
+		mockAccountRepo.On("AdjustBalances", ctx, accountID, models.NewMoney(0, "USD"), models.NewMoney(7000, "USD"),
+			mock.MatchedBy(func(reason string) bool { return strings.HasPrefix(reason, "void ") })).
+			Return(models.Money{}, models.Money{}, nil)
+
+		result, authStatus, err := service.performPartialVoid(ctx, mockTxRepo, mockAccountRepo, authID, 7000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.TransactionStatusVoided, authStatus)
+		assert.Equal(t, models.TransactionTypeVoid, result.Type, "Void must find it by type")
+		assert.Equal(t, int64(7000), result.AmountCents)
+	})
+
+	t.Run("amount exceeds remaining authorization", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewVoidService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+
+		authTx := &models.Transaction{
+			ID:          authID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Currency:    "USD",
+			Status:      models.TransactionStatusActive,
+		}
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(authTx, nil)
+		mockTxRepo.On("SumByReferenceID", ctx, authID, models.TransactionTypeCapture).Return(int64(6000), nil)
+
+		result, _, err := service.performPartialVoid(ctx, mockTxRepo, mockAccountRepo, authID, 4001)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrVoidExceedsAuth)
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeVoidExceedsAuth, svcErr.Code)
+		}
+	})
+
+	t.Run("authorization already used", func(t *testing.T) {
+		mockTxRepo := mocks.NewMockTransactionRepository(t)
+		mockAccountRepo := mocks.NewMockAccountRepository(t)
+		service := NewVoidService(nil, nil)
+		ctx := context.Background()
+
+		authID := uuid.New()
+
+		mockTxRepo.On("FindByIDForUpdate", ctx, authID).Return(&models.Transaction{
+			ID:          authID,
+			Type:        models.TransactionTypeAuthHold,
+			AmountCents: 10000,
+			Status:      models.TransactionStatusVoided,
+		}, nil)
+
+		_, _, err := service.performPartialVoid(ctx, mockTxRepo, mockAccountRepo, authID, 1000)
+
+		var svcErr *ServiceError
+		if assert.ErrorAs(t, err, &svcErr) {
+			assert.Equal(t, ErrCodeAuthAlreadyUsed, svcErr.Code)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// Package tracing configures OpenTelemetry trace export and the span helpers shared by the API layers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/benx421/payment-gateway/bank/internal/config"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies the bank in exported spans
+const ServiceName = "bank-api"
+
+// AccountIDKey is the span attribute carrying the account an operation touched
+const AccountIDKey = attribute.Key("bank.account_id")
+
+// Setup installs the W3C trace context propagator and, when an endpoint is configured, a tracer
+// provider exporting spans over OTLP/HTTP. Without an endpoint the global no-op provider stays in
+// place, so spans cost next to nothing.
+// The returned function flushes buffered spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg *config.TracingConfig, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !cfg.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// AccountID returns the span attribute for an account
+func AccountID(id uuid.UUID) attribute.KeyValue {
+	return AccountIDKey.String(id.String())
+}
+
+// SetAccountID records the account an operation touched on the current span
+func SetAccountID(ctx context.Context, id uuid.UUID) {
+	trace.SpanFromContext(ctx).SetAttributes(AccountID(id))
+}
+
+// End marks span failed when err is non-nil and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,68 @@
+// Package validate checks request fields and collects every failure along with its field's path.
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is a failed check on one field, named by its JSON path in the request
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Validator collects the field errors of one request. The zero value is ready to use.
+// Each field keeps only its first error, so checks can be chained without piling up messages
+// for a field that is already invalid.
+type Validator struct {
+	errs []FieldError
+}
+
+// Check records message against field unless ok holds
+func (v *Validator) Check(ok bool, field, message string) {
+	if ok || v.failed(field) {
+		return
+	}
+	v.errs = append(v.errs, FieldError{Field: field, Message: message})
+}
+
+// Required checks that value is set
+func (v *Validator) Required(field, value string) {
+	v.Check(value != "", field, "is required")
+}
+
+// Digits checks that value is between minLen and maxLen decimal digits
+func (v *Validator) Digits(field, value string, minLen, maxLen int) {
+	ok := len(value) >= minLen && len(value) <= maxLen && strings.Trim(value, "0123456789") == ""
+	v.Check(ok, field, fmt.Sprintf("must be %d to %d digits", minLen, maxLen))
+}
+
+// Positive checks that n is greater than zero
+func (v *Validator) Positive(field string, n int64) {
+	v.Check(n > 0, field, "must be positive")
+}
+
+// Between checks that lo <= n <= hi
+func (v *Validator) Between(field string, n, lo, hi int64) {
+	v.Check(n >= lo && n <= hi, field, fmt.Sprintf("must be between %d and %d", lo, hi))
+}
+
+// Valid reports whether every check so far passed
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Errors returns the recorded field errors in the order they were found, or nil if there are none
+func (v *Validator) Errors() []FieldError {
+	return v.errs
+}
+
+func (v *Validator) failed(field string) bool {
+	for _, e := range v.errs {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	t.Run("collects the first error of each field in order", func(t *testing.T) {
+		var v Validator
+		v.Required("card_number", "")
+		v.Digits("card_number", "", 13, 19)
+		v.Digits("cvv", "12a", 3, 4)
+		v.Positive("amount", 0)
+		v.Between("expiry_month", 13, 1, 12)
+
+		assert.False(t, v.Valid())
+		assert.Equal(t, []FieldError{
+			{Field: "card_number", Message: "is required"},
+			{Field: "cvv", Message: "must be 3 to 4 digits"},
+			{Field: "amount", Message: "must be positive"},
+			{Field: "expiry_month", Message: "must be between 1 and 12"},
+		}, v.Errors())
+	})
+
+	t.Run("passing checks record nothing", func(t *testing.T) {
+		var v Validator
+		v.Required("card_number", "4111111111111111")
+		v.Digits("card_number", "4111111111111111", 13, 19)
+		v.Positive("amount", 1)
+		v.Between("expiry_month", 12, 1, 12)
+		v.Check(true, "currency", "unused")
+
+		assert.True(t, v.Valid())
+		assert.Nil(t, v.Errors())
+	})
+
+	t.Run("digits bounds", func(t *testing.T) {
+		var v Validator
+		v.Digits("short", "12", 3, 4)
+		v.Digits("long", "12345", 3, 4)
+		v.Digits("exact", "1234", 3, 4)
+
+		assert.Len(t, v.Errors(), 2)
+	})
+}
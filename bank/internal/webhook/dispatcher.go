@@ -0,0 +1,245 @@
+// Package webhook delivers transaction status notifications to a configured endpoint.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/google/uuid"
+)
+
+// Header names set on every delivery
+const (
+	SignatureHeader = "X-Signature"
+	EventIDHeader   = "X-Event-ID"
+)
+
+// maxBackoff caps the delay between delivery attempts
+const maxBackoff = 5 * time.Minute
+
+// Event types
+const (
+	EventTransactionCaptured    = "transaction.captured"
+	EventTransactionVoided      = "transaction.voided"
+	EventTransactionRefunded    = "transaction.refunded"
+	EventTransactionChargedBack = "transaction.charged_back"
+)
+
+// Event is the JSON body POSTed to the webhook endpoint
+type Event struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Data      EventData `json:"data"`
+}
+
+// EventData describes the transaction that changed status
+type EventData struct {
+	ReferenceID   *string `json:"reference_id,omitempty"`
+	TransactionID string  `json:"transaction_id"`
+	Type          string  `json:"type"`
+	Status        string  `json:"status"`
+	Currency      string  `json:"currency"`
+	Amount        int64   `json:"amount"`
+}
+
+// Config controls where and how events are delivered
+type Config struct {
+	URL            string
+	Secret         string
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	QueueSize      int
+}
+
+type delivery struct {
+	eventID string
+	payload []byte
+	attempt int
+}
+
+// Dispatcher queues events and delivers them from a background worker.
+//
+// Notify never blocks: when the queue is full the event is dropped and logged.
+// Queued events are held in memory only and are lost on shutdown.
+type Dispatcher struct {
+	client *http.Client
+	logger *slog.Logger
+	queue  chan delivery
+	done   chan struct{}
+	cfg    Config
+}
+
+// NewDispatcher creates a Dispatcher. Run must be called to start delivering.
+func NewDispatcher(cfg Config, client *http.Client, logger *slog.Logger) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Dispatcher{
+		client: client,
+		logger: logger,
+		queue:  make(chan delivery, cfg.QueueSize),
+		done:   make(chan struct{}),
+		cfg:    cfg,
+	}
+}
+
+// Notify queues an event for a transaction whose status change has been committed
+func (d *Dispatcher) Notify(ctx context.Context, txn *models.Transaction) {
+	eventType, ok := eventTypeFor(txn.Type)
+	if !ok {
+		return
+	}
+
+	event := Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data: EventData{
+			TransactionID: txn.ID.String(),
+			Type:          string(txn.Type),
+			Status:        string(txn.Status),
+			Amount:        txn.AmountCents,
+			Currency:      txn.Currency,
+		},
+	}
+	if txn.ReferenceID != nil {
+		ref := txn.ReferenceID.String()
+		event.Data.ReferenceID = &ref
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		d.logger.ErrorContext(ctx, "failed to marshal webhook event", "error", err, "transaction_id", txn.ID)
+		return
+	}
+
+	if !d.enqueue(delivery{eventID: event.ID, payload: payload, attempt: 1}) {
+		d.logger.WarnContext(ctx, "webhook queue full, dropping event", "event_id", event.ID, "type", event.Type)
+	}
+}
+
+// Run delivers queued events until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	defer close(d.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("stopping webhook dispatcher", "pending", len(d.queue))
+			return
+		case job := <-d.queue:
+			d.deliver(ctx, job)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	err := d.send(ctx, job)
+	if err == nil {
+		return
+	}
+
+	if job.attempt >= d.cfg.MaxAttempts {
+		d.logger.Error("webhook delivery failed, giving up",
+			"event_id", job.eventID,
+			"attempts", job.attempt,
+			"error", err,
+		)
+		return
+	}
+
+	delay := d.backoff(job.attempt)
+	d.logger.Warn("webhook delivery failed, retrying",
+		"event_id", job.eventID,
+		"attempt", job.attempt,
+		"retry_in", delay,
+		"error", err,
+	)
+
+	// Retries are re-queued after the delay so a failing endpoint does not stall other events
+	job.attempt++
+	time.AfterFunc(delay, func() {
+		select {
+		case <-d.done:
+			return
+		default:
+		}
+		if !d.enqueue(job) {
+			d.logger.Warn("webhook queue full, dropping retry", "event_id", job.eventID)
+		}
+	})
+}
+
+func (d *Dispatcher) send(ctx context.Context, job delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.cfg.URL, bytes.NewReader(job.payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, job.eventID)
+	req.Header.Set(SignatureHeader, Sign(d.cfg.Secret, job.payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+	defer resp.Body.Close()
+	//nolint:errcheck // Drain so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Dispatcher) enqueue(job delivery) bool {
+	select {
+	case d.queue <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the next attempt, doubling from the initial backoff
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.InitialBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of payload, prefixed with the algorithm
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func eventTypeFor(t models.TransactionType) (string, bool) {
+	switch t {
+	case models.TransactionTypeCapture:
+		return EventTransactionCaptured, true
+	case models.TransactionTypeVoid:
+		return EventTransactionVoided, true
+	case models.TransactionTypeRefund:
+		return EventTransactionRefunded, true
+	case models.TransactionTypeChargeback:
+		return EventTransactionChargedBack, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func captureTxn() *models.Transaction {
+	authID := uuid.New()
+	return &models.Transaction{
+		ID:          uuid.New(),
+		Type:        models.TransactionTypeCapture,
+		Status:      models.TransactionStatusCompleted,
+		AmountCents: 10000,
+		Currency:    "USD",
+		ReferenceID: &authID,
+	}
+}
+
+func startDispatcher(t *testing.T, cfg Config) *Dispatcher {
+	t.Helper()
+
+	d := NewDispatcher(cfg, nil, testLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	go d.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-d.done
+	})
+	return d
+}
+
+func TestDispatcher_DeliversSignedEvent(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body) //nolint:errcheck // test server
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := startDispatcher(t, Config{URL: server.URL, Secret: "s3cret", MaxAttempts: 3, InitialBackoff: time.Millisecond, QueueSize: 10})
+
+	txn := captureTxn()
+	d.Notify(context.Background(), txn)
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+	body := <-bodies
+
+	assert.Equal(t, Sign("s3cret", body), req.Header.Get(SignatureHeader))
+	assert.NotEmpty(t, req.Header.Get(EventIDHeader))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, EventTransactionCaptured, event.Type)
+	assert.Equal(t, txn.ID.String(), event.Data.TransactionID)
+	assert.Equal(t, int64(10000), event.Data.Amount)
+	require.NotNil(t, event.Data.ReferenceID)
+	assert.Equal(t, txn.ReferenceID.String(), *event.Data.ReferenceID)
+}
+
+func TestDispatcher_RetriesFailedDeliveries(t *testing.T) {
+	var attempts atomic.Int32
+	delivered := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		close(delivered)
+	}))
+	defer server.Close()
+
+	d := startDispatcher(t, Config{URL: server.URL, Secret: "s", MaxAttempts: 5, InitialBackoff: time.Millisecond, QueueSize: 10})
+	d.Notify(context.Background(), captureTxn())
+
+	select {
+	case <-delivered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not redelivered")
+	}
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestDispatcher_Notify(t *testing.T) {
+	t.Run("ignores transactions without an event", func(t *testing.T) {
+		d := NewDispatcher(Config{QueueSize: 1}, nil, testLogger())
+		d.Notify(context.Background(), &models.Transaction{Type: models.TransactionTypeAuthHold})
+		assert.Empty(t, d.queue)
+	})
+
+	t.Run("drops events when the queue is full", func(t *testing.T) {
+		d := NewDispatcher(Config{QueueSize: 1}, nil, testLogger())
+		d.Notify(context.Background(), captureTxn())
+		d.Notify(context.Background(), captureTxn())
+		assert.Len(t, d.queue, 1)
+	})
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(Config{InitialBackoff: time.Second, QueueSize: 1}, nil, testLogger())
+
+	assert.Equal(t, time.Second, d.backoff(1))
+	assert.Equal(t, 2*time.Second, d.backoff(2))
+	assert.Equal(t, 8*time.Second, d.backoff(4))
+	assert.Equal(t, maxBackoff, d.backoff(20))
+}
@@ -2,9 +2,11 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 
@@ -63,6 +65,135 @@ func TestAuthorizeAndVoid(t *testing.T) {
 	assert.Equal(t, authID, voidBody["authorization_id"])
 }
 
+func TestVoid_RepeatReturnsExistingVoid(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 20000, "repeat-void-auth")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	var authBody map[string]any
+	require.NoError(t, json.NewDecoder(authResp.Body).Decode(&authBody))
+	authResp.Body.Close()
+	authID := authBody["authorization_id"].(string)
+
+	// Distinct idempotency keys so the second request reaches the service
+	first := ts.Void(t, authID, "repeat-void-1")
+	var firstBody map[string]any
+	require.NoError(t, json.NewDecoder(first.Body).Decode(&firstBody))
+	first.Body.Close()
+	require.Equal(t, http.StatusOK, first.StatusCode)
+
+	second := ts.Void(t, authID, "repeat-void-2")
+	var secondBody map[string]any
+	require.NoError(t, json.NewDecoder(second.Body).Decode(&secondBody))
+	second.Body.Close()
+	require.Equal(t, http.StatusOK, second.StatusCode)
+
+	assert.Equal(t, firstBody["void_id"], secondBody["void_id"])
+}
+
+func TestPartialVoid_FullAmountThenVoid(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 10000, "full-partial-void-auth")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	var authBody map[string]any
+	require.NoError(t, json.NewDecoder(authResp.Body).Decode(&authBody))
+	authResp.Body.Close()
+	authID := authBody["authorization_id"].(string)
+
+	first := ts.PartialVoid(t, authID, 4000, "full-partial-void-1")
+	first.Body.Close()
+	require.Equal(t, http.StatusOK, first.StatusCode)
+
+	rest := ts.PartialVoid(t, authID, 6000, "full-partial-void-2")
+	var restBody map[string]any
+	require.NoError(t, json.NewDecoder(rest.Body).Decode(&restBody))
+	rest.Body.Close()
+	require.Equal(t, http.StatusOK, rest.StatusCode)
+	assert.Equal(t, "voided", restBody["status"], "releasing the whole rest voids the authorization")
+
+	balResp, err := http.Get(ts.URL("/api/v1/accounts/4111111111111111/balance"))
+	require.NoError(t, err)
+	var before map[string]any
+	require.NoError(t, json.NewDecoder(balResp.Body).Decode(&before))
+	balResp.Body.Close()
+
+	voidResp := ts.Void(t, authID, "full-partial-void-3")
+	var voidBody map[string]any
+	require.NoError(t, json.NewDecoder(voidResp.Body).Decode(&voidBody))
+	voidResp.Body.Close()
+	require.Equal(t, http.StatusOK, voidResp.StatusCode)
+	assert.Equal(t, restBody["void_id"], voidBody["void_id"], "the void returns the release already recorded")
+
+	balResp, err = http.Get(ts.URL("/api/v1/accounts/4111111111111111/balance"))
+	require.NoError(t, err)
+	var after map[string]any
+	require.NoError(t, json.NewDecoder(balResp.Body).Decode(&after))
+	balResp.Body.Close()
+	assert.Equal(t, before["available_balance_cents"], after["available_balance_cents"], "nothing is released twice")
+}
+
+func TestPartialVoid_LowersAuthorization(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 10000, "partial-void-auth")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	var authBody map[string]any
+	require.NoError(t, json.NewDecoder(authResp.Body).Decode(&authBody))
+	authResp.Body.Close()
+	authID := authBody["authorization_id"].(string)
+
+	voidResp := ts.PartialVoid(t, authID, 3000, "partial-void-1")
+	var voidBody map[string]any
+	require.NoError(t, json.NewDecoder(voidResp.Body).Decode(&voidBody))
+	voidResp.Body.Close()
+	require.Equal(t, http.StatusOK, voidResp.StatusCode)
+	assert.Equal(t, "partially_voided", voidBody["status"])
+	assert.Equal(t, float64(3000), voidBody["amount"])
+	assert.Equal(t, float64(7000), voidBody["remaining_amount"])
+
+	balResp, err := http.Get(ts.URL("/api/v1/accounts/4111111111111111/balance"))
+	require.NoError(t, err)
+	var balance map[string]any
+	require.NoError(t, json.NewDecoder(balResp.Body).Decode(&balance))
+	balResp.Body.Close()
+	assert.Equal(t, balance["balance_cents"].(float64)-7000, balance["available_balance_cents"])
+
+	over := ts.Capture(t, authID, 7001, "partial-void-cap-1")
+	var overBody map[string]any
+	require.NoError(t, json.NewDecoder(over.Body).Decode(&overBody))
+	over.Body.Close()
+	assert.Equal(t, "capture_exceeds_authorization", errorCode(overBody))
+
+	captured := ts.Capture(t, authID, 5000, "partial-void-cap-2")
+	captured.Body.Close()
+	require.Equal(t, http.StatusOK, captured.StatusCode)
+
+	tooMuch := ts.PartialVoid(t, authID, 2001, "partial-void-2")
+	var tooMuchBody map[string]any
+	require.NoError(t, json.NewDecoder(tooMuch.Body).Decode(&tooMuchBody))
+	tooMuch.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, tooMuch.StatusCode)
+	assert.Equal(t, "void_exceeds_authorization", errorCode(tooMuchBody))
+
+	// Releasing the uncaptured rest completes the authorization
+	rest := ts.PartialVoid(t, authID, 2000, "partial-void-3")
+	rest.Body.Close()
+	require.Equal(t, http.StatusOK, rest.StatusCode)
+
+	done := ts.Capture(t, authID, 1, "partial-void-cap-3")
+	var doneBody map[string]any
+	require.NoError(t, json.NewDecoder(done.Body).Decode(&doneBody))
+	done.Body.Close()
+	assert.Equal(t, "authorization_already_used", errorCode(doneBody))
+}
+
 func TestFullFlow_AuthorizeCaptureRefund(t *testing.T) {
 	ts := SetupTest(t)
 	defer ts.Close()
@@ -95,6 +226,75 @@ func TestFullFlow_AuthorizeCaptureRefund(t *testing.T) {
 	assert.Contains(t, refundBody["refund_id"].(string), "ref_")
 }
 
+func TestCapture_PartialCapturesCappedAtAuthorization(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 10000, "partial-cap-auth")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	var authBody map[string]any
+	require.NoError(t, json.NewDecoder(authResp.Body).Decode(&authBody))
+	authResp.Body.Close()
+	authID := authBody["authorization_id"].(string)
+
+	first := ts.Capture(t, authID, 6000, "partial-cap-1")
+	first.Body.Close()
+	require.Equal(t, http.StatusOK, first.StatusCode)
+
+	over := ts.Capture(t, authID, 5000, "partial-cap-2")
+	var overBody map[string]any
+	require.NoError(t, json.NewDecoder(over.Body).Decode(&overBody))
+	over.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, over.StatusCode)
+	assert.Equal(t, "capture_exceeds_authorization", errorCode(overBody))
+
+	rest := ts.Capture(t, authID, 4000, "partial-cap-3")
+	rest.Body.Close()
+	assert.Equal(t, http.StatusOK, rest.StatusCode)
+
+	done := ts.Capture(t, authID, 1, "partial-cap-4")
+	var doneBody map[string]any
+	require.NoError(t, json.NewDecoder(done.Body).Decode(&doneBody))
+	done.Body.Close()
+	assert.Equal(t, "authorization_already_used", errorCode(doneBody))
+}
+
+func TestRefund_PartialRefundsCappedAtCapture(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 10000, "partial-refund-auth")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	var authBody map[string]any
+	require.NoError(t, json.NewDecoder(authResp.Body).Decode(&authBody))
+	authResp.Body.Close()
+
+	captureResp := ts.Capture(t, authBody["authorization_id"].(string), 10000, "partial-refund-cap")
+	require.Equal(t, http.StatusOK, captureResp.StatusCode)
+
+	var captureBody map[string]any
+	require.NoError(t, json.NewDecoder(captureResp.Body).Decode(&captureBody))
+	captureResp.Body.Close()
+	captureID := captureBody["capture_id"].(string)
+
+	first := ts.Refund(t, captureID, 6000, "partial-refund-1")
+	first.Body.Close()
+	require.Equal(t, http.StatusOK, first.StatusCode)
+
+	over := ts.Refund(t, captureID, 5000, "partial-refund-2")
+	var overBody map[string]any
+	require.NoError(t, json.NewDecoder(over.Body).Decode(&overBody))
+	over.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, over.StatusCode)
+	assert.Equal(t, "refund_exceeds_capture", errorCode(overBody))
+
+	rest := ts.Refund(t, captureID, 4000, "partial-refund-3")
+	rest.Body.Close()
+	assert.Equal(t, http.StatusOK, rest.StatusCode)
+}
+
 func TestAuthorization_InvalidCard(t *testing.T) {
 	ts := SetupTest(t)
 	defer ts.Close()
@@ -106,7 +306,34 @@ func TestAuthorization_InvalidCard(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
 	resp.Body.Close()
 
-	assert.Equal(t, "invalid_card", body["error"])
+	assert.Equal(t, "invalid_card", errorCode(body))
+}
+
+func TestAuthorization_ValidationErrors(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	resp := ts.Authorize(t, "4111-1111", "", 0, "validation-errors-key")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body struct {
+		Error struct {
+			Code   string `json:"code"`
+			Fields []struct {
+				Field   string `json:"field"`
+				Message string `json:"message"`
+			} `json:"fields"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+
+	assert.Equal(t, "validation_failed", body.Error.Code)
+	fields := make([]string, len(body.Error.Fields))
+	for i, f := range body.Error.Fields {
+		fields[i] = f.Field
+	}
+	assert.Equal(t, []string{"card_number", "cvv", "amount"}, fields)
 }
 
 func TestAuthorization_InvalidCVV(t *testing.T) {
@@ -120,7 +347,7 @@ func TestAuthorization_InvalidCVV(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
 	resp.Body.Close()
 
-	assert.Equal(t, "invalid_cvv", body["error"])
+	assert.Equal(t, "invalid_cvv", errorCode(body))
 }
 
 func TestAuthorization_ExpiredCard(t *testing.T) {
@@ -134,7 +361,7 @@ func TestAuthorization_ExpiredCard(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
 	resp.Body.Close()
 
-	assert.Equal(t, "card_expired", body["error"])
+	assert.Equal(t, "card_expired", errorCode(body))
 }
 
 func TestAuthorization_InsufficientFunds(t *testing.T) {
@@ -148,7 +375,37 @@ func TestAuthorization_InsufficientFunds(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
 	resp.Body.Close()
 
-	assert.Equal(t, "insufficient_funds", body["error"])
+	assert.Equal(t, "insufficient_funds", errorCode(body))
+}
+
+func TestAuthorization_DeclineIsRecorded(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	resp := ts.Authorize(t, "5555555555554444", "789", 100, "declined-key") // Balance: $0
+	require.Equal(t, http.StatusPaymentRequired, resp.StatusCode)
+	resp.Body.Close()
+
+	var txnID, status, reason string
+	err := ts.Database.QueryRowContext(context.Background(), `
+		SELECT t.id, t.status, t.decline_reason
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE a.account_number = '5555555555554444'
+	`).Scan(&txnID, &status, &reason)
+	require.NoError(t, err, "the decline should be recorded")
+	assert.Equal(t, "DECLINED", status)
+	assert.Equal(t, "insufficient_funds", reason)
+
+	resp, err = http.Get(ts.URL("/api/v1/transactions/" + txnID))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "DECLINED", body["status"])
+	assert.Equal(t, "insufficient_funds", body["decline_reason"])
 }
 
 func TestCapture_AuthorizationAlreadyUsed(t *testing.T) {
@@ -174,7 +431,7 @@ func TestCapture_AuthorizationAlreadyUsed(t *testing.T) {
 	require.NoError(t, json.NewDecoder(cap2.Body).Decode(&body))
 	cap2.Body.Close()
 
-	assert.Equal(t, "authorization_already_used", body["error"])
+	assert.Equal(t, "authorization_already_used", errorCode(body))
 }
 
 func TestVoid_AfterCapture(t *testing.T) {
@@ -201,7 +458,7 @@ func TestVoid_AfterCapture(t *testing.T) {
 	voidResp.Body.Close()
 
 	// After capture, auth status is COMPLETED so we get "authorization_already_used"
-	assert.Equal(t, "authorization_already_used", body["error"])
+	assert.Equal(t, "authorization_already_used", errorCode(body))
 }
 
 func TestIdempotency_ReplaysSameResponse(t *testing.T) {
@@ -245,6 +502,63 @@ func TestIdempotency_DifferentKeysCreateDifferentAuthorizations(t *testing.T) {
 	assert.NotEqual(t, body1["authorization_id"], body2["authorization_id"])
 }
 
+func TestIdempotency_ConcurrentSameKeyAuthorizesOnce(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	const numGoroutines = 2
+	var wg sync.WaitGroup
+	results := make(chan map[string]any, numGoroutines)
+	replayed := make(chan string, numGoroutines)
+
+	for range numGoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := ts.Authorize(t, "4111111111111111", "123", 10000, "concurrent-same-key")
+			defer resp.Body.Close()
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+			var body map[string]any
+			if assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body)) {
+				results <- body
+				replayed <- resp.Header.Get("X-Idempotent-Replayed")
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+	close(replayed)
+
+	var authIDs []any
+	for body := range results {
+		authIDs = append(authIDs, body["authorization_id"])
+	}
+	require.Len(t, authIDs, numGoroutines)
+	assert.Equal(t, authIDs[0], authIDs[1], "both requests should return the same authorization")
+
+	var replays int
+	for header := range replayed {
+		if header == "true" {
+			replays++
+		}
+	}
+	assert.Equal(t, 1, replays, "exactly one response should be a replay")
+
+	resp, err := http.Get(ts.URL("/api/v1/accounts/4111111111111111/balance"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var balance map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&balance))
+	resp.Body.Close()
+
+	assert.Equal(t, balance["balance_cents"].(float64)-10000, balance["available_balance_cents"],
+		"only one hold should be placed")
+}
+
 func TestConcurrentCaptures_OnlyOneSucceeds(t *testing.T) {
 	ts := SetupTest(t)
 	defer ts.Close()
@@ -322,3 +636,134 @@ func TestGetAuthorization_NotFound(t *testing.T) {
 	require.Equal(t, http.StatusNotFound, resp.StatusCode)
 	resp.Body.Close()
 }
+
+func TestGetAccountBalance_ReflectsHold(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 10000, "balance-key")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+	authResp.Body.Close()
+
+	resp, err := http.Get(ts.URL("/api/v1/accounts/4111111111111111/balance"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+
+	assert.Equal(t, "************1111", body["account_number"])
+	assert.Equal(t, body["balance_cents"].(float64)-10000, body["available_balance_cents"])
+	assert.Equal(t, "USD", body["currency"])
+	assert.NotContains(t, body, "cvv")
+}
+
+func TestGetAccountBalance_NotFound(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL("/api/v1/accounts/4000000000000000/balance"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+	assert.Equal(t, "account_not_found", errorCode(body))
+}
+
+func TestGetAccount_ETag(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	var accountID string
+	require.NoError(t, ts.Database.QueryRowContext(context.Background(),
+		`SELECT id FROM accounts WHERE account_number = '4111111111111111'`).Scan(&accountID))
+
+	get := func(ifNoneMatch string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL("/api/v1/accounts/"+accountID), nil)
+		require.NoError(t, err)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(first.Body).Decode(&body))
+	first.Body.Close()
+	assert.Equal(t, "************1111", body["account_number"])
+	assert.NotContains(t, body, "cvv")
+	etag := first.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	unchanged := get(etag)
+	unchanged.Body.Close()
+	assert.Equal(t, http.StatusNotModified, unchanged.StatusCode)
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 1000, "account-etag-auth")
+	authResp.Body.Close()
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	changed := get(etag)
+	changed.Body.Close()
+	assert.Equal(t, http.StatusOK, changed.StatusCode, "a hold changes the balance, so the old ETag must not match")
+	assert.NotEqual(t, etag, changed.Header.Get("ETag"))
+
+	resp, err := http.Get(ts.URL("/api/v1/accounts/00000000-0000-0000-0000-000000000000"))
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "account_not_found", errorCode(body))
+}
+
+func TestGetTransaction_ReturnsAuthHold(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	authResp := ts.Authorize(t, "4111111111111111", "123", 10000, "get-txn-key")
+	require.Equal(t, http.StatusOK, authResp.StatusCode)
+
+	var authBody map[string]any
+	require.NoError(t, json.NewDecoder(authResp.Body).Decode(&authBody))
+	authResp.Body.Close()
+	txnID := strings.TrimPrefix(authBody["authorization_id"].(string), "auth_")
+
+	resp, err := http.Get(ts.URL("/api/v1/transactions/" + txnID))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+
+	assert.Equal(t, txnID, body["id"])
+	assert.Equal(t, "AUTH_HOLD", body["type"])
+	assert.Equal(t, "ACTIVE", body["status"])
+	assert.Equal(t, float64(10000), body["amount"])
+}
+
+func TestGetTransaction_InvalidAndMissing(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL("/api/v1/transactions/not-a-uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = http.Get(ts.URL("/api/v1/transactions/00000000-0000-0000-0000-000000000000"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+	assert.Equal(t, "transaction_not_found", errorCode(body))
+}
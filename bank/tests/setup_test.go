@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/benx421/payment-gateway/bank/internal/config"
@@ -43,7 +44,10 @@ func SetupTest(t *testing.T) *TestServer {
 
 	resetTestData(t, database)
 
-	router := handlers.NewRouter(database, cfg, logger)
+	ready := &atomic.Bool{}
+	ready.Store(true)
+
+	router := handlers.NewRouter(database, cfg, ready, &atomic.Bool{}, nil, logger)
 	server := httptest.NewServer(router)
 
 	return &TestServer{
@@ -69,13 +73,33 @@ func resetTestData(t *testing.T, database *db.DB) {
 
 	_, err := database.ExecContext(context.Background(), `
 		TRUNCATE TABLE transactions CASCADE;
+		TRUNCATE TABLE transactions_archive CASCADE;
 		TRUNCATE TABLE idempotency_keys CASCADE;
+		TRUNCATE TABLE request_nonces;
+		TRUNCATE TABLE balance_audit;
 		DELETE FROM accounts;
-		INSERT INTO accounts (account_number, cvv, expiry_month, expiry_year, balance_cents, available_balance_cents) VALUES
-			('4111111111111111', '123', 12, 2030, 1000000, 1000000),
-			('4242424242424242', '456', 6, 2030, 50000, 50000),
-			('5555555555554444', '789', 9, 2030, 0, 0),
-			('5105105105105100', '321', 3, 2020, 500000, 500000);
+		WITH seeded (account_number, cvv, expiry_month, expiry_year, balance_cents) AS (
+			VALUES ('4111111111111111', '123', 12, 2030, 1000000::bigint),
+			       ('4242424242424242', '456', 6, 2030, 50000),
+			       ('5555555555554444', '789', 9, 2030, 0),
+			       ('5105105105105100', '321', 3, 2020, 500000)
+		),
+		account AS (
+			INSERT INTO accounts (account_number, cvv, expiry_month, expiry_year)
+			SELECT account_number, cvv, expiry_month, expiry_year FROM seeded
+			RETURNING id, account_number, currency
+		),
+		balance AS (
+			INSERT INTO balances (account_id, currency, balance_cents, available_balance_cents)
+			SELECT a.id, a.currency, s.balance_cents, s.balance_cents
+			FROM account a
+			JOIN seeded s ON s.account_number = a.account_number
+			RETURNING account_id, currency, balance_cents
+		)
+		INSERT INTO balance_audit (account_id, currency, balance_delta_cents, available_balance_delta_cents, actor, reason)
+		SELECT account_id, currency, balance_cents, balance_cents, 'system', 'opening balance'
+		FROM balance
+		WHERE balance_cents <> 0;
 	`)
 	require.NoError(t, err, "failed to reset test data")
 }
@@ -146,6 +170,28 @@ func (ts *TestServer) Void(t *testing.T, authID string, idempotencyKey string) *
 	return resp
 }
 
+// PartialVoid sends a POST request to lower an authorization by amount.
+func (ts *TestServer) PartialVoid(t *testing.T, authID string, amount int64, idempotencyKey string) *http.Response {
+	t.Helper()
+
+	body := map[string]any{
+		"authorization_id": authID,
+		"amount":           amount,
+	}
+	jsonBody, _ := json.Marshal(body)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL("/api/v1/voids"), bytes.NewReader(jsonBody))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
+
 // Refund sends a POST request to refund a capture.
 func (ts *TestServer) Refund(t *testing.T, captureID string, amount int64, idempotencyKey string) *http.Response {
 	t.Helper()
@@ -167,3 +213,9 @@ func (ts *TestServer) Refund(t *testing.T, captureID string, amount int64, idemp
 
 	return resp
 }
+
+// errorCode extracts the code from a decoded {"error":{"code":...}} envelope.
+func errorCode(body map[string]any) any {
+	detail, _ := body["error"].(map[string]any)
+	return detail["code"]
+}
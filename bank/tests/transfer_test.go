@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benx421/payment-gateway/bank/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransfer_ReciprocalTransfersDoNotDeadlock(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	transfers := service.NewTransferService(ts.Database)
+	const rounds = 50
+	accounts := [2]string{"4111111111111111", "4242424242424242"}
+	before := ts.totalBalance(t, accounts[:])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*rounds)
+	for i := range 2 {
+		from, to := accounts[i], accounts[1-i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				_, err := transfers.Transfer(ctx, from, to, 100, "USD")
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err, "reciprocal transfers should all succeed")
+	}
+	assert.Equal(t, before, ts.totalBalance(t, accounts[:]), "transfers should conserve money")
+}
+
+func TestTransfer_RecordsLinkedEntries(t *testing.T) {
+	ts := SetupTest(t)
+	defer ts.Close()
+
+	transfers := service.NewTransferService(ts.Database)
+	ctx := context.Background()
+
+	result, err := transfers.Transfer(ctx, "4111111111111111", "5555555555554444", 2500, "USD")
+	require.NoError(t, err)
+
+	var debits int
+	require.NoError(t, ts.Database.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM transactions WHERE id = $1 AND type = 'TRANSFER_OUT'", result.DebitID).Scan(&debits))
+	assert.Equal(t, 1, debits)
+	var creditRef string
+	require.NoError(t, ts.Database.QueryRowContext(ctx,
+		"SELECT reference_id FROM transactions WHERE id = $1 AND type = 'TRANSFER_IN'", result.CreditID).Scan(&creditRef))
+	assert.Equal(t, result.DebitID.String(), creditRef)
+	assert.Equal(t, int64(2500), ts.totalBalance(t, []string{"5555555555554444"}))
+
+	_, err = transfers.Transfer(ctx, "5555555555554444", "4111111111111111", 2501, "USD")
+	require.ErrorIs(t, err, service.ErrInsufficientFunds)
+	assert.Equal(t, int64(2500), ts.totalBalance(t, []string{"5555555555554444"}), "a rejected transfer changes nothing")
+}
+
+// totalBalance sums the balances of the given accounts
+func (ts *TestServer) totalBalance(t *testing.T, accountNumbers []string) int64 {
+	t.Helper()
+
+	var total int64
+	for _, number := range accountNumbers {
+		var balance int64
+		err := ts.Database.QueryRowContext(context.Background(),
+			"SELECT balance_cents FROM accounts_with_balance WHERE account_number = $1", number).Scan(&balance)
+		require.NoError(t, err, "failed to read balance")
+		total += balance
+	}
+	return total
+}